@@ -0,0 +1,39 @@
+// Package all imports every example package for its side effect of
+// registering its examples with the registry. Programs that want the
+// complete catalog only need to import this package.
+package all
+
+import (
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/actors"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/atomics"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/channels"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/chanpatterns"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/contextdemo"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/deadlocks"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/future"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/goroutines"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/groups"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/lazy"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/leaks"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/locks"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/memorymodel"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/patterns"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/races"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/ratelimit"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/scope"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/selects"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/semaphores"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/shutdown"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/synccollections"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/timers"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/wordcount"
+	_ "github.com/amandm/programming-concepts/GOlang/concurrency/workerpool"
+	_ "github.com/amandm/programming-concepts/GOlang/constants"
+	_ "github.com/amandm/programming-concepts/GOlang/functions"
+	_ "github.com/amandm/programming-concepts/GOlang/generics"
+	_ "github.com/amandm/programming-concepts/GOlang/interfaces"
+	_ "github.com/amandm/programming-concepts/GOlang/memory"
+	_ "github.com/amandm/programming-concepts/GOlang/panics"
+	_ "github.com/amandm/programming-concepts/GOlang/pointers"
+	_ "github.com/amandm/programming-concepts/GOlang/structs"
+)
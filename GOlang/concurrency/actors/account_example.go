@@ -0,0 +1,266 @@
+package actors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(accountExample{})
+}
+
+// accountExample runs the same busy bank account twice: as shared state
+// behind a mutex, and as an actor that is the only goroutine to touch the
+// balance.
+type accountExample struct{}
+
+func (accountExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/actors/account_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "a bank account as an actor with a mailbox, compared with a mutex-guarded one",
+		Tags:          []string{"concurrency", "actors", "message-passing", "mutex", "channels"},
+		Prerequisites: []string{"concurrency/races/racy_counter", "concurrency/channels/unbuffered_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (accountExample) Explain(step string) string {
+	switch step {
+	case "mutex":
+		return "Every goroutine changes the balance itself, and the mutex makes them take turns."
+	case "actor":
+		return "Only the actor's goroutine changes the balance; the others send it messages, and wait for its reply when they need one."
+	case "stop":
+		return "Stopping is a message of its own kind: the actor finishes the message in hand and returns, and sends after that fail instead of hanging."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (accountExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Why does the actor's balance need no mutex?",
+			Choices: []string{"channels make variables atomic", "only one goroutine ever reads or writes it", "it is copied into every message"},
+			Answer:  "only one goroutine ever reads or writes it",
+			Explain: "The actor's goroutine owns the balance; the messages are what is shared, and the channel hands them over safely.",
+		},
+		{
+			Prompt:  "How does a goroutine get the actor's balance?",
+			Choices: []string{"it reads the actor's field", "it sends a message with a reply channel and waits on it"},
+			Answer:  "it sends a message with a reply channel and waits on it",
+			Explain: "That is what Ask does: the message carries a channel for the answer back.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench concurrency".
+func (accountExample) Experiments() []benchlab.Experiment {
+	return []benchlab.Experiment{{
+		Name: "deposits from several goroutines",
+		Approaches: []benchlab.Approach{
+			{Name: "mutex", Bench: func(b *testing.B) {
+				var acc lockedAccount
+				b.SetParallelism(4)
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						acc.deposit(1)
+					}
+				})
+			}},
+			{Name: "actor", Bench: func(b *testing.B) {
+				acc := newAccount()
+				defer acc.Stop()
+				b.SetParallelism(4)
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						acc.Send(deposit{1})
+					}
+				})
+			}},
+		},
+		Guidance: "Each actor message is a channel hand-over between two goroutines, " +
+			"which costs more than an uncontended lock and unlock, so for a " +
+			"single balance the mutex wins. Actors pay off when the state is " +
+			"large or the rules around it are involved: one goroutine applying " +
+			"one message at a time is easy to reason about, and nothing can " +
+			"forget to take a lock.",
+	}}
+}
+
+// errInsufficientFunds is the error of a withdrawal that would overdraw
+// the account.
+var errInsufficientFunds = errors.New("insufficient funds")
+
+// lockedAccount is the shared-state account.
+type lockedAccount struct {
+	mu      sync.Mutex
+	balance int
+}
+
+func (a *lockedAccount) deposit(amount int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.balance += amount
+}
+
+func (a *lockedAccount) withdraw(amount int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if amount > a.balance {
+		return errInsufficientFunds
+	}
+	a.balance -= amount
+	return nil
+}
+
+func (a *lockedAccount) get() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.balance
+}
+
+// The messages the account actor understands.
+type (
+	accountMsg interface{ isAccountMsg() }
+
+	deposit  struct{ amount int }
+	withdraw struct {
+		amount int
+		reply  chan<- error
+	}
+	getBalance struct{ reply chan<- int }
+)
+
+func (deposit) isAccountMsg()    {}
+func (withdraw) isAccountMsg()   {}
+func (getBalance) isAccountMsg() {}
+
+// newAccount starts an account actor. Its balance is a local variable of
+// the handler: nothing else can even reach it.
+func newAccount() *Actor[accountMsg] {
+	balance := 0
+	return Spawn(func(m accountMsg) {
+		switch m := m.(type) {
+		case deposit:
+			balance += m.amount
+		case withdraw:
+			if m.amount > balance {
+				m.reply <- errInsufficientFunds
+				return
+			}
+			balance -= m.amount
+			m.reply <- nil
+		case getBalance:
+			m.reply <- balance
+		}
+	})
+}
+
+// account is what the scenario needs of either kind of account.
+type account struct {
+	deposit  func(int) error
+	withdraw func(int) error
+	balance  func() (int, error)
+}
+
+const (
+	depositors  = 100
+	withdrawers = 50
+)
+
+// busyDay has depositors goroutines each deposit 10 and withdrawers
+// goroutines each withdraw 30, all at once, on top of an opening balance
+// of 1000. There is always enough money, so the day ends at 500.
+func busyDay(acc account) (int, error) {
+	if err := acc.deposit(1000); err != nil {
+		return 0, err
+	}
+	errs := make(chan error, depositors+withdrawers)
+	var wg sync.WaitGroup
+	for range depositors {
+		wg.Go(func() { errs <- acc.deposit(10) })
+	}
+	for range withdrawers {
+		wg.Go(func() { errs <- acc.withdraw(30) })
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return 0, err
+		}
+	}
+	return acc.balance()
+}
+
+// Run has the same busy day at both accounts, then stops the actor.
+func (accountExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Shared state: the goroutines update the balance under a mutex.
+	e.Step("mutex")
+	e.Say("%d deposits of 10 and %d withdrawals of 30, all at once, from an opening balance of 1000.", depositors, withdrawers)
+	var locked lockedAccount
+	got, err := busyDay(account{
+		deposit:  func(n int) error { locked.deposit(n); return nil },
+		withdraw: locked.withdraw,
+		balance:  func() (int, error) { return locked.get(), nil },
+	})
+	if err != nil {
+		return err
+	}
+	e.Value("balance", got, "Closing balance (mutex)")
+	assert.Equal(check, "the mutex-guarded account counts every transaction", got, 500)
+	err = locked.withdraw(600)
+	e.Value("err", err, "Withdrawing 600")
+	check.That(errors.Is(err, errInsufficientFunds), "the mutex-guarded account refuses to overdraw")
+
+	// 2. An actor: the goroutines send messages, and only the actor
+	// touches the balance.
+	e.Step("actor")
+	acc := newAccount()
+	got, err = busyDay(account{
+		deposit: func(n int) error { return acc.Send(deposit{n}) },
+		withdraw: func(n int) error {
+			err, sendErr := Ask(acc, func(reply chan<- error) accountMsg { return withdraw{n, reply} })
+			return errors.Join(sendErr, err)
+		},
+		balance: func() (int, error) {
+			return Ask(acc, func(reply chan<- int) accountMsg { return getBalance{reply} })
+		},
+	})
+	if err != nil {
+		return err
+	}
+	e.Value("balance", got, "Closing balance (actor)")
+	assert.Equal(check, "the actor counts every transaction", got, 500)
+	err, _ = Ask(acc, func(reply chan<- error) accountMsg { return withdraw{600, reply} })
+	e.Value("err", err, "Withdrawing 600")
+	check.That(errors.Is(err, errInsufficientFunds), "the actor refuses to overdraw")
+
+	// 3. Stop the actor; sending to it afterwards fails at once.
+	e.Step("stop")
+	acc.Stop()
+	err = acc.Send(deposit{10})
+	e.Value("err", err, "Send after Stop")
+	check.That(errors.Is(err, ErrStopped), "a stopped actor refuses messages")
+	_, err = Ask(acc, func(reply chan<- int) accountMsg { return getBalance{reply} })
+	check.That(errors.Is(err, ErrStopped), "asking a stopped actor fails instead of waiting for ever")
+	acc.Stop()
+	e.Say("Stopping it again is a no-op.")
+	e.Say("Same result, different ownership: the mutex shares the balance and makes goroutines take turns; the actor shares only messages about it.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,79 @@
+// Package actors contains a tiny actor type and an example built on it.
+//
+// An actor owns its state outright: a single goroutine reads and writes
+// it, handling the messages in its mailbox one at a time. Other
+// goroutines never touch the state; they send messages, and get answers
+// back on reply channels carried by the messages themselves.
+package actors
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrStopped is returned by Send and Ask once the actor has stopped.
+var ErrStopped = errors.New("actors: actor stopped")
+
+// Actor is a goroutine handling messages of type M.
+//
+// The mailbox is unbuffered: Send returns once the actor has taken the
+// message, so every message Send accepted is handled, even when Stop is
+// called right after.
+type Actor[M any] struct {
+	mailbox chan M
+	quit    chan struct{} // closed by Stop
+	done    chan struct{} // closed when the goroutine has returned
+	once    sync.Once
+}
+
+// Spawn starts an actor that calls handle for each message, one at a time,
+// until it is stopped. handle typically updates state that only it uses.
+func Spawn[M any](handle func(M)) *Actor[M] {
+	a := &Actor[M]{
+		mailbox: make(chan M),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(a.done)
+		for {
+			select {
+			case m := <-a.mailbox:
+				handle(m)
+			case <-a.quit:
+				return
+			}
+		}
+	}()
+	return a
+}
+
+// Send gives m to the actor, waiting until it is ready to take it. It
+// returns ErrStopped instead if the actor is stopped first.
+func (a *Actor[M]) Send(m M) error {
+	select {
+	case a.mailbox <- m:
+		return nil
+	case <-a.done:
+		return ErrStopped
+	}
+}
+
+// Stop tells the actor to stop and waits until it has: the message it is
+// handling is its last. Later calls return at once.
+func (a *Actor[M]) Stop() {
+	a.once.Do(func() { close(a.quit) })
+	<-a.done
+}
+
+// Ask sends the message made by msg, which must send its answer on
+// reply, and waits for the answer. The reply channel is buffered, so the
+// actor never waits for the asker.
+func Ask[M, R any](a *Actor[M], msg func(reply chan<- R) M) (R, error) {
+	reply := make(chan R, 1)
+	if err := a.Send(msg(reply)); err != nil {
+		var zero R
+		return zero, err
+	}
+	return <-reply, nil
+}
@@ -0,0 +1,11 @@
+package actors
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkAccount(b *testing.B) { benchlab.Bench(b, accountExample{}) }
@@ -0,0 +1,11 @@
+package atomics
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkCounter(b *testing.B) { benchlab.Bench(b, counterExample{}) }
@@ -0,0 +1,103 @@
+package atomics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(casExample{})
+}
+
+// casExample hands out a limited number of tickets to goroutines with a
+// CompareAndSwap loop: an update that Add can't do, because it depends on
+// the current value.
+type casExample struct{}
+
+func (casExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/atomics/cas_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "CompareAndSwap loops: conditional updates without a lock",
+		Tags:          []string{"concurrency", "atomic", "compare-and-swap", "lock-free"},
+		Prerequisites: []string{"concurrency/atomics/counter_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (casExample) Explain(step string) string {
+	switch step {
+	case "tickets":
+		return "Each goroutine loads the count, decides, and stores only if nobody changed the count in the meantime; if somebody did, it tries again."
+	}
+	return ""
+}
+
+// Flashcards are reviewed by "concepts review".
+func (casExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does CompareAndSwap(old, new) do?",
+			Back:  "Atomically: if the value is still old, set it to new and return true; otherwise change nothing and return false.",
+		},
+		{
+			Front: "What is the shape of a CompareAndSwap loop?",
+			Back:  "Load the value, compute the new one from it, CompareAndSwap(loaded, new); if it fails, someone else got there first, so load again and retry.",
+		},
+	}
+}
+
+// tickets is how many tickets there are to hand out.
+const tickets = 500
+
+// take hands out the next ticket from sold unless all have gone. It
+// reports whether it got one, and counts the attempts that had to retry.
+func take(sold, retries *atomic.Int64) bool {
+	for {
+		n := sold.Load()
+		if n == tickets {
+			return false
+		}
+		if sold.CompareAndSwap(n, n+1) {
+			return true
+		}
+		retries.Add(1)
+	}
+}
+
+// Run lets 8 goroutines try to take 100 tickets each.
+func (casExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. 800 attempts at 500 tickets.
+	e.Step("tickets")
+	var sold, retries, got atomic.Int64
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for range 100 {
+				if take(&sold, &retries) {
+					got.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	e.Say("%d goroutines tried to take 100 of %d tickets each.", workers, tickets)
+	e.Value("sold", sold.Load(), "Tickets sold")
+	e.Value("got", got.Load(), "Tickets the goroutines got")
+	e.Varying("retries", retries.Load(), "CompareAndSwap calls that lost and retried")
+	assert.Equal(check, "never more tickets than there are", sold.Load(), int64(tickets))
+	assert.Equal(check, "every sold ticket went to exactly one goroutine", got.Load(), sold.Load())
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,176 @@
+// Package atomics contains examples about the sync/atomic package:
+// counters, compare-and-swap loops and atomically replaced pointers.
+package atomics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(counterExample{})
+}
+
+// counterExample counts with several goroutines three ways: with the
+// read and the write of counter++ as separate steps, with atomic.Int64.Add
+// and with a mutex.
+//
+// counter++ on a plain int is also a data race, which
+// concurrency/races/racy_counter shows under the race detector. Here the
+// read and the write are atomic on their own, so the race detector stays
+// quiet and what is left is the lost update itself.
+type counterExample struct{}
+
+func (counterExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/atomics/counter_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "atomic.Int64 vs a mutex-protected counter vs a load-then-store counter++",
+		Tags:          []string{"concurrency", "atomic", "mutex", "counter"},
+		Prerequisites: []string{"concurrency/races/racy_counter"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (counterExample) Explain(step string) string {
+	switch step {
+	case "split":
+		return "Between a goroutine's Load and its Store, others load the same old value; all but one of their increments are lost."
+	case "atomic":
+		return "Add does the read, the add and the write as one step that nothing can get in between."
+	case "mutex":
+		return "A mutex gets the same result by making the goroutines take turns."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (counterExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "c is an atomic.Int64. Is c.Store(c.Load() + 1) a safe increment?",
+			Choices: []string{"yes, both calls are atomic", "no, another goroutine can store in between"},
+			Answer:  "no, another goroutine can store in between",
+			Explain: "Each call is atomic, but the pair isn't. Use c.Add(1), or a CompareAndSwap loop.",
+		},
+		{
+			Prompt:  "When is a mutex a better choice than an atomic?",
+			Choices: []string{"when several variables must change together", "for a single counter", "never"},
+			Answer:  "when several variables must change together",
+			Explain: "An atomic protects one word. Keeping two values consistent with each other needs a lock (or an atomic pointer to an immutable struct).",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench concurrency".
+func (counterExample) Experiments() []benchlab.Experiment {
+	return []benchlab.Experiment{{
+		Name: "a counter shared by several goroutines",
+		Approaches: []benchlab.Approach{
+			{Name: "atomic.Int64.Add", Bench: func(b *testing.B) {
+				var c atomic.Int64
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						c.Add(1)
+					}
+				})
+			}},
+			{Name: "sync.Mutex", Bench: func(b *testing.B) {
+				var c lockedCounter
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						c.add(1)
+					}
+				})
+			}},
+		},
+		Guidance: "An atomic add is a single CPU instruction; a mutex is at least one " +
+			"atomic to lock and one to unlock, plus parking goroutines when it is " +
+			"contended. For a lone counter or flag, atomics are both simpler and " +
+			"faster. Reach for the mutex when an update touches more than one value.",
+	}}
+}
+
+const (
+	workers   = 8
+	perWorker = 1000
+)
+
+// lockedCounter is a counter protected by a mutex.
+type lockedCounter struct {
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *lockedCounter) add(d int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n += d
+}
+
+// splitIncrement is counter++ spelled out: a read, then a write of what
+// was read plus one. The Gosched stands for whatever makes the scheduler
+// run another goroutine at just the wrong moment.
+func splitIncrement(c *atomic.Int64) {
+	v := c.Load()
+	runtime.Gosched()
+	c.Store(v + 1)
+}
+
+// count starts the workers, each calling inc perWorker times, and waits
+// for all of them.
+func count(inc func()) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for range perWorker {
+				inc()
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// Run counts to workers*perWorker three times.
+func (counterExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	const want = workers * perWorker
+
+	// 1. Load, then Store: increments get lost.
+	e.Step("split")
+	e.Value("want", want, "Each count should reach")
+	var split atomic.Int64
+	count(func() { splitIncrement(&split) })
+	e.Varying("split", split.Load(), "Load then Store")
+	check.That(split.Load() < want, "a separate read and write lose increments")
+	e.Warn("Each Load and each Store was atomic, and increments were still lost: the pair is not.")
+
+	// 2. Add: one indivisible step.
+	e.Step("atomic")
+	var c atomic.Int64
+	count(func() { c.Add(1) })
+	e.Value("c", c.Load(), "atomic.Int64.Add")
+	assert.Equal(check, "atomic Add loses no increment", c.Load(), int64(want))
+
+	// 3. A mutex: also exact.
+	e.Step("mutex")
+	var m lockedCounter
+	count(func() { m.add(1) })
+	e.Value("m.n", m.n, "Mutex-protected counter")
+	assert.Equal(check, "a mutex loses no increment", m.n, int64(want))
+	e.Say("Run \"concepts bench concurrency\" to compare their speed.")
+	return errors.Join(e.Err(), check.Err())
+}
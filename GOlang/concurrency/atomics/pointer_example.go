@@ -0,0 +1,127 @@
+package atomics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(pointerExample{})
+}
+
+// pointerExample publishes configuration through an atomic.Pointer:
+// readers always see a whole configuration, old or new, never half of each.
+type pointerExample struct{}
+
+func (pointerExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/atomics/pointer_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "atomic.Pointer: swapping in a new immutable value while readers keep reading",
+		Tags:          []string{"concurrency", "atomic", "pointers", "copy-on-write"},
+		Prerequisites: []string{"concurrency/atomics/counter_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (pointerExample) Explain(step string) string {
+	switch step {
+	case "publish":
+		return "The writer never changes a config in place. It builds a new one and swaps the pointer, which is one atomic step."
+	case "swap":
+		return "CompareAndSwap replaces the config only if it is still the one we based our change on."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (pointerExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Why must a config published through an atomic.Pointer never be modified afterwards?",
+			Choices: []string{"readers may be using it without any lock", "atomic.Pointer copies it", "it is on the stack"},
+			Answer:  "readers may be using it without any lock",
+			Explain: "The pointer swap is atomic, but the struct itself is not protected: change a copy and publish that instead.",
+		},
+	}
+}
+
+// config is published whole. Version and Name must always agree.
+type config struct {
+	Version int
+	Name    string
+}
+
+func newConfig(v int) *config {
+	return &config{Version: v, Name: fmt.Sprintf("v%d", v)}
+}
+
+// Run publishes 100 configs while readers read, then updates with
+// CompareAndSwap.
+func (pointerExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	var current atomic.Pointer[config]
+	current.Store(newConfig(1))
+
+	// 1. Readers load the current config over and over while a writer
+	// publishes new ones; every config a reader sees must be consistent.
+	e.Step("publish")
+	var torn, reads atomic.Int64
+	stop := make(chan struct{})
+	var wg, started sync.WaitGroup
+	started.Add(4)
+	for range 4 {
+		wg.Go(func() {
+			started.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				c := current.Load()
+				if c.Name != fmt.Sprintf("v%d", c.Version) {
+					torn.Add(1)
+				}
+				reads.Add(1)
+				runtime.Gosched()
+			}
+		})
+	}
+	started.Wait()
+	for v := 2; v <= 100; v++ {
+		current.Store(newConfig(v))
+		runtime.Gosched() // let the readers at it, even on one CPU
+	}
+	close(stop)
+	wg.Wait()
+	e.Value("current", *current.Load(), "Current config")
+	e.Varying("reads", reads.Load(), "Reads while the configs changed")
+	e.Value("torn", torn.Load(), "Reads that saw a half-updated config")
+	check.That(reads.Load() > 0, "the readers read while the configs changed")
+	assert.Equal(check, "readers only ever see whole configs", torn.Load(), int64(0))
+
+	// 2. CompareAndSwap: an update based on a stale config fails.
+	e.Step("swap")
+	old := current.Load()
+	current.Store(newConfig(101)) // somebody else updates first
+	ok := current.CompareAndSwap(old, newConfig(old.Version+1))
+	e.Value("ok", ok, "CompareAndSwap from the config we read earlier")
+	check.That(!ok, "CompareAndSwap fails when the pointer changed since it was loaded")
+	prev := current.Swap(newConfig(102))
+	e.Value("prev", *prev, "Swap returns the config it replaced")
+	assert.Equal(check, "Swap returns the previous config", prev.Version, 101)
+	return errors.Join(e.Err(), check.Err())
+}
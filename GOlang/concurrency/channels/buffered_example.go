@@ -0,0 +1,99 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(bufferedExample{})
+}
+
+// bufferedExample fills a buffered channel and watches len and cap: sends
+// only block once the buffer is full.
+type bufferedExample struct{}
+
+func (bufferedExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/channels/buffered_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "a buffered channel's len and cap, and when its sends block",
+		Tags:          []string{"concurrency", "channels", "buffered"},
+		Prerequisites: []string{"concurrency/channels/unbuffered_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (bufferedExample) Explain(step string) string {
+	switch step {
+	case "fill":
+		return "Each send lands in the buffer, so it completes at once, with nobody receiving. len counts the buffered values."
+	case "full":
+		return "With len == cap there is no room left: the next send would block until a receive makes room."
+	case "drain":
+		return "Receives take values out in the order they went in, first in, first out."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (bufferedExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "ch := make(chan int, 3) holds 2 values. Does ch <- 1 block?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "There is room for one more value, so the send puts it in the buffer and returns.",
+		},
+		{
+			Prompt:  "ch := make(chan int, 3); ch <- 1; ch <- 2. What are len(ch) and cap(ch)?",
+			Choices: []string{"2 and 3", "3 and 3", "2 and 2"},
+			Answer:  "2 and 3",
+			Explain: "len is how many values are waiting in the buffer, cap the size the channel was made with.",
+		},
+	}
+}
+
+// Run fills, overfills and drains a channel with room for 3 values.
+func (bufferedExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Three sends fit in the buffer, no receiver needed.
+	e.Step("fill")
+	ch := make(chan string, 3)
+	e.Value("cap(ch)", cap(ch), "Capacity of ch")
+	for _, s := range []string{"a", "b", "c"} {
+		ch <- s
+		e.Value("len(ch)", len(ch), "len(ch) after sending "+s)
+	}
+	assert.Equal(check, "three sends fill the buffer", len(ch), cap(ch))
+
+	// 2. A fourth send finds no room; select's default tells us without
+	// blocking.
+	e.Step("full")
+	sent := false
+	select {
+	case ch <- "d":
+		sent = true
+	default:
+	}
+	e.Value("sent", sent, "Fourth value sent")
+	check.That(!sent, "a send on a full buffered channel can't complete")
+
+	// 3. Receiving frees room again, in first-in first-out order.
+	e.Step("drain")
+	for len(ch) > 0 {
+		s := <-ch
+		e.Value("s", s, "Received")
+	}
+	e.Value("len(ch)", len(ch), "len(ch) after draining")
+	return errors.Join(e.Err(), check.Err())
+}
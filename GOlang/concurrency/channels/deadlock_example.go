@@ -0,0 +1,105 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(deadlockExample{})
+}
+
+// deadlockExample sends on a channel nobody will ever receive from.
+//
+// In a program where that send is all that is left to run, the runtime
+// notices that every goroutine is blocked and stops the program with
+//
+//	fatal error: all goroutines are asleep - deadlock!
+//
+// That can't be recovered from, and inside the concepts tool other
+// goroutines are still around, so the runtime wouldn't even notice. The
+// example does what a careful program does instead: it gives up on the
+// send after a timeout, and explains what would have happened.
+// concurrency/deadlocks/send_example runs the real thing in a process of
+// its own.
+type deadlockExample struct{}
+
+func (deadlockExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/channels/deadlock_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "a send with no receiver: the deadlock, caught with a timeout and explained",
+		Tags:          []string{"concurrency", "channels", "deadlock", "timeout"},
+		Prerequisites: []string{"concurrency/channels/unbuffered_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (deadlockExample) Explain(step string) string {
+	switch step {
+	case "send":
+		return "Nobody will ever receive from ch, so the plain send ch <- 1 would wait forever."
+	case "fix":
+		return "The fix is to make sure a receiver exists: here, a goroutine started before the send."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (deadlockExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "main does ch := make(chan int); ch <- 1 and nothing else. What happens?",
+			Choices: []string{"the value is dropped", "the program blocks forever, silently", "fatal error: all goroutines are asleep - deadlock!"},
+			Answer:  "fatal error: all goroutines are asleep - deadlock!",
+			Explain: "main is the only goroutine and it is blocked, so the runtime detects the deadlock and stops the program.",
+		},
+		{
+			Prompt:  "Does ch := make(chan int, 1); ch <- 1 deadlock?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "The buffer has room for the value, so the send completes without a receiver. A second send would deadlock.",
+		},
+	}
+}
+
+// timeout is how long the example waits for a receiver before it calls
+// the send a deadlock.
+const timeout = 50 * time.Millisecond
+
+// Run attempts the send that deadlocks, then the fixed version.
+func (deadlockExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The send that would deadlock, bounded by a timeout.
+	e.Step("send")
+	ch := make(chan int)
+	deadlocked := false
+	select {
+	case ch <- 1:
+	case <-time.After(timeout):
+		deadlocked = true
+	}
+	e.Value("deadlocked", deadlocked, "Send still blocked after 50ms")
+	e.Warn("With nothing else left to run, the runtime would stop here with \"fatal error: all goroutines are asleep - deadlock!\".")
+	check.That(deadlocked, "a send with no receiver never completes")
+
+	// 2. The fix: start the receiver first.
+	e.Step("fix")
+	got := make(chan int)
+	go func() { got <- <-ch }()
+	ch <- 1
+	v := <-got
+	e.Value("v", v, "Received by the goroutine")
+	assert.Equal(check, "with a receiver, the send completes", v, 1)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,173 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(directionsExample{})
+}
+
+// directionsExample narrows channels to send-only (chan<- T) and
+// receive-only (<-chan T), has the type checker reject what each forbids,
+// and builds a small producer/consumer API on top of them.
+type directionsExample struct{}
+
+func (directionsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/channels/directions_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "send-only and receive-only channels, enforced by the compiler",
+		Tags:          []string{"concurrency", "channels", "types", "api-design"},
+		Prerequisites: []string{"concurrency/channels/buffered_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (directionsExample) Explain(step string) string {
+	switch step {
+	case "convert":
+		return "A bidirectional channel converts to either direction by itself; the two views share the same channel."
+	case "compiler":
+		return "These snippets are type-checked right now, the way go build would; what a direction forbids doesn't compile."
+	case "api":
+		return "produce returns <-chan T, so its callers can only receive; only produce can send on or close the channel."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (directionsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "func sink(in <-chan int) { close(in) } — does this compile?",
+			Choices: []string{"yes", "no, a receive-only channel can't be closed"},
+			Answer:  "no, a receive-only channel can't be closed",
+			Explain: "Closing is the sender's job, so only a bidirectional or send-only channel can be closed.",
+		},
+		{
+			Prompt:  "Can a <-chan int be converted back to a chan int?",
+			Choices: []string{"yes, with chan int(in)", "no"},
+			Answer:  "no",
+			Explain: "Directions only ever narrow. That is what lets an API hand out a receive-only channel without fearing sends.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (directionsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Which way does the arrow point in chan<- T and <-chan T?",
+			Back:  "Into chan for send-only (values go into it: chan<- T), out of chan for receive-only (values come out: <-chan T).",
+		},
+		{
+			Front: "Why should a function that starts a producer goroutine return <-chan T rather than chan T?",
+			Back:  "So callers can't send on the channel or close it. The producer stays the only sender and the only one who closes it.",
+		},
+	}
+}
+
+// produce sends items on the channel it returns, from its own goroutine,
+// and closes it when they have all been sent.
+func produce[T any](items ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range items {
+			out <- v
+		}
+	}()
+	return out
+}
+
+// consume calls each for every value received from in until in is
+// closed, then reports how many there were on done.
+func consume[T any](in <-chan T, each func(T), done chan<- int) {
+	n := 0
+	for v := range in {
+		each(v)
+		n++
+	}
+	done <- n
+}
+
+// typeCheck type-checks body as the body of a function and returns the
+// first error, without its position.
+func typeCheck(body string) error {
+	src := "package p\n\nfunc f() {\n" + body + "\n}\n"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		return err
+	}
+	var first error
+	conf := types.Config{Error: func(err error) {
+		if first == nil {
+			first = errors.New(err.(types.Error).Msg)
+		}
+	}}
+	conf.Check("p", fset, []*ast.File{f}, nil)
+	return first
+}
+
+// Run narrows a channel, shows what the compiler rejects, and runs the
+// producer/consumer API.
+func (directionsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. One channel, three views of it.
+	e.Step("convert")
+	ch := make(chan int, 1)
+	var send chan<- int = ch
+	var recv <-chan int = ch
+	e.Value("send", fmt.Sprintf("%T", send), "Type of send")
+	e.Value("recv", fmt.Sprintf("%T", recv), "Type of recv")
+	send <- 42
+	v := <-recv
+	e.Value("v", v, "Sent through send, received through recv")
+	assert.Equal(check, "both views use the same channel", v, 42)
+
+	// 2. What each direction forbids, according to the type checker.
+	e.Step("compiler")
+	for _, c := range []struct{ code, want string }{
+		{"var in <-chan int\nin <- 1", "cannot send to receive-only channel"},
+		{"var out chan<- int\n<-out", "cannot receive from send-only channel"},
+		{"var in <-chan int\nclose(in)", "cannot close receive-only channel"},
+		{"var in <-chan int\nvar c chan int = in\n_ = c", "cannot use in"},
+	} {
+		err := typeCheck(c.code)
+		e.Say("%s", strings.ReplaceAll(c.code, "\n", "; "))
+		e.Value("err", err, "  compile error")
+		check.That(err != nil && strings.Contains(err.Error(), c.want), "the compiler rejects: "+c.want)
+	}
+
+	// 3. The API: produce can only be received from, consume only
+	// reports through done.
+	e.Step("api")
+	var got []string
+	done := make(chan int)
+	go consume(produce("a", "b", "c"), func(s string) { got = append(got, s) }, done)
+	n := <-done
+	e.Value("got", got, "consume received")
+	e.Value("n", n, "consume reported")
+	assert.Equal(check, "consume receives everything produce sent", fmt.Sprint(got), "[a b c]")
+	assert.Equal(check, "consume counts what it received", n, 3)
+	return errors.Join(e.Err(), check.Err())
+}
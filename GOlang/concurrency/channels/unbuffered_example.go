@@ -0,0 +1,124 @@
+// Package channels contains examples about channels: how unbuffered and
+// buffered channels differ, and what happens when a send has nobody to
+// receive it.
+package channels
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(unbufferedExample{})
+}
+
+// unbufferedExample shows that a send on an unbuffered channel waits until
+// another goroutine receives: the two goroutines meet at the channel.
+type unbufferedExample struct{}
+
+func (unbufferedExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/channels/unbuffered_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "a send on an unbuffered channel blocks until someone receives",
+		Tags:          []string{"concurrency", "channels", "unbuffered", "blocking"},
+		Prerequisites: []string{"concurrency/goroutines/launch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (unbufferedExample) Explain(step string) string {
+	switch step {
+	case "try-send":
+		return "A select with a default case tries the send without waiting. Nobody is receiving, so it can't happen."
+	case "blocked-send":
+		return "A goroutine sends for real. Without a receiver it is stuck on that line, however long we wait."
+	case "receive":
+		return "The receive and the blocked send happen together: the value is handed over and both goroutines carry on."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (unbufferedExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "ch := make(chan int). When does ch <- 1 complete?",
+			Choices: []string{"immediately", "when another goroutine receives from ch", "when ch is closed"},
+			Answer:  "when another goroutine receives from ch",
+			Explain: "An unbuffered channel has no room to store a value, so the sender waits until a receiver takes it.",
+		},
+		{
+			Prompt:  "What does len(ch) return for an unbuffered channel?",
+			Answer:  "0",
+			Explain: "An unbuffered channel never holds values; both len and cap are 0.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (unbufferedExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does a send on an unbuffered channel wait for?",
+			Back:  "For a receiver. The send and the receive happen at the same moment, which also synchronizes the two goroutines.",
+		},
+		{
+			Front: "How do you try a channel operation without blocking?",
+			Back:  "Put it in a select with a default case: default runs if the operation can't proceed right now.",
+		},
+	}
+}
+
+// patience is how long the example watches a blocked send to show that it
+// stays blocked.
+const patience = 20 * time.Millisecond
+
+// Run sends on an unbuffered channel without, and then with, a receiver.
+func (unbufferedExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. A non-blocking send: select picks default because no goroutine
+	// is waiting to receive.
+	e.Step("try-send")
+	ch := make(chan int) // no buffer: cap(ch) == 0
+	e.Value("cap(ch)", cap(ch), "Capacity of ch")
+	sent := false
+	select {
+	case ch <- 1:
+		sent = true
+	default:
+	}
+	e.Value("sent", sent, "Sent without a receiver")
+	check.That(!sent, "an unbuffered send can't complete without a receiver")
+
+	// 2. A real send from another goroutine. It blocks on the send, so
+	// done stays false for as long as we care to look.
+	e.Step("blocked-send")
+	var done atomic.Bool
+	go func() {
+		ch <- 42 // blocks here until the receive below
+		done.Store(true)
+	}()
+	time.Sleep(patience)
+	e.Value("done", done.Load(), "Send finished after waiting 20ms")
+	check.That(!done.Load(), "the send waits for a receiver")
+
+	// 3. Receive: the sender is released at the same moment.
+	e.Step("receive")
+	v := <-ch
+	e.Value("v", v, "Received")
+	assert.Equal(check, "the receive gets the sent value", v, 42)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,98 @@
+package chanpatterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(bridgeExample{})
+}
+
+// bridgeExample reads a paginated result: a channel delivering one
+// channel per page, flattened into one stream of items.
+type bridgeExample struct{}
+
+func (bridgeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/chanpatterns/bridge_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "bridge: a channel of channels read as one channel",
+		Tags:          []string{"concurrency", "channels", "context", "generics"},
+		Prerequisites: []string{"concurrency/chanpatterns/ordone_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (bridgeExample) Explain(step string) string {
+	switch step {
+	case "flatten":
+		return "Bridge reads each inner channel to the end before taking the next, so the items stay in order."
+	case "cancel":
+		return "Cancelling stops Bridge in the middle of a page, and the producer of pages with it."
+	}
+	return ""
+}
+
+// pages sends a channel for each of n pages of 3 items, numbered from 1.
+func pages(ctx context.Context, n int) <-chan (<-chan int) {
+	out := make(chan (<-chan int))
+	go func() {
+		defer close(out)
+		for p := range n {
+			page := make(chan int, 3)
+			for i := range 3 {
+				page <- p*3 + i + 1
+			}
+			close(page)
+			select {
+			case out <- page:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Run flattens 3 pages, then stops partway through 1000.
+func (bridgeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	before := leakcheck.Take()
+
+	// 1. Three pages of three items become one stream of nine.
+	e.Step("flatten")
+	var items []int
+	for v := range Bridge(ctx, pages(ctx, 3)) {
+		items = append(items, v)
+	}
+	e.Value("items", items, "Items from 3 pages")
+	assert.Equal(check, "Bridge delivers every page's items in order", fmt.Sprint(items), "[1 2 3 4 5 6 7 8 9]")
+
+	// 2. Stop after 4 items, in the middle of the second page.
+	e.Step("cancel")
+	cctx, cancel := context.WithCancel(ctx)
+	items = nil
+	for v := range Bridge(cctx, pages(cctx, 1000)) {
+		items = append(items, v)
+		if len(items) == 4 {
+			cancel()
+		}
+	}
+	cancel()
+	e.Value("items", items[:4], "First items of 1000 pages")
+	check.That(len(items) <= 5, "Bridge stops right after the cancel")
+	n := leftover(before)
+	e.Value("leftover", n, "Goroutines left behind")
+	assert.Equal(check, "cancelling ends Bridge's goroutines and the pages producer", n, 0)
+	return errors.Join(e.Err(), check.Err())
+}
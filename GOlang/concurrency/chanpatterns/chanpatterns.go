@@ -0,0 +1,177 @@
+// Package chanpatterns is a handful of generic helpers for composing
+// channels: OrDone, Tee, Bridge, Merge and First. Every helper takes a
+// context and stops its goroutines when the context is done, so giving
+// up on a result never leaks anything. Each has an example next to it.
+package chanpatterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+)
+
+// OrDone returns a channel that gets everything in receives until in is
+// closed or ctx is done, whichever comes first, and is then closed. It
+// lets a range loop over in stop on cancellation.
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Tee returns two channels that each get everything in receives, in
+// order. A value goes to both before the next is read, so the slower
+// reader sets the pace.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1, out2 := make(chan T), make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for v := range OrDone(ctx, in) {
+			// Send to both in whichever order they are ready; a nil
+			// channel is never ready, which takes the sent one out.
+			a, b := out1, out2
+			for range 2 {
+				select {
+				case a <- v:
+					a = nil
+				case b <- v:
+					b = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// Bridge flattens a channel of channels: it returns a channel that gets
+// everything from each channel chans delivers, one channel after the
+// other, until chans is closed or ctx is done.
+func Bridge[T any](ctx context.Context, chans <-chan (<-chan T)) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for ch := range OrDone(ctx, chans) {
+			for v := range OrDone(ctx, ch) {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Merge returns a channel that gets everything from all of ins, in
+// whatever order it arrives, and is closed once all of them are (or ctx
+// is done).
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, in := range ins {
+		wg.Go(func() {
+			for v := range OrDone(ctx, in) {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// First calls every one of fns at once and returns the first successful
+// result, cancelling the context of the others. If all of them fail, it
+// returns all their errors joined. fns must return soon after their
+// context is cancelled.
+func First[T any](ctx context.Context, fns ...func(context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		v   T
+		err error
+	}
+	// Buffered, so that the losers can send their results after First has
+	// returned and simply end.
+	results := make(chan result, len(fns))
+	for _, fn := range fns {
+		go func() {
+			v, err := fn(ctx)
+			results <- result{v, err}
+		}()
+	}
+	var zero T
+	var errs []error
+	for range fns {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				return r.v, nil
+			}
+			errs = append(errs, r.err)
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+	return zero, errors.Join(errs...)
+}
+
+// leftover returns how many goroutines of this package started since
+// before are still running after a moment's grace.
+func leftover(before leakcheck.Snapshot) int {
+	n := 0
+	for _, g := range before.Leaked(time.Second) {
+		if strings.Contains(g.Func, "/chanpatterns.") {
+			n++
+		}
+	}
+	return n
+}
+
+// count sends 1, 2, 3, ... on the returned channel, n numbers in all or
+// until ctx is done, and closes it.
+func count(ctx context.Context, n int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 1; i <= n; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,174 @@
+package chanpatterns
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+)
+
+// forever returns a channel that sends 1 until ctx is done, and never
+// closes before then.
+func forever(ctx context.Context) <-chan int {
+	out := make(chan int)
+	go func() {
+		for {
+			select {
+			case out <- 1:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// noLeftovers fails t if goroutines of this package outlive the test.
+func noLeftovers(t *testing.T) {
+	t.Helper()
+	before := leakcheck.Take()
+	t.Cleanup(func() {
+		if n := leftover(before); n > 0 {
+			t.Errorf("%d goroutines left running", n)
+		}
+	})
+}
+
+func TestOrDone(t *testing.T) {
+	noLeftovers(t)
+	got := slices.Collect(chanSeq(OrDone(context.Background(), count(context.Background(), 5))))
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("OrDone passed on %v, want 1 to 5", got)
+	}
+}
+
+func TestOrDoneCancelled(t *testing.T) {
+	noLeftovers(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := forever(ctx)
+	n := 0
+	for range OrDone(ctx, in) {
+		if n++; n == 3 {
+			cancel()
+		}
+	}
+	if n < 3 {
+		t.Errorf("%d values before the channel closed, want at least 3", n)
+	}
+}
+
+func TestTee(t *testing.T) {
+	noLeftovers(t)
+	a, b := Tee(context.Background(), count(context.Background(), 4))
+	var gotA, gotB []int
+	var wg sync.WaitGroup
+	wg.Go(func() { gotA = slices.Collect(chanSeq(a)) })
+	wg.Go(func() { gotB = slices.Collect(chanSeq(b)) })
+	wg.Wait()
+	want := []int{1, 2, 3, 4}
+	if !slices.Equal(gotA, want) || !slices.Equal(gotB, want) {
+		t.Errorf("Tee gave %v and %v, want %v twice", gotA, gotB, want)
+	}
+}
+
+func TestTeeCancelledWithAStalledReader(t *testing.T) {
+	noLeftovers(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	a, b := Tee(ctx, forever(ctx))
+	<-a // b is never read, so the next value can't go out
+	cancel()
+	for range a {
+	}
+	for range b {
+	}
+}
+
+func TestBridge(t *testing.T) {
+	noLeftovers(t)
+	chans := make(chan (<-chan int))
+	go func() {
+		defer close(chans)
+		for range 3 {
+			chans <- count(context.Background(), 2)
+		}
+	}()
+	got := slices.Collect(chanSeq(Bridge(context.Background(), chans)))
+	if !slices.Equal(got, []int{1, 2, 1, 2, 1, 2}) {
+		t.Errorf("Bridge gave %v, want each channel's values in turn", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	noLeftovers(t)
+	ctx := context.Background()
+	got := slices.Sorted(chanSeq(Merge(ctx, count(ctx, 3), count(ctx, 2), count(ctx, 1))))
+	if !slices.Equal(got, []int{1, 1, 1, 2, 2, 3}) {
+		t.Errorf("Merge gave %v, want every value of the three", got)
+	}
+}
+
+func TestMergeCancelled(t *testing.T) {
+	noLeftovers(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Merge(ctx, forever(ctx), forever(ctx))
+	<-out
+	cancel()
+	for range out {
+	}
+}
+
+func TestFirst(t *testing.T) {
+	noLeftovers(t)
+	cancelled := make(chan bool, 1)
+	slow := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		cancelled <- true
+		return "", ctx.Err()
+	}
+	fast := func(context.Context) (string, error) { return "fast", nil }
+	v, err := First(context.Background(), slow, fast)
+	if v != "fast" || err != nil {
+		t.Fatalf("First = %q, %v; want the fast result", v, err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("the slow call's context wasn't cancelled")
+	}
+}
+
+func TestFirstAllFail(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	_, err := First(context.Background(),
+		func(context.Context) (int, error) { return 0, errA },
+		func(context.Context) (int, error) { return 0, errB })
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("First = %v, want both errors", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = First(ctx, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, errA
+	})
+	if !errors.Is(err, context.Canceled) && !errors.Is(err, errA) {
+		t.Errorf("First with a cancelled context = %v", err)
+	}
+}
+
+// chanSeq ranges over ch until it is closed.
+func chanSeq[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package chanpatterns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(firstExample{})
+}
+
+// firstExample sends the same query to three replicas and uses whichever
+// answers first, cancelling the rest.
+type firstExample struct{}
+
+func (firstExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/chanpatterns/first_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "first response wins: query replicas at once, cancel the slower ones",
+		Tags:          []string{"concurrency", "channels", "context", "generics"},
+		Prerequisites: []string{"concurrency/contextdemo/cancel_example", "concurrency/leaks/leak_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (firstExample) Explain(step string) string {
+	switch step {
+	case "race":
+		return "Every replica gets the query at once; the first answer is returned and the others are told to stop through their context."
+	case "failures":
+		return "A replica that fails doesn't win: First waits for a success, and reports every error only if there is none."
+	case "timeout":
+		return "The caller's context still rules: if it is done first, First gives up on all of them."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (firstExample) Questions() []quiz.Question {
+	return []quiz.Question{{
+		Prompt:  "Why is First's results channel buffered with room for every replica?",
+		Choices: []string{"so that slower replicas can send after First has returned, and end", "to make First faster"},
+		Answer:  "so that slower replicas can send after First has returned, and end",
+		Explain: "Nobody receives once First has returned. With an unbuffered channel, every losing replica would block on its send for ever: the leak from concurrency/leaks/leak_example.",
+	}}
+}
+
+var errReplicaDown = errors.New("replica down")
+
+// replica answers with answer after delay, or fails at once if it is down.
+// It counts how often it was cancelled before answering.
+func replica(answer string, delay time.Duration, down bool, cancelled *atomic.Int32) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		if down {
+			return "", errReplicaDown
+		}
+		select {
+		case <-time.After(delay):
+			return answer, nil
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return "", ctx.Err()
+		}
+	}
+}
+
+// Run asks three replicas three times.
+func (firstExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	before := leakcheck.Take()
+
+	// 1. One fast replica, one that never answers in time, one that is
+	// down.
+	e.Step("race")
+	var cancelled atomic.Int32
+	got, err := First(ctx,
+		replica("slow", time.Hour, false, &cancelled),
+		replica("fast", 10*time.Millisecond, false, &cancelled),
+		replica("broken", 0, true, &cancelled),
+	)
+	if err != nil {
+		return err
+	}
+	e.Value("got", got, "First answer")
+	assert.Equal(check, "the fastest successful replica wins", got, "fast")
+	n := leftover(before)
+	e.Value("cancelled", cancelled.Load(), "Replicas cancelled")
+	e.Value("leftover", n, "Goroutines left behind")
+	assert.Equal(check, "the slow replica is cancelled", cancelled.Load(), int32(1))
+	assert.Equal(check, "no losing replica is left running", n, 0)
+
+	// 2. Every replica is down.
+	e.Step("failures")
+	_, err = First(ctx,
+		replica("a", 0, true, &cancelled),
+		replica("b", 0, true, &cancelled),
+	)
+	e.Value("err", err, "First, with every replica down")
+	check.That(errors.Is(err, errReplicaDown), "First fails when every replica does")
+
+	// 3. The caller's deadline comes before any answer.
+	e.Step("timeout")
+	tctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = First(tctx,
+		replica("a", time.Hour, false, &cancelled),
+		replica("b", time.Hour, false, &cancelled),
+	)
+	e.Value("err", err, "First, with a 20ms deadline")
+	check.That(errors.Is(err, context.DeadlineExceeded), "the caller's deadline cancels every replica")
+	n = leftover(before)
+	assert.Equal(check, "nothing is left running after the deadline", n, 0)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,93 @@
+package chanpatterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(mergeExample{})
+}
+
+// mergeExample merges three streams into one, then merges three endless
+// streams and cancels them.
+type mergeExample struct{}
+
+func (mergeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/chanpatterns/merge_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "merge: many channels combined into one, closed when all of them are",
+		Tags:          []string{"concurrency", "channels", "fan-in", "context", "generics"},
+		Prerequisites: []string{"concurrency/chanpatterns/ordone_example", "concurrency/patterns/fan_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (mergeExample) Explain(step string) string {
+	switch step {
+	case "merge":
+		return "One goroutine per input forwards to the same output; a last goroutine closes the output once they have all finished."
+	case "cancel":
+		return "Cancelling ends every forwarding goroutine, and so closes the output."
+	}
+	return ""
+}
+
+// scaled is count's numbers times factor.
+func scaled(ctx context.Context, n, factor int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for v := range OrDone(ctx, count(ctx, n)) {
+			select {
+			case out <- v * factor:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Run merges 1-3, 10-30 and 100-300, then three endless streams.
+func (mergeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	before := leakcheck.Take()
+
+	// 1. Three streams of three.
+	e.Step("merge")
+	var got []int
+	for v := range Merge(ctx, scaled(ctx, 3, 1), scaled(ctx, 3, 10), scaled(ctx, 3, 100)) {
+		got = append(got, v)
+	}
+	e.Varying("got", got, "Merged, in arrival order")
+	slices.Sort(got)
+	e.Value("sorted", got, "Merged, sorted")
+	assert.Equal(check, "Merge delivers every value of every input", fmt.Sprint(got), "[1 2 3 10 20 30 100 200 300]")
+
+	// 2. Three endless streams, cancelled after 10 values.
+	e.Step("cancel")
+	cctx, cancel := context.WithCancel(ctx)
+	n := 0
+	for range Merge(cctx, scaled(cctx, 1e9, 1), scaled(cctx, 1e9, 10), scaled(cctx, 1e9, 100)) {
+		if n++; n == 10 {
+			cancel()
+		}
+	}
+	cancel()
+	left := leftover(before)
+	e.Value("leftover", left, "Goroutines left behind")
+	assert.Equal(check, "cancelling closes the merged channel and ends every goroutine", left, 0)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,85 @@
+package chanpatterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(orDoneExample{})
+}
+
+// orDoneExample ranges over a stream that would go on for a very long
+// time, and stops early by cancelling a context.
+type orDoneExample struct{}
+
+func (orDoneExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/chanpatterns/ordone_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "or-done: a range loop over a channel that stops when a context is cancelled",
+		Tags:          []string{"concurrency", "channels", "context", "generics"},
+		Prerequisites: []string{"concurrency/contextdemo/cancel_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (orDoneExample) Explain(step string) string {
+	switch step {
+	case "range":
+		return "A range loop over a channel only ends when the channel is closed. OrDone closes its channel when the context is done, too."
+	case "leaks":
+		return "Cancelling also ends OrDone's goroutine, even while it is waiting to send."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (orDoneExample) Questions() []quiz.Question {
+	return []quiz.Question{{
+		Prompt:  "Why does OrDone check ctx.Done() both while receiving and while sending?",
+		Choices: []string{"either one can wait for ever", "the second check is only for speed"},
+		Answer:  "either one can wait for ever",
+		Explain: "The input may never send again, and the reader may never receive again; each wait needs a way out.",
+	}}
+}
+
+// Run reads three numbers from a practically endless stream.
+func (orDoneExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Range over OrDone and cancel after three values.
+	e.Step("range")
+	before := leakcheck.Take()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var got []int
+	for v := range OrDone(ctx, count(ctx, 1_000_000_000)) {
+		got = append(got, v)
+		if len(got) == 3 {
+			cancel()
+		}
+	}
+	e.Value("got", got[:3], "Received before the cancel")
+	// OrDone may already have received the next value when cancel is
+	// called, and its select may still pick sending it over returning.
+	check.That(fmt.Sprint(got[:3]) == "[1 2 3]" && len(got) <= 4, "the loop ends right after the cancel")
+	e.Say("The loop ended because cancel() closed OrDone's channel; the stream itself never ran out.")
+
+	// 2. Everything started is gone.
+	e.Step("leaks")
+	n := leftover(before)
+	e.Value("leftover", n, "Goroutines left behind")
+	assert.Equal(check, "cancelling ends OrDone's goroutine and the stream's", n, 0)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,92 @@
+package chanpatterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(teeExample{})
+}
+
+// teeExample splits one stream into two, like the Unix tee command: one
+// reader sums the numbers while the other keeps them.
+type teeExample struct{}
+
+func (teeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/chanpatterns/tee_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "tee: every value of one channel delivered to two readers",
+		Tags:          []string{"concurrency", "channels", "context", "generics"},
+		Prerequisites: []string{"concurrency/chanpatterns/ordone_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (teeExample) Explain(step string) string {
+	switch step {
+	case "split":
+		return "A value received from a channel is gone for everybody else; to give it to two readers, Tee sends it twice."
+	case "cancel":
+		return "Cancelling closes both outputs, so both readers' loops end."
+	}
+	return ""
+}
+
+// Run tees five numbers, then tees an endless stream and cancels it.
+func (teeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	before := leakcheck.Take()
+
+	// 1. Both readers see every value.
+	e.Step("split")
+	a, b := Tee(ctx, count(ctx, 5))
+	var wg sync.WaitGroup
+	sum := 0
+	wg.Go(func() {
+		for v := range a {
+			sum += v
+		}
+	})
+	var kept []int
+	for v := range b {
+		kept = append(kept, v)
+	}
+	wg.Wait()
+	e.Value("sum", sum, "First reader: the sum")
+	e.Value("kept", kept, "Second reader: every value")
+	assert.Equal(check, "the first reader gets every value", sum, 15)
+	assert.Equal(check, "the second reader gets every value, in order", fmt.Sprint(kept), "[1 2 3 4 5]")
+
+	// 2. Cancel while both readers are still reading.
+	e.Step("cancel")
+	cctx, cancel := context.WithCancel(ctx)
+	a, b = Tee(cctx, count(cctx, 1_000_000_000))
+	seen := 0
+	wg.Go(func() {
+		for range a {
+		}
+	})
+	for range b {
+		if seen++; seen == 10 {
+			cancel()
+		}
+	}
+	wg.Wait()
+	cancel()
+	n := leftover(before)
+	e.Value("leftover", n, "Goroutines left behind")
+	assert.Equal(check, "cancelling closes both outputs and ends Tee's goroutines", n, 0)
+	return errors.Join(e.Err(), check.Err())
+}
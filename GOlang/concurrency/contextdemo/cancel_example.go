@@ -0,0 +1,113 @@
+package contextdemo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(cancelExample{})
+}
+
+// cancelExample starts workers under a context made by WithCancel, lets
+// them work for a while, cancels, and checks that they all stop.
+type cancelExample struct{}
+
+func (cancelExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/contextdemo/cancel_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "context.WithCancel: stopping goroutines by hand",
+		Tags:          []string{"concurrency", "context", "cancellation"},
+		Prerequisites: []string{"concurrency/patterns/pipeline_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (cancelExample) Explain(step string) string {
+	switch step {
+	case "work":
+		return "Each worker selects on ctx.Done() next to its work, so it can notice cancellation between two units of work."
+	case "cancel":
+		return "cancel closes the context's Done channel. Every goroutine receiving from it wakes up at once."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (cancelExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "What does ctx.Err() return after cancel() was called on a WithCancel context?",
+			Choices: []string{"nil", "context.Canceled", "context.DeadlineExceeded"},
+			Answer:  "context.Canceled",
+			Explain: "Err is nil while the context is live, and says why it ended afterwards.",
+		},
+		{
+			Prompt:  "cancel() returns. Have the goroutines using the context stopped?",
+			Choices: []string{"yes", "not necessarily"},
+			Answer:  "not necessarily",
+			Explain: "cancel only closes Done; each goroutine stops when it next checks. Wait for them (e.g. with a WaitGroup) if you need them gone.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (cancelExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Why does go vet complain when the cancel func from WithCancel is discarded?",
+			Back:  "The context's resources are only released when it is cancelled (or its parent is), so it leaks. Call cancel, usually with defer, even when the work finishes normally.",
+		},
+	}
+}
+
+// Run starts three workers and cancels them.
+func (cancelExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Start the workers and let them run.
+	e.Step("work")
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workers := []*worker{start(cctx), start(cctx), start(cctx)}
+	for _, wk := range workers {
+		if !wk.started(time.Second) {
+			return errors.New("a worker never started working")
+		}
+	}
+	e.Say("Started %d workers under a WithCancel context.", len(workers))
+	e.Value("ctx.Err()", cctx.Err(), "Before cancel, ctx.Err()")
+	assert.Equal(check, "a live context has no error", cctx.Err(), nil)
+
+	// 2. Cancel: every worker returns, and none does any more work.
+	e.Step("cancel")
+	cancel()
+	var atCancel []int64
+	for _, wk := range workers {
+		atCancel = append(atCancel, wk.steps.Load())
+	}
+	e.Value("ctx.Err()", cctx.Err(), "After cancel, ctx.Err()")
+	check.That(errors.Is(cctx.Err(), context.Canceled), "a cancelled context reports context.Canceled")
+	for i, wk := range workers {
+		if !wk.stopped(time.Second) {
+			return errors.New("a worker didn't stop after cancel")
+		}
+		e.Varying("steps", wk.steps.Load(), fmt.Sprintf("Units of work done by worker %d", i+1))
+		check.That(wk.steps.Load() > 0, "each worker did some work before cancel")
+		check.That(wk.stoppedSince(atCancel[i]), "no new work starts after cancel")
+	}
+	e.Say("All workers returned, and none did any more work afterwards.")
+	return errors.Join(e.Err(), check.Err())
+}
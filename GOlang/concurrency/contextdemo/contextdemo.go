@@ -0,0 +1,74 @@
+// Package contextdemo contains examples about stopping goroutines with a
+// context.Context: cancelling by hand, timeouts and deadlines, and how
+// cancellation flows from a context to the contexts derived from it.
+package contextdemo
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// worker is a goroutine that does a unit of work every millisecond until
+// its context is done.
+type worker struct {
+	steps   atomic.Int64
+	working chan struct{} // closed after the first unit of work
+	done    chan struct{}
+}
+
+// start runs a worker under ctx.
+func start(ctx context.Context) *worker {
+	w := &worker{working: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		tick := time.NewTicker(time.Millisecond)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				// When Done and the ticker are both ready, select picks
+				// one at random, so look at the context again before
+				// starting the work.
+				if ctx.Err() != nil {
+					return
+				}
+				if w.steps.Add(1) == 1 {
+					close(w.working)
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// started reports whether the worker does its first unit of work within d.
+func (w *worker) started(d time.Duration) bool {
+	select {
+	case <-w.working:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// stopped reports whether the worker returns within d.
+func (w *worker) stopped(d time.Duration) bool {
+	select {
+	case <-w.done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// stoppedSince reports whether the worker did at most one unit of work
+// after its count was at, taken when its context was cancelled: the one
+// it may have been in the middle of. That is what "it stopped" has to mean
+// to whoever is waiting for its results. Call it once stopped returned
+// true.
+func (w *worker) stoppedSince(at int64) bool {
+	return w.steps.Load() <= at+1
+}
@@ -0,0 +1,106 @@
+package contextdemo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(timeoutExample{})
+}
+
+// timeoutExample lets contexts cancel themselves: one after a duration
+// (WithTimeout) and one at a point in time (WithDeadline).
+type timeoutExample struct{}
+
+func (timeoutExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/contextdemo/timeout_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "context.WithTimeout and WithDeadline: contexts that cancel themselves",
+		Tags:          []string{"concurrency", "context", "timeout", "deadline"},
+		Prerequisites: []string{"concurrency/contextdemo/cancel_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (timeoutExample) Explain(step string) string {
+	switch step {
+	case "timeout":
+		return "Nobody calls cancel here: the context's own timer does it once the timeout has passed."
+	case "deadline":
+		return "WithTimeout(ctx, d) is WithDeadline(ctx, time.Now().Add(d)); a deadline is handy when several steps share one budget."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (timeoutExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "What does ctx.Err() return once a WithTimeout context's time is up?",
+			Choices: []string{"context.Canceled", "context.DeadlineExceeded", "nil"},
+			Answer:  "context.DeadlineExceeded",
+			Explain: "Canceled means somebody called cancel; DeadlineExceeded means the clock ran out.",
+		},
+		{
+			Prompt:  "A parent context ends in 1s. What is the deadline of WithTimeout(parent, time.Minute)?",
+			Choices: []string{"1 minute from now", "1 second from now"},
+			Answer:  "1 second from now",
+			Explain: "A child can't outlive its parent: the earlier deadline wins.",
+		},
+	}
+}
+
+// timeout is how long the contexts in this example live.
+const timeout = 30 * time.Millisecond
+
+// Run waits for a timeout and for a deadline.
+func (timeoutExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. WithTimeout: the worker stops by itself after the timeout.
+	e.Step("timeout")
+	began := time.Now()
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	wk := start(tctx)
+	<-tctx.Done()
+	atTimeout := wk.steps.Load()
+	if !wk.stopped(time.Second) {
+		return errors.New("the worker didn't stop after the timeout")
+	}
+	elapsed := time.Since(began)
+	e.Say("A worker ran under a context with a %v timeout.", timeout)
+	e.Varying("elapsed", elapsed.Round(time.Millisecond), "It stopped after")
+	e.Value("ctx.Err()", tctx.Err(), "ctx.Err()")
+	check.That(elapsed >= timeout, "the worker ran until the timeout")
+	check.That(errors.Is(tctx.Err(), context.DeadlineExceeded), "a timed-out context reports context.DeadlineExceeded")
+	check.That(wk.stoppedSince(atTimeout), "no new work starts after the timeout")
+
+	// 2. WithDeadline: the same, with the end given as a time. A child
+	// with a later deadline still ends with its parent.
+	e.Step("deadline")
+	deadline := time.Now().Add(timeout)
+	dctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	child, cancelChild := context.WithTimeout(dctx, time.Minute)
+	defer cancelChild()
+	got, _ := child.Deadline()
+	check.That(got.Equal(deadline), "a child's deadline is never later than its parent's")
+	e.Say("The child asked for a minute, but its deadline is its parent's.")
+	<-child.Done()
+	e.Value("child.Err()", child.Err(), "When the parent's deadline passed, child.Err()")
+	check.That(errors.Is(child.Err(), context.DeadlineExceeded), "the child ends with its parent's deadline")
+	check.That(!time.Now().Before(deadline), "the child ended no earlier than the deadline")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,151 @@
+package contextdemo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(treeExample{})
+}
+
+// treeExample derives a small tree of contexts, runs a worker under each,
+// and cancels the tree one branch at a time:
+//
+//	root
+//	├── a
+//	│   └── a1
+//	└── b
+type treeExample struct{}
+
+func (treeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/contextdemo/tree_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "derived contexts: cancelling a parent cancels its children, never the other way",
+		Tags:          []string{"concurrency", "context", "cancellation"},
+		Prerequisites: []string{"concurrency/contextdemo/timeout_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (treeExample) Explain(step string) string {
+	switch step {
+	case "branch":
+		return "Cancelling a reaches everything derived from a, but nothing above it or beside it."
+	case "root":
+		return "Cancelling the root reaches every context still alive under it."
+	}
+	return ""
+}
+
+// Flashcards are reviewed by "concepts review".
+func (treeExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Which contexts does cancelling a context cancel?",
+			Back:  "Itself and every context derived from it, directly or indirectly. Its parent and siblings are not affected.",
+		},
+	}
+}
+
+// node is one context of the tree, with its worker.
+type node struct {
+	name   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	worker *worker
+	// atCancel is how much work the worker had done when the context was
+	// cancelled (see cancelNode).
+	atCancel int64
+}
+
+// derive makes a child of parent called name and starts its worker.
+func derive(parent context.Context, name string) *node {
+	ctx, cancel := context.WithCancel(parent)
+	return &node{name: name, ctx: ctx, cancel: cancel, worker: start(ctx)}
+}
+
+// cancelNode cancels n, and notes for it and every node cancelled with it
+// how much work its worker had done.
+func cancelNode(n *node, nodes []*node) {
+	var live []*node
+	for _, m := range nodes {
+		if m.ctx.Err() == nil {
+			live = append(live, m)
+		}
+	}
+	n.cancel()
+	for _, m := range live {
+		if m.ctx.Err() != nil {
+			m.atCancel = m.worker.steps.Load()
+		}
+	}
+}
+
+// report records which nodes are still running, and checks that exactly
+// the nodes in want are.
+func report(e *event.Emitter, check *assert.Checker, nodes []*node, want ...string) error {
+	var running []string
+	for _, n := range nodes {
+		if n.ctx.Err() == nil {
+			running = append(running, n.name)
+			continue
+		}
+		if !n.worker.stopped(time.Second) {
+			return fmt.Errorf("the worker under %s didn't stop after cancel", n.name)
+		}
+		check.That(n.worker.stoppedSince(n.atCancel), "no new work starts under "+n.name+" after cancel")
+	}
+	e.Value("running", running, "Still running")
+	assert.Equal(check, "exactly these contexts are still running", fmt.Sprint(running), fmt.Sprint(want))
+	return nil
+}
+
+// Run cancels branch a, then the root.
+func (treeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Build the tree.
+	e.Step("tree")
+	root := derive(ctx, "root")
+	a := derive(root.ctx, "a")
+	a1 := derive(a.ctx, "a1")
+	b := derive(root.ctx, "b")
+	nodes := []*node{root, a, a1, b}
+	for _, n := range nodes {
+		defer n.cancel()
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := report(e, check, nodes, "root", "a", "a1", "b"); err != nil {
+		return err
+	}
+
+	// 2. Cancel a: a1 goes with it; root and b carry on.
+	e.Step("branch")
+	cancelNode(a, nodes)
+	e.Say("Cancelled a.")
+	if err := report(e, check, nodes, "root", "b"); err != nil {
+		return err
+	}
+	check.That(errors.Is(a1.ctx.Err(), context.Canceled), "a1 reports context.Canceled although nobody cancelled it directly")
+
+	// 3. Cancel the root: b goes with it.
+	e.Step("root")
+	cancelNode(root, nodes)
+	e.Say("Cancelled root.")
+	if err := report(e, check, nodes); err != nil {
+		return err
+	}
+	return errors.Join(e.Err(), check.Err())
+}
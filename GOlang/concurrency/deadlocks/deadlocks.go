@@ -0,0 +1,65 @@
+// Package deadlocks is a gallery of programs that deadlock on purpose.
+//
+// A deadlocked Go program is stopped by the runtime with a fatal error
+// that recover can't catch, so each deadlock runs in a process of its own
+// (see the isolate package). The example then reads what the runtime
+// printed and points at the line every goroutine was stuck on.
+package deadlocks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+)
+
+// crashTimeout is how long a deadlock program gets to be stopped by the
+// runtime before it is killed.
+const crashTimeout = 10 * time.Second
+
+// crash runs the deadlock program called name in its own process and
+// records the runtime's verdict and where each goroutine was stuck.
+func crash(ctx context.Context, e *event.Emitter, check *assert.Checker, name string) error {
+	res, err := isolate.Run(ctx, name, crashTimeout)
+	if err != nil {
+		return err
+	}
+	if res.TimedOut {
+		return errors.New(name + " hung instead of being stopped by the runtime")
+	}
+	e.Warn("The runtime stopped the program: fatal error: %s", res.Fatal)
+	assert.Equal(check, "the runtime detects the deadlock", res.Fatal, "all goroutines are asleep - deadlock!")
+	check.That(res.ExitCode != 0, "a deadlocked program exits with an error")
+	for _, g := range res.Goroutines {
+		e.Say("A goroutine was stuck in [%s] in %s:", g.State, shortFunc(g.Func))
+		if text, ok := sourceLine(g.File, g.Line); ok {
+			e.Say("    %d  %s", g.Line, text)
+		}
+	}
+	return nil
+}
+
+// sourceLine returns line n of file, if the source is around.
+func sourceLine(file string, n int) (string, bool) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", false
+	}
+	lines := strings.Split(string(data), "\n")
+	if n < 1 || n > len(lines) {
+		return "", false
+	}
+	return strings.TrimSpace(lines[n-1]), true
+}
+
+// shortFunc strips the import path from a function name:
+// "github.com/x/deadlocks.sendAlone" becomes "deadlocks.sendAlone".
+func shortFunc(name string) string {
+	return filepath.Base(name)
+}
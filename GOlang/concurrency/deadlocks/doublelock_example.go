@@ -0,0 +1,127 @@
+package deadlocks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(doubleLockExample{})
+	isolate.RegisterDeadlock("deadlocks/doublelock", depositTwice)
+}
+
+// doubleLockExample locks a mutex that the same goroutine already holds,
+// by calling one locking method from another.
+type doubleLockExample struct{}
+
+func (doubleLockExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/deadlocks/doublelock_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "locking a sync.Mutex twice from the same goroutine",
+		Tags:          []string{"concurrency", "deadlock", "mutex"},
+		Prerequisites: []string{"concurrency/deadlocks/send_example", "concurrency/locks/rwmutex_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (doubleLockExample) Explain(step string) string {
+	switch step {
+	case "crash":
+		return "deposit holds mu and calls balance, which locks mu again and waits for it to be unlocked: by itself."
+	case "fix":
+		return "The exported methods lock; the helpers they share assume the lock is held and never take it."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (doubleLockExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Is sync.Mutex reentrant: can the goroutine holding it lock it again?",
+			Choices: []string{"yes", "no, the second Lock waits forever"},
+			Answer:  "no, the second Lock waits forever",
+			Explain: "A Mutex doesn't know which goroutine holds it. Split methods into locking ones and helpers that expect the lock to be held.",
+		},
+	}
+}
+
+// account is the broken version: deposit calls balance while holding mu.
+type account struct {
+	mu    sync.Mutex
+	cents int
+}
+
+func (a *account) balance() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cents
+}
+
+func (a *account) deposit(cents int) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cents += cents
+	return a.balance() // locks a.mu again
+}
+
+// depositTwice is the deadlocking program.
+func depositTwice() {
+	var a account
+	a.deposit(100)
+}
+
+// fixedAccount keeps locking in its exported-style methods and does the
+// work in helpers that expect the lock to be held.
+type fixedAccount struct {
+	mu    sync.Mutex
+	cents int
+}
+
+func (a *fixedAccount) balance() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.balanceLocked()
+}
+
+// balanceLocked must be called with a.mu held.
+func (a *fixedAccount) balanceLocked() int { return a.cents }
+
+func (a *fixedAccount) deposit(cents int) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cents += cents
+	return a.balanceLocked()
+}
+
+// Run crashes depositTwice in its own process, then runs the fix.
+func (doubleLockExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The real thing.
+	e.Step("crash")
+	if err := crash(ctx, e, check, "deadlocks/doublelock"); err != nil {
+		return err
+	}
+
+	// 2. The fix: a helper that doesn't lock.
+	e.Step("fix")
+	var a fixedAccount
+	got := a.deposit(100)
+	e.Value("got", got, "deposit(100) returns the balance")
+	assert.Equal(check, "deposit returns the new balance", got, 100)
+	assert.Equal(check, "balance agrees", a.balance(), 100)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,105 @@
+package deadlocks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(mutualExample{})
+	isolate.RegisterDeadlock("deadlocks/mutual", waitForEachOther)
+}
+
+// mutualExample has two goroutines that each wait for the other to send
+// first, while main waits for both.
+type mutualExample struct{}
+
+func (mutualExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/deadlocks/mutual_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "two goroutines each waiting for the other: a circular wait",
+		Tags:          []string{"concurrency", "deadlock", "channels"},
+		Prerequisites: []string{"concurrency/deadlocks/send_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (mutualExample) Explain(step string) string {
+	switch step {
+	case "crash":
+		return "ping waits to hear from pong before answering, and pong waits to hear from ping: neither ever goes first."
+	case "fix":
+		return "Breaking the circle takes one side going first: ping sends, then waits for the answer."
+	}
+	return ""
+}
+
+// Flashcards are reviewed by "concepts review".
+func (mutualExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What is a circular wait?",
+			Back:  "Goroutines that each wait for something only another one of them can provide, around in a circle. None can go on, so all of them are stuck.",
+		},
+		{
+			Front: "The runtime only reports a deadlock when all goroutines are asleep. What if one other goroutine is still running?",
+			Back:  "Then the stuck goroutines just stay stuck, forever and silently: a leak rather than a crash. Most deadlocks in servers look like that.",
+		},
+	}
+}
+
+// waitForEachOther is the deadlocking program.
+func waitForEachOther() {
+	ping, pong := make(chan string), make(chan string)
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		msg := <-pong
+		ping <- "re: " + msg
+	})
+	wg.Go(func() {
+		msg := <-ping
+		pong <- "re: " + msg
+	})
+	wg.Wait()
+}
+
+// Run crashes waitForEachOther in its own process, then runs the fix.
+func (mutualExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The real thing: three goroutines, all stuck.
+	e.Step("crash")
+	if err := crash(ctx, e, check, "deadlocks/mutual"); err != nil {
+		return err
+	}
+
+	// 2. The fix: ping goes first.
+	e.Step("fix")
+	ping, pong := make(chan string), make(chan string)
+	var reply string
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		ping <- "hello"
+		reply = <-pong
+	})
+	wg.Go(func() {
+		msg := <-ping
+		pong <- "re: " + msg
+	})
+	wg.Wait()
+	e.Value("reply", reply, "ping's reply, once ping goes first")
+	assert.Equal(check, "the exchange completes once one side goes first", reply, "re: hello")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,84 @@
+package deadlocks
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(sendExample{})
+	isolate.RegisterDeadlock("deadlocks/send", sendAlone)
+}
+
+// sendExample sends on an unbuffered channel that nobody receives from.
+type sendExample struct{}
+
+func (sendExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/deadlocks/send_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "the simplest deadlock: a send with no receiver, run for real",
+		Tags:          []string{"concurrency", "deadlock", "channels"},
+		Prerequisites: []string{"concurrency/channels/deadlock_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (sendExample) Explain(step string) string {
+	switch step {
+	case "crash":
+		return "This time the deadlock is real: the program runs in a process of its own, and the runtime stops it."
+	case "fix":
+		return "A goroutine that receives gives the send somewhere to go."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (sendExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Can recover() catch \"all goroutines are asleep - deadlock!\"?",
+			Choices: []string{"yes, like any panic", "no, it is a fatal error"},
+			Answer:  "no, it is a fatal error",
+			Explain: "Fatal errors end the program without running deferred calls, which is why this example runs the deadlock in a separate process.",
+		},
+	}
+}
+
+// sendAlone is the deadlocking program: main sends, and there is nobody
+// who could ever receive.
+func sendAlone() {
+	ch := make(chan int)
+	ch <- 1
+}
+
+// Run crashes sendAlone in its own process, then runs the fix.
+func (sendExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The real thing.
+	e.Step("crash")
+	if err := crash(ctx, e, check, "deadlocks/send"); err != nil {
+		return err
+	}
+
+	// 2. The fix: receive in another goroutine.
+	e.Step("fix")
+	ch := make(chan int)
+	go func() { ch <- 1 }()
+	v := <-ch
+	e.Value("v", v, "Received, with a receiver in place")
+	assert.Equal(check, "the send completes once somebody receives", v, 1)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,135 @@
+// Package future is a small future (or promise) type built on a channel:
+// a value that a goroutine is still computing, which any number of
+// goroutines can wait for. The example in future_example.go compares it
+// with passing results over plain channels.
+package future
+
+import (
+	"context"
+	"errors"
+)
+
+// Future is the result of a computation that may not have finished. Its
+// zero value is not usable; futures come from Go, Ready, Then and the
+// functions combining them.
+type Future[T any] struct {
+	done chan struct{} // closed once val and err are set
+	val  T
+	err  error
+}
+
+// Go runs f in a new goroutine and returns its future result.
+func Go[T any](f func() (T, error)) *Future[T] {
+	fut := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer close(fut.done)
+		fut.val, fut.err = f()
+	}()
+	return fut
+}
+
+// Ready returns a future that has already finished with v and err.
+func Ready[T any](v T, err error) *Future[T] {
+	fut := &Future[T]{done: make(chan struct{}), val: v, err: err}
+	close(fut.done)
+	return fut
+}
+
+// Done returns a channel that is closed when the result is available, for
+// use in a select.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the computation to finish and returns its result. It
+// may be called any number of times, from any number of goroutines, and
+// always returns the same thing.
+func (f *Future[T]) Result() (T, error) {
+	<-f.done
+	return f.val, f.err
+}
+
+// Await is Result, except that it gives up and returns ctx.Err() if ctx
+// is done first. The computation itself carries on.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Then returns the future result of calling next with f's value once f
+// has finished. If f fails, next isn't called and the new future fails
+// with the same error.
+func Then[T, U any](f *Future[T], next func(T) (U, error)) *Future[U] {
+	return Go(func() (U, error) {
+		v, err := f.Result()
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+		return next(v)
+	})
+}
+
+// All returns the future values of fs, in the same order. It fails with
+// the first error among them, as soon as that is known, without waiting
+// for the rest.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	return Go(func() ([]T, error) {
+		failed := make(chan error, len(fs))
+		for _, f := range fs {
+			go func() {
+				if _, err := f.Result(); err != nil {
+					failed <- err
+				}
+			}()
+		}
+		vals := make([]T, len(fs))
+		for i, f := range fs {
+			select {
+			case <-f.done:
+				if f.err != nil {
+					return nil, f.err
+				}
+				vals[i] = f.val
+			case err := <-failed:
+				return nil, err
+			}
+		}
+		return vals, nil
+	})
+}
+
+// ErrNoFutures is the error of Any called with no futures.
+var ErrNoFutures = errors.New("future: no futures")
+
+// Any returns the value of whichever of fs succeeds first. If all of them
+// fail, it fails with all their errors joined.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	return Go(func() (T, error) {
+		var zero T
+		if len(fs) == 0 {
+			return zero, ErrNoFutures
+		}
+		results := make(chan *Future[T], len(fs))
+		for _, f := range fs {
+			go func() {
+				<-f.done
+				results <- f
+			}()
+		}
+		var errs []error
+		for range fs {
+			f := <-results
+			if f.err == nil {
+				return f.val, nil
+			}
+			errs = append(errs, f.err)
+		}
+		return zero, errors.Join(errs...)
+	})
+}
@@ -0,0 +1,210 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(futureExample{})
+}
+
+// futureExample looks up an order's price the way a shop's backend might,
+// first with a goroutine and a result channel per lookup, then with the
+// Future type from future.go.
+type futureExample struct{}
+
+func (futureExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/future/future_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "a generic future type built on a channel, compared with plain result channels",
+		Tags:          []string{"concurrency", "futures", "promises", "channels", "generics"},
+		Prerequisites: []string{"concurrency/workerpool/pool_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (futureExample) Explain(step string) string {
+	switch step {
+	case "channels":
+		return "Each lookup needs its own channel and a struct to carry the value and the error, and its result can be received only once."
+	case "future":
+		return "A future closes a channel when its result is set; every receive from a closed channel succeeds, so anyone can wait for it, any number of times."
+	case "then":
+		return "Then chains a computation onto a result that doesn't exist yet; an error skips the rest of the chain."
+	case "all":
+		return "All fails as soon as any of its futures fails, without waiting for the slow ones."
+	case "any":
+		return "Any takes the first success and ignores failures, unless everything fails."
+	case "await":
+		return "Await stops waiting when its context is done, but nothing can stop the goroutine computing the result."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (futureExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Two goroutines need a result that a third one sends, once, on an unbuffered channel. What happens?",
+			Choices: []string{"both get it", "one gets it and the other waits for ever", "the send panics"},
+			Answer:  "one gets it and the other waits for ever",
+			Explain: "A value sent on a channel is received by exactly one receiver. A future closes a channel instead, which every waiter sees.",
+		},
+		{
+			Prompt:  "Await(ctx) returns ctx.Err() because the context timed out. Is the computation cancelled?",
+			Choices: []string{"yes", "no, only the waiting stops"},
+			Answer:  "no, only the waiting stops",
+			Explain: "The future has no way to stop its goroutine; to make the work itself stoppable, pass the context to the function given to Go.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (futureExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How can many goroutines wait for one result on a channel?",
+			Back:  "Store the result, then close the channel: every receive from a closed channel returns at once, so all of them see it. That is a future.",
+		},
+	}
+}
+
+var (
+	errOutOfStock  = errors.New("out of stock")
+	errUnavailable = errors.New("replica unavailable")
+)
+
+// lookup returns v after delay, or err if it isn't nil.
+func lookup[T any](delay time.Duration, v T, err error) func() (T, error) {
+	return func() (T, error) {
+		time.Sleep(delay)
+		return v, err
+	}
+}
+
+// Run looks up a price and a discount, chains and combines lookups, and
+// waits for one with a deadline.
+func (futureExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	price := lookup(20*time.Millisecond, 1250, nil)  // in cents
+	discount := lookup(10*time.Millisecond, 10, nil) // in percent
+
+	// 1. Plain channels: a result type and a channel per lookup.
+	e.Step("channels")
+	type result struct {
+		v   int
+		err error
+	}
+	start := func(f func() (int, error)) <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			v, err := f()
+			ch <- result{v, err}
+		}()
+		return ch
+	}
+	pc, dc := start(price), start(discount)
+	p, d := <-pc, <-dc
+	if p.err != nil || d.err != nil {
+		return errors.Join(p.err, d.err)
+	}
+	e.Value("price", p.v, "Price (cents)")
+	e.Value("discount", d.v, "Discount (%)")
+	select {
+	case r := <-pc:
+		e.Value("again", r.v, "Second receive")
+	default:
+		e.Say("A second receive from pc would wait for ever: its one result is gone.")
+	}
+
+	// 2. The same with futures; a result can be read again.
+	e.Step("future")
+	pf, df := Go(price), Go(discount)
+	pv, err := pf.Result()
+	if err != nil {
+		return err
+	}
+	again, _ := pf.Result()
+	dv, err := df.Result()
+	if err != nil {
+		return err
+	}
+	e.Value("price", pv, "pf.Result()")
+	e.Value("again", again, "pf.Result(), again")
+	e.Value("discount", dv, "df.Result()")
+	assert.Equal(check, "a future gives the same result as the channel", pv, p.v)
+	assert.Equal(check, "Result can be called again", again, pv)
+
+	// 3. Then: a total computed from a price that is still being looked up.
+	e.Step("then")
+	total := Then(Go(price), func(cents int) (int, error) { return cents * (100 - dv) / 100, nil })
+	label := Then(total, func(cents int) (string, error) { return fmt.Sprintf("$%d.%02d", cents/100, cents%100), nil })
+	text, err := label.Result()
+	e.Value("label", text, "Label, two Thens after the lookup")
+	assert.Equal(check, "Then applies each step in turn", text, "$11.25")
+	check.That(err == nil, "a successful chain has no error")
+	stock := Go(lookup(time.Millisecond, 0, errOutOfStock))
+	called := false
+	_, err = Then(stock, func(n int) (int, error) { called = true; return n, nil }).Result()
+	e.Value("err", err, "Then after a failed lookup")
+	check.That(errors.Is(err, errOutOfStock), "the error goes down the chain")
+	check.That(!called, "the step after a failure is skipped")
+
+	// 4. All: every item of a basket, or the first error.
+	e.Step("all")
+	began := time.Now()
+	basket := All(
+		Go(lookup(10*time.Millisecond, 500, nil)),
+		Go(lookup(time.Second, 700, nil)),
+		Go(lookup(5*time.Millisecond, 0, errOutOfStock)),
+	)
+	_, err = basket.Result()
+	e.Value("err", err, "All, with one item out of stock")
+	e.Varying("elapsed", time.Since(began).Round(time.Millisecond), "Time until All failed")
+	check.That(errors.Is(err, errOutOfStock), "All fails with the failed future's error")
+	check.That(time.Since(began) < 500*time.Millisecond, "All doesn't wait for the 1s lookup to fail")
+	prices, err := All(Go(lookup(10*time.Millisecond, 500, nil)), Go(lookup(time.Millisecond, 700, nil))).Result()
+	e.Value("prices", prices, "All, with everything in stock")
+	assert.Equal(check, "All keeps the futures' order, not their finishing order", fmt.Sprint(prices), "[500 700]")
+	check.That(err == nil, "All succeeds when every future does")
+
+	// 5. Any: ask three replicas, take the first answer. The first fails
+	// at once and the third doesn't answer until Any is done.
+	e.Step("any")
+	late := make(chan struct{})
+	first, err := Any(
+		Go(lookup(0, 0, errUnavailable)),
+		Go(lookup(10*time.Millisecond, 1250, nil)),
+		Go(func() (int, error) { <-late; return 1249, nil }),
+	).Result()
+	close(late)
+	e.Value("first", first, "Any: the first successful replica")
+	assert.Equal(check, "Any skips failures and takes the first success", first, 1250)
+	check.That(err == nil, "Any succeeds if one future does")
+	_, err = Any(Go(lookup(0, 0, errUnavailable)), Go(lookup(0, 0, errUnavailable))).Result()
+	e.Value("err", err, "Any, when every replica fails")
+	check.That(errors.Is(err, errUnavailable), "Any fails only when all of them fail")
+
+	// 6. Await: wait no longer than 20ms for a 1s lookup.
+	e.Step("await")
+	tctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = Go(lookup(time.Second, 0, nil)).Await(tctx)
+	e.Value("err", err, "Await with a 20ms timeout")
+	check.That(errors.Is(err, context.DeadlineExceeded), "Await returns the context's error")
+	e.Warn("The 1s lookup is still running in its goroutine: stopping the work takes a context passed to the work itself.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,108 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blocked returns a future that finishes with v once release is closed.
+func blocked[T any](release <-chan struct{}, v T) *Future[T] {
+	return Go(func() (T, error) {
+		<-release
+		return v, nil
+	})
+}
+
+func TestResultFromManyGoroutines(t *testing.T) {
+	release := make(chan struct{})
+	f := blocked(release, 42)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			if v, err := f.Result(); v != 42 || err != nil {
+				t.Errorf("Result() = %v, %v; want 42, nil", v, err)
+			}
+		})
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestAwaitCancelled(t *testing.T) {
+	release := make(chan struct{})
+	f := blocked(release, "done")
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() {
+		_, err := f.Await(ctx)
+		errc <- err
+	}()
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Await after cancel = %v, want %v", err, context.Canceled)
+	}
+	// Giving up on the future doesn't stop the computation.
+	close(release)
+	if v, err := f.Await(context.Background()); v != "done" || err != nil {
+		t.Errorf("Await() = %q, %v; want the result after all", v, err)
+	}
+}
+
+func TestThen(t *testing.T) {
+	double := func(n int) (int, error) { return 2 * n, nil }
+	if v, err := Then(Ready(21, nil), double).Result(); v != 42 || err != nil {
+		t.Errorf("Then on 21 = %v, %v; want 42", v, err)
+	}
+	errBoom := errors.New("boom")
+	called := false
+	_, err := Then(Ready(0, errBoom), func(int) (int, error) {
+		called = true
+		return 0, nil
+	}).Result()
+	if !errors.Is(err, errBoom) || called {
+		t.Errorf("Then on a failure = %v, called %v; want the failure, not calling next", err, called)
+	}
+}
+
+func TestAll(t *testing.T) {
+	vals, err := All(Ready(1, nil), Go(func() (int, error) {
+		time.Sleep(time.Millisecond)
+		return 2, nil
+	}), Ready(3, nil)).Result()
+	if !slices.Equal(vals, []int{1, 2, 3}) || err != nil {
+		t.Errorf("All = %v, %v; want [1 2 3] in order", vals, err)
+	}
+}
+
+func TestAllFailsFast(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	errBoom := errors.New("boom")
+	all := All(blocked(release, 1), Ready(0, errBoom))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// The first future never finishes while All is awaited.
+	if _, err := all.Await(ctx); !errors.Is(err, errBoom) {
+		t.Errorf("All = %v, want %v without waiting for the rest", err, errBoom)
+	}
+}
+
+func TestAny(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	errA, errB := errors.New("a"), errors.New("b")
+	if v, err := Any(blocked(release, "slow"), Ready("", errA), Ready("fast", nil)).Result(); v != "fast" || err != nil {
+		t.Errorf("Any = %q, %v; want the first success", v, err)
+	}
+	_, err := Any(Ready("", errA), Ready("", errB)).Result()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Any of failures = %v, want both errors", err)
+	}
+	if _, err := Any[int]().Result(); !errors.Is(err, ErrNoFutures) {
+		t.Errorf("Any() = %v, want %v", err, ErrNoFutures)
+	}
+}
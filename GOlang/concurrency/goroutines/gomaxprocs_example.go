@@ -0,0 +1,202 @@
+package goroutines
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(&gomaxprocsExample{goroutines: 8, work: 10_000_000})
+}
+
+// gomaxprocsExample runs the same CPU-bound goroutines with different
+// GOMAXPROCS settings and watches the scheduler while they run. The
+// timings are your machine's; try
+//
+//	concepts run concurrency/goroutines/gomaxprocs_example -- -goroutines 16
+//
+// or set GODEBUG=schedtrace=100 to have the runtime print the state of
+// its run queues ten times a second.
+type gomaxprocsExample struct {
+	goroutines int
+	work       int
+}
+
+func (*gomaxprocsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/goroutines/gomaxprocs_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "GOMAXPROCS: CPU-bound goroutines run concurrently on 1 thread, in parallel on several",
+		Tags:          []string{"concurrency", "goroutines", "scheduling", "gomaxprocs", "parallelism"},
+		Prerequisites: []string{"concurrency/goroutines/interleave_example"},
+	}
+}
+
+// SetFlags lets the learner choose the number of goroutines and how much
+// each one computes.
+func (g *gomaxprocsExample) SetFlags(fs *flag.FlagSet) {
+	fs.IntVar(&g.goroutines, "goroutines", g.goroutines, "number of CPU-bound goroutines")
+	fs.IntVar(&g.work, "work", g.work, "loop iterations per goroutine")
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (*gomaxprocsExample) Explain(step string) string {
+	switch step {
+	case "machine":
+		return "GOMAXPROCS is how many threads may run Go code at the same time. It defaults to the number of CPUs the program may use."
+	case "procs":
+		return "With GOMAXPROCS=1 the goroutines take turns on one thread: concurrency. With more, several really run at once: parallelism."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (*gomaxprocsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "With GOMAXPROCS=1, 8 CPU-bound goroutines are started. How many are running Go code at any moment?",
+			Choices: []string{"1", "8", "it depends on the number of CPUs"},
+			Answer:  "1",
+			Explain: "They all make progress, because the scheduler preempts them in turn, but only one runs at a time. The others wait in the run queue.",
+		},
+		{
+			Prompt:  "Does raising GOMAXPROCS above the number of CPUs make CPU-bound code faster?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "There are no more cores to run on; the operating system just switches the extra threads around.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (*gomaxprocsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Concurrency vs parallelism",
+			Back:  "Concurrency is structuring a program as independent tasks that may take turns; parallelism is running several of them at the same instant, which needs more than one core (and GOMAXPROCS > 1).",
+		},
+	}
+}
+
+// spin is CPU-bound work: n rounds of an xorshift generator.
+func spin(n int) uint64 {
+	x := uint64(88172645463325252)
+	for range n {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return x
+}
+
+// run is one run of the goroutines.
+type run struct {
+	wall     time.Duration
+	sum      uint64
+	running  int // most goroutines running at once
+	runnable int // most goroutines waiting for a thread
+	created  uint64
+}
+
+var schedMetrics = []metrics.Sample{
+	{Name: "/sched/goroutines/running:goroutines"},
+	{Name: "/sched/goroutines/runnable:goroutines"},
+	{Name: "/sched/goroutines-created:goroutines"},
+}
+
+// sched reads the scheduler metrics: goroutines running and waiting to
+// run, and goroutines created so far.
+func sched() (running, runnable int, created uint64) {
+	metrics.Read(schedMetrics)
+	return int(schedMetrics[0].Value.Uint64()), int(schedMetrics[1].Value.Uint64()), schedMetrics[2].Value.Uint64()
+}
+
+// measure runs goroutines goroutines of spin(work) with the given
+// GOMAXPROCS, sampling the scheduler every millisecond while they run.
+func measure(procs, goroutines, work int) run {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+	var r run
+	_, _, created := sched()
+	sums := make([]uint64, goroutines)
+	var wg sync.WaitGroup
+	began := time.Now()
+	for i := range goroutines {
+		wg.Go(func() { sums[i] = spin(work) })
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	tick := time.NewTicker(time.Millisecond)
+	defer tick.Stop()
+	for waiting := true; waiting; {
+		select {
+		case <-done:
+			waiting = false
+		case <-tick.C:
+			running, runnable, _ := sched()
+			r.running = max(r.running, running)
+			r.runnable = max(r.runnable, runnable)
+		}
+	}
+	r.wall = time.Since(began).Round(time.Millisecond)
+	_, _, now := sched()
+	r.created = now - created
+	for _, s := range sums {
+		r.sum ^= s
+	}
+	return r
+}
+
+// Run measures the goroutines with GOMAXPROCS 1 and the number of CPUs.
+func (g *gomaxprocsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. What this machine has.
+	e.Step("machine")
+	e.Varying("NumCPU", runtime.NumCPU(), "runtime.NumCPU()")
+	e.Varying("GOMAXPROCS", runtime.GOMAXPROCS(0), "runtime.GOMAXPROCS(0)")
+	e.Say("%d goroutines each spin %d rounds, with nothing to wait for: pure CPU work.", g.goroutines, g.work)
+
+	// 2. The same goroutines on one thread, then on one per CPU. (On a
+	// single-CPU machine, the two are the same.)
+	e.Step("procs")
+	var first run
+	for i, procs := range []int{1, runtime.NumCPU()} {
+		r := measure(procs, g.goroutines, g.work)
+		if i == 0 {
+			first = r
+			e.Say("GOMAXPROCS=1:")
+		} else {
+			e.Say("GOMAXPROCS=NumCPU:")
+		}
+		e.Varying("wall", r.wall, "  wall time")
+		e.Varying("running", r.running, "  most goroutines running at once")
+		e.Varying("runnable", r.runnable, "  most goroutines waiting for a thread")
+		if i > 0 {
+			e.Varying("speedup", fmt.Sprintf("%.1fx", float64(first.wall)/float64(max(r.wall, time.Millisecond))), "  speedup over GOMAXPROCS=1")
+		}
+		check.That(r.running <= procs, "no more goroutines run at once than GOMAXPROCS allows")
+		check.That(r.created >= uint64(g.goroutines), "every goroutine was created")
+		assert.Equal(check, "the result doesn't depend on the setting", r.sum, first.sum)
+	}
+	e.Varying("NumGoroutine", runtime.NumGoroutine(), "runtime.NumGoroutine() afterwards")
+	e.Warn("With GOMAXPROCS=1 the goroutines are still concurrent: they all run, taking turns. Only the wall time shows that none ran in parallel.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,110 @@
+package goroutines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(interleaveExample{})
+}
+
+// interleaveExample lets three goroutines print a few lines each. The
+// lines of one goroutine come out in order, but how the goroutines take
+// turns is up to the scheduler and changes from run to run.
+type interleaveExample struct{}
+
+func (interleaveExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/goroutines/interleave_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "goroutines printing at the same time: their lines interleave unpredictably",
+		Tags:          []string{"concurrency", "goroutines", "scheduling"},
+		Prerequisites: []string{"concurrency/goroutines/launch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (interleaveExample) Explain(step string) string {
+	switch step {
+	case "print":
+		return "Three goroutines each print three lines as fast as they can; nothing coordinates who goes when."
+	case "order":
+		return "Within one goroutine the order is fixed. Across goroutines, any interleaving is possible."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (interleaveExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Goroutine A prints A1 then A2; goroutine B prints B1 then B2. Which output is impossible?",
+			Choices: []string{"A1 B1 A2 B2", "B1 B2 A1 A2", "A2 A1 B1 B2"},
+			Answer:  "A2 A1 B1 B2",
+			Explain: "Each goroutine runs its own statements in order, so A1 always comes before A2. Everything else depends on scheduling.",
+		},
+	}
+}
+
+// logger collects printed lines. Printing to one writer from several
+// goroutines needs a mutex too, or the writes themselves would race.
+type logger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *logger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+// Run starts the goroutines and then looks at the order their lines came in.
+func (interleaveExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Three goroutines, three lines each, all started at once.
+	e.Step("print")
+	var log logger
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for _, name := range []string{"A", "B", "C"} {
+		wg.Go(func() {
+			<-start
+			for i := 1; i <= 3; i++ {
+				log.Printf("%s%d", name, i)
+			}
+		})
+	}
+	close(start)
+	wg.Wait()
+	e.Varying("lines", strings.Join(log.lines, " "), "Lines in the order they were printed")
+
+	// 2. Each goroutine's own lines are still in order.
+	e.Step("order")
+	for _, name := range []string{"A", "B", "C"} {
+		var own []string
+		for _, line := range log.lines {
+			if strings.HasPrefix(line, name) {
+				own = append(own, line)
+			}
+		}
+		e.Value(name, strings.Join(own, " "), "Lines of goroutine "+name)
+		check.That(slices.IsSorted(own), "goroutine "+name+" printed its lines in order")
+	}
+	e.Say("Run the example a few times: the first line changes, the per-goroutine order never does.")
+	return errors.Join(e.Err(), check.Err())
+}
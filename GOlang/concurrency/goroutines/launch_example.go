@@ -0,0 +1,120 @@
+// Package goroutines contains examples about starting goroutines: what the
+// go statement does, why a program doesn't wait for its goroutines, why
+// the output of goroutines doesn't come in a fixed order, and how many of
+// them run in parallel.
+package goroutines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(launchExample{})
+}
+
+// launchExample starts a few goroutines with the go statement and waits
+// for them with a sync.WaitGroup.
+type launchExample struct{}
+
+func (launchExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/goroutines/launch_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "starting goroutines with go and waiting for them with a WaitGroup",
+		Tags:          []string{"concurrency", "goroutines", "waitgroup"},
+		Prerequisites: []string{"pointers/function_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (launchExample) Explain(step string) string {
+	switch step {
+	case "call":
+		return "A plain call runs square to completion before the next line runs."
+	case "go":
+		return "go square(...) returns at once; square runs in a new goroutine while the caller carries on."
+	case "wait":
+		return "wg.Wait blocks until every goroutine started with wg.Go has returned, so now all the results are there."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (launchExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "What does the statement go square(3, &r) wait for before the next line runs?",
+			Choices: []string{"for square to return", "for square to start", "nothing"},
+			Answer:  "nothing",
+			Explain: "The go statement only schedules square to run in a new goroutine. The caller continues immediately.",
+		},
+		{
+			Prompt:  "When is it safe to read the results the goroutines wrote?",
+			Choices: []string{"right after the go statements", "after wg.Wait() returns"},
+			Answer:  "after wg.Wait() returns",
+			Explain: "Wait returns once every goroutine is done, and everything they wrote before finishing is visible after it.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (launchExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does the go statement do?",
+			Back:  "It starts running a function call in a new goroutine and returns immediately, without waiting for the call to finish.",
+		},
+		{
+			Front: "How do you wait for a group of goroutines to finish?",
+			Back:  "Start them with wg.Go(f) (or wg.Add(1) plus defer wg.Done()) on a sync.WaitGroup, then call wg.Wait().",
+		},
+	}
+}
+
+// square stores n*n in *result. It is called once directly and then from
+// goroutines; the function itself doesn't know or care which.
+func square(n int, result *int) {
+	*result = n * n
+}
+
+// Run calls square directly, then in goroutines.
+func (launchExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. An ordinary call: the caller waits for square to return.
+	e.Step("call")
+	var r int
+	square(3, &r)
+	e.Value("r", r, "square(3) called directly, r right after the call")
+	assert.Equal(check, "a direct call has finished when it returns", r, 9)
+
+	// 2. The same function, started as goroutines. Each gets its own slot
+	// in results, so no two goroutines write the same variable.
+	e.Step("go")
+	results := make([]int, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Go(func() { square(i+1, &results[i]) })
+	}
+	e.Say("Started %d goroutines with wg.Go; the loop didn't wait for any of them.", len(results))
+	e.Say("Reading results now would be a data race: the goroutines may still be writing them.")
+
+	// 3. Wait for all of them. Only now are the results ready to read.
+	e.Step("wait")
+	wg.Wait()
+	e.Value("results", results, "results after wg.Wait()")
+	assert.Equal(check, "every goroutine stored its square", fmt.Sprint(results), "[1 4 9 16]")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,108 @@
+package goroutines
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(mainExitExample{})
+}
+
+// mainExitExample shows that nothing waits for a goroutine unless the
+// program says so. A Go program ends as soon as main returns, taking every
+// goroutine that is still running down with it.
+//
+// The example can't end the concepts process to prove the point, so
+// fakeMain plays the part of main: whatever it started but didn't wait
+// for is exactly what a real program would lose.
+type mainExitExample struct{}
+
+func (mainExitExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/goroutines/main_exit_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "why goroutines stop when main returns, and how waiting fixes it",
+		Tags:          []string{"concurrency", "goroutines", "main"},
+		Prerequisites: []string{"concurrency/goroutines/launch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (mainExitExample) Explain(step string) string {
+	switch step {
+	case "no-wait":
+		return "fakeMain starts a goroutine and returns straight away. In a real program, the process would exit at this point."
+	case "wait":
+		return "This time main waits on a channel that the goroutine closes when it is done."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (mainExitExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "main does go fmt.Println(\"hi\") and then returns. What does the program print?",
+			Choices: []string{"always hi", "maybe hi, most likely nothing", "it panics"},
+			Answer:  "maybe hi, most likely nothing",
+			Explain: "The program exits when main returns, whether or not the goroutine got to run.",
+		},
+		{
+			Prompt:  "Which of these makes main wait for a goroutine?",
+			Choices: []string{"time.Sleep(time.Second)", "receiving from a channel the goroutine closes when done", "runtime.Gosched()"},
+			Answer:  "receiving from a channel the goroutine closes when done",
+			Explain: "A sleep only makes it likely that the goroutine has finished; only synchronization (a channel, a WaitGroup) guarantees it.",
+		},
+	}
+}
+
+// fakeMain stands in for a program's main function. It starts work in a
+// goroutine and, if wait is false, returns without waiting for it. The
+// goroutine can't finish until release is closed, so when fakeMain
+// returns without waiting, the work is certainly not done.
+func fakeMain(wait bool, release <-chan struct{}, finished *atomic.Bool) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-release // the "work": it takes a moment
+		finished.Store(true)
+	}()
+	if wait {
+		<-done
+	}
+}
+
+// Run calls fakeMain without and with waiting.
+func (mainExitExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Start the goroutine and return without waiting.
+	e.Step("no-wait")
+	var finished atomic.Bool
+	release := make(chan struct{})
+	fakeMain(false, release, &finished)
+	e.Value("finished", finished.Load(), "Goroutine finished when fakeMain returned")
+	e.Warn("A real main returning here ends the program, and the goroutine's work is lost.")
+	check.That(!finished.Load(), "returning from main doesn't wait for goroutines")
+	close(release) // let the abandoned goroutine end, the concepts tool keeps running
+
+	// 2. Start it again, but wait for it to be done before returning.
+	e.Step("wait")
+	var finishedAgain atomic.Bool
+	release = make(chan struct{})
+	close(release) // no need to hold it back this time
+	fakeMain(true, release, &finishedAgain)
+	e.Value("finished", finishedAgain.Load(), "Goroutine finished when fakeMain returned")
+	check.That(finishedAgain.Load(), "waiting on done means the goroutine has finished")
+	return errors.Join(e.Err(), check.Err())
+}
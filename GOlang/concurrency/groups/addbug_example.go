@@ -0,0 +1,109 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(addBugExample{})
+}
+
+// addBugExample shows the classic WaitGroup mistake, calling Add inside
+// the goroutine instead of before starting it, and the fix.
+//
+// Whether the bug bites depends on how soon the scheduler starts the
+// goroutines, so the buggy version holds them at a gate that only opens
+// once Wait has returned. That is the unlucky schedule made certain; it
+// is the same bug either way.
+type addBugExample struct{}
+
+func (addBugExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/groups/addbug_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "the \"wg.Add inside the goroutine\" bug, and why Add goes before go",
+		Tags:          []string{"concurrency", "sync", "waitgroup", "bugs"},
+		Prerequisites: []string{"concurrency/groups/waitgroup_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (addBugExample) Explain(step string) string {
+	switch step {
+	case "bug":
+		return "Wait returns as soon as the counter is zero. If no goroutine has run its Add yet, that is right away."
+	case "fix":
+		return "Adding before the go statement means the counter already covers every goroutine when Wait is called."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (addBugExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Why must wg.Add be called before the go statement rather than inside the goroutine?",
+			Choices: []string{"Add is not safe to call concurrently", "Wait may run before the goroutine has called Add", "Done would panic"},
+			Answer:  "Wait may run before the goroutine has called Add",
+			Explain: "Then the counter is still zero and Wait returns without waiting. wg.Go does the Add for you, in the right place.",
+		},
+	}
+}
+
+// join is wg.Add(1) under another name. go vet knows the mistake this
+// example makes and would refuse it if it were spelled out.
+func join(wg *sync.WaitGroup) { wg.Add(1) }
+
+// Run waits for 4 goroutines wrongly, then correctly.
+func (addBugExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	const n = 4
+
+	// 1. The bug: each goroutine adds itself, but Wait gets there first.
+	e.Step("bug")
+	var wg sync.WaitGroup
+	var done atomic.Int32
+	var cleanup sync.WaitGroup // only lets the example wait for them later
+	gate := make(chan struct{})
+	for range n {
+		cleanup.Go(func() {
+			<-gate
+			join(&wg) // the bug: should be wg.Add(1) before go
+			defer wg.Done()
+			done.Add(1)
+		})
+	}
+	wg.Wait()
+	e.Value("done", done.Load(), "Goroutines finished when Wait returned")
+	assert.Equal(check, "Wait returns before any goroutine has run", int(done.Load()), 0)
+	e.Warn("Wait returned before the goroutines had even started: its counter was still zero.")
+	close(gate)
+	cleanup.Wait()
+
+	// 2. The fix: Add before go (or let wg.Go do it).
+	e.Step("fix")
+	var wg2 sync.WaitGroup
+	var done2 atomic.Int32
+	for range n {
+		wg2.Add(1)
+		go func() {
+			defer wg2.Done()
+			done2.Add(1)
+		}()
+	}
+	wg2.Wait()
+	e.Value("done", done2.Load(), "Goroutines finished when Wait returned")
+	assert.Equal(check, "with Add before go, Wait waits for all of them", int(done2.Load()), n)
+	return errors.Join(e.Err(), check.Err())
+}
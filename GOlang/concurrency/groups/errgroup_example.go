@@ -0,0 +1,130 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(errGroupExample{})
+}
+
+// errGroupExample runs the tasks of waitgroup_example with an errgroup:
+// the first error cancels the rest, and SetLimit bounds how many tasks
+// run at once.
+type errGroupExample struct{}
+
+func (errGroupExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/groups/errgroup_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "errgroup: the first error cancels the group, and SetLimit bounds parallelism",
+		Tags:          []string{"concurrency", "errgroup", "errors", "context", "cancellation"},
+		Prerequisites: []string{"concurrency/groups/waitgroup_example", "concurrency/contextdemo/cancel_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (errGroupExample) Explain(step string) string {
+	switch step {
+	case "first-error":
+		return "errgroup.WithContext cancels its context as soon as a task returns an error, and Wait returns that first error."
+	case "limit":
+		return "With SetLimit(n), g.Go blocks while n tasks are running, so at most n run at once."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (errGroupExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Three tasks of an errgroup fail. What does g.Wait() return?",
+			Choices: []string{"all three errors joined", "the first error", "the last error"},
+			Answer:  "the first error",
+			Explain: "errgroup keeps only the first error; the others are usually just context.Canceled caused by it.",
+		},
+		{
+			Prompt:  "What does g.Go do when the group already runs SetLimit tasks?",
+			Choices: []string{"returns an error", "blocks until one finishes", "starts the task anyway"},
+			Answer:  "blocks until one finishes",
+			Explain: "Use g.TryGo if you would rather not block.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (errGroupExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does errgroup add to a sync.WaitGroup?",
+			Back:  "Tasks return errors, Wait returns the first one, WithContext cancels the other tasks when one fails, and SetLimit bounds how many run at once.",
+		},
+	}
+}
+
+// limit is the most tasks the "limit" step lets run at once.
+const limit = 2
+
+// Run runs the tasks in an errgroup, then a bounded batch.
+func (errGroupExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The failing task cancels gctx, and every other task gives up.
+	e.Step("first-error")
+	start := time.Now()
+	g, gctx := errgroup.WithContext(ctx)
+	var cancelled atomic.Int32
+	for i := range tasks {
+		g.Go(func() error {
+			_, err := task(gctx, i)
+			if errors.Is(err, context.Canceled) {
+				cancelled.Add(1)
+			}
+			return err
+		})
+	}
+	err := g.Wait()
+	elapsed := time.Since(start)
+	e.Value("err", err, "g.Wait()")
+	e.Value("cancelled", cancelled.Load(), "Tasks stopped by the cancellation")
+	e.Varying("elapsed", elapsed.Round(time.Millisecond), "Wait returned after")
+	check.That(errors.Is(err, errTask), "Wait returns the task's error, not context.Canceled")
+	assert.Equal(check, "the first error cancels every other task", int(cancelled.Load()), tasks-1)
+
+	// 2. SetLimit: track how many tasks run at the same time.
+	e.Step("limit")
+	var g2 errgroup.Group
+	g2.SetLimit(limit)
+	var running, most atomic.Int32
+	for range 6 {
+		g2.Go(func() error {
+			n := running.Add(1)
+			for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	if err := g2.Wait(); err != nil {
+		return err
+	}
+	e.Say("Ran 6 tasks with SetLimit(%d).", limit)
+	e.Value("most", most.Load(), "Most tasks running at once")
+	assert.Equal(check, "SetLimit bounds the tasks running at once", int(most.Load()), limit)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,127 @@
+// Package groups contains examples about waiting for a group of
+// goroutines: sync.WaitGroup, and errgroup, which adds errors,
+// cancellation and a limit on how many run at once.
+package groups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(waitGroupExample{})
+}
+
+// waitGroupExample runs a batch of tasks with a sync.WaitGroup, one of
+// which fails, to show what a WaitGroup does and what it leaves to you.
+type waitGroupExample struct{}
+
+func (waitGroupExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/groups/waitgroup_example",
+		Topic:         "concurrency",
+		Level:         registry.Beginner,
+		Description:   "sync.WaitGroup: waiting for goroutines, and collecting their errors yourself",
+		Tags:          []string{"concurrency", "sync", "waitgroup", "errors"},
+		Prerequisites: []string{"concurrency/goroutines/launch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (waitGroupExample) Explain(step string) string {
+	switch step {
+	case "wait":
+		return "Each goroutine writes to its own slot of the results slice, so they need no lock, and Wait makes their writes visible."
+	case "errors":
+		return "A WaitGroup only counts. Errors get a slot of their own, and a failing task can't stop the others."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (waitGroupExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "One of the tasks in a WaitGroup fails early. When does Wait return?",
+			Choices: []string{"right after the failure", "when every task has finished"},
+			Answer:  "when every task has finished",
+			Explain: "A WaitGroup knows nothing about errors: it waits until its counter is back to zero.",
+		},
+	}
+}
+
+// tasks is how many tasks each example in this package runs, failing
+// the one that fails, and taskTime how long a task that doesn't fail takes.
+const (
+	tasks    = 5
+	failing  = 2
+	taskTime = 50 * time.Millisecond
+)
+
+// errTask is what the failing task returns.
+var errTask = errors.New("task 2 failed")
+
+// task is task number i. The failing task fails almost at once; the
+// others work for taskTime, or until ctx is done.
+func task(ctx context.Context, i int) (string, error) {
+	if i == failing {
+		time.Sleep(2 * time.Millisecond)
+		return "", errTask
+	}
+	select {
+	case <-time.After(taskTime):
+		return fmt.Sprintf("result%d", i), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Run runs the tasks under a WaitGroup.
+func (waitGroupExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Add, Done (here inside wg.Go) and Wait; the results go into
+	// slots indexed by task.
+	e.Step("wait")
+	start := time.Now()
+	var wg sync.WaitGroup
+	results := make([]string, tasks)
+	errs := make([]error, tasks)
+	for i := range tasks {
+		wg.Go(func() {
+			results[i], errs[i] = task(ctx, i)
+		})
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	e.Value("results", results, "Results by task")
+	e.Varying("elapsed", elapsed.Round(time.Millisecond), "Wait returned after")
+	check.That(elapsed >= taskTime, "Wait waits for every task, failed or not")
+
+	// 2. The errors: it's up to the caller to look at them, and every
+	// other task ran to the end anyway.
+	e.Step("errors")
+	err := errors.Join(errs...)
+	e.Value("err", err, "errors.Join(errs...)")
+	check.That(errors.Is(err, errTask), "the failure is in the collected errors")
+	finished := 0
+	for _, r := range results {
+		if r != "" {
+			finished++
+		}
+	}
+	e.Value("finished", finished, "Tasks that ran to the end")
+	assert.Equal(check, "the failure doesn't stop the other tasks", finished, tasks-1)
+	e.Say("errgroup does this bookkeeping for you: see concurrency/groups/errgroup_example.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,164 @@
+// Package lazy contains examples about initializing things on first use,
+// safely, when the first use may come from many goroutines at once.
+package lazy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(onceExample{})
+}
+
+// onceExample builds a lazy singleton with sync.Once and with
+// sync.OnceValue, and has 1000 goroutines ask for it at the same moment.
+type onceExample struct{}
+
+func (onceExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/lazy/once_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "sync.Once and sync.OnceValue: lazy singletons that are built exactly once",
+		Tags:          []string{"concurrency", "sync", "once", "lazy-initialization", "singleton"},
+		Prerequisites: []string{"concurrency/groups/waitgroup_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (onceExample) Explain(step string) string {
+	switch step {
+	case "once":
+		return "The first caller of once.Do runs the function; every other caller waits until it has returned, then goes on without running it."
+	case "oncevalue":
+		return "sync.OnceValue wraps the same idea into a function that returns the value, so there is no package variable to forget to guard."
+	case "error":
+		return "Once means once: if the initializer fails, the failure is what every later caller gets too."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (onceExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "1000 goroutines call once.Do(init) at the same time while init takes a second. What do the 999 that lose do?",
+			Choices: []string{"return at once, before init is done", "wait for init to finish", "run init too"},
+			Answer:  "wait for init to finish",
+			Explain: "When Do returns, init has completed, so callers can rely on what it set up.",
+		},
+		{
+			Prompt:  "The function given to sync.OnceValues returned an error. What do later calls return?",
+			Choices: []string{"they retry the function", "the same error"},
+			Answer:  "the same error",
+			Explain: "The result, error included, is computed once and kept. Code that needs retries has to build them itself.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (onceExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What is wrong with `if db == nil { db = connect() }` called from several goroutines?",
+			Back:  "It is a data race: two goroutines can both see nil and both connect. sync.Once (or sync.OnceValue) makes the check and the initialization one safe step.",
+		},
+	}
+}
+
+// callers is how many goroutines ask for the singleton at once.
+const callers = 1000
+
+// database is the expensive thing that should only be built once.
+type database struct{ id int64 }
+
+// connects counts how often a database was built.
+var connects atomic.Int64
+
+// connect builds a database, slowly.
+func connect() *database {
+	time.Sleep(time.Millisecond)
+	return &database{id: connects.Add(1)}
+}
+
+// stampede calls get from callers goroutines that all start together, and
+// returns what each of them got.
+func stampede[T any](get func() T) []T {
+	got := make([]T, callers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Go(func() {
+			<-start
+			got[i] = get()
+		})
+	}
+	close(start)
+	wg.Wait()
+	return got
+}
+
+// same reports whether every element of s is s[0].
+func same[T comparable](s []T) bool {
+	for _, v := range s {
+		if v != s[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Run lets the goroutines stampede three lazy singletons.
+func (onceExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	connects.Store(0)
+
+	// 1. sync.Once guarding a variable.
+	e.Step("once")
+	var once sync.Once
+	var db *database
+	got := stampede(func() *database {
+		once.Do(func() { db = connect() })
+		return db
+	})
+	e.Say("%d goroutines asked for the database at the same time.", callers)
+	e.Value("connects", connects.Load(), "Times connect ran")
+	assert.Equal(check, "once.Do runs connect exactly once", connects.Load(), int64(1))
+	check.That(got[0] != nil && same(got), "every caller gets the same database, fully built")
+
+	// 2. sync.OnceValue: the same, as a function.
+	e.Step("oncevalue")
+	connects.Store(0)
+	getDB := sync.OnceValue(connect)
+	got = stampede(getDB)
+	e.Value("connects", connects.Load(), "Times connect ran")
+	assert.Equal(check, "OnceValue runs connect exactly once", connects.Load(), int64(1))
+	check.That(same(got), "every caller gets the same database")
+
+	// 3. sync.OnceValues with a failing initializer: no second chance.
+	e.Step("error")
+	var attempts atomic.Int64
+	load := sync.OnceValues(func() (*database, error) {
+		attempts.Add(1)
+		return nil, errors.New("connection refused")
+	})
+	errs := stampede(func() error { _, err := load(); return err })
+	e.Value("err", errs[0], "What every caller got")
+	e.Value("attempts", attempts.Load(), "Times the initializer ran")
+	assert.Equal(check, "a failed initializer is not retried", attempts.Load(), int64(1))
+	check.That(errs[0] != nil && same(errs), "every caller gets the same error")
+	e.Warn("A failed Once stays failed. If retrying makes sense, don't use Once for it.")
+	return errors.Join(e.Err(), check.Err())
+}
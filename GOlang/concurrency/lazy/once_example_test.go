@@ -0,0 +1,36 @@
+package lazy
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStampede(t *testing.T) {
+	var n atomic.Int64
+	got := stampede(func() int64 { return n.Add(1) })
+	if len(got) != callers || n.Load() != callers {
+		t.Fatalf("%d results from %d calls, want %d of each", len(got), n.Load(), callers)
+	}
+	seen := map[int64]bool{}
+	for _, v := range got {
+		seen[v] = true
+	}
+	if len(seen) != callers {
+		t.Errorf("%d distinct results, want one per caller", len(seen))
+	}
+	if same(got) {
+		t.Error("same reports distinct results as the same")
+	}
+}
+
+func TestOnceValueStampede(t *testing.T) {
+	connects.Store(0)
+	got := stampede(sync.OnceValue(connect))
+	if n := connects.Load(); n != 1 {
+		t.Errorf("connect ran %d times, want 1", n)
+	}
+	if got[0] == nil || !same(got) {
+		t.Error("the callers didn't all get the same database")
+	}
+}
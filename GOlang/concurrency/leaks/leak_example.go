@@ -0,0 +1,138 @@
+// Package leaks contains examples about goroutine leaks: goroutines that
+// block forever, and how to show that a fix really ends them.
+package leaks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(leakExample{})
+}
+
+// leakExample asks three replicas the same question and takes the first
+// answer. The losers send their answers to a channel nobody reads any
+// more and stay blocked forever, unless the channel has room for them.
+// The leakcheck package counts the goroutines left behind.
+type leakExample struct{}
+
+func (leakExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/leaks/leak_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "a goroutine leak from a first-answer-wins query, found and fixed",
+		Tags:          []string{"concurrency", "goroutines", "leaks", "channels"},
+		Prerequisites: []string{"concurrency/channels/buffered_example", "concurrency/deadlocks/send_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (leakExample) Explain(step string) string {
+	switch step {
+	case "leak":
+		return "first returns as soon as one answer arrives. The two slower replicas still try to send theirs, and nobody will ever receive them."
+	case "fix":
+		return "With a buffer as big as the number of replicas, every send succeeds at once and every goroutine can return."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (leakExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Why doesn't the runtime report the leaked goroutines as a deadlock?",
+			Choices: []string{"they are not blocked", "other goroutines are still running"},
+			Answer:  "other goroutines are still running",
+			Explain: "The runtime only notices when every goroutine is blocked. A leak is a few stuck goroutines in a program that carries on, getting bigger each time it happens.",
+		},
+		{
+			Prompt:  "Besides a buffered channel, what else fixes the leak?",
+			Choices: []string{"a select with a ctx.Done() case next to the send, cancelled once the first answer is in", "closing the channel after the first answer"},
+			Answer:  "a select with a ctx.Done() case next to the send, cancelled once the first answer is in",
+			Explain: "Closing it would make the other senders panic. Giving each send a way to give up works.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (leakExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What is a goroutine leak?",
+			Back:  "A goroutine that can never finish, usually because it is blocked on a channel nobody will use again. It and everything it references stay in memory for the life of the program.",
+		},
+	}
+}
+
+// replicaDelays is how long each replica takes to answer.
+var replicaDelays = []time.Duration{time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond}
+
+// first asks every replica and returns the first answer. Each replica
+// sends its answer on results; first reads only one of them.
+func first(results chan string) string {
+	for i, d := range replicaDelays {
+		go func() {
+			time.Sleep(d)
+			results <- fmt.Sprintf("answer from replica %d", i)
+		}()
+	}
+	return <-results
+}
+
+// report records each leaked goroutine of this package and returns how
+// many there are. Goroutines of other packages (the runner's, say) are
+// none of this example's business.
+func report(e *event.Emitter, leaked []leakcheck.Goroutine) int {
+	n := 0
+	for _, g := range leaked {
+		if !strings.Contains(g.Func, "/leaks.") {
+			continue
+		}
+		n++
+		e.Say("Leaked: a goroutine stuck in [%s] in %s at %s:%d", g.State, filepath.Base(g.Func), filepath.Base(g.File), g.Line)
+	}
+	e.Value("leaked", n, "Goroutines left behind")
+	return n
+}
+
+// Run leaks two goroutines, then doesn't.
+func (leakExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	grace := 50 * time.Millisecond // longer than the slowest replica
+
+	// 1. An unbuffered channel: the losers block forever.
+	e.Step("leak")
+	before := leakcheck.Take()
+	results := make(chan string)
+	e.Value("answer", first(results), "first returned")
+	leaked := report(e, before.Leaked(grace))
+	assert.Equal(check, "the two slower replicas leak", leaked, len(replicaDelays)-1)
+	e.Warn("Nothing crashes and nothing is printed: leaked goroutines just pile up for as long as the program runs.")
+	for range leaked {
+		<-results // let them go, so the leak doesn't outlive the example
+	}
+
+	// 2. A channel with room for every answer: nobody is left behind.
+	e.Step("fix")
+	before = leakcheck.Take()
+	e.Value("answer", first(make(chan string, len(replicaDelays))), "first returned")
+	leaked = report(e, before.Leaked(grace))
+	assert.Equal(check, "with a buffer for every replica, nothing leaks", leaked, 0)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,15 @@
+package locks
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkCond(b *testing.B) { benchlab.Bench(b, condExample{}) }
+
+func BenchmarkRWMutex(b *testing.B) { benchlab.Bench(b, rwMutexExample{}) }
+
+func BenchmarkSyncMap(b *testing.B) { benchlab.Bench(b, syncMapExample{}) }
@@ -0,0 +1,344 @@
+// Package locks contains examples about protecting shared data with the
+// sync package: its locks, its condition variables, and its concurrent map.
+package locks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(condExample{})
+}
+
+// condExample builds a bounded queue from a mutex and two sync.Conds,
+// shows why Wait belongs in a loop, and builds the same queue from a
+// buffered channel.
+type condExample struct{}
+
+func (condExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/locks/cond_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "sync.Cond: Wait, Signal and Broadcast in a bounded queue, vs a buffered channel",
+		Tags:          []string{"concurrency", "sync", "cond", "mutex", "channels"},
+		Prerequisites: []string{"concurrency/locks/rwmutex_example", "concurrency/channels/buffered_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (condExample) Explain(step string) string {
+	switch step {
+	case "queue":
+		return "Get waits on notEmpty while the queue is empty, Put on notFull while it is full; each wakes one goroutine on the other side with Signal."
+	case "loop":
+		return "Waking up only means the condition may have changed. By the time the woken goroutine has the lock again, somebody else may have taken the item."
+	case "broadcast":
+		return "Close has news for every waiting goroutine, not just one, so it uses Broadcast."
+	case "channel":
+		return "A buffered channel is a bounded queue already, with closing built in, and it works in a select."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (condExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Why is cond.Wait called in a for loop rather than after an if?",
+			Choices: []string{"Wait can return before the condition holds", "Wait returns immediately the first time", "the compiler requires it"},
+			Answer:  "Wait can return before the condition holds",
+			Explain: "Between the Signal and the waiter getting the lock back, another goroutine can make the condition false again; Broadcast wakes waiters that have nothing to do. Recheck after every wake-up.",
+		},
+		{
+			Prompt:  "What does cond.Wait do with the cond's lock?",
+			Choices: []string{"nothing", "unlocks it while waiting and locks it again before returning", "locks it"},
+			Answer:  "unlocks it while waiting and locks it again before returning",
+			Explain: "The caller must hold c.L; Wait releases it so others can change the condition, and returns with it held.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (condExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "The sync.Cond wait idiom",
+			Back:  "c.L.Lock(); for !condition() { c.Wait() }; /* use the state */; c.L.Unlock()",
+		},
+		{
+			Front: "Signal vs Broadcast",
+			Back:  "Signal wakes one waiting goroutine, enough when one change lets one waiter proceed. Broadcast wakes them all, e.g. when closing.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench concurrency".
+func (condExample) Experiments() []benchlab.Experiment {
+	return []benchlab.Experiment{{
+		Name: "a bounded queue of 16 between a producer and a consumer",
+		Approaches: []benchlab.Approach{
+			{Name: "sync.Cond", Bench: func(b *testing.B) { pass(b, newCondQueue[int](16)) }},
+			{Name: "buffered channel", Bench: func(b *testing.B) { pass(b, newChanQueue[int](16)) }},
+		},
+		Guidance: "Both park a goroutine when the queue is full or empty and wake it " +
+			"later, and cost about the same. The channel is rarely slower and " +
+			"does more: it can be used in a select, with a timeout or a context. " +
+			"sync.Cond is for conditions that aren't a queue, over state you " +
+			"already guard with a mutex.",
+	}}
+}
+
+// queue is a bounded FIFO queue. Get returns false once the queue is
+// closed and empty.
+type queue[T any] interface {
+	Put(T)
+	Get() (T, bool)
+	Close()
+}
+
+// pass moves b.N items through q.
+func pass(b *testing.B, q queue[int]) {
+	go func() {
+		for i := range b.N {
+			q.Put(i)
+		}
+		q.Close()
+	}()
+	for {
+		if _, ok := q.Get(); !ok {
+			return
+		}
+	}
+}
+
+// condQueue is a queue built from a mutex and two condition variables
+// using it: one for "not empty any more", one for "not full any more".
+type condQueue[T any] struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []T
+	size     int
+	closed   bool
+	most     int // longest the queue has been
+}
+
+func newCondQueue[T any](size int) *condQueue[T] {
+	q := &condQueue[T]{size: size}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	return q
+}
+
+// Put waits for room and appends v. Putting to a closed queue panics,
+// like sending on a closed channel.
+func (q *condQueue[T]) Put(v T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == q.size && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		panic("put to closed queue")
+	}
+	q.items = append(q.items, v)
+	q.most = max(q.most, len(q.items))
+	q.notEmpty.Signal()
+}
+
+// Get waits for an item and removes it.
+func (q *condQueue[T]) Get() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	v := q.items[0]
+	q.items = q.items[1:]
+	q.notFull.Signal()
+	return v, true
+}
+
+// Close wakes every waiting Get, and every waiting Put so that it can
+// panic: neither has anything left to wait for.
+func (q *condQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+// chanQueue is the same queue as a buffered channel.
+type chanQueue[T any] chan T
+
+func newChanQueue[T any](size int) chanQueue[T] { return make(chanQueue[T], size) }
+
+func (q chanQueue[T]) Put(v T)        { q <- v }
+func (q chanQueue[T]) Get() (T, bool) { v, ok := <-q; return v, ok }
+func (q chanQueue[T]) Close()         { close(q) }
+
+// exchange has 3 producers put 20 numbers each on q while 2 consumers get
+// them, and returns every number received, sorted.
+func exchange(q queue[int]) []int {
+	var producers, consumers sync.WaitGroup
+	for p := range 3 {
+		producers.Go(func() {
+			for i := range 20 {
+				q.Put(p*20 + i)
+			}
+		})
+	}
+	var mu sync.Mutex
+	var got []int
+	for range 2 {
+		consumers.Go(func() {
+			for {
+				v, ok := q.Get()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				got = append(got, v)
+				mu.Unlock()
+			}
+		})
+	}
+	producers.Wait()
+	q.Close()
+	consumers.Wait()
+	slices.Sort(got)
+	return got
+}
+
+// wakeUps has waiters goroutines wait on an empty queue, each checking
+// the queue once after waking up (an if instead of a for), then puts one
+// item and broadcasts. It returns how many woke up to an empty queue.
+func wakeUps(waiters int) int {
+	var (
+		mu      sync.Mutex
+		cond    = sync.NewCond(&mu)
+		items   []int
+		waiting int
+		empty   int
+		wg      sync.WaitGroup
+	)
+	for range waiters {
+		wg.Go(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if len(items) == 0 { // wrong: should be for
+				waiting++
+				cond.Wait()
+			}
+			if len(items) == 0 {
+				empty++ // would take from an empty queue
+				return
+			}
+			items = items[1:]
+		})
+	}
+	// Wait registers the goroutine with the cond before it unlocks mu, so
+	// once all of them have counted themselves in, all of them are waiting.
+	for {
+		mu.Lock()
+		if waiting == waiters {
+			break
+		}
+		mu.Unlock()
+		runtime.Gosched()
+	}
+	items = append(items, 1)
+	cond.Broadcast()
+	mu.Unlock()
+	wg.Wait()
+	return empty
+}
+
+// Run passes numbers through both queues and looks at how a waiting
+// goroutine wakes up.
+func (condExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	want := make([]int, 60)
+	for i := range want {
+		want[i] = i
+	}
+
+	// 1. A bounded queue of 4 built on sync.Cond.
+	e.Step("queue")
+	e.Say("3 producers put 20 numbers each on a queue of 4; 2 consumers get them.")
+	cq := newCondQueue[int](4)
+	got := exchange(cq)
+	e.Value("received", len(got), "Numbers received")
+	e.Varying("most", cq.most, "Longest the queue got")
+	assert.Equal(check, "every number arrives exactly once", fmt.Sprint(got), fmt.Sprint(want))
+	check.That(cq.most <= 4, "Put waits while the queue is full")
+
+	// 2. Checking the condition once after Wait is not enough.
+	e.Step("loop")
+	empty := wakeUps(3)
+	e.Value("empty", empty, "Waiters that woke up to an empty queue, with if")
+	assert.Equal(check, "Broadcast wakes every waiter, but only one finds the item", empty, 2)
+	e.Warn("Always wait in a loop: for !condition { c.Wait() }. Even after a Signal, another goroutine can take the item before the woken one gets the lock back.")
+
+	// 3. Closing: Broadcast wakes every consumer waiting on an empty queue.
+	e.Step("broadcast")
+	cq = newCondQueue[int](4)
+	var wg sync.WaitGroup
+	results := make(chan bool, 3)
+	for range 3 {
+		wg.Go(func() {
+			_, ok := cq.Get()
+			results <- ok
+		})
+	}
+	time.Sleep(10 * time.Millisecond) // let them wait (they are fine either way)
+	cq.Close()
+	wg.Wait()
+	close(results)
+	woken := 0
+	for ok := range results {
+		check.That(!ok, "Get on a closed, empty queue reports false")
+		woken++
+	}
+	e.Value("woken", woken, "Consumers that returned after Close")
+	assert.Equal(check, "Close wakes every waiting consumer", woken, 3)
+
+	// 4. The same queue as a buffered channel.
+	e.Step("channel")
+	got = exchange(newChanQueue[int](4))
+	e.Value("received", len(got), "Numbers received through a chan int of capacity 4")
+	assert.Equal(check, "the channel queue delivers the same numbers", fmt.Sprint(got), fmt.Sprint(want))
+	ch := newChanQueue[int](4)
+	var timedOut bool
+	select {
+	case <-ch:
+	case <-time.After(10 * time.Millisecond):
+		timedOut = true
+	}
+	e.Value("timedOut", timedOut, "Get with a 10ms timeout, on the empty channel")
+	check.That(timedOut, "a channel receive can be given up on in a select")
+	e.Say("cond.Wait can't be given a timeout or a context; reach for sync.Cond only when a channel doesn't fit the condition.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,252 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(rwMutexExample{})
+}
+
+// rwMutexExample protects the same table of numbers with a sync.Mutex and
+// with a sync.RWMutex, and watches how many goroutines get in at once.
+type rwMutexExample struct{}
+
+func (rwMutexExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/locks/rwmutex_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "sync.Mutex vs sync.RWMutex: concurrent readers, exclusive writers",
+		Tags:          []string{"concurrency", "sync", "mutex", "rwmutex"},
+		Prerequisites: []string{"concurrency/races/racy_counter"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (rwMutexExample) Explain(step string) string {
+	switch step {
+	case "mutex":
+		return "A Mutex lets one goroutine in at a time, even if all it wants is to read."
+	case "rwmutex":
+		return "An RWMutex lets any number of readers in together with RLock; only Lock, for writing, needs it to itself."
+	case "writer":
+		return "While a writer holds Lock, no reader can hold RLock: it never sees a half-written table."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (rwMutexExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Three goroutines call RLock on the same RWMutex. How many hold it at once?",
+			Choices: []string{"1", "3"},
+			Answer:  "3",
+			Explain: "Read locks are shared. Only Lock excludes everybody else.",
+		},
+		{
+			Prompt:  "When is an RWMutex slower than a plain Mutex?",
+			Choices: []string{"never", "when writes are frequent or critical sections are tiny", "when there are many readers"},
+			Answer:  "when writes are frequent or critical sections are tiny",
+			Explain: "It does more bookkeeping per lock; that only pays off when many readers would otherwise wait for each other.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (rwMutexExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Can a goroutine holding RLock upgrade to Lock?",
+			Back:  "No. Calling Lock while holding RLock deadlocks: Lock waits for every reader, including itself. Release the read lock first.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench concurrency".
+func (rwMutexExample) Experiments() []benchlab.Experiment {
+	guidance := "Readers of the RWMutex run side by side, so with many cores and " +
+		"mostly reads it wins, and the more so the longer each read holds the lock. " +
+		"With frequent writes everybody queues behind the writers anyway, and the " +
+		"RWMutex's extra bookkeeping makes it the slower one. On a single core " +
+		"(GOMAXPROCS=1) nothing runs side by side and the plain Mutex is as good or better."
+	return []benchlab.Experiment{
+		{
+			Name: "read-heavy: 1 write per 100 operations",
+			Approaches: []benchlab.Approach{
+				{Name: "Mutex", Bench: func(b *testing.B) { workload(b, new(mutexTable), 100) }},
+				{Name: "RWMutex", Bench: func(b *testing.B) { workload(b, new(rwTable), 100) }},
+			},
+			Guidance: guidance,
+		},
+		{
+			Name: "write-heavy: 1 write per 2 operations",
+			Approaches: []benchlab.Approach{
+				{Name: "Mutex", Bench: func(b *testing.B) { workload(b, new(mutexTable), 2) }},
+				{Name: "RWMutex", Bench: func(b *testing.B) { workload(b, new(rwTable), 2) }},
+			},
+			Guidance: guidance,
+		},
+	}
+}
+
+// table is shared data: a read sums it, a write changes one entry.
+type table interface {
+	sum() int
+	set(i, v int)
+}
+
+// mutexTable guards its numbers with a Mutex.
+type mutexTable struct {
+	mu   sync.Mutex
+	nums [64]int
+}
+
+func (t *mutexTable) sum() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total := 0
+	for _, n := range t.nums {
+		total += n
+	}
+	return total
+}
+
+func (t *mutexTable) set(i, v int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nums[i%len(t.nums)] = v
+}
+
+// rwTable guards its numbers with an RWMutex.
+type rwTable struct {
+	mu   sync.RWMutex
+	nums [64]int
+}
+
+func (t *rwTable) sum() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	total := 0
+	for _, n := range t.nums {
+		total += n
+	}
+	return total
+}
+
+func (t *rwTable) set(i, v int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nums[i%len(t.nums)] = v
+}
+
+// workload uses t from several goroutines, writing once every writeEvery
+// operations and reading otherwise.
+func workload(b *testing.B, t table, writeEvery int) {
+	b.SetParallelism(4)
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if i%writeEvery == 0 {
+				t.set(i, i)
+			} else {
+				t.sum()
+			}
+		}
+	})
+}
+
+// occupancy tracks how many goroutines are inside a critical section and
+// the most there ever were.
+type occupancy struct {
+	inside, most atomic.Int32
+}
+
+// enter notes a goroutine coming in and returns how many are inside now.
+func (o *occupancy) enter() int32 {
+	n := o.inside.Add(1)
+	for m := o.most.Load(); n > m && !o.most.CompareAndSwap(m, n); m = o.most.Load() {
+	}
+	return n
+}
+
+func (o *occupancy) leave() { o.inside.Add(-1) }
+
+// readers runs n goroutines that each hold a lock for a moment, using
+// lock and unlock, and returns the most that held it at once.
+func readers(n int, lock, unlock func()) int32 {
+	var o occupancy
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() {
+			lock()
+			defer unlock()
+			o.enter()
+			defer o.leave()
+			time.Sleep(10 * time.Millisecond)
+		})
+	}
+	wg.Wait()
+	return o.most.Load()
+}
+
+// Run lets 3 readers at the data with each lock, then a writer.
+func (rwMutexExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	const n = 3
+
+	// 1. Mutex: the readers take turns.
+	e.Step("mutex")
+	var mu sync.Mutex
+	most := readers(n, mu.Lock, mu.Unlock)
+	e.Value("most", most, "Most readers inside at once with Mutex.Lock")
+	assert.Equal(check, "a Mutex lets one goroutine in at a time", most, 1)
+
+	// 2. RWMutex: the readers share it.
+	e.Step("rwmutex")
+	var rw sync.RWMutex
+	most = readers(n, rw.RLock, rw.RUnlock)
+	e.Value("most", most, "Most readers inside at once with RWMutex.RLock")
+	assert.Equal(check, "RLock lets every reader in together", most, n)
+
+	// 3. A writer: while it holds Lock, no reader is inside.
+	e.Step("writer")
+	var o occupancy
+	var wg sync.WaitGroup
+	var sawReader atomic.Bool
+	var entered atomic.Int32
+	rw.Lock()
+	for range n {
+		wg.Go(func() {
+			rw.RLock()
+			defer rw.RUnlock()
+			o.enter()
+			defer o.leave()
+			entered.Add(1)
+		})
+	}
+	time.Sleep(10 * time.Millisecond) // give the readers time to queue up
+	sawReader.Store(o.inside.Load() > 0)
+	rw.Unlock()
+	wg.Wait()
+	e.Value("sawReader", sawReader.Load(), "A reader got in while the writer held Lock")
+	check.That(!sawReader.Load(), "Lock excludes the readers")
+	e.Value("entered", entered.Load(), "Readers that got in once the writer unlocked")
+	assert.Equal(check, "every reader gets in after Unlock", entered.Load(), n)
+	e.Say("Run \"concepts bench concurrency\" to see when the RWMutex pays off.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,239 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(syncMapExample{})
+}
+
+// syncMapExample shares a map between goroutines twice: as a plain map
+// behind a mutex, and as a sync.Map. Its benchmarks show which one wins
+// for which mix of reads and writes.
+type syncMapExample struct{}
+
+func (syncMapExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/locks/syncmap_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "sync.Map vs a map guarded by a mutex, and when each is appropriate",
+		Tags:          []string{"concurrency", "sync", "sync-map", "maps", "mutex"},
+		Prerequisites: []string{"concurrency/locks/rwmutex_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (syncMapExample) Explain(step string) string {
+	switch step {
+	case "guarded":
+		return "A plain map must never be written while anything else reads or writes it; the mutex makes every access take its turn."
+	case "syncmap":
+		return "sync.Map does its own locking, and LoadOrStore checks and inserts in one step."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (syncMapExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Which workload is sync.Map designed for?",
+			Choices: []string{"keys written once and then read many times, or goroutines using disjoint keys", "frequent updates of the same keys", "any shared map"},
+			Answer:  "keys written once and then read many times, or goroutines using disjoint keys",
+			Explain: "Those are the two cases its documentation names. Otherwise a map with a mutex is usually as fast and keeps its types.",
+		},
+		{
+			Prompt:  "What happens when two goroutines write to a plain map at the same time?",
+			Choices: []string{"the last write wins", "the runtime may stop the program with \"concurrent map writes\""},
+			Answer:  "the runtime may stop the program with \"concurrent map writes\"",
+			Explain: "It is a fatal error, not a panic: recover can't catch it.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench concurrency".
+func (syncMapExample) Experiments() []benchlab.Experiment {
+	guidance := "sync.Map shines when keys are written once and then only read, and " +
+		"when goroutines work on disjoint sets of keys: reads then take no lock at " +
+		"all. When the same keys are written often it has to fall back to its own " +
+		"internal mutex and does more work than a plain map would, and it costs " +
+		"you the types: every Load returns any. Start with a map and a mutex; " +
+		"switch to sync.Map when a profile shows the lock is contended and the " +
+		"workload is one of its two."
+	var experiments []benchlab.Experiment
+	for _, mix := range []struct {
+		name       string
+		writeEvery int
+	}{
+		{"read-mostly: 1 write per 1000 operations", 1000},
+		{"write-heavy: 1 write per 2 operations", 2},
+	} {
+		experiments = append(experiments, benchlab.Experiment{
+			Name: mix.name,
+			Approaches: []benchlab.Approach{
+				{Name: "sync.Map", Bench: func(b *testing.B) { mapWorkload(b, new(syncMap), mix.writeEvery) }},
+				{Name: "map + Mutex", Bench: func(b *testing.B) { mapWorkload(b, newMutexMap(), mix.writeEvery) }},
+				{Name: "map + RWMutex", Bench: func(b *testing.B) { mapWorkload(b, newRWMutexMap(), mix.writeEvery) }},
+			},
+			Guidance: guidance,
+		})
+	}
+	return experiments
+}
+
+// sharedMap is a map from ints to ints that goroutines can share.
+type sharedMap interface {
+	load(k int) (int, bool)
+	store(k, v int)
+}
+
+// mutexMap is a plain map behind a Mutex.
+type mutexMap struct {
+	mu sync.Mutex
+	m  map[int]int
+}
+
+func newMutexMap() *mutexMap { return &mutexMap{m: map[int]int{}} }
+
+func (m *mutexMap) load(k int) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *mutexMap) store(k, v int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m[k] = v
+}
+
+// rwMutexMap is a plain map behind an RWMutex.
+type rwMutexMap struct {
+	mu sync.RWMutex
+	m  map[int]int
+}
+
+func newRWMutexMap() *rwMutexMap { return &rwMutexMap{m: map[int]int{}} }
+
+func (m *rwMutexMap) load(k int) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *rwMutexMap) store(k, v int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m[k] = v
+}
+
+// syncMap is a sync.Map with the type assertions in one place.
+type syncMap struct{ m sync.Map }
+
+func (m *syncMap) load(k int) (int, bool) {
+	v, ok := m.m.Load(k)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (m *syncMap) store(k, v int) { m.m.Store(k, v) }
+
+// keys is how many keys the benchmarks use.
+const keys = 1000
+
+// mapWorkload fills m with keys entries and then uses it from several
+// goroutines, writing once every writeEvery operations and reading
+// otherwise.
+func mapWorkload(b *testing.B, m sharedMap, writeEvery int) {
+	for k := range keys {
+		m.store(k, k)
+	}
+	b.SetParallelism(4)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			k := i * 7919 % keys
+			if i%writeEvery == 0 {
+				m.store(k, i)
+			} else {
+				m.load(k)
+			}
+		}
+	})
+}
+
+// Run fills both kinds of map from several goroutines, then races them
+// to insert the same key with LoadOrStore.
+func (syncMapExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	const writers, each = 8, 100
+
+	// 1. A map behind a mutex, written by 8 goroutines at once.
+	e.Step("guarded")
+	guarded := newMutexMap()
+	var wg sync.WaitGroup
+	for g := range writers {
+		wg.Go(func() {
+			for i := range each {
+				guarded.store(g*each+i, i)
+			}
+		})
+	}
+	wg.Wait()
+	e.Value("len", len(guarded.m), "Entries in the mutex-guarded map")
+	assert.Equal(check, "no write to the guarded map is lost", len(guarded.m), writers*each)
+
+	// 2. The same with a sync.Map; Range is how to count its entries.
+	e.Step("syncmap")
+	var sm sync.Map
+	for g := range writers {
+		wg.Go(func() {
+			for i := range each {
+				sm.Store(g*each+i, i)
+			}
+		})
+	}
+	wg.Wait()
+	n := 0
+	sm.Range(func(_, _ any) bool { n++; return true })
+	e.Value("n", n, "Entries in the sync.Map")
+	assert.Equal(check, "no write to the sync.Map is lost", n, writers*each)
+
+	// LoadOrStore: of 100 goroutines storing under the same key, exactly
+	// one stores; the others load the winner's value.
+	var stored atomic.Int32
+	for g := range 100 {
+		wg.Go(func() {
+			if _, loaded := sm.LoadOrStore("winner", g); !loaded {
+				stored.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+	v, _ := sm.Load("winner")
+	_, isInt := v.(int)
+	e.Value("stored", stored.Load(), "Goroutines whose LoadOrStore stored")
+	assert.Equal(check, "LoadOrStore stores exactly once", stored.Load(), int32(1))
+	check.That(isInt, "Load returns any; the int has to be asserted back out")
+	e.Warn("sync.Map's keys and values are any: the compiler no longer checks their types.")
+	e.Say("Run \"concepts bench concurrency\" to see when sync.Map is the faster one.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,157 @@
+// Package memorymodel contains examples about the Go memory model: the
+// rules for when a write in one goroutine is guaranteed to be seen by a
+// read in another. The short version, from https://go.dev/ref/mem: only
+// when the write happens before the read, and only synchronization
+// (channels, locks, atomics and the sync package) creates such an order
+// between goroutines.
+package memorymodel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(busyWaitExample{})
+	isolate.Register("memorymodel/busywait", spinOnFlag)
+}
+
+// busyWaitExample waits for another goroutine by spinning on a plain
+// bool, which the memory model doesn't promise ever to work, then with an
+// atomic.Bool, which it does. The broken version runs in a process of its
+// own (see the isolate package), so that when it hangs, only that process
+// has to be killed.
+type busyWaitExample struct{}
+
+func (busyWaitExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/memorymodel/busywait_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "spinning on a plain bool flag is broken; an atomic.Bool creates the happens-before edge",
+		Tags:          []string{"concurrency", "memory-model", "data-race", "atomics"},
+		Prerequisites: []string{"concurrency/memorymodel/happens_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (busyWaitExample) Explain(step string) string {
+	switch step {
+	case "broken":
+		return "Nothing orders the flag's write before the loop's reads, so the memory model promises neither that the loop ends nor that msg is set when it does."
+	case "starved":
+		return "With one thread and no preemption, the goroutine that would set the flag never even gets to run: the loop gives the scheduler no reason to stop it."
+	case "atomic":
+		return "An atomic store that is observed by an atomic load happens before it, so the loop must end, and so does everything written before the store become visible."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (busyWaitExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "\"for !ready {}\" ended and then printed msg. Is msg guaranteed to be \"hello\"?",
+			Choices: []string{"yes, ready was set after msg", "no, there is no happens-before edge"},
+			Answer:  "no, there is no happens-before edge",
+			Explain: "Without synchronization, the compiler and the CPU may reorder the two writes or the reads, and another goroutine may see them in any order, or not at all.",
+		},
+		{
+			Prompt:  "What is the smallest fix for the spinning flag?",
+			Choices: []string{"make it an atomic.Bool", "add time.Sleep to the loop", "declare it volatile"},
+			Answer:  "make it an atomic.Bool",
+			Explain: "Sleeping changes the timing but not the guarantees, and Go has no volatile. (Better still, wait on a channel and don't spin at all.)",
+		},
+	}
+}
+
+var (
+	ready bool
+	msg   string
+)
+
+// spinOnFlag is the broken program: a goroutine sets msg, then ready,
+// while main spins until ready is true and then prints msg. It is a data
+// race on both variables.
+func spinOnFlag() {
+	go func() {
+		msg = "hello"
+		ready = true
+	}()
+	for !ready {
+	}
+	fmt.Println(msg)
+}
+
+// spinAtomic is the fix, and returns what it saw of msg.
+func spinAtomic() string {
+	var (
+		done atomic.Bool
+		m    string
+	)
+	go func() {
+		m = "hello"
+		done.Store(true)
+	}()
+	for !done.Load() {
+		runtime.Gosched() // let the writer run, even where nothing preempts
+	}
+	return m
+}
+
+// outcome describes how a run of spinOnFlag ended.
+func outcome(res isolate.Result, timeout time.Duration) string {
+	if res.TimedOut {
+		return fmt.Sprintf("hung, killed after %v", timeout)
+	}
+	return fmt.Sprintf("ended, printing %q", strings.TrimSpace(string(res.Stdout)))
+}
+
+// Run runs the broken version twice in another process, then the fix.
+func (busyWaitExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	const timeout = 2 * time.Second
+
+	// 1. The broken loop on this machine, as it comes.
+	e.Step("broken")
+	res, err := isolate.Run(ctx, "memorymodel/busywait", timeout)
+	if err != nil {
+		return err
+	}
+	// Today's compiler reloads ready on every iteration and today's
+	// scheduler preempts the loop, so it usually ends; neither is a
+	// promise. A compiler may keep ready in a register and spin for ever.
+	e.Varying("outcome", outcome(res, timeout), "for !ready {}")
+	e.Warn("Working today is not the same as correct: ready and msg are raced on, and any build with -race says so.")
+
+	// 2. The same program on one thread, without asynchronous preemption.
+	e.Step("starved")
+	res, err = isolate.Run(ctx, "memorymodel/busywait", timeout, "GOMAXPROCS=1", "GODEBUG=asyncpreemptoff=1")
+	if err != nil {
+		return err
+	}
+	e.Value("outcome", outcome(res, timeout), "for !ready {}, with GOMAXPROCS=1 and GODEBUG=asyncpreemptoff=1")
+	check.That(res.TimedOut, "with nothing to make it stop, the loop spins for ever")
+
+	// 3. The flag as an atomic.Bool.
+	e.Step("atomic")
+	got := spinAtomic()
+	e.Value("msg", got, "msg, after the loop on done.Load()")
+	assert.Equal(check, "the atomic store happens before the load that sees it, and so does the write to msg", got, "hello")
+	e.Say("Spinning still wastes a core while it waits; a channel or a sync.WaitGroup waits without it.")
+	return errors.Join(e.Err(), check.Err())
+}
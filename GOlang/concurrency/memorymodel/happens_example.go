@@ -0,0 +1,183 @@
+package memorymodel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(happensExample{})
+}
+
+// happensExample publishes a value from one goroutine to another over
+// each kind of happens-before edge the memory model defines. Each version
+// is correct, which the race detector confirms: run the golden tests with
+// -race and it has nothing to say about this file.
+type happensExample struct{}
+
+func (happensExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/memorymodel/happens_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "happens-before edges: channel send and receive, unlock and lock, atomic store and load",
+		Tags:          []string{"concurrency", "memory-model", "channels", "mutex", "atomics"},
+		Prerequisites: []string{"concurrency/races/racy_counter", "concurrency/atomics/pointer_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step,
+// each the rule from the memory model that the step relies on.
+func (happensExample) Explain(step string) string {
+	switch step {
+	case "send":
+		return "\"A send on a channel is synchronized before the completion of the corresponding receive from that channel.\""
+	case "close":
+		return "\"The closing of a channel is synchronized before a receive that returns because the channel is closed.\""
+	case "unbuffered":
+		return "\"A receive from an unbuffered channel is synchronized before the completion of the corresponding send on that channel.\""
+	case "mutex":
+		return "\"For any sync.Mutex l and n < m, call n of l.Unlock() is synchronized before call m of l.Lock() returns.\""
+	case "atomic":
+		return "\"If the effect of an atomic operation A is observed by atomic operation B, then A is synchronized before B.\""
+	case "waitgroup":
+		return "Done is synchronized before the Wait that it unblocks returns: everything a goroutine did before Done is visible after Wait."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (happensExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "A goroutine sets x = 1 and then sends on ch. Another receives from ch and reads x. What can it read?",
+			Choices: []string{"only 1", "0 or 1"},
+			Answer:  "only 1",
+			Explain: "x = 1 comes before the send in its goroutine, the send is synchronized before the receive, and the receive comes before the read: happens-before is transitive.",
+		},
+		{
+			Prompt:  "With an unbuffered channel, the receiver writes y = 2 before receiving. Does the sender see y == 2 after its send returns?",
+			Choices: []string{"yes", "no guarantee"},
+			Answer:  "yes",
+			Explain: "On an unbuffered channel, the receive is synchronized before the send completes, so the edge also runs backwards. Not so on a buffered one.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (happensExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "When is a read in one goroutine guaranteed to see a write in another?",
+			Back:  "When the write happens before the read: a chain of program order within goroutines and synchronizing operations (channels, locks, atomics, sync) between them.",
+		},
+	}
+}
+
+// message is what gets published: several fields, written with plain
+// assignments before the synchronizing operation.
+type message struct {
+	text  string
+	count int
+}
+
+// Run publishes a message over each edge.
+func (happensExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	want := message{"hello", 42}
+	published := func(step string, got message) {
+		e.Value("got", got, "Seen by the other goroutine")
+		assert.Equal(check, step+": every write before the edge is visible after it", got, want)
+	}
+
+	// 1. Write, then send; receive, then read.
+	e.Step("send")
+	var m message
+	ch := make(chan struct{}, 1)
+	go func() {
+		m = message{"hello", 42}
+		ch <- struct{}{}
+	}()
+	<-ch
+	published("send", m)
+
+	// 2. Write, then close; the receive that sees the close, then read.
+	e.Step("close")
+	m = message{}
+	done := make(chan struct{})
+	go func() {
+		m = message{"hello", 42}
+		close(done)
+	}()
+	<-done
+	published("close", m)
+
+	// 3. The other direction: on an unbuffered channel, the receiver's
+	// writes before its receive are visible to the sender after its send.
+	e.Step("unbuffered")
+	m = message{}
+	unbuf := make(chan struct{})
+	go func() {
+		m = message{"hello", 42}
+		<-unbuf
+	}()
+	unbuf <- struct{}{}
+	published("unbuffered", m)
+
+	// 4. Write under the lock, unlock; lock, then read.
+	e.Step("mutex")
+	m = message{}
+	var mu sync.Mutex
+	var set bool
+	go func() {
+		mu.Lock()
+		defer mu.Unlock()
+		m = message{"hello", 42}
+		set = true
+	}()
+	for {
+		mu.Lock()
+		if set {
+			mu.Unlock()
+			break
+		}
+		mu.Unlock()
+		runtime.Gosched()
+	}
+	published("mutex", m)
+
+	// 5. Plain writes, then an atomic store; the atomic load that sees it,
+	// then plain reads.
+	e.Step("atomic")
+	m = message{}
+	var flag atomic.Bool
+	go func() {
+		m = message{"hello", 42}
+		flag.Store(true)
+	}()
+	for !flag.Load() {
+		runtime.Gosched() // give the writer a turn
+	}
+	published("atomic", m)
+
+	// 6. Write, then Done; Wait, then read.
+	e.Step("waitgroup")
+	m = message{}
+	var wg sync.WaitGroup
+	wg.Go(func() { m = message{"hello", 42} })
+	wg.Wait()
+	published("waitgroup", m)
+	e.Say("In each case the reader only looks after seeing the synchronizing operation; that is what makes the plain writes before it safe to read.")
+	return errors.Join(e.Err(), check.Err())
+}
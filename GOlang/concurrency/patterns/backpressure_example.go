@@ -0,0 +1,229 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(backpressureExample{})
+}
+
+// backpressureExample connects fast producers to slow consumers through a
+// small buffered channel, three times: once letting the producers block
+// when it is full, once dropping the new item, and once dropping the
+// oldest queued item to make room.
+type backpressureExample struct{}
+
+func (backpressureExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/patterns/backpressure_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "fast producers, slow consumers: block, drop newest or drop oldest when the buffer is full",
+		Tags:          []string{"concurrency", "channels", "backpressure", "producer-consumer"},
+		Prerequisites: []string{"concurrency/patterns/fan_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (backpressureExample) Explain(step string) string {
+	switch step {
+	case "block":
+		return "A plain send waits while the buffer is full, so the producers slow down to the consumers' pace: that is backpressure."
+	case "drop-newest":
+		return "A send in a select with a default never waits: when the buffer is full, the new item is thrown away."
+	case "drop-oldest":
+		return "When the buffer is full, the producer receives the oldest item itself to make room, so what gets through is as recent as possible."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (backpressureExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Producers make items faster than consumers can handle them, and no item may be lost. Which strategy?",
+			Choices: []string{"block", "drop newest", "drop oldest"},
+			Answer:  "block",
+			Explain: "Only blocking keeps every item; the price is that the producers run no faster than the consumers.",
+		},
+		{
+			Prompt:  "A sensor sends readings faster than the display can draw them. Which strategy keeps the display up to date?",
+			Choices: []string{"block", "drop newest", "drop oldest"},
+			Answer:  "drop oldest",
+			Explain: "Blocking would show ever older readings, and dropping the newest throws away exactly the ones that matter.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (backpressureExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How do you send on a channel without waiting when it is full?",
+			Back:  "select { case ch <- v: default: /* full: drop v, or make room */ }",
+		},
+		{
+			Front: "What is backpressure?",
+			Back:  "A full queue making its producers wait, so that a slow consumer slows everything upstream instead of letting work pile up without bound.",
+		},
+	}
+}
+
+const (
+	producers     = 3
+	perProducer   = 10
+	consumers     = 2
+	bufferSize    = 4
+	produceEvery  = time.Millisecond
+	consumeTakes  = 5 * time.Millisecond
+	totalProduced = producers * perProducer
+)
+
+// item is what the producers make.
+type item struct {
+	id      int
+	created time.Time
+}
+
+// strategy sends it on ch, or doesn't, and returns how many items were
+// dropped to do so.
+type strategy func(ch chan item, it item) (dropped int)
+
+// block waits for room.
+func block(ch chan item, it item) int {
+	ch <- it
+	return 0
+}
+
+// dropNewest drops it if ch is full.
+func dropNewest(ch chan item, it item) int {
+	select {
+	case ch <- it:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// dropOldest receives the oldest item from ch while ch is full, until it
+// fits. Another producer can fill the room first, hence the loop.
+func dropOldest(ch chan item, it item) int {
+	dropped := 0
+	for {
+		select {
+		case ch <- it:
+			return dropped
+		default:
+		}
+		select {
+		case <-ch:
+			dropped++
+		default:
+		}
+	}
+}
+
+// metrics are the measurements of one run.
+type metrics struct {
+	delivered int
+	dropped   int
+	producing time.Duration // until every producer was done
+	latency   time.Duration // average time from creation to consumption
+}
+
+// produce runs the producers and consumers with send until every item
+// has been either consumed or dropped.
+func produce(send strategy) metrics {
+	ch := make(chan item, bufferSize)
+	var dropped atomic.Int64
+	began := time.Now()
+	var prod sync.WaitGroup
+	for p := range producers {
+		prod.Go(func() {
+			for i := range perProducer {
+				dropped.Add(int64(send(ch, item{id: p*perProducer + i, created: time.Now()})))
+				time.Sleep(produceEvery)
+			}
+		})
+	}
+	var (
+		mu     sync.Mutex
+		m      metrics
+		waited time.Duration
+	)
+	var cons sync.WaitGroup
+	for range consumers {
+		cons.Go(func() {
+			for it := range ch {
+				time.Sleep(consumeTakes)
+				mu.Lock()
+				m.delivered++
+				waited += time.Since(it.created)
+				mu.Unlock()
+			}
+		})
+	}
+	prod.Wait()
+	m.producing = time.Since(began)
+	close(ch)
+	cons.Wait()
+	m.dropped = int(dropped.Load())
+	if m.delivered > 0 {
+		m.latency = (waited / time.Duration(m.delivered)).Round(time.Millisecond)
+	}
+	m.producing = m.producing.Round(time.Millisecond)
+	return m
+}
+
+// Run tries each strategy on the same workload and prints its metrics.
+func (backpressureExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	show := func(m metrics) {
+		e.Value("produced", totalProduced, "Produced")
+		e.Varying("delivered", m.delivered, "Delivered")
+		e.Varying("dropped", m.dropped, "Dropped")
+		e.Varying("producing", m.producing, "Time the producers took")
+		e.Varying("latency", m.latency, "Average time an item waited")
+		assert.Equal(check, "every item is either delivered or dropped", m.delivered+m.dropped, totalProduced)
+	}
+
+	// 1. Block: the producers wait for the consumers.
+	e.Step("block")
+	e.Say("%d producers make an item every %v; %d consumers take %v per item; the buffer holds %d.",
+		producers, produceEvery, consumers, consumeTakes, bufferSize)
+	m := produce(block)
+	show(m)
+	assert.Equal(check, "blocking loses nothing", m.dropped, 0)
+	// The consumers can only have taken so many items while the producers
+	// were still going, and the rest had to fit in the buffer.
+	least := time.Duration((totalProduced-bufferSize-consumers)/consumers) * consumeTakes
+	check.That(m.producing >= least, "the producers are held back to the consumers' pace")
+
+	// 2. Drop the newest item when the buffer is full.
+	e.Step("drop-newest")
+	m = produce(dropNewest)
+	show(m)
+	check.That(m.dropped > 0, "producers faster than the consumers overflow the buffer")
+
+	// 3. Drop the oldest queued item to make room.
+	e.Step("drop-oldest")
+	m = produce(dropOldest)
+	show(m)
+	check.That(m.dropped > 0, "producers faster than the consumers overflow the buffer")
+	e.Say("Dropping the newest keeps the first items; dropping the oldest, the latest ones, which therefore waited less.")
+	e.Warn("An unbounded queue is not a fourth strategy: it only postpones the choice until memory runs out.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,175 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(&fanExample{workers: 4})
+}
+
+// fanExample fans jobs out to several goroutines and fans their results
+// back into one channel, and times that against doing the jobs one after
+// the other. Try
+//
+//	concepts run concurrency/patterns/fan_example -- -ordered -workers 8
+type fanExample struct {
+	workers int
+	ordered bool
+}
+
+func (*fanExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/patterns/fan_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "fan-out to N goroutines, fan-in to one channel, ordered or as they come",
+		Tags:          []string{"concurrency", "fan-out", "fan-in", "channels"},
+		Prerequisites: []string{"concurrency/patterns/pipeline_example"},
+	}
+}
+
+// SetFlags lets the learner choose the number of workers and whether the
+// results are put back in order.
+func (f *fanExample) SetFlags(fs *flag.FlagSet) {
+	fs.IntVar(&f.workers, "workers", f.workers, "number of goroutines to fan out to")
+	fs.BoolVar(&f.ordered, "ordered", f.ordered, "collect the results in job order instead of as they arrive")
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (*fanExample) Explain(step string) string {
+	switch step {
+	case "serial":
+		return "The baseline: one goroutine does every job, one after the other."
+	case "fan-out":
+		return "The workers all receive from the same jobs channel, so each job goes to whichever worker is free."
+	case "fan-in":
+		return "Every worker sends to the same results channel; a goroutine closes it once all workers are done."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (*fanExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Several workers send on one results channel. When may it be closed?",
+			Choices: []string{"when the first worker is done", "when all workers are done", "never"},
+			Answer:  "when all workers are done",
+			Explain: "Closing it earlier would make a worker that is still running panic on its send. A WaitGroup tells when all are done.",
+		},
+		{
+			Prompt:  "How can fanned-in results be put back in job order?",
+			Choices: []string{"they already are", "send the job's index with each result and place it by index"},
+			Answer:  "send the job's index with each result and place it by index",
+			Explain: "Results arrive in the order jobs finish. Carrying the index along lets the collector undo that.",
+		},
+	}
+}
+
+// jobs is how many jobs the example runs, and jobTime how long each one takes.
+const (
+	jobs    = 12
+	jobTime = 10 * time.Millisecond
+)
+
+// result is a job's output together with the job's index.
+type result struct {
+	index int
+	value string
+}
+
+// process is the job: it takes a while and describes its input.
+func process(i int) string {
+	time.Sleep(jobTime)
+	return fmt.Sprintf("job%d", i)
+}
+
+// fanOut starts workers goroutines taking indexes from jobs, and fans
+// their results into the returned channel, which is closed when every
+// worker is done.
+func fanOut(workers int, jobs <-chan int) <-chan result {
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range jobs {
+				results <- result{i, process(i)}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// Run does the jobs serially, then fanned out.
+func (f *fanExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	if f.workers < 1 {
+		return fmt.Errorf("-workers must be at least 1, got %d", f.workers)
+	}
+
+	// 1. Serial baseline.
+	e.Step("serial")
+	start := time.Now()
+	var serial []string
+	for i := range jobs {
+		serial = append(serial, process(i))
+	}
+	serialTime := time.Since(start)
+	e.Varying("serialTime", serialTime.Round(time.Millisecond), fmt.Sprintf("%d jobs one after the other took", jobs))
+
+	// 2. Fan out: feed the job indexes to the workers.
+	e.Step("fan-out")
+	start = time.Now()
+	jobCh := make(chan int)
+	go func() {
+		defer close(jobCh)
+		for i := range jobs {
+			jobCh <- i
+		}
+	}()
+	results := fanOut(f.workers, jobCh)
+	e.Say("Fanned %d jobs out to %d workers.", jobs, f.workers)
+
+	// 3. Fan in: one loop receives everything. In ordered mode the
+	// results are placed by index; otherwise they are kept as they come.
+	e.Step("fan-in")
+	var arrived []string
+	placed := make([]string, jobs)
+	for r := range results {
+		arrived = append(arrived, r.value)
+		placed[r.index] = r.value
+	}
+	fanTime := time.Since(start)
+	if f.ordered {
+		e.Value("placed", placed, "Results in job order")
+		check.That(slices.Equal(placed, serial), "ordered collection matches the serial results")
+	} else {
+		e.Varying("arrived", arrived, "Results in the order they arrived")
+		check.That(len(arrived) == jobs, "every job's result arrived")
+	}
+	e.Varying("fanTime", fanTime.Round(time.Millisecond), fmt.Sprintf("%d jobs on %d workers took", jobs, f.workers))
+	e.Varying("speedup", fmt.Sprintf("%.1fx", float64(serialTime)/float64(fanTime)), "Speedup over serial")
+	if f.workers > 1 {
+		// The jobs wait rather than compute, so they overlap even on one CPU.
+		check.That(fanTime < serialTime*3/4, "fanning out to several workers beats the serial version")
+	}
+	return errors.Join(e.Err(), check.Err())
+}
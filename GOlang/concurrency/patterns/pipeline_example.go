@@ -0,0 +1,183 @@
+// Package patterns contains examples of the classic ways of wiring
+// goroutines together with channels: pipelines, fan-out and fan-in.
+package patterns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(pipelineExample{})
+}
+
+// pipelineExample connects three stages with channels: a generator of
+// numbers, a stage that squares them, and a sink that adds them up. It
+// stops the pipeline twice: by running out of input, and by cancelling.
+type pipelineExample struct{}
+
+func (pipelineExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/patterns/pipeline_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "a generator → square → sum pipeline, shut down by closing and by cancelling",
+		Tags:          []string{"concurrency", "pipeline", "channels", "context", "cancellation"},
+		Prerequisites: []string{"concurrency/selects/timeout_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (pipelineExample) Explain(step string) string {
+	switch step {
+	case "drain":
+		return "The generator closes its channel when it runs out. square's range loop ends, so it closes its own channel, and so on down the line."
+	case "cancel":
+		return "The generator never runs out this time. Cancelling the context makes every stage return, wherever it is blocked."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (pipelineExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Who should close the channel between two pipeline stages?",
+			Choices: []string{"the stage that sends on it", "the stage that receives from it", "main"},
+			Answer:  "the stage that sends on it",
+			Explain: "Only the sender knows when there will be no more values; a receiver closing it would make the sender panic.",
+		},
+		{
+			Prompt:  "The sink stops reading early. Why do the other stages need a ctx.Done() case?",
+			Choices: []string{"they would block forever on their next send", "closing is slow", "it makes them faster"},
+			Answer:  "they would block forever on their next send",
+			Explain: "With nobody receiving, a send never completes; the goroutine leaks. Selecting on ctx.Done() lets it give up.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (pipelineExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How does shutdown propagate through a pipeline when the input runs out?",
+			Back:  "Each stage closes its output channel when its input channel is closed and drained, so the close travels downstream stage by stage.",
+		},
+		{
+			Front: "How do you stop a pipeline early?",
+			Back:  "Cancel a context that every stage selects on next to each send (and receive), so each one returns instead of blocking.",
+		},
+	}
+}
+
+// stages records which stages have returned, in order.
+type stages struct {
+	mu      sync.Mutex
+	stopped []string
+	wg      sync.WaitGroup
+}
+
+// stop notes that a stage is returning. Stages call it before closing
+// their output, so the next stage can't note its own stop first.
+func (s *stages) stop(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = append(s.stopped, name)
+}
+
+// generate sends the numbers from 1 to n (forever if n is 0) on the
+// returned channel, and closes it when done.
+func generate(ctx context.Context, s *stages, n int) <-chan int {
+	out := make(chan int)
+	s.wg.Go(func() {
+		defer close(out)
+		defer s.stop("generate") // deferred calls run last first: this one before close
+		for i := 1; n == 0 || i <= n; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return out
+}
+
+// square sends the square of every number it receives, and closes its
+// output once its input is closed.
+func square(ctx context.Context, s *stages, in <-chan int) <-chan int {
+	out := make(chan int)
+	s.wg.Go(func() {
+		defer close(out)
+		defer s.stop("square")
+		for v := range in {
+			select {
+			case out <- v * v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return out
+}
+
+// sum adds up what it receives, until its input is closed or it has had
+// limit values (if limit > 0).
+func sum(in <-chan int, limit int) (total, count int) {
+	for v := range in {
+		total += v
+		count++
+		if count == limit {
+			break
+		}
+	}
+	return total, count
+}
+
+// Run shuts the pipeline down by draining it, then by cancelling it.
+func (pipelineExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Finite input: closing propagates from the generator downstream.
+	e.Step("drain")
+	var s stages
+	total, _ := sum(square(ctx, &s, generate(ctx, &s, 5)), 0)
+	s.wg.Wait()
+	e.Value("total", total, "Sum of the squares of 1 to 5")
+	e.Value("stopped", s.stopped, "Order in which the stages stopped")
+	assert.Equal(check, "1+4+9+16+25", total, 55)
+	check.That(slices.Equal(s.stopped, []string{"generate", "square"}), "the close travels downstream: generate stops before square")
+
+	// 2. Endless input: the sink stops after 3 values and cancels, and
+	// every stage notices.
+	e.Step("cancel")
+	cctx, cancel := context.WithCancel(ctx)
+	var s2 stages
+	total, count := sum(square(cctx, &s2, generate(cctx, &s2, 0)), 3)
+	cancel()
+	stopped := make(chan struct{})
+	go func() { s2.wg.Wait(); close(stopped) }()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		return errors.New("the pipeline didn't stop after cancel")
+	}
+	slices.Sort(s2.stopped) // they stop at the same time, in any order
+	e.Value("total", total, "Sum of the first 3 squares")
+	e.Value("count", count, "Values read before cancelling")
+	e.Value("stopped", s2.stopped, "Stages that stopped after cancel")
+	assert.Equal(check, "1+4+9", total, 14)
+	assert.Equal(check, "both stages stop after cancel", len(s2.stopped), 2)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,160 @@
+package races
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/datarace"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(appendExample{})
+	isolate.Register("races/append", racyAppends)
+}
+
+// appendExample has goroutines append to one slice, then fixes it twice:
+// with a mutex, and by giving every goroutine its own element. Run
+//
+//	concepts race concurrency/races/append_example
+//
+// to see the race detector flag only the first version.
+type appendExample struct{}
+
+func (appendExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/races/append_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "goroutines appending to a shared slice lose elements, and two fixes",
+		Tags:          []string{"concurrency", "data-race", "slices", "mutex"},
+		Prerequisites: []string{"concurrency/races/racy_counter"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (appendExample) Explain(step string) string {
+	switch step {
+	case "racy":
+		return "append reads the slice's length, writes past it, and stores a new slice header: two goroutines doing that at once overwrite each other's element."
+	case "mutex":
+		return "With the append under a mutex, one goroutine at a time reads and replaces the header."
+	case "indexed":
+		return "A slice made to its final length up front needs no append: each goroutine writes its own element, and different elements don't race."
+	}
+	return ""
+}
+
+// RaceNotes explain the race detector's report (see "concepts race").
+func (appendExample) RaceNotes() []datarace.Note {
+	return []datarace.Note{{
+		Func: "appendShared",
+		Explain: "*s = append(*s, v) reads the slice header and writes a new one. Goroutines " +
+			"running it at once can append to the same length, so one element overwrites " +
+			"the other, or one header overwrites the other.",
+	}}
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (appendExample) Questions() []quiz.Question {
+	return []quiz.Question{{
+		Prompt:  "Goroutine i writes results[i] of a slice made with make([]int, n). Is that a data race?",
+		Choices: []string{"yes, they share the slice", "no, each writes a different element"},
+		Answer:  "no, each writes a different element",
+		Explain: "A race needs two accesses to the same memory. Different elements of an array are different variables; only the slice header is shared, and nobody writes it.",
+	}}
+}
+
+const appenders, perAppender = 4, 1000
+
+// appendShared appends v to *s. It is the racy line.
+func appendShared(s *[]int, v int) {
+	*s = append(*s, v)
+}
+
+// lockedAppend appends v to *s while holding mu.
+func lockedAppend(mu *sync.Mutex, s *[]int, v int) {
+	mu.Lock()
+	defer mu.Unlock()
+	*s = append(*s, v)
+}
+
+// appendAll has the appenders call add for their numbers, all at once.
+func appendAll(add func(v int)) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for g := range appenders {
+		wg.Go(func() {
+			<-start
+			for i := range perAppender {
+				add(g*perAppender + i)
+			}
+		})
+	}
+	close(start)
+	wg.Wait()
+}
+
+// racyAppends is the racy program: the appenders share a slice without a
+// lock. It prints the slice's final length.
+func racyAppends() {
+	var shared []int
+	appendAll(func(v int) { appendShared(&shared, v) })
+	fmt.Println(len(shared))
+}
+
+// complete reports whether s holds each number from 0 to n-1 once.
+func complete(s []int, n int) bool {
+	s = slices.Sorted(slices.Values(s))
+	for i, v := range s {
+		if v != i {
+			return false
+		}
+	}
+	return len(s) == n
+}
+
+// Run appends 4000 numbers three ways.
+func (appendExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	want := appenders * perAppender
+
+	e.Step("racy")
+	e.Say("%d goroutines each append %d numbers to the same slice, in a process of their own.", appenders, perAppender)
+	nums, err := runRacy(ctx, "races/append", 1)
+	if err != nil {
+		return err
+	}
+	// With one core, the goroutines may happen not to interleave at all.
+	e.Varying("len", nums[0], "len(shared) (racy)")
+	e.Value("want", want, "Expected length")
+	e.Warn("Two appends that read the same length write the same element: one number is lost.")
+	check.That(nums[0] <= want, "racy appends never add elements")
+
+	e.Step("mutex")
+	var mu sync.Mutex
+	var locked []int
+	appendAll(func(v int) { lockedAppend(&mu, &locked, v) })
+	e.Value("len", len(locked), "len(locked)")
+	check.That(complete(locked, want), "with a mutex, every number is appended once")
+
+	e.Step("indexed")
+	indexed := make([]int, want)
+	appendAll(func(v int) { indexed[v] = v })
+	e.Value("len", len(indexed), "len(indexed)")
+	check.That(complete(indexed, want), "writing distinct elements keeps every number")
+
+	e.Step("next")
+	e.Say("Run \"concepts race concurrency/races/append_example\": the race detector flags appendShared, and neither fix.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,69 @@
+package races
+
+import (
+	"sync"
+	"testing"
+)
+
+// The racy versions are the point of the examples, so only the fixes are
+// tested here. Under go test -race, a fix that still races fails its test.
+
+func TestLockedIncrement(t *testing.T) {
+	var mu sync.Mutex
+	counter := 0
+	count(func() { lockedIncrement(&mu, &counter) })
+	if want := workers * perWorker; counter != want {
+		t.Errorf("counter = %d, want %d", counter, want)
+	}
+}
+
+func TestLockedAppend(t *testing.T) {
+	var mu sync.Mutex
+	var s []int
+	appendAll(func(v int) { lockedAppend(&mu, &s, v) })
+	if !complete(s, appenders*perAppender) {
+		t.Errorf("%d numbers appended, want each of 0 to %d once", len(s), appenders*perAppender-1)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		s    []int
+		n    int
+		want bool
+	}{
+		{[]int{2, 0, 1}, 3, true},
+		{[]int{0, 1}, 3, false},
+		{[]int{0, 1, 1}, 3, false},
+		{nil, 0, true},
+	}
+	for _, tt := range tests {
+		if got := complete(tt.s, tt.n); got != tt.want {
+			t.Errorf("complete(%v, %d) = %v, want %v", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestOnceConfig(t *testing.T) {
+	if n := ask(sync.OnceValue(load)); n != 8 {
+		t.Errorf("%d of 8 goroutines got the configuration", n)
+	}
+}
+
+func TestLockedMap(t *testing.T) {
+	var mu sync.Mutex
+	m := map[int]int{}
+	write(func(key int) {
+		mu.Lock()
+		defer mu.Unlock()
+		m[key]++
+	})
+	for key, n := range m {
+		if n != 100 {
+			t.Errorf("m[%d] = %d, want 100", key, n)
+		}
+	}
+	if len(m) != mapWriters*mapKeys {
+		t.Errorf("len(m) = %d, want %d", len(m), mapWriters*mapKeys)
+	}
+}
@@ -0,0 +1,163 @@
+package races
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/datarace"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(lazyInitExample{})
+	isolate.Register("races/lazyinit", racyLazyInit)
+}
+
+// lazyInitExample initializes a configuration on first use, from several
+// goroutines at once: with a nil check, then with sync.OnceValue. Run
+//
+//	concepts race concurrency/races/lazyinit_example
+//
+// to see the race detector flag the nil check.
+type lazyInitExample struct{}
+
+func (lazyInitExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/races/lazyinit_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "lazy initialization with a nil check races; sync.OnceValue doesn't",
+		Tags:          []string{"concurrency", "data-race", "lazy-init", "sync.Once"},
+		Prerequisites: []string{"concurrency/races/racy_counter", "concurrency/lazy/once_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (lazyInitExample) Explain(step string) string {
+	switch step {
+	case "racy":
+		return "Every goroutine that sees nil before the first one has stored its config loads one of its own, and the pointer is written while others read it."
+	case "once":
+		return "sync.OnceValue runs the loader once; every other caller waits for it and gets its result, with the write ordered before their reads."
+	}
+	return ""
+}
+
+// RaceNotes explain the race detector's report (see "concepts race").
+func (lazyInitExample) RaceNotes() []datarace.Note {
+	return []datarace.Note{{
+		Func: "(*lazyConfig).get",
+		Explain: "The nil check reads c.cfg and the initialization writes it, with nothing " +
+			"ordering the goroutines. Even a goroutine that sees the pointer isn't " +
+			"guaranteed to see the fields it points to written.",
+	}}
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (lazyInitExample) Questions() []quiz.Question {
+	return []quiz.Question{{
+		Prompt:  "The racy lazy init happened to load the config just once. Is it correct?",
+		Choices: []string{"yes", "no, it still has a data race"},
+		Answer:  "no, it still has a data race",
+		Explain: "The read of the pointer and its write are unordered. The race detector reports it whether or not a second load happened.",
+	}}
+}
+
+// settings is what loading the configuration produces.
+type settings struct {
+	name    string
+	retries int
+}
+
+// loads counts the calls to load.
+var loads atomic.Int32
+
+// load reads the configuration, which takes a moment.
+func load() *settings {
+	loads.Add(1)
+	time.Sleep(time.Millisecond)
+	return &settings{name: "prod", retries: 3}
+}
+
+// lazyConfig loads its configuration when first asked.
+type lazyConfig struct {
+	cfg *settings
+}
+
+// get is the racy lazy initialization.
+func (c *lazyConfig) get() *settings {
+	if c.cfg == nil {
+		c.cfg = load()
+	}
+	return c.cfg
+}
+
+// ask has 8 goroutines call get at once and returns how many got a
+// usable configuration.
+func ask(get func() *settings) int {
+	var wg sync.WaitGroup
+	var ok atomic.Int32
+	start := make(chan struct{})
+	for range 8 {
+		wg.Go(func() {
+			<-start
+			if cfg := get(); cfg != nil && cfg.retries == 3 {
+				ok.Add(1)
+			}
+		})
+	}
+	close(start)
+	wg.Wait()
+	return int(ok.Load())
+}
+
+// racyLazyInit is the racy program: the goroutines share a lazyConfig. It
+// prints how many times the config was loaded and how many goroutines got
+// a usable one.
+func racyLazyInit() {
+	var c lazyConfig
+	ok := ask(c.get)
+	fmt.Println(loads.Load(), ok)
+}
+
+// Run initializes the configuration both ways.
+func (lazyInitExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	e.Step("racy")
+	e.Say("8 goroutines ask for the config at once, in a process of their own.")
+	nums, err := runRacy(ctx, "races/lazyinit", 2)
+	if err != nil {
+		return err
+	}
+	e.Varying("loads", nums[0], "Times the config was loaded (nil check)")
+	e.Value("ok", nums[1], "Goroutines that got a config")
+	e.Warn("Every goroutine that got past the nil check before the first load finished loaded the config again.")
+	check.That(nums[0] >= 1, "the racy version loads at least once")
+
+	e.Step("once")
+	loads.Store(0)
+	// The fix: sync.OnceValue does the check and the waiting. It is made
+	// here rather than at package level, so every run loads afresh.
+	onceConfig := sync.OnceValue(load)
+	ok := ask(onceConfig)
+	e.Value("loads", loads.Load(), "Times the config was loaded (sync.OnceValue)")
+	e.Value("ok", ok, "Goroutines that got a config")
+	assert.Equal(check, "sync.OnceValue loads exactly once", loads.Load(), int32(1))
+	assert.Equal(check, "every goroutine gets the loaded config", ok, 8)
+
+	e.Step("next")
+	e.Say("Run \"concepts race concurrency/races/lazyinit_example\": the race detector flags the nil check, and not sync.OnceValue.")
+	return errors.Join(e.Err(), check.Err())
+}
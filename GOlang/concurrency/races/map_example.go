@@ -0,0 +1,129 @@
+package races
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(mapExample{})
+	isolate.Register("races/map", mapWrites)
+}
+
+// mapExample writes to one map from several goroutines. The runtime
+// checks for that itself, race detector or not, and stops the program
+// with a fatal error when it notices; so the racy version runs in a
+// process of its own (see the isolate package). The fix is a mutex.
+type mapExample struct{}
+
+func (mapExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/races/map_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "concurrent map writes: a fatal error from the runtime, and the mutex that fixes it",
+		Tags:          []string{"concurrency", "data-race", "maps", "mutex"},
+		Prerequisites: []string{"concurrency/races/racy_counter", "concurrency/deadlocks/send_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (mapExample) Explain(step string) string {
+	switch step {
+	case "racy":
+		return "A map write can move entries around; a second write at the same time could corrupt the map, so the runtime crashes the program if it catches one."
+	case "mutex":
+		return "Under a mutex, one goroutine at a time writes to the map."
+	}
+	return ""
+}
+
+// Flashcards are reviewed by "concepts review".
+func (mapExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{{
+		Front: "fatal error: concurrent map writes",
+		Back:  "Two goroutines wrote to the same map at once. It can't be recovered; guard the map with a mutex, or use sync.Map for the cases it is made for.",
+	}}
+}
+
+const mapWriters, mapKeys = 4, 1000
+
+// write has the writers increment every key of m 100 times, each with
+// its own keys, calling inc for each increment.
+func write(inc func(key int)) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for g := range mapWriters {
+		wg.Go(func() {
+			<-start
+			for i := range 100 * mapKeys {
+				inc(g*mapKeys + i%mapKeys)
+			}
+		})
+	}
+	close(start)
+	wg.Wait()
+}
+
+// mapWrites is the racy program: the writers share m without a lock. It
+// prints how many keys m ended up with, if the runtime let it get that
+// far.
+func mapWrites() {
+	m := map[int]int{}
+	write(func(key int) { m[key]++ })
+	fmt.Println(len(m))
+}
+
+// Run has the writers write to a map without and with a mutex.
+func (mapExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	e.Step("racy")
+	e.Say("%d goroutines increment keys of the same map, in a process of their own.", mapWriters)
+	res, err := isolate.Run(ctx, "races/map", time.Minute)
+	if err != nil {
+		return err
+	}
+	if res.TimedOut {
+		return errors.New("the racy map program hung")
+	}
+	// The runtime only notices writes that really overlap, which on a
+	// single core they rarely do.
+	outcome := "finished without being caught"
+	if res.Fatal != "" {
+		outcome = "fatal error: " + res.Fatal
+	}
+	e.Varying("outcome", outcome, "The racy program")
+	e.Warn("A map written by several goroutines is a bug whether or not the runtime catches it: under -race, it always reports it.")
+	check.That(res.Fatal == "" || res.Fatal == "concurrent map writes", "the runtime's only complaint is concurrent map writes")
+
+	e.Step("mutex")
+	var mu sync.Mutex
+	m := map[int]int{}
+	write(func(key int) {
+		mu.Lock()
+		defer mu.Unlock()
+		m[key]++
+	})
+	total := 0
+	for _, n := range m {
+		total += n
+	}
+	e.Value("len", len(m), "len(m)")
+	e.Value("total", total, "Sum of the counts")
+	assert.Equal(check, "every writer's keys are there", len(m), mapWriters*mapKeys)
+	assert.Equal(check, "no increment is lost", total, mapWriters*100*mapKeys)
+	return errors.Join(e.Err(), check.Err())
+}
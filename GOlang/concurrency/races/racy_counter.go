@@ -0,0 +1,212 @@
+// Package races contains examples with data races in them on purpose, to
+// show what goes wrong and how the race detector catches it. Each racy
+// version is followed by a fixed one, which "concepts race" shows the
+// race detector has nothing to say about.
+//
+// The racy versions run in a process of their own (see the isolate
+// package): a race can tear a slice header or a pointer and corrupt
+// memory, which must not happen inside a concepts server that keeps
+// running, and it mustn't fail "go test -race" of the examples either.
+package races
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/datarace"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(racyCounter{})
+	isolate.Register("races/counter", racyCount)
+}
+
+// racyCounter lets several goroutines increment the same int, first with
+// no synchronization at all and then under a mutex. Run it with
+//
+//	concepts race concurrency/races/racy_counter
+//
+// to see the race detector point at the line that races.
+type racyCounter struct{}
+
+func (racyCounter) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/races/racy_counter",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "goroutines incrementing a shared counter with and without a mutex",
+		Tags:          []string{"concurrency", "goroutines", "data-race", "mutex"},
+		Prerequisites: []string{"concurrency/goroutines/launch_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (racyCounter) Explain(step string) string {
+	switch step {
+	case "racy":
+		return "Every goroutine reads counter, adds one and writes it back, with nothing stopping two of them from doing so at the same time."
+	case "mutex":
+		return "Now each read-add-write happens while holding mu, so the goroutines take turns and no increment is lost."
+	}
+	return ""
+}
+
+// RaceNotes explain the race detector's report (see "concepts race").
+func (racyCounter) RaceNotes() []datarace.Note {
+	return []datarace.Note{{
+		Func: "increment",
+		Explain: "*counter++ reads counter and then writes it. Two goroutines run this line " +
+			"with nothing ordering them, so one can write a value computed from a read the " +
+			"other already made stale.",
+	}}
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (racyCounter) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "8 goroutines each run counter++ 10000 times on a shared int, without a mutex. What is counter afterwards?",
+			Choices: []string{"always 80000", "at most 80000, and often less", "more than 80000"},
+			Answer:  "at most 80000, and often less",
+			Explain: "counter++ is a read followed by a write. When two goroutines read the same old value, one of the two increments is lost.",
+		},
+		{
+			Prompt:  "The racy version printed 80000 on your machine. Is it correct?",
+			Choices: []string{"yes, it gave the right answer", "no, it still has a data race"},
+			Answer:  "no, it still has a data race",
+			Explain: "A data race is a bug even when it happens not to lose an update; on another machine or another run it will. The race detector reports it either way.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (racyCounter) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What is a data race?",
+			Back:  "Two goroutines access the same variable at the same time, at least one of them writes, and nothing (a mutex, a channel, an atomic) orders the accesses.",
+		},
+		{
+			Front: "How do you find data races in Go?",
+			Back:  "Build, run or test with -race. The race detector reports each race with the stacks of both conflicting accesses.",
+		},
+	}
+}
+
+const (
+	workers   = 8
+	perWorker = 10000
+)
+
+// increment adds one to *counter. It is the racy line: the read and the
+// write of *counter are two separate steps that other goroutines can get
+// in between.
+func increment(counter *int) {
+	*counter++
+}
+
+// lockedIncrement adds one to *counter while holding mu.
+func lockedIncrement(mu *sync.Mutex, counter *int) {
+	mu.Lock()
+	defer mu.Unlock()
+	*counter++
+}
+
+// count starts the workers, each calling inc perWorker times, and waits
+// for all of them to finish. The workers wait for each other before they
+// start, so they really do run at the same time instead of one after the
+// other.
+func count(inc func()) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for range workers {
+		wg.Go(func() {
+			<-start
+			for range perWorker {
+				inc()
+			}
+		})
+	}
+	close(start)
+	wg.Wait()
+}
+
+// racyCount is the racy program: the workers increment a shared counter
+// without a lock. It prints the final count.
+func racyCount() {
+	counter := 0
+	count(func() { increment(&counter) })
+	fmt.Println(counter)
+}
+
+// runRacy runs the racy program called name in a process of its own and
+// returns the n numbers it printed.
+func runRacy(ctx context.Context, name string, n int) ([]int, error) {
+	res, err := isolate.Run(ctx, name, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case res.TimedOut:
+		return nil, fmt.Errorf("the racy program %s hung", name)
+	case res.Fatal != "":
+		return nil, fmt.Errorf("the racy program %s crashed: %s", name, res.Fatal)
+	}
+	fields := strings.Fields(string(res.Stdout))
+	if len(fields) != n {
+		return nil, fmt.Errorf("the racy program %s printed %q, want %d numbers", name, res.Stdout, n)
+	}
+	nums := make([]int, n)
+	for i, f := range fields {
+		if nums[i], err = strconv.Atoi(f); err != nil {
+			return nil, fmt.Errorf("the racy program %s: %v", name, err)
+		}
+	}
+	return nums, nil
+}
+
+// Run counts to workers*perWorker twice, without and with a mutex.
+func (racyCounter) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	want := workers * perWorker
+
+	e.Step("racy")
+	e.Say("%d goroutines each increment counter %d times, without synchronization, in a process of their own.", workers, perWorker)
+	nums, err := runRacy(ctx, "races/counter", 1)
+	if err != nil {
+		return err
+	}
+	counter := nums[0]
+	// How many increments get lost depends on how the goroutines happen
+	// to be scheduled: it changes from run to run, and can even be none.
+	e.Varying("counter", counter, "Final value of counter (racy)")
+	e.Value("want", want, "Expected value")
+	e.Warn("counter++ is a read and a write; when two goroutines interleave them, one increment is lost.")
+	check.That(counter <= want, "the racy counter never exceeds the number of increments")
+
+	e.Step("mutex")
+	e.Say("The same, but every increment holds a sync.Mutex.")
+	var mu sync.Mutex
+	counter = 0
+	count(func() { lockedIncrement(&mu, &counter) })
+	e.Value("counter", counter, "Final value of counter (mutex)")
+	assert.Equal(check, "with a mutex, no increment is lost", counter, want)
+
+	e.Step("next")
+	e.Say("Run \"concepts race concurrency/races/racy_counter\" to see the race detector catch the first version.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,81 @@
+// Package ratelimit is two small rate limiters: a token bucket, which
+// allows bursts, and a leaky bucket, which spaces requests out evenly.
+// The example in throttle_example.go compares them with each other and
+// with golang.org/x/time/rate.
+//
+// Both take the current time as an argument instead of reading the
+// clock, so a simulation (or a test) can replay any timeline in no time.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket allows requests while it has tokens. It holds up to burst
+// tokens and gains rate tokens per second; each request takes one.
+type TokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a full bucket that refills at rate tokens per
+// second, up to burst.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// Allow reports whether a request at now may go ahead, taking a token if
+// so. Calls must not go back in time.
+func (b *TokenBucket) Allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// LeakyBucket lets requests through one every interval. Requests that
+// arrive faster wait in a queue of up to size requests, and are turned
+// away when it is full.
+type LeakyBucket struct {
+	interval time.Duration
+	size     int
+
+	mu   sync.Mutex
+	next time.Time // when the next request may go through
+}
+
+// NewLeakyBucket returns an empty bucket that lets one request through
+// every interval and queues up to size.
+func NewLeakyBucket(interval time.Duration, size int) *LeakyBucket {
+	return &LeakyBucket{interval: interval, size: size}
+}
+
+// Reserve schedules a request arriving at now. It returns the time the
+// request may go through, or false if the queue is full. Calls must not
+// go back in time.
+func (b *LeakyBucket) Reserve(now time.Time) (time.Time, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	at := b.next
+	if at.Before(now) {
+		at = now
+	}
+	// at-now is how long the queue ahead of this request takes to drain.
+	if at.Sub(now) > time.Duration(b.size)*b.interval {
+		return time.Time{}, false
+	}
+	b.next = at.Add(b.interval)
+	return at, true
+}
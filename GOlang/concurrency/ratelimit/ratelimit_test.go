@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// at returns the moment ms milliseconds after start.
+func at(ms int) time.Time {
+	return start.Add(time.Duration(ms) * time.Millisecond)
+}
+
+func TestTokenBucketRefills(t *testing.T) {
+	b := NewTokenBucket(2, 3) // a token every 500ms, up to 3
+	steps := []struct {
+		ms   int
+		want bool
+	}{
+		{0, true}, {0, true}, {0, true}, // the burst
+		{0, false},
+		{400, false}, // 0.8 of a token
+		{500, true},  // 1.0
+		{500, false},
+		{1000, true},
+		{10000, true}, {10000, true}, {10000, true}, // refilled, but only to 3
+		{10000, false},
+	}
+	for i, s := range steps {
+		if got := b.Allow(at(s.ms)); got != s.want {
+			t.Errorf("request %d, at %dms: Allow = %v, want %v", i, s.ms, got, s.want)
+		}
+	}
+}
+
+func TestTokenBucketConcurrent(t *testing.T) {
+	b := NewTokenBucket(1, 10)
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() {
+			if b.Allow(start) {
+				allowed.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+	if n := allowed.Load(); n != 10 {
+		t.Errorf("%d of 100 simultaneous requests allowed, want the burst of 10", n)
+	}
+}
+
+func TestLeakyBucketSpacesRequests(t *testing.T) {
+	b := NewLeakyBucket(100*time.Millisecond, 2)
+	want := []int{0, 100, 200} // one goes through now, two wait
+	for i, ms := range want {
+		got, ok := b.Reserve(start)
+		if !ok || !got.Equal(at(ms)) {
+			t.Errorf("request %d: Reserve = %v, %v; want %dms", i, got.Sub(start), ok, ms)
+		}
+	}
+	if _, ok := b.Reserve(start); ok {
+		t.Error("a request was queued in a full bucket")
+	}
+	// After the queue drains, a request goes through when it arrives.
+	if got, ok := b.Reserve(at(1000)); !ok || !got.Equal(at(1000)) {
+		t.Errorf("Reserve on an empty bucket = %v, %v; want at once", got.Sub(start), ok)
+	}
+}
+
+func TestLeakyBucketConcurrent(t *testing.T) {
+	b := NewLeakyBucket(10*time.Millisecond, 5)
+	var mu sync.Mutex
+	slots := map[time.Time]bool{}
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			if when, ok := b.Reserve(start); ok {
+				mu.Lock()
+				defer mu.Unlock()
+				if slots[when] {
+					t.Errorf("two requests reserved %v", when.Sub(start))
+				}
+				slots[when] = true
+			}
+		})
+	}
+	wg.Wait()
+	if len(slots) != 6 {
+		t.Errorf("%d requests reserved, want 1 going through and 5 queued", len(slots))
+	}
+}
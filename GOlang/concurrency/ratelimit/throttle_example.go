@@ -0,0 +1,193 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(throttleExample{})
+}
+
+// throttleExample replays the same two seconds of API calls against a
+// token bucket, a leaky bucket and golang.org/x/time/rate, then makes a
+// few calls for real with rate.Limiter.Wait.
+type throttleExample struct{}
+
+func (throttleExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/ratelimit/throttle_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "throttling API calls with a token bucket, a leaky bucket and x/time/rate",
+		Tags:          []string{"concurrency", "rate-limiting", "token-bucket", "leaky-bucket"},
+		Prerequisites: []string{"concurrency/timers/ticker_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (throttleExample) Explain(step string) string {
+	switch step {
+	case "token":
+		return "The full bucket lets the start of the burst straight through; after that, calls get through as fast as tokens come back."
+	case "leaky":
+		return "The leaky bucket never lets two calls through closer together than its interval; a burst is queued, and what doesn't fit is refused."
+	case "x/time/rate":
+		return "rate.Limiter is a token bucket too; given the same timeline, it makes the same decisions."
+	case "wait":
+		return "Instead of refusing, Wait blocks until the call is allowed: the caller slows down to the limit."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (throttleExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "A token bucket refills at 10/s and holds 5. After an idle minute, 30 calls arrive at once. How many go through at once?",
+			Choices: []string{"5", "10", "30"},
+			Answer:  "5",
+			Explain: "Idle time fills the bucket only up to its size; the burst size bounds how many calls can go at once.",
+		},
+		{
+			Prompt:  "Which limiter suits a downstream service that must never see two calls within 100ms?",
+			Choices: []string{"a token bucket with burst 5", "a leaky bucket with a 100ms interval"},
+			Answer:  "a leaky bucket with a 100ms interval",
+			Explain: "A token bucket allows bursts by design; a leaky bucket spaces every call out.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (throttleExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Token bucket vs leaky bucket?",
+			Back:  "A token bucket allows bursts up to its size and a long-run average of its refill rate. A leaky bucket lets requests out at a fixed pace, queueing (or refusing) the rest.",
+		},
+	}
+}
+
+// call is one simulated API call.
+type call struct{ at time.Duration }
+
+// timeline is the calls the example replays: a burst of 30 at the start,
+// then one every 20ms (50 a second) until 2s.
+func timeline() []call {
+	var calls []call
+	for range 30 {
+		calls = append(calls, call{0})
+	}
+	for at := 20 * time.Millisecond; at < 2*time.Second; at += 20 * time.Millisecond {
+		calls = append(calls, call{at})
+	}
+	return calls
+}
+
+// perSecond counts the allowed calls in each second; allowed is sorted.
+func perSecond(allowed []time.Duration) []int {
+	var n []int
+	for _, at := range allowed {
+		for int(at/time.Second) >= len(n) {
+			n = append(n, 0)
+		}
+		n[at/time.Second]++
+	}
+	return n
+}
+
+// The limits: 10 calls a second, bursts of up to 5.
+const (
+	limit = 10
+	burst = 5
+)
+
+// Run replays the timeline against each limiter, then waits for real.
+func (throttleExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := timeline()
+
+	// 1. Token bucket.
+	e.Step("token")
+	e.Say("%d calls: a burst of 30, then 50 a second for 2s. The limit is %d a second, bursts of %d.", len(calls), limit, burst)
+	tb := NewTokenBucket(limit, burst)
+	var tokenAllowed []time.Duration
+	for _, c := range calls {
+		if tb.Allow(start.Add(c.at)) {
+			tokenAllowed = append(tokenAllowed, c.at)
+		}
+	}
+	n := perSecond(tokenAllowed)
+	e.Value("n", n, "Calls allowed in each second")
+	burstThrough := 0
+	for _, at := range tokenAllowed {
+		if at == 0 {
+			burstThrough++
+		}
+	}
+	e.Value("burstThrough", burstThrough, "Of the burst, allowed at once")
+	assert.Equal(check, "the token bucket lets a burst of its size through", burstThrough, burst)
+	check.That(n[1] <= limit, "after the burst, no more than the rate gets through per second")
+
+	// 2. Leaky bucket: one call every 100ms, up to 5 waiting.
+	e.Step("leaky")
+	lb := NewLeakyBucket(time.Second/limit, burst)
+	var slots []time.Duration
+	maxWait := time.Duration(0)
+	for _, c := range calls {
+		if at, ok := lb.Reserve(start.Add(c.at)); ok {
+			slots = append(slots, at.Sub(start))
+			maxWait = max(maxWait, at.Sub(start)-c.at)
+		}
+	}
+	minGap := time.Hour
+	for i := 1; i < len(slots); i++ {
+		minGap = min(minGap, slots[i]-slots[i-1])
+	}
+	e.Value("n", perSecond(slots), "Calls let through in each second")
+	e.Value("minGap", minGap, "Smallest gap between two calls let through")
+	e.Value("maxWait", maxWait, "Longest a call waited in the queue")
+	check.That(minGap >= time.Second/limit, "the leaky bucket spaces every call out by its interval")
+	check.That(maxWait <= burst*time.Second/limit, "no call waits longer than the queue takes to drain")
+
+	// 3. x/time/rate on the same timeline agrees with the token bucket.
+	e.Step("x/time/rate")
+	rl := rate.NewLimiter(limit, burst)
+	var rateAllowed []time.Duration
+	for _, c := range calls {
+		if rl.AllowN(start.Add(c.at), 1) {
+			rateAllowed = append(rateAllowed, c.at)
+		}
+	}
+	e.Value("n", perSecond(rateAllowed), "Calls allowed in each second")
+	assert.Equal(check, "rate.Limiter decides like the token bucket", fmt.Sprint(rateAllowed), fmt.Sprint(tokenAllowed))
+
+	// 4. For real: 10 calls through a 100/s limiter with Wait.
+	e.Step("wait")
+	wl := rate.NewLimiter(100, 1)
+	began := time.Now()
+	for range 10 {
+		if err := wl.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	elapsed := time.Since(began)
+	observed := 9 / elapsed.Seconds() // 9 intervals between 10 calls
+	e.Varying("elapsed", elapsed.Round(time.Millisecond), "10 calls at 100/s took")
+	e.Varying("observed", fmt.Sprintf("%.0f/s", observed), "Observed rate")
+	check.That(observed <= 100*1.1, "Wait holds the calls to the limit")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,103 @@
+// Package scope is structured concurrency in a few lines: goroutines are
+// started in a scope, and the scope doesn't end until every one of them
+// has. Nothing started in a scope can outlive it, so no goroutine is
+// orphaned or leaked by returning early, and an error in one goroutine
+// cancels the others. The example in scope_example.go puts it to work on
+// patterns that go wrong elsewhere in this repository.
+package scope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPanicked wraps the value a goroutine in a scope panicked with.
+var ErrPanicked = errors.New("scope: goroutine panicked")
+
+// errCancelled is the cancellation cause of a scope ended with Cancel.
+var errCancelled = errors.New("scope: cancelled")
+
+// Scope is a set of goroutines whose lifetimes end before the scope's
+// does. It is only valid during the call to Run that created it.
+type Scope struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	err   error // the first error
+	ended bool
+}
+
+// Run calls body with a new scope and returns once body and every
+// goroutine started in the scope have returned. The scope's context is
+// derived from ctx and cancelled on the first error, which Run returns.
+// A goroutine that panics counts as failing with ErrPanicked.
+func Run(ctx context.Context, body func(s *Scope) error) error {
+	s := &Scope{}
+	s.ctx, s.cancel = context.WithCancelCause(ctx)
+	defer s.cancel(nil)
+	// Join even if body panics: the goroutines must not outlive the scope.
+	defer func() {
+		s.wg.Wait()
+		s.mu.Lock()
+		s.ended = true
+		s.mu.Unlock()
+	}()
+	s.fail(body(s))
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Context returns the scope's context, which is done when the scope is
+// cancelled or fails, or when Run's context is done.
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+// Go starts f in a new goroutine of the scope, passing it the scope's
+// context. f should return soon after the context is done. Go panics if
+// the scope has already ended.
+func (s *Scope) Go(f func(ctx context.Context) error) {
+	s.mu.Lock()
+	ended := s.ended
+	s.mu.Unlock()
+	if ended {
+		panic("scope: Go called after the scope ended")
+	}
+	s.wg.Go(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				s.fail(fmt.Errorf("%w: %v", ErrPanicked, r))
+			}
+		}()
+		s.fail(f(s.ctx))
+	})
+}
+
+// Cancel cancels the scope's context without making it fail: it is how a
+// scope that has what it needs tells the rest of its goroutines to stop.
+func (s *Scope) Cancel() {
+	s.cancel(errCancelled)
+}
+
+// fail records err if it is the first error, and cancels the scope. A
+// goroutine returning the context's error after Cancel doesn't count.
+func (s *Scope) fail(err error) {
+	if err == nil {
+		return
+	}
+	if errors.Is(context.Cause(s.ctx), errCancelled) && errors.Is(err, context.Canceled) {
+		return
+	}
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.mu.Unlock()
+	s.cancel(err)
+}
@@ -0,0 +1,198 @@
+package scope
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(scopeExample{})
+}
+
+// scopeExample runs the patterns that orphan or leak goroutines elsewhere
+// in this repository, first with plain go statements and then in a scope
+// from scope.go.
+type scopeExample struct{}
+
+func (scopeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/scope/scope_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "structured concurrency: goroutines that can't outlive their scope, cancelled on the first error",
+		Tags:          []string{"concurrency", "structured-concurrency", "context", "goroutine-leak"},
+		Prerequisites: []string{"concurrency/leaks/leak_example", "concurrency/groups/errgroup_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (scopeExample) Explain(step string) string {
+	switch step {
+	case "orphans":
+		return "A function that starts goroutines with go and returns leaves them running: nothing ties their lifetime to the call."
+	case "join":
+		return "Run doesn't return until every goroutine of the scope has, so the same function can't leave anything behind."
+	case "first":
+		return "Once a scope has the answer it needs, Cancel tells the rest to stop, and Run waits until they have."
+	case "error":
+		return "The first error cancels the scope's context, so the other goroutines stop early instead of finishing work nobody will use."
+	case "panic":
+		return "A panic in one of the scope's goroutines becomes the scope's error instead of crashing the program."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (scopeExample) Questions() []quiz.Question {
+	return []quiz.Question{{
+		Prompt:  "What does a scope guarantee when Run returns?",
+		Choices: []string{"every goroutine started in it has returned", "every goroutine has been told to stop", "nothing"},
+		Answer:  "every goroutine started in it has returned",
+		Explain: "That is the whole idea: goroutines are nested in the call that started them, like the calls themselves.",
+	}}
+}
+
+// ours counts the goroutines of this package that leakcheck found.
+func ours(leaked []leakcheck.Goroutine) int {
+	n := 0
+	for _, g := range leaked {
+		if strings.Contains(g.Func, "/scope.") {
+			n++
+		}
+	}
+	return n
+}
+
+// query answers with the name of replica after delay unless ctx is done.
+func query(ctx context.Context, replica string, delay time.Duration) (string, error) {
+	select {
+	case <-time.After(delay):
+		return replica, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Run compares plain goroutines with scopes.
+func (scopeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Plain goroutines: the function returns, its goroutines don't.
+	e.Step("orphans")
+	release := make(chan struct{})
+	before := leakcheck.Take()
+	func() {
+		for range 3 {
+			go func() { <-release }() // say, a background refresh
+		}
+	}()
+	orphans := ours(before.Leaked(0))
+	close(release)
+	e.Value("orphans", orphans, "Goroutines still running after the function returned")
+	assert.Equal(check, "plain goroutines outlive the function that started them", orphans, 3)
+
+	// 2. The same in a scope: Run waits for them.
+	e.Step("join")
+	before = leakcheck.Take()
+	var finished atomic.Int32
+	err := Run(ctx, func(s *Scope) error {
+		for range 3 {
+			s.Go(func(context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				finished.Add(1)
+				return nil
+			})
+		}
+		return nil // the body is done; the goroutines may not be
+	})
+	left := ours(before.Leaked(0))
+	e.Value("finished", finished.Load(), "Goroutines finished when Run returned")
+	e.Value("left", left, "Goroutines still running")
+	check.That(err == nil, "a scope whose goroutines succeed succeeds")
+	assert.Equal(check, "Run joins every goroutine of the scope", finished.Load(), int32(3))
+	assert.Equal(check, "nothing outlives the scope", left, 0)
+
+	// 3. First response: the pattern that leaks in leak_example, where
+	// the losers wait for ever to send their answers.
+	e.Step("first")
+	before = leakcheck.Take()
+	var winner string
+	err = Run(ctx, func(s *Scope) error {
+		answers := make(chan string) // unbuffered, as in the leak
+		for i, delay := range []time.Duration{time.Millisecond, 50 * time.Millisecond, time.Hour} {
+			s.Go(func(ctx context.Context) error {
+				v, err := query(ctx, []string{"a", "b", "c"}[i], delay)
+				if err != nil {
+					return err
+				}
+				select {
+				case answers <- v:
+				case <-ctx.Done(): // the loser's way out
+				}
+				return nil
+			})
+		}
+		winner = <-answers
+		s.Cancel()
+		return nil
+	})
+	left = ours(before.Leaked(0))
+	e.Value("winner", winner, "Fastest replica")
+	e.Value("left", left, "Goroutines still running")
+	check.That(err == nil, "cancelling a scope isn't a failure")
+	assert.Equal(check, "the fastest replica wins", winner, "a")
+	assert.Equal(check, "the slower replicas are stopped, not leaked", left, 0)
+
+	// 4. Errors: the first one cancels the rest.
+	e.Step("error")
+	errDisk := errors.New("disk full")
+	began := time.Now()
+	var stopped atomic.Int32
+	err = Run(ctx, func(s *Scope) error {
+		s.Go(func(context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			return errDisk
+		})
+		for range 2 {
+			s.Go(func(ctx context.Context) error {
+				_, err := query(ctx, "slow", time.Hour)
+				if err != nil {
+					stopped.Add(1)
+				}
+				return err
+			})
+		}
+		return nil
+	})
+	e.Value("err", err, "Run's error")
+	e.Value("stopped", stopped.Load(), "Goroutines cancelled by the error")
+	e.Varying("elapsed", time.Since(began).Round(time.Millisecond), "Time until Run returned")
+	check.That(errors.Is(err, errDisk), "Run returns the first error, not the cancellations it caused")
+	assert.Equal(check, "the error cancels the other goroutines", stopped.Load(), int32(2))
+	check.That(time.Since(began) < time.Minute, "Run doesn't wait for the hour-long queries")
+
+	// 5. A panic becomes an error.
+	e.Step("panic")
+	err = Run(ctx, func(s *Scope) error {
+		s.Go(func(context.Context) error {
+			var m map[string]int
+			m["x"] = 1 // assignment to entry in nil map
+			return nil
+		})
+		return nil
+	})
+	e.Value("err", err, "Run's error")
+	check.That(errors.Is(err, ErrPanicked), "a panicking goroutine fails the scope instead of the program")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,143 @@
+package scope
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+)
+
+// waitForCancel is a goroutine body that runs until its scope is done.
+func waitForCancel(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRunWaitsForEveryGoroutine(t *testing.T) {
+	before := leakcheck.Take()
+	var done atomic.Int64
+	err := Run(context.Background(), func(s *Scope) error {
+		for range 10 {
+			s.Go(func(context.Context) error {
+				time.Sleep(time.Millisecond)
+				done.Add(1)
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil || done.Load() != 10 {
+		t.Errorf("Run = %v with %d of 10 goroutines done", err, done.Load())
+	}
+	if leaked := before.Leaked(time.Second); len(leaked) > 0 {
+		t.Errorf("goroutines outlive the scope: %+v", leaked)
+	}
+}
+
+func TestErrorCancelsTheOthers(t *testing.T) {
+	errBoom := errors.New("boom")
+	var cancelled atomic.Int64
+	err := Run(context.Background(), func(s *Scope) error {
+		for range 3 {
+			s.Go(func(ctx context.Context) error {
+				err := waitForCancel(ctx)
+				cancelled.Add(1)
+				return err
+			})
+		}
+		s.Go(func(context.Context) error { return errBoom })
+		return nil
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Run = %v, want the first error, %v", err, errBoom)
+	}
+	if n := cancelled.Load(); n != 3 {
+		t.Errorf("%d of 3 goroutines were cancelled", n)
+	}
+}
+
+func TestBodyError(t *testing.T) {
+	errBoom := errors.New("boom")
+	err := Run(context.Background(), func(s *Scope) error {
+		s.Go(waitForCancel)
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Run = %v, want %v", err, errBoom)
+	}
+}
+
+func TestPanicIsAnError(t *testing.T) {
+	err := Run(context.Background(), func(s *Scope) error {
+		s.Go(waitForCancel)
+		s.Go(func(context.Context) error { panic("oops") })
+		return nil
+	})
+	if !errors.Is(err, ErrPanicked) {
+		t.Errorf("Run = %v, want %v", err, ErrPanicked)
+	}
+}
+
+func TestCancelIsNotAFailure(t *testing.T) {
+	err := Run(context.Background(), func(s *Scope) error {
+		for range 3 {
+			s.Go(waitForCancel)
+		}
+		s.Cancel()
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Run after Cancel = %v, want nil", err)
+	}
+}
+
+func TestParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Run(ctx, func(s *Scope) error {
+		s.Go(waitForCancel)
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestBodyPanicStillJoins(t *testing.T) {
+	var finished atomic.Bool
+	func() {
+		defer func() {
+			if r := recover(); r != "body failed" {
+				t.Errorf("recovered %v, want the body's panic", r)
+			}
+		}()
+		Run(context.Background(), func(s *Scope) error {
+			s.Go(func(context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				finished.Store(true)
+				return nil
+			})
+			panic("body failed")
+		})
+	}()
+	if !finished.Load() {
+		t.Error("the panic left the scope before its goroutine ended")
+	}
+}
+
+func TestGoAfterTheScopeEnded(t *testing.T) {
+	var escaped *Scope
+	Run(context.Background(), func(s *Scope) error {
+		escaped = s
+		return nil
+	})
+	defer func() {
+		if recover() == nil {
+			t.Error("Go after the scope ended didn't panic")
+		}
+	}()
+	escaped.Go(waitForCancel)
+}
@@ -0,0 +1,104 @@
+package selects
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(defaultExample{})
+}
+
+// defaultExample uses the default case of select to send and receive
+// without ever blocking.
+type defaultExample struct{}
+
+func (defaultExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/selects/default_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "non-blocking sends and receives with select and default",
+		Tags:          []string{"concurrency", "select", "default", "non-blocking"},
+		Prerequisites: []string{"concurrency/selects/random_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (defaultExample) Explain(step string) string {
+	switch step {
+	case "receive":
+		return "tryReceive never waits: when the channel is empty, default runs instead."
+	case "send":
+		return "trySend drops the value instead of waiting when the buffer is full, a common way to shed load."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (defaultExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "A select has a receive case on an empty channel and a default case. What happens?",
+			Choices: []string{"it blocks until a value arrives", "default runs immediately", "it panics"},
+			Answer:  "default runs immediately",
+			Explain: "default runs whenever no other case is ready, which makes the whole select non-blocking.",
+		},
+	}
+}
+
+// tryReceive returns the next value of ch, or ok == false right away if
+// there is none.
+func tryReceive(ch <-chan int) (v int, ok bool) {
+	select {
+	case v = <-ch:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// trySend sends v on ch if that is possible right now, and reports whether
+// it was.
+func trySend(ch chan<- int, v int) bool {
+	select {
+	case ch <- v:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run receives from and sends to a channel with room for 2 values.
+func (defaultExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	ch := make(chan int, 2)
+
+	// 1. Receiving from an empty channel doesn't block.
+	e.Step("receive")
+	_, ok := tryReceive(ch)
+	e.Value("ok", ok, "tryReceive on an empty channel got a value")
+	check.That(!ok, "tryReceive returns at once from an empty channel")
+
+	// 2. Sending five values into room for two: three are dropped.
+	e.Step("send")
+	dropped := 0
+	for v := 1; v <= 5; v++ {
+		if !trySend(ch, v) {
+			dropped++
+		}
+	}
+	e.Value("dropped", dropped, "Values dropped because the buffer was full")
+	assert.Equal(check, "three of five values don't fit in a buffer of two", dropped, 3)
+	v, ok := tryReceive(ch)
+	e.Value("v", v, "tryReceive now gets")
+	check.That(ok && v == 1, "the first value sent is the first received")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,106 @@
+// Package selects contains examples about the select statement: which case
+// runs when several are ready, non-blocking operations with default, and
+// timeouts. (The package can't be called select, that is a keyword.)
+package selects
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(randomExample{})
+}
+
+// randomExample runs a select with two cases that are both ready, many
+// times over, and counts which one was picked.
+type randomExample struct{}
+
+func (randomExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/selects/random_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "select picks at random among the cases that are ready",
+		Tags:          []string{"concurrency", "select", "channels"},
+		Prerequisites: []string{"concurrency/channels/buffered_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (randomExample) Explain(step string) string {
+	switch step {
+	case "race":
+		return "Both channels always have a value waiting, so both cases are ready every time the select runs."
+	case "tally":
+		return "Neither case wins every time: select chooses uniformly at random, so neither channel can starve the other."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (randomExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Two cases of a select are ready at the same time. Which one runs?",
+			Choices: []string{"the first one in source order", "the last one in source order", "one of them, chosen at random"},
+			Answer:  "one of them, chosen at random",
+			Explain: "The spec says select chooses via a uniform pseudo-random selection, so case order doesn't give priority.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (randomExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "If several cases of a select are ready, which runs?",
+			Back:  "One chosen uniformly at random. Writing a case first gives it no priority.",
+		},
+	}
+}
+
+// rounds is how many times the select runs.
+const rounds = 1000
+
+// Run lets two always-ready channels compete rounds times.
+func (randomExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Keep one value waiting in each channel, and select between them.
+	e.Step("race")
+	a, b := make(chan int, 1), make(chan int, 1)
+	picks := map[string]int{}
+	for range rounds {
+		a <- 1
+		b <- 1
+		select {
+		case <-a:
+			picks["a"]++
+			<-b // empty b again for the next round
+		case <-b:
+			picks["b"]++
+			<-a
+		}
+	}
+	e.Say("Ran a select between two ready channels %d times.", rounds)
+
+	// 2. How often each case won changes with every run, but both win
+	// about half the time.
+	e.Step("tally")
+	e.Varying("picks[a]", picks["a"], "Times case a was chosen")
+	e.Varying("picks[b]", picks["b"], "Times case b was chosen")
+	assert.Equal(check, "every round picked exactly one case", picks["a"]+picks["b"], rounds)
+	// With a fair coin, falling outside 40-60% in 1000 rounds happens
+	// less than once in a billion runs.
+	check.That(picks["a"] > rounds*4/10 && picks["b"] > rounds*4/10, "both cases are chosen about equally often")
+	return errors.Join(e.Err(), check.Err())
+}
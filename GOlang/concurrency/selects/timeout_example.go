@@ -0,0 +1,106 @@
+package selects
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(timeoutExample{})
+}
+
+// timeoutExample bounds how long it waits for a result with time.After,
+// once per operation and once for a whole loop.
+type timeoutExample struct{}
+
+func (timeoutExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/selects/timeout_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "giving up on slow operations with select and time.After",
+		Tags:          []string{"concurrency", "select", "timeout", "time"},
+		Prerequisites: []string{"concurrency/selects/random_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (timeoutExample) Explain(step string) string {
+	switch step {
+	case "per-call":
+		return "time.After returns a channel that receives after the duration; whichever case is ready first wins."
+	case "overall":
+		return "Creating the timer once, outside the loop, bounds the loop as a whole instead of each receive."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (timeoutExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "A loop does select { case v := <-ch: ...; case <-time.After(time.Second): return } and ch gets a value every 0.5s. When does the loop time out?",
+			Choices: []string{"after 1 second", "never, as long as values keep coming"},
+			Answer:  "never, as long as values keep coming",
+			Explain: "time.After is called again in every iteration, so each wait gets a fresh second. Create the timer before the loop to bound the whole loop.",
+		},
+	}
+}
+
+// slow sends v on the returned channel after delay, like a slow call.
+func slow(v int, delay time.Duration) <-chan int {
+	ch := make(chan int, 1) // buffered, so the goroutine can finish even if nobody waits
+	go func() {
+		time.Sleep(delay)
+		ch <- v
+	}()
+	return ch
+}
+
+// Run waits for fast and slow results with timeouts.
+func (timeoutExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. One timeout per call: the fast one delivers, the slow one times out.
+	e.Step("per-call")
+	for _, delay := range []time.Duration{0, 200 * time.Millisecond} {
+		outcome := "result"
+		select {
+		case <-slow(1, delay):
+		case <-time.After(50 * time.Millisecond):
+			outcome = "timeout"
+		}
+		e.Value("outcome", outcome, "Call taking "+delay.String()+" with a 50ms timeout")
+		want := "result"
+		if delay > 0 {
+			want = "timeout"
+		}
+		assert.Equal(check, "a call taking "+delay.String()+" with a 50ms timeout ends in a "+want, outcome, want)
+	}
+
+	// 2. One timeout for the whole loop: values keep arriving every 20ms,
+	// but the loop still stops after about 100ms.
+	e.Step("overall")
+	deadline := time.After(100 * time.Millisecond)
+	received := 0
+loop:
+	for {
+		select {
+		case <-slow(1, 20*time.Millisecond):
+			received++
+		case <-deadline:
+			break loop
+		}
+	}
+	e.Varying("received", received, "Values received before the overall timeout")
+	check.That(received >= 1 && received <= 5, "an overall timeout of 100ms lets through at most 5 values arriving 20ms apart")
+	return errors.Join(e.Err(), check.Err())
+}
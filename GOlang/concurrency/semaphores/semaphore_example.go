@@ -0,0 +1,173 @@
+// Package semaphores contains examples about bounding how many goroutines
+// do something at once.
+package semaphores
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(semaphoreExample{})
+}
+
+// semaphoreExample starts a goroutine per "download" but lets only a few
+// of them download at once: first with a buffered channel as a counting
+// semaphore, then with golang.org/x/sync/semaphore, whose permits can be
+// weighted and whose Acquire can be cancelled.
+type semaphoreExample struct{}
+
+func (semaphoreExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/semaphores/semaphore_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "counting semaphores from a buffered channel and from x/sync/semaphore",
+		Tags:          []string{"concurrency", "semaphore", "channels", "bounded-concurrency"},
+		Prerequisites: []string{"concurrency/channels/buffered_example", "concurrency/groups/waitgroup_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (semaphoreExample) Explain(step string) string {
+	switch step {
+	case "channel":
+		return "Sending into the buffered channel takes a permit; when the buffer is full the send blocks until somebody receives, which gives a permit back."
+	case "weighted":
+		return "A weighted semaphore hands out permits in any amount: a big download takes two of the four."
+	case "cancel":
+		return "Acquire takes a context, so a goroutine waiting for a permit can give up."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (semaphoreExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "sem := make(chan struct{}, 3). What bounds the goroutines to three at a time?",
+			Choices: []string{"sem <- struct{}{} blocks when the buffer is full", "receiving from an empty channel blocks", "the capacity limits goroutines"},
+			Answer:  "sem <- struct{}{} blocks when the buffer is full",
+			Explain: "Each goroutine sends before it starts and receives when it is done; a fourth send has to wait for a receive.",
+		},
+		{
+			Prompt:  "Why prefer a semaphore over a pool of 3 workers for 1000 downloads?",
+			Choices: []string{"it uses less memory", "it is often simpler: one goroutine per job, no job channel"},
+			Answer:  "it is often simpler: one goroutine per job, no job channel",
+			Explain: "Both bound the concurrency. Goroutines are cheap, so starting one per job and limiting the work inside is a common, simple shape.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (semaphoreExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How do you make a counting semaphore out of a channel?",
+			Back:  "make(chan struct{}, n). Acquire: sem <- struct{}{}. Release: <-sem. At most n goroutines are between the two.",
+		},
+	}
+}
+
+// inFlight tracks how much is being downloaded at once, and the most
+// there ever was.
+type inFlight struct {
+	now, most atomic.Int64
+}
+
+func (f *inFlight) start(n int64) {
+	now := f.now.Add(n)
+	for m := f.most.Load(); now > m && !f.most.CompareAndSwap(m, now); m = f.most.Load() {
+	}
+}
+
+func (f *inFlight) done(n int64) { f.now.Add(-n) }
+
+// download pretends to fetch a file; bigger files take longer.
+func download(f *inFlight, size int64) {
+	f.start(size)
+	defer f.done(size)
+	time.Sleep(time.Duration(size) * 5 * time.Millisecond)
+}
+
+// chanSemaphore is a counting semaphore made of a buffered channel.
+type chanSemaphore chan struct{}
+
+func (s chanSemaphore) acquire() { s <- struct{}{} }
+func (s chanSemaphore) release() { <-s }
+
+// Run downloads 12 files three times, bounded each time.
+func (semaphoreExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	const files, limit = 12, 3
+
+	// 1. A buffered channel with room for 3.
+	e.Step("channel")
+	var f inFlight
+	sem := make(chanSemaphore, limit)
+	var wg sync.WaitGroup
+	for range files {
+		wg.Go(func() {
+			sem.acquire()
+			defer sem.release()
+			download(&f, 1)
+		})
+	}
+	wg.Wait()
+	e.Say("Started %d download goroutines, with a channel semaphore of %d.", files, limit)
+	e.Value("most", f.most.Load(), "Most downloads in flight at once")
+	assert.Equal(check, "the channel semaphore bounds the downloads", f.most.Load(), int64(limit))
+
+	// 2. x/sync/semaphore with 4 permits: every third file is big and
+	// takes 2.
+	e.Step("weighted")
+	var wf inFlight
+	ws := semaphore.NewWeighted(4)
+	for i := range files {
+		size := int64(1)
+		if i%3 == 0 {
+			size = 2
+		}
+		wg.Go(func() {
+			if err := ws.Acquire(ctx, size); err != nil {
+				return
+			}
+			defer ws.Release(size)
+			download(&wf, size)
+		})
+	}
+	wg.Wait()
+	// Whether a waiting big file leaves a permit unused depends on the
+	// order the goroutines queued in.
+	e.Varying("most", wf.most.Load(), "Most permits' worth of downloads in flight at once")
+	check.That(wf.most.Load() <= 4, "the weighted semaphore never lets more than 4 permits' worth run")
+	check.That(wf.most.Load() >= 3, "the weighted semaphore lets several downloads run together")
+
+	// 3. With the permits all taken, an Acquire under a timeout gives up.
+	e.Step("cancel")
+	if err := ws.Acquire(ctx, 4); err != nil {
+		return err
+	}
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err := ws.Acquire(tctx, 1)
+	ws.Release(4)
+	e.Value("err", err, "Acquire while every permit is taken")
+	check.That(errors.Is(err, context.DeadlineExceeded), "Acquire gives up when its context ends")
+	check.That(ws.TryAcquire(4), "after Release, all 4 permits are free again")
+	ws.Release(4)
+	return errors.Join(e.Err(), check.Err())
+}
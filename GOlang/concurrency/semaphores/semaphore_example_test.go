@@ -0,0 +1,42 @@
+package semaphores
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestInFlight(t *testing.T) {
+	var f inFlight
+	f.start(2)
+	f.start(3)
+	f.done(3)
+	f.start(1)
+	if now, most := f.now.Load(), f.most.Load(); now != 3 || most != 5 {
+		t.Errorf("now %d, most %d; want 3 and 5", now, most)
+	}
+}
+
+func TestChanSemaphoreBounds(t *testing.T) {
+	for _, limit := range []int{1, 2, 5} {
+		var f inFlight
+		sem := make(chanSemaphore, limit)
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				sem.acquire()
+				defer sem.release()
+				download(&f, 1)
+			})
+		}
+		wg.Wait()
+		if most := f.most.Load(); most > int64(limit) || most < 1 {
+			t.Errorf("limit %d: %d downloads at once", limit, most)
+		}
+		if now := f.now.Load(); now != 0 {
+			t.Errorf("limit %d: %d downloads still in flight", limit, now)
+		}
+		if len(sem) != 0 {
+			t.Errorf("limit %d: %d permits never released", limit, len(sem))
+		}
+	}
+}
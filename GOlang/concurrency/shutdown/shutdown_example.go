@@ -0,0 +1,275 @@
+// Package shutdown contains an example of stopping a service gracefully:
+// catching the signal, finishing the work in flight, and cleaning up in
+// the right order.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(&shutdownExample{})
+}
+
+// shutdownExample runs a small service, an HTTP server and a pair of
+// background workers, and shuts it down on SIGTERM. By default the
+// example sends the SIGTERM to itself once work is in flight, so every
+// run is the same; try
+//
+//	concepts run concurrency/shutdown/shutdown_example -- -wait
+//
+// to send it yourself with Ctrl-C or kill.
+type shutdownExample struct {
+	wait bool
+}
+
+func (*shutdownExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/shutdown/shutdown_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "graceful shutdown: signal.NotifyContext, draining, timeouts and cleanup order",
+		Tags:          []string{"concurrency", "signals", "shutdown", "http", "context"},
+		Prerequisites: []string{"concurrency/contextdemo/timeout_example", "concurrency/workerpool/pool_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// SetFlags lets the learner send the signal themselves.
+func (s *shutdownExample) SetFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&s.wait, "wait", s.wait, "wait for a real SIGTERM or Ctrl-C instead of sending one")
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (*shutdownExample) Explain(step string) string {
+	switch step {
+	case "work":
+		return "Requests and jobs are in flight: a shutdown now must neither drop them nor wait for ever."
+	case "signal":
+		return "signal.NotifyContext turns SIGTERM into a cancelled context, which the rest of the program already knows how to handle."
+	case "drain":
+		return "Cleanup runs in the reverse order of startup: stop taking requests, finish the work, and only then close what the work uses."
+	case "timeout":
+		return "A request that doesn't finish in time can't hold the shutdown hostage: after the timeout, the server closes its connections."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (*shutdownExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "What does http.Server.Shutdown do with requests that are being handled?",
+			Choices: []string{"cancels them", "waits for them to finish, or for its context to end", "ignores them"},
+			Answer:  "waits for them to finish, or for its context to end",
+			Explain: "It closes the listeners first, so no new requests come in, then waits for the active ones. Close, unlike Shutdown, drops them.",
+		},
+		{
+			Prompt:  "Why close the database after the workers, not before?",
+			Choices: []string{"the workers still use it to finish their jobs", "it is faster"},
+			Answer:  "the workers still use it to finish their jobs",
+			Explain: "Tear things down in the reverse order of their dependencies: users first, then what they use.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (*shutdownExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does signal.NotifyContext(ctx, syscall.SIGTERM) return?",
+			Back:  "A context that is cancelled when SIGTERM arrives, and a stop function that unregisters the signal (after which a second SIGTERM kills the program as usual).",
+		},
+	}
+}
+
+// service is the program being shut down: an HTTP server, workers taking
+// jobs from a queue, and a "database" both of them use.
+type service struct {
+	srv  *http.Server
+	addr string
+	jobs chan int
+	wg   sync.WaitGroup
+
+	handling sync.WaitGroup // requests that have started
+	done     atomic.Int32   // jobs finished
+	dbOpen   atomic.Bool
+
+	mu      sync.Mutex
+	cleanup []string
+}
+
+// note records a cleanup step.
+func (s *service) note(what string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cleanup = append(s.cleanup, what)
+}
+
+// start starts the service. Requests to /slow take delay, unless their
+// connection is closed first; each job takes 20ms.
+func start(delay time.Duration) (*service, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &service{addr: "http://" + ln.Addr().String(), jobs: make(chan int, 8)}
+	s.dbOpen.Store(true)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		s.handling.Done()
+		select {
+		case <-time.After(delay):
+			fmt.Fprintln(w, "done")
+		case <-r.Context().Done(): // the server closed the connection
+		}
+	})
+	s.srv = &http.Server{Handler: mux}
+	go s.srv.Serve(ln)
+	for range 2 {
+		s.wg.Go(func() {
+			for range s.jobs {
+				time.Sleep(20 * time.Millisecond)
+				if s.dbOpen.Load() { // the job's result goes to the database
+					s.done.Add(1)
+				}
+			}
+		})
+	}
+	return s, nil
+}
+
+// request makes count concurrent requests to /slow, waits until the
+// server is handling all of them, and returns a channel that gets each
+// response's status code (or 0 for a failed request).
+func (s *service) request(count int) <-chan int {
+	codes := make(chan int, count)
+	s.handling.Add(count)
+	for range count {
+		go func() {
+			resp, err := http.Get(s.addr + "/slow")
+			if err != nil {
+				codes <- 0
+				return
+			}
+			resp.Body.Close()
+			codes <- resp.StatusCode
+		}()
+	}
+	s.handling.Wait()
+	return codes
+}
+
+// stop shuts the service down in order, giving the HTTP server up to
+// timeout to finish its requests.
+func (s *service) stop(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := s.srv.Shutdown(ctx)
+	if err != nil {
+		s.srv.Close() // out of time: drop what is left
+	}
+	s.note("HTTP server stopped")
+	close(s.jobs)
+	s.wg.Wait()
+	s.note("workers drained")
+	s.dbOpen.Store(false)
+	s.note("database closed")
+	return err
+}
+
+// Run starts the service, puts it to work, and shuts it down on SIGTERM;
+// then shuts down a service whose request won't finish in time.
+func (x *shutdownExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Start the service and give it work.
+	e.Step("work")
+	sigCtx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	s, err := start(100 * time.Millisecond)
+	if err != nil {
+		return err
+	}
+	for i := range 4 {
+		s.jobs <- i
+	}
+	codes := s.request(3)
+	e.Say("3 requests are being handled and 4 jobs are queued.")
+
+	// 2. SIGTERM arrives.
+	e.Step("signal")
+	if x.wait {
+		e.Say("Waiting for SIGTERM or Ctrl-C (process %d)...", os.Getpid())
+	} else if err := sigterm(); err != nil {
+		return err
+	}
+	<-sigCtx.Done()
+	stop() // from now on, a second signal stops the program at once
+	e.Say("Signal received: shutting down.")
+
+	// 3. Drain, with plenty of time.
+	e.Step("drain")
+	if err := s.stop(5 * time.Second); err != nil {
+		return err
+	}
+	var ok int
+	for range 3 {
+		if <-codes == http.StatusOK {
+			ok++
+		}
+	}
+	e.Value("ok", ok, "Requests in flight that completed")
+	e.Value("done", s.done.Load(), "Queued jobs that completed")
+	e.Value("cleanup", s.cleanup, "Cleanup order")
+	assert.Equal(check, "every request in flight completes", ok, 3)
+	assert.Equal(check, "every queued job completes before the database closes", s.done.Load(), int32(4))
+	check.That(slices.Equal(s.cleanup, []string{"HTTP server stopped", "workers drained", "database closed"}), "cleanup runs in reverse order of dependencies")
+	_, err = http.Get(s.addr + "/slow")
+	check.That(err != nil, "a stopped server refuses new connections")
+
+	// 4. A request that takes too long: Shutdown gives up after 50ms.
+	e.Step("timeout")
+	s, err = start(5 * time.Second)
+	if err != nil {
+		return err
+	}
+	codes = s.request(1)
+	began := time.Now()
+	err = s.stop(50 * time.Millisecond)
+	e.Value("err", err, "Shutdown with a 50ms timeout")
+	e.Value("code", <-codes, "Status of the request that was cut off (0: connection closed)")
+	check.That(errors.Is(err, context.DeadlineExceeded), "Shutdown reports that it ran out of time")
+	check.That(time.Since(began) < time.Second, "the shutdown doesn't wait for the stuck request")
+	return errors.Join(e.Err(), check.Err())
+}
+
+// sigterm sends SIGTERM to this process, as "kill" or a container
+// runtime would.
+func sigterm() error {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,215 @@
+// Package synccollections is a few generic collections that are safe to
+// use from many goroutines at once: a Map, a Queue and a Counter. Each
+// guards its data with a mutex of its own, so code using them needs no
+// locking, and no lock can be forgotten. The example in
+// collections_example.go stresses all three.
+package synccollections
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"sync"
+)
+
+// Map is a map guarded by a read-write mutex. The zero value is an empty
+// map ready to use; a Map must not be copied after first use.
+type Map[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// Load returns the value stored under key, and whether there was one.
+func (m *Map[K, V]) Load(key K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[key]
+	return v, ok
+}
+
+// Store sets the value under key.
+func (m *Map[K, V]) Store(key K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.m == nil {
+		m.m = map[K]V{}
+	}
+	m.m[key] = v
+}
+
+// LoadOrStore returns the value under key if there is one, and otherwise
+// stores v and returns it. loaded reports which happened.
+func (m *Map[K, V]) LoadOrStore(key K, v V) (actual V, loaded bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.m[key]; ok {
+		return old, true
+	}
+	if m.m == nil {
+		m.m = map[K]V{}
+	}
+	m.m[key] = v
+	return v, false
+}
+
+// Update replaces the value under key with f's result, atomically: no
+// other change to the map happens between f reading the old value and
+// its result being stored. f gets the zero value and false if key isn't
+// there. f must not use m.
+func (m *Map[K, V]) Update(key K, f func(old V, ok bool) V) V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.m == nil {
+		m.m = map[K]V{}
+	}
+	old, ok := m.m[key]
+	v := f(old, ok)
+	m.m[key] = v
+	return v
+}
+
+// Delete removes key.
+func (m *Map[K, V]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.m, key)
+}
+
+// Len returns the number of keys.
+func (m *Map[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.m)
+}
+
+// All returns the keys and values as they were when it was called. The
+// map may be changed while they are iterated over.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	m.mu.RLock()
+	snapshot := maps.Clone(m.m)
+	m.mu.RUnlock()
+	return maps.All(snapshot)
+}
+
+// Queue is an unbounded first-in, first-out queue. The zero value is an
+// empty queue ready to use; a Queue must not be copied after first use.
+type Queue[T any] struct {
+	mu    sync.Mutex
+	items []T
+	// ready has a value in it while the queue is non-empty, so that Wait
+	// can wait for an item in a select.
+	ready chan struct{}
+}
+
+// signal keeps ready in step with the queue. q.mu must be held.
+func (q *Queue[T]) signal() {
+	if q.ready == nil {
+		q.ready = make(chan struct{}, 1)
+	}
+	if len(q.items) > 0 {
+		select {
+		case q.ready <- struct{}{}:
+		default:
+		}
+	} else {
+		select {
+		case <-q.ready:
+		default:
+		}
+	}
+}
+
+// Push adds v at the back of the queue.
+func (q *Queue[T]) Push(v T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, v)
+	q.signal()
+}
+
+// Pop removes and returns the item at the front of the queue. It returns
+// false at once if the queue is empty.
+func (q *Queue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var zero T
+	if len(q.items) == 0 {
+		return zero, false
+	}
+	v := q.items[0]
+	q.items[0] = zero // don't keep it reachable
+	q.items = q.items[1:]
+	q.signal()
+	return v, true
+}
+
+// Wait is Pop, except that it waits for an item, or returns ctx.Err() if
+// ctx is done first.
+func (q *Queue[T]) Wait(ctx context.Context) (T, error) {
+	for {
+		if v, ok := q.Pop(); ok {
+			return v, nil
+		}
+		q.mu.Lock()
+		q.signal()
+		ready := q.ready
+		q.mu.Unlock()
+		// Another waiter may still take the item first, hence the loop;
+		// a successful Pop passes the value on if items are left.
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Counter counts occurrences of keys. The zero value is ready to use; a
+// Counter must not be copied after first use.
+type Counter[K comparable] struct {
+	mu     sync.Mutex
+	counts map[K]int
+	total  int
+}
+
+// Add adds n to key's count and returns the new count.
+func (c *Counter[K]) Add(key K, n int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = map[K]int{}
+	}
+	c.counts[key] += n
+	c.total += n
+	return c.counts[key]
+}
+
+// Get returns key's count.
+func (c *Counter[K]) Get(key K) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[key]
+}
+
+// Total returns the sum of all counts.
+func (c *Counter[K]) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// Snapshot returns a copy of every count, consistent with Total at the
+// same moment.
+func (c *Counter[K]) Snapshot() (counts map[K]int, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.counts), c.total
+}
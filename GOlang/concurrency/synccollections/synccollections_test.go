@@ -0,0 +1,147 @@
+package synccollections
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapZeroValue(t *testing.T) {
+	var m Map[string, int]
+	if _, ok := m.Load("a"); ok || m.Len() != 0 {
+		t.Fatal("the zero Map isn't empty")
+	}
+	m.Delete("a")
+	if v, loaded := m.LoadOrStore("a", 1); v != 1 || loaded {
+		t.Errorf("LoadOrStore on a missing key = %v, %v; want 1, false", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore("a", 2); v != 1 || !loaded {
+		t.Errorf("LoadOrStore on a present key = %v, %v; want 1, true", v, loaded)
+	}
+	m.Store("b", 3)
+	m.Delete("a")
+	if v, ok := m.Load("b"); v != 3 || !ok || m.Len() != 1 {
+		t.Errorf("after Store and Delete: Load(b) = %v, %v; Len() = %d", v, ok, m.Len())
+	}
+}
+
+func TestMapConcurrent(t *testing.T) {
+	var m Map[int, int]
+	var stored atomic.Int64
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				m.Update(i%10, func(old int, _ bool) int { return old + 1 })
+				if _, loaded := m.LoadOrStore(100+i%10, g); !loaded {
+					stored.Add(1)
+				}
+				for range m.All() {
+				}
+			}
+		})
+	}
+	wg.Wait()
+	for key := range 10 {
+		if v, _ := m.Load(key); v != 800 {
+			t.Errorf("m[%d] = %d, want 800: an Update was lost", key, v)
+		}
+	}
+	if n := stored.Load(); n != 10 {
+		t.Errorf("LoadOrStore stored %d times, want once per key", n)
+	}
+}
+
+func TestMapAllIsASnapshot(t *testing.T) {
+	var m Map[int, bool]
+	m.Store(1, true)
+	m.Store(2, true)
+	n := 0
+	for k := range m.All() {
+		m.Delete(k) // doesn't deadlock, or change what is iterated
+		m.Store(k+10, true)
+		n++
+	}
+	if n != 2 || m.Len() != 2 {
+		t.Errorf("iterated %d keys and left %d, want 2 and 2", n, m.Len())
+	}
+}
+
+func TestQueueOrder(t *testing.T) {
+	var q Queue[int]
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Pop on an empty queue succeeded")
+	}
+	for i := range 3 {
+		q.Push(i)
+	}
+	var got []int
+	for v, ok := q.Pop(); ok; v, ok = q.Pop() {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{0, 1, 2}) || q.Len() != 0 {
+		t.Errorf("popped %v, leaving %d; want [0 1 2], leaving none", got, q.Len())
+	}
+}
+
+func TestQueueWait(t *testing.T) {
+	var q Queue[int]
+	const items = 1000
+	var got atomic.Int64
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for range 4 {
+		wg.Go(func() {
+			for {
+				if _, err := q.Wait(ctx); err != nil {
+					return
+				}
+				if got.Add(1) == items {
+					cancel()
+				}
+			}
+		})
+	}
+	for i := range items {
+		q.Push(i)
+	}
+	wg.Wait()
+	if n := got.Load(); n != items || q.Len() != 0 {
+		t.Errorf("the waiters took %d items and left %d, want %d and none", n, q.Len(), items)
+	}
+}
+
+func TestQueueWaitCancelled(t *testing.T) {
+	var q Queue[int]
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait on an empty queue = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	var c Counter[string]
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 500 {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				if counts, total := c.Snapshot(); counts["a"]+counts["b"] != total {
+					t.Errorf("Snapshot counts %v don't add up to %d", counts, total)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if a, b, total := c.Get("a"), c.Get("b"), c.Total(); a != 4000 || b != 8000 || total != 12000 {
+		t.Errorf("a %d, b %d, total %d; want 4000, 8000 and 12000", a, b, total)
+	}
+}
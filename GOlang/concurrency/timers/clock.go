@@ -0,0 +1,209 @@
+// Package timers contains examples about time.Timer, time.Ticker and
+// time.AfterFunc: firing, stopping, resetting, and the stale-value gotcha
+// that Go 1.23 removed.
+//
+// Waiting for real seconds would make the examples slow and their timing
+// flaky, so the code in them takes a Clock, and Run passes a fake one
+// whose time only moves when the example calls Advance. Production code
+// built the same way is tested the same way.
+package timers
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock is the part of the time package the examples use.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a *time.Timer behind an interface. C is nil for timers made by
+// AfterFunc.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is a *time.Ticker behind an interface.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// realClock is the time package itself.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+func (realClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return realTimer{time.AfterFunc(d, f)} }
+
+type realTimer struct{ *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// fakeClock is a Clock whose time stands still until Advance moves it.
+// Timers and tickers fire during Advance, in order, each seeing Now as
+// the moment it was due. Their channels hold one value, like the time
+// package's did before Go 1.23; a value that doesn't fit is dropped.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	// legacy makes Stop and Reset leave a fired value in the channel, as
+	// they did before Go 1.23.
+	legacy bool
+}
+
+// newFakeClock returns a fake clock set to midnight on 1 January 2025.
+func newFakeClock(legacy bool) *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), legacy: legacy}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// since returns how long after midnight t is, for printing.
+func (c *fakeClock) since(t time.Time) time.Duration {
+	return t.Sub(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (c *fakeClock) add(d, period time.Duration, f func()) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, when: c.now.Add(d), period: period, f: f, active: true}
+	if f == nil {
+		t.ch = make(chan time.Time, 1)
+	}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer { return c.add(d, 0, nil) }
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker { return fakeTicker{c.add(d, d, nil)} }
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer { return c.add(d, 0, f) }
+
+// Advance moves the clock forward by d, firing everything that comes due
+// on the way. AfterFunc functions run in the goroutine calling Advance.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	for {
+		t := c.next(end)
+		if t == nil {
+			break
+		}
+		c.now = t.when
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			t.active = false
+		}
+		if t.f != nil {
+			c.mu.Unlock()
+			t.f()
+			c.mu.Lock()
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default: // nobody took the last value: this one is dropped
+		}
+	}
+	c.now = end
+	c.mu.Unlock()
+}
+
+// next returns the active timer that is due first, no later than end.
+func (c *fakeClock) next(end time.Time) *fakeTimer {
+	due := slices.DeleteFunc(slices.Clone(c.timers), func(t *fakeTimer) bool {
+		return !t.active || t.when.After(end)
+	})
+	if len(due) == 0 {
+		return nil
+	}
+	return slices.MinFunc(due, func(a, b *fakeTimer) int { return a.when.Compare(b.when) })
+}
+
+// fakeTimer is a timer, ticker or AfterFunc of a fakeClock.
+type fakeTimer struct {
+	clock  *fakeClock
+	ch     chan time.Time
+	when   time.Time
+	period time.Duration
+	f      func()
+	active bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+// stop deactivates the timer and, unless the clock is legacy, takes back
+// a value it sent that nobody received: since Go 1.23 such a timer counts
+// as not having fired yet. It reports whether the timer was active.
+func (t *fakeTimer) stop() bool {
+	was := t.active
+	t.active = false
+	if !t.clock.legacy && t.ch != nil {
+		select {
+		case <-t.ch:
+			was = true
+		default:
+		}
+	}
+	return was
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.stop()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	was := t.stop()
+	t.active, t.when = true, t.clock.now.Add(d)
+	return was
+}
+
+// fakeTicker is a fakeTimer that repeats.
+type fakeTicker struct{ *fakeTimer }
+
+func (t fakeTicker) Stop() { t.fakeTimer.Stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stop()
+	t.active, t.when, t.period = true, t.clock.now.Add(d), d
+}
+
+// fired reports whether a value is waiting in ch, taking it if so.
+func fired(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case v := <-ch:
+		return v, true
+	default:
+		return time.Time{}, false
+	}
+}
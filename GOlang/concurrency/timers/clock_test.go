@@ -0,0 +1,103 @@
+package timers
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFakeTimer(t *testing.T) {
+	c := newFakeClock(false)
+	tm := c.NewTimer(5 * time.Second)
+	c.Advance(4 * time.Second)
+	if _, ok := fired(tm.C()); ok {
+		t.Fatal("the timer fired a second early")
+	}
+	c.Advance(time.Second)
+	v, ok := fired(tm.C())
+	if !ok || c.since(v) != 5*time.Second {
+		t.Fatalf("fired(C) = %v, %v; want the time 5s after midnight", c.since(v), ok)
+	}
+	c.Advance(time.Hour)
+	if _, ok := fired(tm.C()); ok {
+		t.Error("a timer fired twice")
+	}
+}
+
+func TestAdvanceFiresInOrder(t *testing.T) {
+	c := newFakeClock(false)
+	var order []time.Duration
+	for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		c.AfterFunc(d, func() { order = append(order, c.since(c.Now())) })
+	}
+	c.Advance(10 * time.Second)
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if !slices.Equal(order, want) {
+		t.Errorf("the functions ran at %v, want %v", order, want)
+	}
+	if got := c.since(c.Now()); got != 10*time.Second {
+		t.Errorf("Now is %v after midnight, want 10s", got)
+	}
+}
+
+func TestTickerDropsMissedTicks(t *testing.T) {
+	c := newFakeClock(false)
+	tk := c.NewTicker(time.Second)
+	c.Advance(3500 * time.Millisecond)
+	v, ok := fired(tk.C())
+	if !ok || c.since(v) != time.Second {
+		t.Fatalf("first tick at %v, %v; want 1s", c.since(v), ok)
+	}
+	if _, ok := fired(tk.C()); ok {
+		t.Error("the ticks nobody received were kept")
+	}
+	tk.Reset(2 * time.Second)
+	c.Advance(2 * time.Second)
+	if v, ok := fired(tk.C()); !ok || c.since(v) != 5500*time.Millisecond {
+		t.Errorf("after Reset, tick at %v, %v; want 5.5s", c.since(v), ok)
+	}
+	tk.Stop()
+	c.Advance(time.Hour)
+	if _, ok := fired(tk.C()); ok {
+		t.Error("a stopped ticker ticked")
+	}
+}
+
+func TestStopAfterFiring(t *testing.T) {
+	for _, tt := range []struct {
+		legacy bool
+		stop   bool // what Stop returns
+		stale  bool // whether the value is still in the channel
+	}{
+		{legacy: false, stop: true, stale: false},
+		{legacy: true, stop: false, stale: true},
+	} {
+		c := newFakeClock(tt.legacy)
+		tm := c.NewTimer(time.Second)
+		c.Advance(time.Second) // fires, and nobody receives
+		if got := tm.Stop(); got != tt.stop {
+			t.Errorf("legacy %v: Stop() = %v, want %v", tt.legacy, got, tt.stop)
+		}
+		if _, ok := fired(tm.C()); ok != tt.stale {
+			t.Errorf("legacy %v: a value is left in C: %v, want %v", tt.legacy, ok, tt.stale)
+		}
+	}
+}
+
+func TestAdvanceWhileWaiting(t *testing.T) {
+	c := newFakeClock(false)
+	tk := c.NewTicker(time.Second)
+	got := make(chan time.Duration)
+	go func() {
+		for range 3 {
+			got <- c.since(<-tk.C())
+		}
+	}()
+	for i := 1; i <= 3; i++ {
+		c.Advance(time.Second)
+		if d := <-got; d != time.Duration(i)*time.Second {
+			t.Errorf("tick %d at %v", i, d)
+		}
+	}
+	tk.Stop()
+}
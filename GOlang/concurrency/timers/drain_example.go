@@ -0,0 +1,99 @@
+package timers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(drainExample{})
+}
+
+// drainExample shows the stale-value gotcha of timers before Go 1.23,
+// the idiom that worked around it, and that the time package no longer
+// needs it. The old behavior can't be had from the time package any
+// more, so the first two steps use a fake clock that imitates it.
+type drainExample struct{}
+
+func (drainExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/timers/drain_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "the pre-Go 1.23 timer drain gotcha, its workaround, and the fix",
+		Tags:          []string{"concurrency", "time", "timers", "gotchas"},
+		Prerequisites: []string{"concurrency/timers/timer_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (drainExample) Explain(step string) string {
+	switch step {
+	case "stale":
+		return "Before Go 1.23, a timer that fired put its value in a buffered channel, and Reset didn't take it out again."
+	case "idiom":
+		return "The workaround: stop the timer, and if it had already fired, receive the stale value before resetting."
+	case "go1.23":
+		return "Since Go 1.23, Stop and Reset make sure no stale value can be received afterwards; the idiom isn't needed."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (drainExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Before Go 1.23, a timer fired unnoticed and was Reset for 1 minute. What did the next receive from t.C do?",
+			Choices: []string{"wait a minute", "return the stale value at once"},
+			Answer:  "return the stale value at once",
+			Explain: "The value from the first firing was still in the channel's buffer; code that used it as a timeout timed out immediately.",
+		},
+	}
+}
+
+// Run resets a timer that fired unnoticed, three ways.
+func (drainExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Old behavior: the value of the first firing is still there.
+	e.Step("stale")
+	old := newFakeClock(true)
+	t := old.NewTimer(time.Second)
+	old.Advance(2 * time.Second) // fires, but nobody is looking
+	t.Reset(time.Minute)
+	_, stale := fired(t.C())
+	e.Value("stale", stale, "Right after Reset(time.Minute), a receive got a value")
+	check.That(stale, "before Go 1.23, Reset leaves the stale value in the channel")
+	e.Warn("A timeout built on this timer would have expired at once instead of after a minute.")
+
+	// 2. The workaround, still on the old behavior.
+	e.Step("idiom")
+	t = old.NewTimer(time.Second)
+	old.Advance(2 * time.Second)
+	if !t.Stop() {
+		<-t.C() // drain the stale value; only safe if nobody received it yet
+	}
+	t.Reset(time.Minute)
+	_, stale = fired(t.C())
+	e.Value("stale", stale, "With the drain before Reset, a receive got a value")
+	check.That(!stale, "draining after Stop removes the stale value")
+
+	// 3. The time package today: nothing stale, no idiom.
+	e.Step("go1.23")
+	rt := realClock{}.NewTimer(time.Millisecond)
+	time.Sleep(5 * time.Millisecond) // fires, but nobody is looking
+	rt.Reset(time.Hour)
+	_, stale = fired(rt.C())
+	rt.Stop()
+	e.Value("stale", stale, "time.Timer, right after Reset(time.Hour), a receive got a value")
+	check.That(!stale, "since Go 1.23, Reset leaves no stale value")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,110 @@
+package timers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(tickerExample{})
+}
+
+// tickerExample watches a ticker on a fake clock: its ticks, what happens
+// to ticks nobody is ready for, Reset and Stop.
+type tickerExample struct{}
+
+func (tickerExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/timers/ticker_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "time.Ticker: regular ticks, dropped ticks, Reset and Stop",
+		Tags:          []string{"concurrency", "time", "tickers", "testing"},
+		Prerequisites: []string{"concurrency/timers/timer_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (tickerExample) Explain(step string) string {
+	switch step {
+	case "tick":
+		return "A ticker sends the time on its channel every period, until it is stopped."
+	case "slow":
+		return "The channel holds one tick. A receiver that falls behind doesn't get a backlog: the ticks that don't fit are dropped."
+	case "reset":
+		return "Reset changes the period; the next tick comes one new period after the Reset."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (tickerExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "A 1s ticker's receiver is busy for 5s. How many ticks are waiting when it comes back?",
+			Choices: []string{"5", "1", "0"},
+			Answer:  "1",
+			Explain: "The ticker drops ticks for slow receivers instead of queueing them, so the receiver doesn't fall ever further behind.",
+		},
+	}
+}
+
+// Run ticks a 1s ticker for a while.
+func (tickerExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	clock := newFakeClock(false)
+
+	// 1. Three ticks, each received right away.
+	e.Step("tick")
+	tk := clock.NewTicker(time.Second)
+	var ticks []time.Duration
+	for range 3 {
+		clock.Advance(time.Second)
+		if at, ok := fired(tk.C()); ok {
+			ticks = append(ticks, clock.since(at))
+		}
+	}
+	e.Value("ticks", ticks, "Ticks received")
+	assert.Equal(check, "a 1s ticker ticks every second", len(ticks), 3)
+
+	// 2. Nobody receives for 5s: only one tick is waiting.
+	e.Step("slow")
+	clock.Advance(5 * time.Second)
+	waiting := len(tk.C())
+	at, _ := fired(tk.C())
+	e.Value("waiting", waiting, "Ticks waiting after 5s of not receiving")
+	e.Value("at", clock.since(at), "The waiting tick is from")
+	assert.Equal(check, "a slow receiver finds one tick, not five", waiting, 1)
+	assert.Equal(check, "the tick that waits is the first one", clock.since(at), 4*time.Second)
+
+	// 3. Reset to 2s: ticks at +2s and +4s.
+	e.Step("reset")
+	tk.Reset(2 * time.Second)
+	start := clock.Now()
+	ticks = nil
+	for range 4 {
+		clock.Advance(time.Second)
+		if at, ok := fired(tk.C()); ok {
+			ticks = append(ticks, at.Sub(start))
+		}
+	}
+	e.Value("ticks", ticks, "Ticks in the 4s after Reset(2s)")
+	assert.Equal(check, "after Reset(2s) it ticks every 2s", len(ticks), 2)
+
+	// 4. Stop: no more ticks, ever.
+	e.Step("stop")
+	tk.Stop()
+	clock.Advance(time.Hour)
+	_, ok := fired(tk.C())
+	e.Value("ok", ok, "A tick arrived in the hour after Stop")
+	check.That(!ok, "a stopped ticker doesn't tick")
+	return errors.Join(e.Err(), check.Err())
+}
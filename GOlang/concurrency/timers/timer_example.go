@@ -0,0 +1,125 @@
+package timers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(timerExample{})
+}
+
+// timerExample fires, stops and resets timers, and runs a function with
+// AfterFunc, on a fake clock.
+type timerExample struct{}
+
+func (timerExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/timers/timer_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "time.Timer and time.AfterFunc: firing, Stop and Reset",
+		Tags:          []string{"concurrency", "time", "timers", "testing"},
+		Prerequisites: []string{"concurrency/selects/timeout_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (timerExample) Explain(step string) string {
+	switch step {
+	case "fire":
+		return "A timer sends the time on its channel once, when it is due. A receive before then would block."
+	case "stop":
+		return "Stop returns true if it stopped the timer in time, false if the timer had already fired or been stopped."
+	case "reset":
+		return "Reset rearms a timer, fired or not, to go off d after now."
+	case "afterfunc":
+		return "AfterFunc has no channel: when it is due, it calls the function in a goroutine of its own."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (timerExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "t := time.AfterFunc(time.Second, f); t.Stop() returns true. Has f run?",
+			Choices: []string{"yes", "no, and it won't"},
+			Answer:  "no, and it won't",
+			Explain: "true means Stop got there first. false would mean f has already been started.",
+		},
+		{
+			Prompt:  "Why does a test of code using time.NewTimer(time.Minute) not want to wait a minute?",
+			Choices: []string{"it doesn't have to: pass the code a clock, and give the test a fake one", "it has to"},
+			Answer:  "it doesn't have to: pass the code a clock, and give the test a fake one",
+			Explain: "This example does that: its time only moves when it calls Advance, so a minute takes no time at all.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (timerExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How do you make code that uses timers testable without sleeping?",
+			Back:  "Inject a clock: the code calls clock.NewTimer instead of time.NewTimer, and tests pass a fake clock that they advance by hand.",
+		},
+	}
+}
+
+// Run drives four timers on a fake clock.
+func (timerExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	clock := newFakeClock(false)
+
+	// 1. A 5s timer: not yet at 3s, fired at 5s.
+	e.Step("fire")
+	t := clock.NewTimer(5 * time.Second)
+	clock.Advance(3 * time.Second)
+	_, ok := fired(t.C())
+	e.Value("ok", ok, "Fired after 3s")
+	check.That(!ok, "a 5s timer hasn't fired after 3s")
+	clock.Advance(2 * time.Second)
+	at, ok := fired(t.C())
+	e.Value("at", clock.since(at), "Fired, with the time it was due")
+	check.That(ok && clock.since(at) == 5*time.Second, "a 5s timer fires at 5s")
+
+	// 2. Stop in time, and too late.
+	e.Step("stop")
+	t2 := clock.NewTimer(5 * time.Second)
+	e.Value("stopped", t2.Stop(), "Stop before it fires")
+	clock.Advance(10 * time.Second)
+	_, ok = fired(t2.C())
+	check.That(!ok, "a stopped timer never fires")
+	e.Value("stopped", t.Stop(), "Stop on the timer that fired (and was received)")
+	check.That(!t.Stop(), "Stop on a fired timer returns false")
+
+	// 3. Reset the fired timer for another 2s.
+	e.Step("reset")
+	t.Reset(2 * time.Second)
+	start := clock.Now()
+	clock.Advance(2 * time.Second)
+	at, ok = fired(t.C())
+	e.Value("after", at.Sub(start), "The reset timer fired after")
+	check.That(ok && at.Sub(start) == 2*time.Second, "a reset timer fires d after the Reset")
+
+	// 4. AfterFunc: a function instead of a channel.
+	e.Step("afterfunc")
+	calls := 0
+	af := clock.AfterFunc(time.Second, func() { calls++ })
+	clock.Advance(time.Second)
+	clock.Advance(time.Minute)
+	e.Value("calls", calls, "Calls after a minute")
+	assert.Equal(check, "AfterFunc calls f once", calls, 1)
+	check.That(!af.Stop(), "Stop after f ran returns false")
+	return errors.Join(e.Err(), check.Err())
+}
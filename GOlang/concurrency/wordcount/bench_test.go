@@ -0,0 +1,11 @@
+package wordcount
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkWordCount(b *testing.B) { benchlab.Bench(b, wordCountExample{}) }
@@ -0,0 +1,80 @@
+// Package wordcount solves one problem, counting the words of many
+// documents at once, in the two styles Go offers: goroutines updating a
+// shared map under a mutex, and goroutines sending their counts to a
+// reducer goroutine that owns the map. The example in
+// wordcount_example.go checks that they agree, and benchmarks them.
+package wordcount
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// words splits doc into lower-case words.
+func words(doc string) []string {
+	return strings.FieldsFunc(strings.ToLower(doc), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
+
+// distribute starts workers goroutines that each call do for some of docs,
+// and returns when all of them are done.
+func distribute(docs []string, workers int, do func(doc string)) {
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for doc := range jobs {
+				do(doc)
+			}
+		})
+	}
+	for _, doc := range docs {
+		jobs <- doc
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// Shared counts the words of docs with workers goroutines, all adding to
+// one map guarded by a mutex. Every word is one lock and unlock: simple,
+// but the goroutines queue for the lock.
+func Shared(docs []string, workers int) map[string]int {
+	var mu sync.Mutex
+	counts := map[string]int{}
+	distribute(docs, workers, func(doc string) {
+		for _, w := range words(doc) {
+			mu.Lock()
+			counts[w]++
+			mu.Unlock()
+		}
+	})
+	return counts
+}
+
+// Messages counts the words of docs with workers goroutines, each counting
+// a document on its own and sending the result to a reducer goroutine,
+// the only one to touch the total.
+func Messages(docs []string, workers int) map[string]int {
+	partials := make(chan map[string]int, workers)
+	total := make(chan map[string]int)
+	go func() {
+		counts := map[string]int{}
+		for partial := range partials {
+			for w, n := range partial {
+				counts[w] += n
+			}
+		}
+		total <- counts
+	}()
+	distribute(docs, workers, func(doc string) {
+		partial := map[string]int{}
+		for _, w := range words(doc) {
+			partial[w]++
+		}
+		partials <- partial
+	})
+	close(partials)
+	return <-total
+}
@@ -0,0 +1,207 @@
+package wordcount
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(wordCountExample{})
+}
+
+// wordCountExample counts the words of the same documents with Shared
+// and with Messages from wordcount.go. The numbers are the same; run
+//
+//	concepts bench concurrency
+//
+// to see what each approach costs.
+type wordCountExample struct{}
+
+func (wordCountExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/wordcount/wordcount_example",
+		Topic:         "concurrency",
+		Level:         registry.Advanced,
+		Description:   "the same word count with a mutex-guarded shared map and with channels and a reducer",
+		Tags:          []string{"concurrency", "mutex", "channels", "message-passing", "case-study"},
+		Prerequisites: []string{"concurrency/actors/account_example", "concurrency/patterns/fan_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (wordCountExample) Explain(step string) string {
+	switch step {
+	case "shared":
+		return "Every worker adds each word straight to the one map, taking the mutex every time."
+	case "messages":
+		return "Each worker counts a whole document in a map of its own, and sends it to the reducer; no map is ever shared."
+	case "compare":
+		return "Both ways must give the same counts; the difference is in how much the goroutines have to coordinate to get there."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (wordCountExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Messages sends one map per document to the reducer. What if it sent one message per word instead?",
+			Choices: []string{"about as fast", "much slower: a channel operation per word", "faster"},
+			Answer:  "much slower: a channel operation per word",
+			Explain: "Message passing pays per message, so batch the work into large messages. The benchmark includes this version.",
+		},
+		{
+			Prompt:  "Which version can have a data race if someone forgets a line?",
+			Choices: []string{"Shared", "Messages"},
+			Answer:  "Shared",
+			Explain: "Forget mu.Lock and the map is written by several goroutines at once. In Messages, each map has a single owner by construction.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench concurrency".
+func (wordCountExample) Experiments() []benchlab.Experiment {
+	docs := corpus()
+	return []benchlab.Experiment{{
+		Name: fmt.Sprintf("counting the words of %d documents with %d workers", len(docs), workers),
+		Approaches: []benchlab.Approach{
+			{Name: "shared map + mutex", Bench: func(b *testing.B) {
+				for range b.N {
+					Shared(docs, workers)
+				}
+			}},
+			{Name: "channels + reducer", Bench: func(b *testing.B) {
+				for range b.N {
+					Messages(docs, workers)
+				}
+			}},
+			{Name: "a message per word", Bench: func(b *testing.B) {
+				for range b.N {
+					perWord(docs, workers)
+				}
+			}},
+		},
+		Guidance: "The shared map takes a lock per word, so the workers spend their time " +
+			"queueing for it, more so the more cores there are. Counting each document " +
+			"alone and sending one map to the reducer needs no lock at all and only one " +
+			"channel operation per document, which usually makes it the fastest, at the cost of " +
+			"building and merging the partial maps. A message per word is message " +
+			"passing done wrong: the channel becomes the lock, and a slower one. The " +
+			"lesson is less \"mutexes vs channels\" than \"share as little as often as you can\".",
+	}}
+}
+
+const workers = 4
+
+// vocabulary is what the documents are made of.
+var vocabulary = strings.Fields(`the a of and to in is it that was he for on are
+	with as his they be at one have this from or had by hot word but what some
+	we can out other were all there when up use your how said an each she`)
+
+// corpus returns 200 documents of 500 pseudo-random words each, the same
+// every time. Words near the start of the vocabulary are more frequent,
+// as in English.
+func corpus() []string {
+	r := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(r, 1.2, 1, uint64(len(vocabulary)-1))
+	docs := make([]string, 200)
+	for i := range docs {
+		var b strings.Builder
+		for j := range 500 {
+			if j > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(vocabulary[zipf.Uint64()])
+		}
+		docs[i] = b.String()
+	}
+	return docs
+}
+
+// perWord is Messages with a message for every word.
+func perWord(docs []string, workers int) map[string]int {
+	found := make(chan string, 64)
+	total := make(chan map[string]int)
+	go func() {
+		counts := map[string]int{}
+		for w := range found {
+			counts[w]++
+		}
+		total <- counts
+	}()
+	distribute(docs, workers, func(doc string) {
+		for _, w := range words(doc) {
+			found <- w
+		}
+	})
+	close(found)
+	return <-total
+}
+
+// top returns the n most frequent words of counts with their counts.
+func top(counts map[string]int, n int) []string {
+	ws := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(ws, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+	var out []string
+	for _, w := range ws[:n] {
+		out = append(out, fmt.Sprintf("%s=%d", w, counts[w]))
+	}
+	return out
+}
+
+// Run counts the corpus both ways and compares the results.
+func (wordCountExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	docs := corpus()
+	total := 0
+	for _, doc := range docs {
+		total += len(words(doc))
+	}
+
+	// 1. Shared memory: one map, one mutex.
+	e.Step("shared")
+	e.Say("Counting the words of %d documents (%d words) with %d workers.", len(docs), total, workers)
+	began := time.Now()
+	shared := Shared(docs, workers)
+	e.Varying("elapsed", time.Since(began).Round(time.Microsecond), "Shared map + mutex took")
+	e.Value("top", top(shared, 5), "Most frequent words")
+
+	// 2. Message passing: partial counts sent to a reducer.
+	e.Step("messages")
+	began = time.Now()
+	messages := Messages(docs, workers)
+	e.Varying("elapsed", time.Since(began).Round(time.Microsecond), "Channels + reducer took")
+	e.Value("top", top(messages, 5), "Most frequent words")
+
+	// 3. The same answer either way.
+	e.Step("compare")
+	sum := 0
+	for _, n := range messages {
+		sum += n
+	}
+	e.Value("distinct", len(messages), "Distinct words")
+	e.Value("sum", sum, "Words counted")
+	check.That(maps.Equal(shared, messages), "both approaches count every word the same")
+	assert.Equal(check, "every word is counted once", sum, total)
+	check.That(maps.Equal(Shared(docs, 1), shared), "the counts don't depend on the number of workers")
+	e.Say("Run \"concepts bench concurrency\" to compare their cost, and a third version with a message per word.")
+	return errors.Join(e.Err(), check.Err())
+}
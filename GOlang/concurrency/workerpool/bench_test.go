@@ -0,0 +1,11 @@
+package workerpool
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkPool(b *testing.B) { benchlab.Bench(b, poolExample{}) }
@@ -0,0 +1,164 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(poolExample{})
+}
+
+// poolExample processes a batch of jobs with the Pool from workerpool.go:
+// submit, collect the results, and drain the pool when done.
+type poolExample struct{}
+
+func (poolExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "concurrency/workerpool/pool_example",
+		Topic:         "concurrency",
+		Level:         registry.Intermediate,
+		Description:   "a generic worker pool: submitting jobs, collecting results, draining gracefully",
+		Tags:          []string{"concurrency", "worker-pool", "channels", "generics"},
+		Prerequisites: []string{"concurrency/channels/buffered_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (poolExample) Explain(step string) string {
+	switch step {
+	case "submit":
+		return "A goroutine submits the jobs while this one collects results, so neither side can block the other for good."
+	case "collect":
+		return "Results arrive in the order the jobs finish, not the order they were submitted."
+	case "drain":
+		return "Close stops new jobs but lets the queued ones finish; the results channel closes once they have."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz concurrency".
+func (poolExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Jobs 1, 2, 3 are submitted to a pool with 3 workers. In which order do their results arrive?",
+			Choices: []string{"1, 2, 3", "3, 2, 1", "in whatever order the jobs finish"},
+			Answer:  "in whatever order the jobs finish",
+			Explain: "Each worker sends its result as soon as its job is done; a quick job 3 can easily beat a slow job 1.",
+		},
+		{
+			Prompt:  "What happens to jobs already in the queue when Close is called?",
+			Choices: []string{"they are dropped", "they still run", "Close panics"},
+			Answer:  "they still run",
+			Explain: "Close only closes the job channel; workers keep ranging over it until the queued jobs are done.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench concurrency".
+func (poolExample) Experiments() []benchlab.Experiment {
+	ex := benchlab.Experiment{
+		Name: "16 jobs of 1ms waiting each, by pool size",
+		Guidance: "Each job spends its time waiting (like a network call), so workers " +
+			"overlap their waits and the batch gets faster with every worker added, " +
+			"up to one worker per job. For CPU-bound jobs the gains stop at the " +
+			"number of cores (runtime.NumCPU), and extra workers only add overhead.",
+	}
+	for _, size := range []int{1, 2, 4, 8, 16} {
+		name := fmt.Sprintf("%d workers", size)
+		if size == 1 {
+			name = "1 worker"
+		}
+		ex.Approaches = append(ex.Approaches, benchlab.Approach{
+			Name: name,
+			Bench: func(b *testing.B) {
+				for range b.N {
+					runBatch(size, 16, time.Millisecond)
+				}
+			},
+		})
+	}
+	return []benchlab.Experiment{ex}
+}
+
+// runBatch processes jobs jobs, each waiting for delay, on a pool of the
+// given size.
+func runBatch(size, jobs int, delay time.Duration) {
+	p := New(size, func(int) int { time.Sleep(delay); return 0 })
+	go func() {
+		for i := range jobs {
+			p.Submit(context.Background(), i)
+		}
+		p.Close()
+	}()
+	for range p.Results() {
+	}
+}
+
+// job is one piece of work: square n, taking a moment to do it.
+type job struct {
+	n     int
+	delay time.Duration
+}
+
+// square is the work function of the pool.
+func square(j job) int {
+	time.Sleep(j.delay)
+	return j.n * j.n
+}
+
+// Run squares the numbers 1 to 8 on a pool of 3 workers.
+func (poolExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Start the pool and submit from a separate goroutine. The later
+	// jobs are quicker, so they can overtake the earlier ones.
+	e.Step("submit")
+	pool := New(3, square)
+	submitted := make(chan error, 1)
+	go func() {
+		defer pool.Close() // 3. drain: no more jobs, let the queued ones finish
+		for n := 1; n <= 8; n++ {
+			if err := pool.Submit(ctx, job{n: n, delay: time.Duration(9-n) * time.Millisecond}); err != nil {
+				submitted <- err
+				return
+			}
+		}
+		submitted <- nil
+	}()
+	e.Say("Submitting 8 jobs to a pool of 3 workers.")
+
+	// 2. Collect every result. The loop ends when the pool has drained
+	// and closed the results channel.
+	e.Step("collect")
+	var results []int
+	for r := range pool.Results() {
+		results = append(results, r)
+	}
+	if err := <-submitted; err != nil {
+		return err
+	}
+	e.Varying("results", results, "Results in the order they arrived")
+	slices.Sort(results)
+	e.Value("results", results, "Results sorted")
+	assert.Equal(check, "every job produced its square", fmt.Sprint(results), "[1 4 9 16 25 36 49 64]")
+
+	// 4. After the drain, the pool refuses new work.
+	e.Step("drain")
+	err := pool.Submit(ctx, job{n: 9})
+	e.Value("err", err, "Submit after Close")
+	check.That(errors.Is(err, ErrClosed), "a closed pool rejects new jobs")
+	return errors.Join(e.Err(), check.Err())
+}
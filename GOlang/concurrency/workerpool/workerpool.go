@@ -0,0 +1,86 @@
+// Package workerpool is a small, reusable worker pool: a fixed number of
+// goroutines taking jobs from a queue and sending their results to one
+// channel. The example in pool_example.go walks through using it.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned by Submit after Close.
+var ErrClosed = errors.New("workerpool: pool is closed")
+
+// Pool runs jobs of type J on a fixed number of worker goroutines, each
+// job producing a result of type R.
+//
+// Results must be received while jobs are being submitted: once the queue
+// and the results channel are both full, Submit waits for room.
+type Pool[J, R any] struct {
+	jobs    chan J
+	results chan R
+
+	mu     sync.RWMutex // guards closed, and jobs against being closed mid-send
+	closed bool
+}
+
+// New starts a pool with the given number of workers, each handling one
+// job at a time with work. The job queue holds up to workers jobs that
+// haven't been picked up yet.
+func New[J, R any](workers int, work func(J) R) *Pool[J, R] {
+	p := &Pool[J, R]{
+		jobs:    make(chan J, workers),
+		results: make(chan R, workers),
+	}
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for job := range p.jobs {
+				p.results <- work(job)
+			}
+		})
+	}
+	// Results is closed once the last worker has sent its last result.
+	go func() {
+		wg.Wait()
+		close(p.results)
+	}()
+	return p
+}
+
+// Submit queues a job, waiting for room in the queue if necessary. It
+// returns ErrClosed after Close, and ctx.Err() if ctx is done before the
+// job could be queued.
+func (p *Pool[J, R]) Submit(ctx context.Context, job J) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Results returns the channel the results arrive on, in the order the
+// jobs finish. It is closed when the pool has been closed and every job
+// submitted before has finished.
+func (p *Pool[J, R]) Results() <-chan R {
+	return p.results
+}
+
+// Close stops the pool from accepting jobs. The jobs already submitted
+// still run: the pool drains, and Results is closed afterwards. Close
+// waits for Submit calls in progress and may be called more than once.
+func (p *Pool[J, R]) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+}
@@ -0,0 +1,98 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/leakcheck"
+)
+
+func TestEveryJobRuns(t *testing.T) {
+	before := leakcheck.Take()
+	p := New(4, func(n int) int { return n * n })
+	var wg sync.WaitGroup
+	// Several goroutines submit at once, as the pool allows.
+	for g := range 4 {
+		wg.Go(func() {
+			for n := range 25 {
+				if err := p.Submit(context.Background(), g*25+n); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		p.Close()
+	}()
+	got, sum := 0, 0
+	for r := range p.Results() {
+		got++
+		sum += r
+	}
+	want := 0
+	for n := range 100 {
+		want += n * n
+	}
+	if got != 100 || sum != want {
+		t.Errorf("%d results adding up to %d, want 100 adding up to %d", got, sum, want)
+	}
+	if leaked := before.Leaked(time.Second); len(leaked) > 0 {
+		t.Errorf("the pool's goroutines outlive it: %+v", leaked)
+	}
+}
+
+func TestSubmitCancelled(t *testing.T) {
+	started := make(chan int, 2)
+	release := make(chan struct{})
+	p := New(1, func(n int) int {
+		started <- n
+		<-release
+		return n
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := p.Submit(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	<-started // the worker holds job 1
+	if err := p.Submit(ctx, 2); err != nil {
+		t.Fatal(err) // and job 2 fills the queue
+	}
+
+	errc := make(chan error)
+	go func() { errc <- p.Submit(ctx, 3) }()
+	select {
+	case err := <-errc:
+		t.Fatalf("Submit to a full queue returned %v without waiting", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Submit after cancel = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	p.Close()
+	var got []int
+	for r := range p.Results() {
+		got = append(got, r)
+	}
+	if len(got) != 2 {
+		t.Errorf("results %v, want those of jobs 1 and 2 only", got)
+	}
+}
+
+func TestClose(t *testing.T) {
+	p := New(2, func(n int) int { return n })
+	p.Close()
+	p.Close()
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Close = %v, want %v", err, ErrClosed)
+	}
+	if _, ok := <-p.Results(); ok {
+		t.Error("Results is open after a Close with no jobs")
+	}
+}
@@ -0,0 +1,94 @@
+// Package constants contains examples about Go's constants: iota, and the
+// typed enums built from it.
+//
+// The String methods of Weekday and Priority are generated by stringer
+// into weekday_string.go. Regenerate them after changing either type with
+//
+//	go generate ./GOlang/constants
+//
+// which needs stringer (golang.org/x/tools/cmd/stringer) on the PATH.
+package constants
+
+import (
+	"strconv"
+	"strings"
+)
+
+//go:generate stringer -type=Weekday,Priority
+
+// Weekday is a day of the week, numbered from Sunday like time.Weekday.
+type Weekday int
+
+const (
+	Sunday Weekday = iota
+	Monday
+	Tuesday
+	Wednesday
+	Thursday
+	Friday
+	Saturday
+)
+
+// Weekend reports whether d is a Saturday or a Sunday.
+func (d Weekday) Weekend() bool {
+	return d == Saturday || d == Sunday
+}
+
+// Priority is how urgent a task is. It starts at 1, so that a Priority
+// nobody set, the zero value, is none of them.
+type Priority int
+
+const (
+	_ Priority = iota
+	Low
+	Medium
+	High
+)
+
+// Size is a number of bytes. Its constants skip iota's 0, and compute
+// each value from iota instead of using it as it is.
+type Size int64
+
+const (
+	_       = iota
+	KB Size = 1 << (10 * iota)
+	MB
+	GB
+	TB
+)
+
+// Permission is a set of bit flags, one bit per constant.
+type Permission uint8
+
+const (
+	Read Permission = 1 << iota
+	Write
+	Execute
+)
+
+// Has reports whether every flag in q is set in p.
+func (p Permission) Has(q Permission) bool {
+	return p&q == q
+}
+
+// String lists the flags set in p, since stringer only names single
+// values and has no name for Read|Write.
+func (p Permission) String() string {
+	if p == 0 {
+		return "none"
+	}
+	var names []string
+	for _, f := range []struct {
+		flag Permission
+		name string
+	}{{Read, "Read"}, {Write, "Write"}, {Execute, "Execute"}} {
+		if p.Has(f.flag) {
+			names = append(names, f.name)
+			p &^= f.flag
+		}
+	}
+	if p != 0 {
+		names = append(names, "Permission("+strconv.Itoa(int(p))+")")
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,164 @@
+package constants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(enumsExample{})
+}
+
+// enumsExample builds enums with iota: Weekday counts from 0, Priority
+// skips it, Size computes its values from iota, and Permission gives each
+// constant a bit of its own. Weekday and Priority print their names with
+// the String methods stringer generated; Permission's is written by hand.
+type enumsExample struct{}
+
+func (enumsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "constants/enums_example",
+		Topic:         "constants",
+		Level:         registry.Beginner,
+		Description:   "iota and typed enums: skipped values, computed values, bit flags and stringer",
+		Tags:          []string{"constants", "iota", "enums", "bit-flags", "go-generate"},
+		Prerequisites: []string{"interfaces/dispatch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (enumsExample) Explain(step string) string {
+	switch step {
+	case "iota":
+		return "iota is 0 in the first line of a const block and goes up by one each line. A line with no expression repeats the previous one, with the new iota."
+	case "skip":
+		return "_ = iota uses up a value without naming it. Skipping 0 keeps the zero value from meaning something by accident; an expression like 1 << (10 * iota) makes iota a counter for other values."
+	case "flags":
+		return "1 << iota gives every constant its own bit, so they combine with |, test with &, and clear with &^."
+	case "stringer":
+		return "stringer writes a String method that names each constant, so %v prints Monday rather than 1. Values with no constant print as Weekday(9)."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz constants".
+func (enumsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "const ( A = iota; B; _; D ). What is D?",
+			Choices: []string{"2", "3", "4"},
+			Answer:  "3",
+			Explain: "The _ line still takes iota's 2, so D, on the fourth line, gets 3.",
+		},
+		{
+			Prompt:  "With Read = 1 << iota, Write, Execute: what is Read | Execute?",
+			Choices: []string{"3", "5", "6"},
+			Answer:  "5",
+			Explain: "Read is 1 and Execute is 4, each a bit of its own, and | sets both.",
+		},
+		{
+			Prompt:  "d := 3; fmt.Println(Weekday(d)) prints what, with the generated String method?",
+			Choices: []string{"3", "Wednesday", "Weekday(3)"},
+			Answer:  "Wednesday",
+			Explain: "Println calls String, which looks the value up among the constants stringer saw.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (enumsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Why start an enum with _ = iota?",
+			Back:  "So that the zero value, which every unset variable has, isn't a valid constant. Unless the zero value is a sensible default, like Sunday or Unknown.",
+		},
+		{
+			Front: "What happens to a stringer-generated file when a constant's value changes?",
+			Back:  "The package stops compiling: the generated func _() indexes an array of length 1 with each constant minus its old value, until go generate is run again.",
+		},
+	}
+}
+
+// schedule takes a Weekday, which any untyped constant can be, but an
+// int variable can't without a conversion.
+func schedule(d Weekday) string {
+	if d.Weekend() {
+		return d.String() + ", a day off"
+	}
+	return d.String() + ", a working day"
+}
+
+// Run builds the enums and prints them.
+func (enumsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. iota counts the lines of a const block.
+	e.Step("iota")
+	t := table.New("constant", "value", "Weekend()")
+	for d := Sunday; d <= Saturday; d++ {
+		t.Row(d, int(d), d.Weekend())
+	}
+	e.Diagram(t)
+	assert.Equal(check, "Sunday is iota's first value", int(Sunday), 0)
+	assert.Equal(check, "each line after it gets the next one", int(Saturday), 6)
+	e.Value("schedule(Monday)", schedule(Monday), "schedule(Monday)")
+	e.Value("schedule(6)", schedule(6), "schedule(6), an untyped constant")
+	n := 3
+	// schedule(n) // cannot use n (variable of type int) as Weekday value
+	e.Value("schedule(Weekday(n))", schedule(Weekday(n)), "schedule(Weekday(n)), with n an int")
+	assert.Equal(check, "an untyped constant becomes a Weekday", schedule(6), "Saturday, a day off")
+
+	// 2. Skipping values, and computing them.
+	e.Step("skip")
+	var unset Priority
+	e.Value("unset", unset, "A Priority nobody set")
+	e.Value("Low", fmt.Sprintf("%v = %d", Low, Low), "Low")
+	assert.Equal(check, "the zero value is none of the priorities", unset.String(), "Priority(0)")
+	assert.Equal(check, "Low starts at 1", int(Low), 1)
+	s := table.New("constant", "value")
+	for _, size := range []struct {
+		name string
+		v    Size
+	}{{"KB", KB}, {"MB", MB}, {"GB", GB}, {"TB", TB}} {
+		s.Row(size.name, int64(size.v))
+	}
+	e.Diagram(s)
+	assert.Equal(check, "MB repeats KB's expression with iota = 2", MB, Size(1<<20))
+	assert.Equal(check, "and TB with iota = 4", TB, Size(1<<40))
+
+	// 3. Bit flags.
+	e.Step("flags")
+	perm := Read | Write
+	e.Value("perm", perm, "Read | Write")
+	e.Value("perm", fmt.Sprintf("%03b", uint8(perm)), "The same, in binary")
+	assert.Equal(check, "each flag is a bit of its own", fmt.Sprint(Read, Write, Execute), "Read Write Execute")
+	assert.Equal(check, "| sets both bits", uint8(perm), 0b011)
+	check.That(perm.Has(Write) && !perm.Has(Execute), "Has tests a bit with &")
+	check.That(!perm.Has(Write|Execute), "Has asks for every flag it is given")
+	perm &^= Write
+	e.Value("perm", perm, "After perm &^= Write")
+	assert.Equal(check, "&^ clears a bit", perm, Read)
+	assert.Equal(check, "bits without a name are shown as a number", (Read | 8).String(), "Read|Permission(8)")
+
+	// 4. The String methods stringer generated.
+	e.Step("stringer")
+	e.Value("Wednesday", fmt.Sprintf("%v / %d", Wednesday, Wednesday), "%v and %d of Wednesday")
+	e.Value("Weekday(9)", Weekday(9), "A Weekday with no constant")
+	e.Value("High", High, "High, from the Priority block that skips 0")
+	assert.Equal(check, "%v uses the generated String", fmt.Sprint(Wednesday), "Wednesday")
+	assert.Equal(check, "%d still prints the number", fmt.Sprintf("%d", Wednesday), "3")
+	assert.Equal(check, "values with no constant print as a conversion", Weekday(9).String(), "Weekday(9)")
+	assert.Equal(check, "the generated code accounts for Priority starting at 1", High.String(), "High")
+	e.Say("weekday_string.go is checked in, so building doesn't need stringer; go generate ./GOlang/constants rewrites it.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,11 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkDefer(b *testing.B) { benchlab.Bench(b, deferExample{}) }
@@ -0,0 +1,261 @@
+// Package functions contains examples about what Go functions can do
+// besides take arguments and return a result: defer work until they
+// return, take a variable number of arguments, and return several named
+// results.
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(deferExample{})
+}
+
+// deferExample runs deferred calls and records when they ran and what
+// they saw: last in first out, with arguments taken when the defer
+// statement ran, all at once when the function returns even if they were
+// deferred in a loop, and in time to change the function's named results.
+type deferExample struct{}
+
+func (deferExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "functions/defer_example",
+		Topic:         "functions",
+		Level:         registry.Beginner,
+		Description:   "defer: LIFO order, argument evaluation, defers in loops and named results",
+		Tags:          []string{"functions", "defer", "named-results", "cleanup"},
+		Prerequisites: []string{"pointers/closure_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (deferExample) Explain(step string) string {
+	switch step {
+	case "lifo":
+		return "Deferred calls are pushed on a stack as the defer statements run and popped when the function returns: the last one deferred runs first."
+	case "arguments":
+		return "defer f(x) evaluates f and x right away and keeps the values for later. A deferred closure instead reads x when it finally runs."
+	case "loop":
+		return "A defer runs when the function returns, not at the end of the loop iteration. Deferring in a loop piles the calls up until then."
+	case "named-results":
+		return "return n first assigns n to the result, then runs the deferred calls. A deferred closure can still change a named result before the caller sees it."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz functions".
+func (deferExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "for i := range 3 { defer fmt.Print(i) } prints what?",
+			Choices: []string{"012", "210", "222"},
+			Answer:  "210",
+			Explain: "Each defer evaluates its own i when it runs, and the deferred calls run last in first out.",
+		},
+		{
+			Prompt:  "func f() (n int) { defer func() { n *= 2 }(); return 3 }. What does f return?",
+			Choices: []string{"3", "6"},
+			Answer:  "6",
+			Explain: "return 3 sets n to 3, then the deferred closure doubles it, and then f returns.",
+		},
+		{
+			Prompt:  "x := 1; defer fmt.Println(x); x = 2. What is printed?",
+			Choices: []string{"1", "2"},
+			Answer:  "1",
+			Explain: "The arguments of a deferred call are evaluated when the defer statement runs.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (deferExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Why not defer f.Close() inside a loop over many files?",
+			Back:  "Every file stays open until the function returns. Move the loop body into its own function, so each defer runs at the end of each iteration.",
+		},
+		{
+			Front: "How does a deferred call change what a function returns?",
+			Back:  "Only through named results: the deferred closure assigns to them after return has set them and before the caller gets them.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench functions".
+func (deferExample) Experiments() []benchlab.Experiment {
+	var mu sync.Mutex
+	return []benchlab.Experiment{{
+		Name: "locking and unlocking a mutex",
+		Approaches: []benchlab.Approach{
+			{Name: "defer mu.Unlock()", Bench: func(b *testing.B) {
+				for range b.N {
+					func() {
+						mu.Lock()
+						defer mu.Unlock()
+						benchCount++
+					}()
+				}
+			}},
+			{Name: "mu.Unlock() at the end", Bench: func(b *testing.B) {
+				for range b.N {
+					func() {
+						mu.Lock()
+						benchCount++
+						mu.Unlock()
+					}()
+				}
+			}},
+		},
+		Guidance: "Since Go 1.14 the compiler open-codes most defers: it inlines the " +
+			"deferred call at every return, and the cost is a few bits of bookkeeping. " +
+			"Defers in loops, and functions with more than 8 of them, still go through " +
+			"the runtime and cost more. The safety of defer, which also unlocks when " +
+			"the code panics or grows another return, is nearly always worth it.",
+	}}
+}
+
+// benchCount is what the benchmarks change while holding the mutex.
+var benchCount int
+
+// trail records the order things happen in.
+type trail []string
+
+func (t *trail) add(format string, args ...any) {
+	*t = append(*t, fmt.Sprintf(format, args...))
+}
+
+// lifo defers three calls and records when each one runs.
+func lifo(t *trail) {
+	for i := 1; i <= 3; i++ {
+		t.add("defer %d", i)
+		defer t.add("run %d", i)
+	}
+	t.add("return")
+}
+
+// arguments defers a call and a closure that both use x, then changes x.
+func arguments(t *trail) {
+	x := 1
+	defer t.add("deferred call saw x = %d", x)
+	defer func() { t.add("deferred closure saw x = %d", x) }()
+	x = 2
+}
+
+// file is a stand-in for an *os.File that counts how many are open.
+type file struct{ open *int }
+
+func openFile(open, most *int) file {
+	*open++
+	*most = max(*most, *open)
+	return file{open}
+}
+
+func (f file) Close() { *f.open-- }
+
+// processInLoop opens n files, deferring each Close in the loop, and
+// returns how many were open at once.
+func processInLoop(n int) (most int) {
+	open := 0
+	for range n {
+		f := openFile(&open, &most)
+		defer f.Close()
+	}
+	return most
+}
+
+// processEach does the same with the loop body in a function of its own.
+func processEach(n int) (most int) {
+	open := 0
+	for range n {
+		func() {
+			f := openFile(&open, &most)
+			defer f.Close()
+		}()
+	}
+	return most
+}
+
+// doubled returns 3, which its deferred closure doubles.
+func doubled() (n int) {
+	defer func() { n *= 2 }()
+	return 3
+}
+
+// unnamed tries the same without a named result.
+func unnamed() int {
+	n := 3
+	defer func() { n *= 2 }()
+	return n
+}
+
+// errNotFound is what load fails with.
+var errNotFound = errors.New("not found")
+
+// load fails, and its deferred closure adds what it was loading to the
+// error, on every return path at once.
+func load(name string) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("loading %s: %w", name, err)
+		}
+	}()
+	if name == "" {
+		return errors.New("no name")
+	}
+	return errNotFound
+}
+
+// Run defers, and checks when each deferred call ran and what it saw.
+func (deferExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Last in, first out.
+	e.Step("lifo")
+	var t trail
+	lifo(&t)
+	e.Value("trail", t, "What lifo did, in order")
+	assert.Equal(check, "the deferred calls run after the return, last one first", fmt.Sprint(t), "[defer 1 defer 2 defer 3 return run 3 run 2 run 1]")
+
+	// 2. Arguments are evaluated at the defer statement.
+	e.Step("arguments")
+	t = nil
+	arguments(&t)
+	e.Value("trail", t, "What arguments' deferred calls saw, after x = 2")
+	assert.Equal(check, "the closure reads x when it runs", t[0], "deferred closure saw x = 2")
+	assert.Equal(check, "the deferred call got x's value at the defer statement", t[1], "deferred call saw x = 1")
+
+	// 3. Defers in a loop wait for the function, not the iteration.
+	e.Step("loop")
+	inLoop, each := processInLoop(5), processEach(5)
+	e.Value("inLoop", inLoop, "Files open at once, deferring Close in the loop")
+	e.Value("each", each, "Files open at once, with a function per iteration")
+	assert.Equal(check, "every file stays open until processInLoop returns", inLoop, 5)
+	assert.Equal(check, "a function per iteration closes each file before the next", each, 1)
+
+	// 4. Deferred closures can change named results.
+	e.Step("named-results")
+	e.Value("doubled()", doubled(), "doubled(), which returns 3 and doubles n in a defer")
+	e.Value("unnamed()", unnamed(), "unnamed(), the same without a named result")
+	assert.Equal(check, "the defer doubled the named result", doubled(), 6)
+	assert.Equal(check, "without a name, the defer only changed a local copy", unnamed(), 3)
+	err := load("config.yaml")
+	e.Value("err", err, "load(\"config.yaml\")")
+	e.Value("err", load(""), "load(\"\")")
+	check.That(errors.Is(err, errNotFound) && err.Error() == "loading config.yaml: not found", "the deferred closure wraps the error on the way out")
+	e.Say("A deferred closure that calls recover can turn a panic into an error the same way.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,230 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(resultsExample{})
+}
+
+// resultsExample returns more than one value: plainly, with the results
+// named to say what they are, with naked returns, which leave the reader
+// to work out what is being returned, and with a deferred function that
+// changes the named results on the way out.
+type resultsExample struct{}
+
+func (resultsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "functions/results_example",
+		Topic:         "functions",
+		Level:         registry.Beginner,
+		Description:   "multiple and named results, naked returns and what deferred functions do to results",
+		Tags:          []string{"functions", "results", "named-results", "errors", "defer"},
+		Prerequisites: []string{"functions/defer_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (resultsExample) Explain(step string) string {
+	switch step {
+	case "multiple":
+		return "A function can return several values, and the caller assigns them all at once, with _ for the ones it doesn't want. The last one is usually an error or an ok bool."
+	case "named":
+		return "Named results are variables that start at their zero value. In the signature they document what each result is, which helps most when two have the same type."
+	case "naked":
+		return "A bare return returns whatever the named results hold at that point. In a long function the reader has to track every assignment to know what that is."
+	case "deferred":
+		return "return x assigns x to the named result, then the deferred functions run, and they can still change it. That is how a recover becomes an error."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz functions".
+func (resultsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "func f() (x int) { x = 1; defer func() { x *= 10 }(); return 2 }. What does f return?",
+			Choices: []string{"10", "2", "20"},
+			Answer:  "20",
+			Explain: "return 2 sets x to 2, replacing the 1, and then the deferred function multiplies it by 10.",
+		},
+		{
+			Prompt:  "func g() (err error) { if err := h(); err != nil { return } ... }. Does it compile?",
+			Choices: []string{"yes, and returns h's error", "yes, and returns nil", "no"},
+			Answer:  "no",
+			Explain: "The err declared in the if shadows the result, and the compiler refuses a bare return while it does: \"result parameter err not in scope at return\".",
+		},
+		{
+			Prompt:  "In func (q, r int), what are q and r when the function starts?",
+			Choices: []string{"0 and 0", "undefined until assigned"},
+			Answer:  "0 and 0",
+			Explain: "Named results are declared and zeroed like any other local variable.",
+		},
+		{
+			Prompt:  "n, _ := strconv.Atoi(\"x\"). What happens to the error?",
+			Choices: []string{"it is discarded, and n is 0", "the program panics", "it doesn't compile"},
+			Answer:  "it is discarded, and n is 0",
+			Explain: "The blank identifier drops the error, which is legal and usually a bug: Atoi's 0 is indistinguishable from a real 0.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (resultsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "When are named results worth it?",
+			Back:  "When the names document results of the same type, like (lat, long float64), or when a deferred function needs to change a result. Not to save typing with bare returns.",
+		},
+		{
+			Front: "Why return 0, 0, err rather than a bare return on an error path?",
+			Back:  "A bare return also hands back whatever the other results held when the error happened, and the reader can't see it at the return.",
+		},
+	}
+}
+
+// divmod returns the quotient and remainder of a / b.
+func divmod(a, b int) (int, int) {
+	return a / b, a % b
+}
+
+// splitHost splits "host:port". Naming the results says which string is
+// which, something (string, string, error) alone doesn't.
+func splitHost(addr string) (host, port string, err error) {
+	host, port, ok := strings.Cut(addr, ":")
+	if !ok {
+		return "", "", fmt.Errorf("%q has no port", addr)
+	}
+	return host, port, nil
+}
+
+// parseRangeNaked parses "lo-hi" with bare returns. On the second error
+// lo has already been set, and is returned with it.
+func parseRangeNaked(s string) (lo, hi int, err error) {
+	a, b, _ := strings.Cut(s, "-")
+	lo, err = strconv.Atoi(a)
+	if err != nil {
+		return
+	}
+	hi, err = strconv.Atoi(b)
+	if err != nil {
+		return
+	}
+	if hi < lo {
+		err = fmt.Errorf("%d-%d is backwards", lo, hi)
+	}
+	return
+}
+
+// parseRange is the same with every result spelled out.
+func parseRange(s string) (lo, hi int, err error) {
+	a, b, _ := strings.Cut(s, "-")
+	lo, err = strconv.Atoi(a)
+	if err != nil {
+		return 0, 0, err
+	}
+	hi, err = strconv.Atoi(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	if hi < lo {
+		return 0, 0, fmt.Errorf("%d-%d is backwards", lo, hi)
+	}
+	return lo, hi, nil
+}
+
+// replaced sets its result, then returns something else, which a
+// deferred function changes again.
+func replaced() (x int) {
+	x = 1
+	defer func() { x *= 10 }()
+	return 2
+}
+
+// safeDivide returns a / b, with dividing by zero's panic turned into an
+// error by assigning to err after the panic.
+func safeDivide(a, b int) (q int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("dividing %d by %d: %v", a, b, r)
+		}
+	}()
+	return a / b, nil
+}
+
+// Run returns multiple values every way it can.
+func (resultsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Several results, assigned at once.
+	e.Step("multiple")
+	q, r := divmod(17, 5)
+	e.Value("q, r", fmt.Sprint(q, " ", r), "q, r := divmod(17, 5)")
+	assert.Equal(check, "both results come back", q*5+r, 17)
+	_, r = divmod(9, 4)
+	e.Value("r", r, "_, r = divmod(9, 4), the quotient thrown away")
+	n, convErr := strconv.Atoi("x")
+	e.Value("n", n, "n from strconv.Atoi(\"x\")")
+	e.Value("err", convErr, "and its error")
+	check.That(n == 0 && convErr != nil, "only the error tells a failed 0 from a real one")
+	// fmt.Println(divmod(17, 5) + 1) // a multi-value call can't be one operand
+	e.Say("A call with several results can only be assigned, returned, or passed as the whole argument list: f(divmod(17, 5)) works for func f(q, r int).")
+
+	// 2. Named results: documentation, and zero values to start from.
+	e.Step("named")
+	host, port, err := splitHost("example.com:443")
+	e.Value("host", host, "host from splitHost(\"example.com:443\")")
+	e.Value("port", port, "and port")
+	assert.Equal(check, "the names say which string is the port", port, "443")
+	_, _, err = splitHost("example.com")
+	e.Value("err", err, "splitHost(\"example.com\")")
+	check.That(err != nil, "a missing port is an error")
+
+	// 3. Bare returns: what do they return?
+	e.Step("naked")
+	t := table.New("input", "bare returns", "explicit returns")
+	var naked, explicit [3]string
+	for i, in := range []string{"3-8", "5-x", "9-2"} {
+		lo, hi, err := parseRangeNaked(in)
+		naked[i] = fmt.Sprintf("%d, %d, %v", lo, hi, err != nil)
+		lo, hi, err = parseRange(in)
+		explicit[i] = fmt.Sprintf("%d, %d, %v", lo, hi, err != nil)
+		t.Row(in, naked[i], explicit[i])
+	}
+	e.Diagram(t)
+	e.Say("Each cell is lo, hi, and whether there was an error.")
+	assert.Equal(check, "both agree on valid input", naked[0], explicit[0])
+	assert.Equal(check, "the bare return leaked the lo it had already parsed", naked[1], "5, 0, true")
+	assert.Equal(check, "and both bounds of a backwards range", naked[2], "9, 2, true")
+	assert.Equal(check, "the explicit returns say exactly what they return", explicit[1], "0, 0, true")
+	// func g() (err error) { if err := h(); err != nil { return } ... }
+	// doesn't compile: result parameter err not in scope at return
+	e.Warn("Callers should ignore the other results when err != nil, but not all do. A bare return far from the assignments makes that easy to miss when reviewing.")
+
+	// 4. Deferred functions see, and can change, the named results.
+	e.Step("deferred")
+	e.Value("replaced()", replaced(), "x = 1; defer x *= 10; return 2")
+	assert.Equal(check, "return 2 replaced the 1, then the defer ran", replaced(), 20)
+	q, err = safeDivide(7, 2)
+	e.Value("q", q, "safeDivide(7, 2)")
+	check.That(q == 3 && err == nil, "without a panic, the results are returned as they are")
+	q, err = safeDivide(7, 0)
+	e.Value("err", err, "safeDivide(7, 0)")
+	check.That(q == 0 && err != nil, "the deferred recover turned the panic into the err result")
+	e.Say("panics/boundary_example does the same at the boundary of a whole package.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,190 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(variadicExample{})
+}
+
+// variadicExample calls functions with a ...int parameter in each way Go
+// allows: with separate arguments, with none, and with a slice expanded
+// by ..., which hands the function the caller's own slice rather than a
+// copy of it.
+type variadicExample struct{}
+
+func (variadicExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "functions/variadic_example",
+		Topic:         "functions",
+		Level:         registry.Beginner,
+		Description:   "variadic parameters: ... expansion, nil vs empty arguments and a mutated caller's slice",
+		Tags:          []string{"functions", "variadic", "slices", "aliasing"},
+		Prerequisites: []string{"memory/slicealias_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (variadicExample) Explain(step string) string {
+	switch step {
+	case "parameter":
+		return "Inside the function, nums ...int is an ordinary []int. The compiler packs the separate arguments of each call into a new slice."
+	case "expand":
+		return "s... passes an existing slice as the variadic parameter itself. It has to be the only argument for that parameter: sum(1, s...) doesn't compile."
+	case "nil-or-empty":
+		return "With no arguments the parameter is nil. Expanding an empty slice passes that slice, empty but not nil."
+	case "aliasing":
+		return "Expanding s doesn't copy it: the function gets a slice of the same array, and whatever it writes there the caller sees in s."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz functions".
+func (variadicExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "func f(nums ...int) { fmt.Println(nums == nil) }. What does f() print?",
+			Choices: []string{"true", "false"},
+			Answer:  "true",
+			Explain: "No arguments means no slice: nums is nil, with length 0.",
+		},
+		{
+			Prompt:  "s := []int{1, 2}; double(s...), where double sets each nums[i] *= 2. What is s afterwards?",
+			Choices: []string{"[1 2]", "[2 4]"},
+			Answer:  "[2 4]",
+			Explain: "s... passes s itself, so double writes to s's backing array.",
+		},
+		{
+			Prompt:  "names := []string{\"a\", \"b\"}. Does fmt.Println(names...) compile?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "Println takes ...any, so only an []any can be expanded into it. A []string has to be copied into one first.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (variadicExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What is the type of nums in func f(nums ...int)?",
+			Back:  "[]int. The ... only changes how f can be called.",
+		},
+		{
+			Front: "How does a variadic function keep from changing its caller's slice?",
+			Back:  "It doesn't write to its parameter, or it copies it first with slices.Clone. A caller that expands a slice into it may have to assume the worst.",
+		},
+	}
+}
+
+// sum adds up nums.
+func sum(nums ...int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+// inspect says what a variadic parameter was given.
+func inspect(nums ...int) string {
+	if nums == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("len %d, not nil", len(nums))
+}
+
+// first returns the address of nums' first element, or nil.
+func first(nums ...int) *int {
+	if len(nums) == 0 {
+		return nil
+	}
+	return &nums[0]
+}
+
+// normalize scales nums so that the largest is 100. It writes to nums,
+// which belongs to the caller when the caller expanded a slice.
+func normalize(nums ...int) []int {
+	top := slices.Max(nums)
+	for i := range nums {
+		nums[i] = nums[i] * 100 / top
+	}
+	return nums
+}
+
+// normalized does the same on a copy.
+func normalized(nums ...int) []int {
+	return normalize(slices.Clone(nums)...)
+}
+
+// Run calls the variadic functions every way it can.
+func (variadicExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. A variadic parameter is a slice.
+	e.Step("parameter")
+	e.Value("sum(1, 2, 3)", sum(1, 2, 3), "sum(1, 2, 3)")
+	e.Value("type", fmt.Sprintf("%T", sum), "The type of sum")
+	assert.Equal(check, "the arguments arrive as one []int", sum(1, 2, 3), 6)
+	assert.Equal(check, "... is part of the function's type", fmt.Sprintf("%T", sum), "func(...int) int")
+
+	// 2. Expanding a slice with ....
+	e.Step("expand")
+	s := []int{4, 5, 6}
+	e.Value("sum(s...)", sum(s...), "sum(s...), with s = [4 5 6]")
+	assert.Equal(check, "s... passes s's elements", sum(s...), 15)
+	// sum(1, s...) // can't mix separate arguments with an expanded slice
+	e.Say("sum(1, s...) doesn't compile. sum(append([]int{1}, s...)...) does, with a new slice.")
+	words := []string{"go", "gopher"}
+	args := make([]any, len(words))
+	for i, word := range words {
+		args[i] = word
+	}
+	e.Value("fmt.Sprintf", fmt.Sprintf("%s and %s", args...), "fmt.Sprintf(\"%s and %s\", args...), with words copied into an []any")
+	// fmt.Sprintf("%s and %s", words...) // a []string is not an []any
+	assert.Equal(check, "an []any expands into ...any", fmt.Sprintf("%s and %s", args...), "go and gopher")
+
+	// 3. No arguments is nil; an empty slice is not.
+	e.Step("nil-or-empty")
+	t := table.New("call", "nums")
+	t.Row("inspect()", inspect())
+	t.Row("inspect(nil...)", inspect(nil...))
+	t.Row("inspect([]int{}...)", inspect([]int{}...))
+	t.Row("inspect(7)", inspect(7))
+	e.Diagram(t)
+	assert.Equal(check, "no arguments gives a nil slice", inspect(), "nil")
+	assert.Equal(check, "expanding an empty slice passes it as it is", inspect([]int{}...), "len 0, not nil")
+	assert.Equal(check, "sum of nothing is 0", sum(), 0)
+
+	// 4. The expanded slice is the caller's.
+	e.Step("aliasing")
+	scores := []int{30, 60, 120}
+	check.That(first(scores...) == &scores[0], "the function gets scores' own array, not a copy")
+	out := normalize(scores...)
+	e.Value("out", out, "normalize(scores...)")
+	e.Value("scores", scores, "The caller's scores afterwards")
+	assert.Equal(check, "normalize rewrote the caller's slice", fmt.Sprint(scores), "[25 50 100]")
+	a, b, c := 30, 60, 120
+	normalize(a, b, c)
+	check.That(a == 30 && b == 60 && c == 120, "separate arguments are copied into a new slice, so nothing of the caller's changes")
+	scores = []int{30, 60, 120}
+	out = normalized(scores...)
+	e.Value("out", out, "normalized(scores...)")
+	e.Value("scores", scores, "The caller's scores afterwards")
+	assert.Equal(check, "normalized worked on a copy", fmt.Sprint(scores), "[30 60 120]")
+	e.Say("append(s, more...) is the same: it may write to s's array past len(s), if s has room.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,216 @@
+package generics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(basicsExample{})
+}
+
+// basicsExample instantiates the functions of generics.go, first naming
+// the type arguments and then letting the compiler infer them, and runs
+// them over ints, floats, strings and a type of its own, checking each.
+type basicsExample struct{}
+
+func (basicsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "generics/basics_example",
+		Topic:         "generics",
+		Level:         registry.Beginner,
+		Description:   "type parameters: Min, Map, Filter and Reduce, instantiation and type inference",
+		Tags:          []string{"generics", "type-parameters", "functions", "slices"},
+		Prerequisites: []string{"interfaces/dispatch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (basicsExample) Explain(step string) string {
+	switch step {
+	case "instantiate":
+		return "Min[int] substitutes int for T. The result is an ordinary function, func(int, ...int) int, that can be called or stored like any other."
+	case "infer":
+		return "Usually the compiler works the type arguments out from the function arguments, so Min(3, 1, 2) means Min[int](3, 1, 2)."
+	case "element-types":
+		return "The same Min, Map, Filter and Reduce work for every type their constraints allow, checked at compile time for each."
+	case "pipeline":
+		return "Filter, Map and Reduce compose: each one's result type is inferred and becomes the next one's T."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz generics".
+func (basicsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "What type does the compiler infer for T in Min(2.5, 1)?",
+			Choices: []string{"int", "float64", "it doesn't compile"},
+			Answer:  "float64",
+			Explain: "Both arguments are untyped constants. Inference picks the default type of the most general kind among them, float64 for 2.5.",
+		},
+		{
+			Prompt:  "Why does Map(words, strings.ToUpper) not need Map[string, string]?",
+			Choices: []string{"T comes from words and U from the function's result type", "Map has default type arguments"},
+			Answer:  "T comes from words and U from the function's result type",
+			Explain: "Every type parameter appears in the parameter types, so the arguments pin them all down.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (basicsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "When must you write the type arguments out, as in Map[int, string](...)?",
+			Back:  "When the compiler can't infer them: a type parameter that only appears in the result, or arguments like nil that carry no type. Or to take a function value, as in f := Min[int].",
+		},
+		{
+			Front: "What does the constraint in Min[T cmp.Ordered] allow?",
+			Back:  "Every type whose underlying type is an integer, float or string type: exactly the ones < works on.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench generics".
+func (basicsExample) Experiments() []benchlab.Experiment {
+	ints := make([]int, 1000)
+	boxed := make([]any, len(ints))
+	for i := range ints {
+		ints[i] = (i * 7919) % 1000
+		boxed[i] = ints[i]
+	}
+	return []benchlab.Experiment{{
+		Name: "the smallest of 1000 ints",
+		Approaches: []benchlab.Approach{
+			{Name: "Min[int]", Bench: func(b *testing.B) {
+				for range b.N {
+					benchInt = Min(ints[0], ints[1:]...)
+				}
+			}},
+			{Name: "hand-written for []int", Bench: func(b *testing.B) {
+				for range b.N {
+					benchInt = minInts(ints)
+				}
+			}},
+			{Name: "[]any with assertions", Bench: func(b *testing.B) {
+				for range b.N {
+					benchInt = minAny(boxed)
+				}
+			}},
+		},
+		Guidance: "The compiler generates code for each shape of type argument, and " +
+			"for int that is the same loop you would write by hand, so Min[int] " +
+			"costs what minInts does. Writing it once for []any instead boxes every " +
+			"element and checks its type on every comparison.",
+	}}
+}
+
+// benchInt keeps the benchmarks' results alive.
+var benchInt int
+
+// minInts is Min written for one type.
+func minInts(s []int) int {
+	m := s[0]
+	for _, v := range s[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// minAny is Min written before generics, for any type at all, as long as
+// it is an int.
+func minAny(s []any) int {
+	m := s[0].(int)
+	for _, v := range s[1:] {
+		if n := v.(int); n < m {
+			m = n
+		}
+	}
+	return m
+}
+
+// celsius is a type of this package; its underlying type float64 puts it
+// in cmp.Ordered.
+type celsius float64
+
+// member is an element type with fields, for the pipeline step.
+type member struct {
+	Name string
+	Age  int
+}
+
+// Run instantiates, infers, and runs the functions over several types.
+func (basicsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Explicit instantiation: name the type argument.
+	e.Step("instantiate")
+	e.Value("Min[int](3, 1, 2)", Min[int](3, 1, 2), "Min[int](3, 1, 2)")
+	minString := Min[string]
+	e.Value("minString", fmt.Sprintf("%T", minString), "Type of minString := Min[string]")
+	e.Value("minString(\"pear\", \"apple\")", minString("pear", "apple"), "minString(\"pear\", \"apple\")")
+	assert.Equal(check, "Min[string] is an ordinary function", fmt.Sprintf("%T", minString), "func(string, ...string) string")
+	lengths := Map[string, int]([]string{"go", "gopher"}, func(s string) int { return len(s) })
+	e.Value("lengths", lengths, "Map[string, int] over \"go\", \"gopher\"")
+
+	// 2. Inference: the compiler works the type arguments out.
+	e.Step("infer")
+	i, f := Min(3, 1, 2), Min(2.5, 1)
+	e.Value("Min(3, 1, 2)", fmt.Sprintf("%v (%T)", i, i), "Min(3, 1, 2)")
+	e.Value("Min(2.5, 1)", fmt.Sprintf("%v (%T)", f, f), "Min(2.5, 1)")
+	upper := Map([]string{"go", "gopher"}, strings.ToUpper)
+	e.Value("upper", upper, "Map(words, strings.ToUpper), T and U both inferred as string")
+	assert.Equal(check, "Min(2.5, 1) infers float64", fmt.Sprintf("%T", f), "float64")
+	assert.Equal(check, "Map infers U from the function it is given", fmt.Sprintf("%T", upper), "[]string")
+	e.Say("A type parameter that appears only in the result, or arguments like nil that have no type, leave nothing to infer from: then the type arguments have to be written out.")
+
+	// 3. One implementation, several element types.
+	e.Step("element-types")
+	ints := []int{5, -2, 9, 4}
+	floats := []float64{2.5, 0.5, 1.5}
+	words := []string{"pear", "apple", "fig"}
+	temps := []celsius{21.5, 18, 25}
+	t := table.New("element type", "Min", "Filter", "Map", "Reduce")
+	t.Row("int", Min(ints[0], ints[1:]...), Filter(ints, func(n int) bool { return n > 0 }), Map(ints, strconv.Itoa), Reduce(ints, 0, func(a, n int) int { return a + n }))
+	t.Row("float64", Min(floats[0], floats[1:]...), Filter(floats, func(x float64) bool { return x >= 1 }), Map(floats, func(x float64) float64 { return x * 2 }), Reduce(floats, 0.0, func(a, x float64) float64 { return a + x }))
+	t.Row("string", Min(words[0], words[1:]...), Filter(words, func(s string) bool { return len(s) > 3 }), Map(words, strings.ToUpper), Reduce(words, "", func(a, s string) string { return a + s[:1] }))
+	t.Row("celsius", Min(temps[0], temps[1:]...), Filter(temps, func(c celsius) bool { return c > 20 }), Map(temps, func(c celsius) float64 { return float64(c)*9/5 + 32 }), Reduce(temps, 0, func(n int, c celsius) int { return n + 1 }))
+	e.Diagram(t)
+	assert.Equal(check, "Min over ints", Min(ints[0], ints[1:]...), -2)
+	assert.Equal(check, "Min over float64s", Min(floats[0], floats[1:]...), 0.5)
+	assert.Equal(check, "Min over strings compares them byte by byte", Min(words[0], words[1:]...), "apple")
+	assert.Equal(check, "Min over celsius, a type of our own", Min(temps[0], temps[1:]...), celsius(18))
+	assert.Equal(check, "Filter keeps the order", fmt.Sprint(Filter(ints, func(n int) bool { return n > 0 })), "[5 9 4]")
+	assert.Equal(check, "Map can change the element type", fmt.Sprint(Map(temps, func(c celsius) float64 { return float64(c)*9/5 + 32 })), "[70.7 64.4 77]")
+	assert.Equal(check, "Reduce's accumulator can have its own type", Reduce(temps, 0, func(n int, c celsius) int { return n + 1 }), 3)
+
+	// 4. Composing them: every type is inferred along the way.
+	e.Step("pipeline")
+	members := []member{{"Ada", 36}, {"Tim", 17}, {"Grace", 45}, {"Linus", 12}}
+	adults := Filter(members, func(m member) bool { return m.Age >= 18 })
+	names := Map(adults, func(m member) string { return m.Name })
+	total := Reduce(adults, 0, func(sum int, m member) int { return sum + m.Age })
+	e.Value("names", names, "Names of the adult members")
+	e.Value("total", total, "Their ages added up")
+	assert.Equal(check, "Filter then Map over structs", fmt.Sprint(names), "[Ada Grace]")
+	assert.Equal(check, "Reduce adds up a field", total, 81)
+	e.Say("The standard library has many of these ready-made: slices.Min, slices.IndexFunc, maps.Keys, and min and max are built in.")
+	return errors.Join(e.Err(), check.Err())
+}
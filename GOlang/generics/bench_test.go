@@ -0,0 +1,11 @@
+package generics
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkBasics(b *testing.B) { benchlab.Bench(b, basicsExample{}) }
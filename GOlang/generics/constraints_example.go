@@ -0,0 +1,291 @@
+package generics
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(constraintsExample{})
+}
+
+// constraintsExample writes its own constraints: a union of types, the
+// same union with ~ so that defined types like score get in, a method
+// constraint, and one that asks for both. Every call the compiler would
+// reject is left commented out in Run and type-checked with go/types
+// instead, against a copy of the declarations in constraintsSrc.
+type constraintsExample struct{}
+
+func (constraintsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "generics/constraints_example",
+		Topic:         "generics",
+		Level:         registry.Intermediate,
+		Description:   "constraints and type sets: unions, ~ approximation, method constraints and what doesn't compile",
+		Tags:          []string{"generics", "constraints", "type-sets", "interfaces", "go/types"},
+		Prerequisites: []string{"generics/basics_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (constraintsExample) Explain(step string) string {
+	switch step {
+	case "union":
+		return "int | int64 | float64 is a type set of exactly three types. Sum can use + on T because every type in the set has it."
+	case "tilde":
+		return "~int means every type whose underlying type is int. Without the ~, a defined type like score is not in the set, even though it is an int underneath."
+	case "methods":
+		return "A constraint with a method admits any type that has it, whatever its underlying type, and lets the generic code call it."
+	case "combined":
+		return "A constraint can ask for both: a type in the set that also has the method. The type set is the intersection."
+	case "compiler":
+		return "Constraints with a type set can only constrain type parameters. And a type parameter only supports the operations every type in its set has."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz generics".
+func (constraintsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "type score int. Does score satisfy interface{ int | int64 }?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "score is a different type from int. Only interface{ ~int | ~int64 } includes types whose underlying type is int.",
+		},
+		{
+			Prompt:  "Why is var n Number a compile error, when Number is interface{ ~int | ~float64 }?",
+			Choices: []string{"interfaces with type sets can only be used as constraints", "Number has no methods"},
+			Answer:  "interfaces with type sets can only be used as constraints",
+			Explain: "A variable of an interface type needs a method set to call through. A union says which types are allowed, which only means something for a type parameter.",
+		},
+		{
+			Prompt:  "func Double[T any](x T) T { return x + x }. Does it compile?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "any includes types without +, like structs, so the compiler can't allow + on T. Constrain T to a set of types that all have it.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (constraintsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does ~ mean in a constraint such as ~string?",
+			Back:  "Every type whose underlying type is string, including defined types like type path string. Without ~, just string itself.",
+		},
+		{
+			Front: "What operations may generic code use on a type parameter?",
+			Back:  "Only those that every type in its constraint's type set supports, and the constraint's methods.",
+		},
+	}
+}
+
+// Number is every type that is an int, int64 or float64 underneath.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// exactNumber is the same union without the ~: only those three types.
+type exactNumber interface {
+	int | int64 | float64
+}
+
+// Stringer is a method constraint: any type with a String method.
+type Stringer interface {
+	String() string
+}
+
+// labeledNumber asks for both: a Number that is also a Stringer.
+type labeledNumber interface {
+	Number
+	Stringer
+}
+
+// Sum adds up s.
+func Sum[T Number](s []T) T {
+	var total T
+	for _, v := range s {
+		total += v
+	}
+	return total
+}
+
+// sumExact adds up s, for the three types exactNumber lists.
+func sumExact[T exactNumber](s []T) T {
+	var total T
+	for _, v := range s {
+		total += v
+	}
+	return total
+}
+
+// Join calls String on every element of s and joins the results.
+func Join[T Stringer](s []T, sep string) string {
+	parts := make([]string, len(s))
+	for i, v := range s {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, sep)
+}
+
+// total adds up s and labels the sum with its own String method.
+func total[T labeledNumber](s []T) string {
+	return "total " + Sum(s).String()
+}
+
+// score is a defined type over int, with a String method.
+type score int
+
+func (s score) String() string { return strconv.Itoa(int(s)) + " pts" }
+
+// meters is a defined type over float64, without one.
+type meters float64
+
+// label has a String method but isn't a number.
+type label string
+
+func (l label) String() string { return string(l) }
+
+// constraintsSrc repeats the declarations above for typeCheck, without
+// the imports go/types would need an importer for.
+const constraintsSrc = `package p
+
+type Number interface{ ~int | ~int64 | ~float64 }
+type exactNumber interface{ int | int64 | float64 }
+type Stringer interface{ String() string }
+type labeledNumber interface {
+	Number
+	Stringer
+}
+
+func Sum[T Number](s []T) T           { var t T; for _, v := range s { t += v }; return t }
+func sumExact[T exactNumber](s []T) T { var t T; for _, v := range s { t += v }; return t }
+func Join[T Stringer](s []T, sep string) string { return "" }
+func total[T labeledNumber](s []T) string     { return Sum(s).String() }
+
+type score int
+func (s score) String() string { return "" }
+type meters float64
+type label string
+func (l label) String() string { return string(l) }
+`
+
+// typeCheck type-checks decls and body, as the body of a function, in a
+// package with constraintsSrc's declarations, and returns the first error
+// without its position.
+func typeCheck(decls, body string) error {
+	src := constraintsSrc + "\n" + decls + "\nfunc f() {\n" + body + "\n}\n"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		return err
+	}
+	var first error
+	conf := types.Config{Error: func(err error) {
+		if first == nil {
+			first = errors.New(err.(types.Error).Msg)
+		}
+	}}
+	conf.Check("p", fset, []*ast.File{f}, nil)
+	return first
+}
+
+// rejection is a commented-out case of Run: declarations and statements
+// the compiler refuses, and part of the message it refuses them with.
+type rejection struct {
+	decls, body, want string
+}
+
+// rejected type-checks each case and checks that the compiler refuses it
+// for the reason given.
+func rejected(e *event.Emitter, check *assert.Checker, cases ...rejection) {
+	for _, c := range cases {
+		err := typeCheck(c.decls, c.body)
+		e.Say("%s", strings.ReplaceAll(strings.TrimSpace(c.decls+"\n"+c.body), "\n", "; "))
+		e.Value("err", err, "  compile error")
+		check.That(err != nil && strings.Contains(err.Error(), c.want), "the compiler rejects it: "+c.want)
+	}
+}
+
+// Run calls the generic functions with types inside and outside their
+// constraints.
+func (constraintsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. A union: exactly the types listed.
+	e.Step("union")
+	check.That(typeCheck("", "") == nil, "the declarations copied for the type checker compile")
+	e.Value("Sum(ints)", Sum([]int{1, 2, 3}), "Sum([]int{1, 2, 3})")
+	e.Value("Sum(floats)", Sum([]float64{0.5, 0.25}), "Sum([]float64{0.5, 0.25})")
+	e.Value("sumExact(int64s)", sumExact([]int64{10, 20}), "sumExact([]int64{10, 20})")
+	assert.Equal(check, "Sum works for int", Sum([]int{1, 2, 3}), 6)
+	assert.Equal(check, "and for float64", Sum([]float64{0.5, 0.25}), 0.75)
+	// Sum([]string{"a", "b"}) // string is not in Number
+	// Sum([]int32{1, 2})      // neither is int32
+	rejected(e, check,
+		rejection{"", `Sum([]string{"a", "b"})`, "string does not satisfy Number"},
+		rejection{"", `Sum([]int32{1, 2})`, "int32 does not satisfy Number"},
+	)
+
+	// 2. ~: the underlying type is what counts.
+	e.Step("tilde")
+	points := []score{10, 25}
+	e.Value("Sum(points)", Sum(points), "Sum([]score{10, 25}), with ~int in Number")
+	e.Value("Sum(legs)", Sum([]meters{1.5, 2}), "Sum([]meters{1.5, 2}), with ~float64")
+	assert.Equal(check, "score gets into Number through ~int", Sum(points), score(35))
+	// sumExact(points) // exactNumber has int, not ~int
+	rejected(e, check,
+		rejection{"", `sumExact([]score{10, 25})`, "possibly missing ~ for int in exactNumber"},
+	)
+
+	// 3. A method constraint: any type with String.
+	e.Step("methods")
+	e.Value("Join(points)", Join(points, ", "), "Join([]score{10, 25}, \", \")")
+	e.Value("Join(labels)", Join([]label{"gold", "silver"}, "/"), "Join([]label{\"gold\", \"silver\"}, \"/\")")
+	assert.Equal(check, "Join calls score's String", Join(points, ", "), "10 pts, 25 pts")
+	assert.Equal(check, "and label's, whatever the underlying type", Join([]label{"gold", "silver"}, "/"), "gold/silver")
+	// Join([]int{1, 2}, ", ") // int has no String method
+	rejected(e, check,
+		rejection{"", `Join([]int{1, 2}, ", ")`, "missing method String"},
+	)
+
+	// 4. Both at once: a number with a String method.
+	e.Step("combined")
+	e.Value("total(points)", total(points), "total([]score{10, 25})")
+	assert.Equal(check, "score is a Number and a Stringer", total(points), "total 35 pts")
+	// total([]meters{1, 2}) // a Number, but no String method
+	// total([]label{"a"})   // a String method, but not a Number
+	rejected(e, check,
+		rejection{"", `total([]meters{1, 2})`, "missing method String"},
+		rejection{"", `total([]label{"a"})`, "label does not satisfy labeledNumber"},
+	)
+
+	// 5. What else the compiler refuses.
+	e.Step("compiler")
+	// var n Number                                  // a type set can't be a variable's type
+	// func double[T any](x T) T { return x + x }    // not every type in any has +
+	// func half[T Number](x T) T { return x >> 1 }  // float64 has no >>
+	rejected(e, check,
+		rejection{"", "var n Number\n_ = n", "cannot use type Number outside a type constraint"},
+		rejection{"func double[T any](x T) T { return x + x }", "", "operator + not defined"},
+		rejection{"func half[T Number](x T) T { return x >> 1 }", "", "shifted operand"},
+	)
+	e.Say("The type checker's messages are the compiler's: go build prints the same ones.")
+	return errors.Join(e.Err(), check.Err())
+}
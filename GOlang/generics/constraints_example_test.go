@@ -0,0 +1,92 @@
+package generics
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestConstrainedFunctions(t *testing.T) {
+	if got := Sum([]score{3, 4}); got != 7 {
+		t.Errorf("Sum of scores = %v, want 7", got)
+	}
+	if got := Sum([]meters{1.5, 2}); got != 3.5 {
+		t.Errorf("Sum of meters = %v, want 3.5", got)
+	}
+	if got := sumExact([]int64{1, 2}); got != 3 {
+		t.Errorf("sumExact = %v, want 3", got)
+	}
+	if got := Join([]label{"a", "b"}, ", "); got != "a, b" {
+		t.Errorf("Join = %q", got)
+	}
+	if got := total([]score{1, 2}); got != "total 3 pts" {
+		t.Errorf("total = %q, want the sum's own String", got)
+	}
+}
+
+func TestTypeCheck(t *testing.T) {
+	if err := typeCheck("", "_ = Sum([]score{1}); _ = total([]score{1}); _ = Join([]label{\"a\"}, \"\")"); err != nil {
+		t.Errorf("calls within the constraints: %v", err)
+	}
+	if err := typeCheck("", "_ = total([]meters{1})"); err == nil || !strings.Contains(err.Error(), "does not satisfy") {
+		t.Errorf("meters for labeledNumber: %v, want it rejected", err)
+	}
+}
+
+// TestConstraintsSrcInStep checks that constraintsSrc, which typeCheck
+// checks the rejected cases against, still declares what the file does.
+func TestConstraintsSrcInStep(t *testing.T) {
+	fset := token.NewFileSet()
+	real, err := parser.ParseFile(fset, "constraints_example.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied, err := parser.ParseFile(fset, "constraintsSrc", constraintsSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := signatures(fset, real)
+	for name, sig := range signatures(fset, copied) {
+		if want[name] != sig {
+			t.Errorf("constraintsSrc declares %s as %s, the file as %s", name, sig, want[name])
+		}
+	}
+}
+
+// signatures returns the type, or function or method signature, of every
+// declaration in f, by name, with the spaces taken out.
+func signatures(fset *token.FileSet, f *ast.File) map[string]string {
+	print := func(n ast.Node) string {
+		var b bytes.Buffer
+		printer.Fprint(&b, fset, n)
+		return strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, b.String())
+	}
+	sigs := map[string]string{}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil {
+				name = print(d.Recv.List[0].Type) + "." + name
+			}
+			sigs[name] = print(d.Type)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if s, ok := spec.(*ast.TypeSpec); ok {
+					sigs[s.Name.Name] = print(s.Type)
+				}
+			}
+		}
+	}
+	return sigs
+}
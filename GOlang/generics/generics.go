@@ -0,0 +1,49 @@
+// Package generics contains examples about type parameters: functions and
+// types written once for many element types, and the constraints that say
+// which types those are. The functions in this file are what the examples
+// instantiate.
+package generics
+
+import "cmp"
+
+// Min returns the smallest of its arguments. It needs at least one, so
+// there is always an answer.
+func Min[T cmp.Ordered](first T, rest ...T) T {
+	m := first
+	for _, v := range rest {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// Map returns f applied to every element of s, in order.
+func Map[T, U any](s []T, f func(T) U) []U {
+	out := make([]U, len(s))
+	for i, v := range s {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// Filter returns the elements of s for which keep returns true, in order.
+func Filter[T any](s []T, keep func(T) bool) []T {
+	var out []T
+	for _, v := range s {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Reduce folds s into one value: it starts from init and combines it with
+// every element in turn.
+func Reduce[T, A any](s []T, init A, combine func(A, T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = combine(acc, v)
+	}
+	return acc
+}
@@ -0,0 +1,52 @@
+package generics
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMin(t *testing.T) {
+	if got := Min(3); got != 3 {
+		t.Errorf("Min(3) = %v", got)
+	}
+	if got := Min(4, -2, 7, -2); got != -2 {
+		t.Errorf("Min(4, -2, 7, -2) = %v, want -2", got)
+	}
+	if got := Min("pear", "apple", "fig"); got != "apple" {
+		t.Errorf("Min of strings = %q, want apple", got)
+	}
+}
+
+func TestMap(t *testing.T) {
+	got := Map([]int{1, 2, 3}, strconv.Itoa)
+	if !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Errorf("Map = %q", got)
+	}
+	if got := Map(nil, strconv.Itoa); len(got) != 0 {
+		t.Errorf("Map of nil = %q, want empty", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	even := func(n int) bool { return n%2 == 0 }
+	if got := Filter([]int{1, 2, 3, 4, 6}, even); !slices.Equal(got, []int{2, 4, 6}) {
+		t.Errorf("Filter = %v, want [2 4 6] in order", got)
+	}
+	if got := Filter([]int{1, 3}, even); got != nil {
+		t.Errorf("Filter with nothing kept = %#v, want nil", got)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	if got := Reduce([]int{1, 2, 3, 4}, 0, func(a, v int) int { return a + v }); got != 10 {
+		t.Errorf("Reduce summing = %v, want 10", got)
+	}
+	join := func(a string, v int) string { return a + strconv.Itoa(v) }
+	if got := Reduce([]int{1, 2, 3}, ">", join); got != ">123" {
+		t.Errorf("Reduce joining = %q, want the elements in order after init", got)
+	}
+	if got := Reduce(nil, 42, func(a, v int) int { return 0 }); got != 42 {
+		t.Errorf("Reduce of nil = %v, want init", got)
+	}
+}
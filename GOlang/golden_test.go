@@ -0,0 +1,38 @@
+package golang_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/amandm/programming-concepts/GOlang/all"
+	"github.com/amandm/programming-concepts/internal/goldentest"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files instead of comparing against them")
+
+// TestMain lets the examples that crash a process of their own run the
+// test binary again, as they do the concepts command.
+func TestMain(m *testing.M) {
+	isolate.Main()
+	code := m.Run()
+	isolate.Cleanup()
+	os.Exit(code)
+}
+
+// TestGolden is "concepts golden" as a test: every example must print
+// what its golden file in testdata/golden says.
+func TestGolden(t *testing.T) {
+	dir := filepath.Join("..", "testdata", "golden")
+	for _, c := range registry.All() {
+		t.Run(c.Describe().Name, func(t *testing.T) {
+			if err := goldentest.Check(context.Background(), c, dir, *update); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
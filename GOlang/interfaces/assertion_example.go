@@ -0,0 +1,203 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(assertionExample{})
+}
+
+// assertionExample gets concrete values back out of interfaces: with the
+// comma-ok assertion, with the single-value one that panics on the wrong
+// type, and with a type switch that sorts a mixed []any. The last step
+// replaces a type switch over Notifiers with a method, the usual cure
+// when one keeps growing.
+type assertionExample struct{}
+
+func (assertionExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "interfaces/assertion_example",
+		Topic:         "interfaces",
+		Level:         registry.Intermediate,
+		Description:   "type assertions, comma-ok, panics and type switches, and when a switch is a design smell",
+		Tags:          []string{"interfaces", "type-assertions", "type-switches", "panics"},
+		Prerequisites: []string{"interfaces/dispatch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (assertionExample) Explain(step string) string {
+	switch step {
+	case "comma-ok":
+		return "v, ok := x.(T) never panics: ok says whether x holds a T, and v is T's zero value when it doesn't."
+	case "panic":
+		return "v := x.(T) without ok panics when x holds something else, with a *runtime.TypeAssertionError saying what it held."
+	case "switch":
+		return "A type switch tries its cases in order. In a case with one type, v has that type; in a case with several, or in default, v keeps the switched interface's type."
+	case "smell":
+		return "A type switch over your own types has to change every time a type is added. A method on the interface puts that code with each type instead."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz interfaces".
+func (assertionExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "var x any = 42; s, ok := x.(string). What are s and ok?",
+			Choices: []string{"\"\" and false", "\"42\" and false", "it panics"},
+			Answer:  "\"\" and false",
+			Explain: "The comma-ok form doesn't panic or convert: s is the zero string and ok is false.",
+		},
+		{
+			Prompt:  "In case int, string: inside a type switch on x any, what is the type of v?",
+			Choices: []string{"int", "string", "any"},
+			Answer:  "any",
+			Explain: "With more than one type in the case, the compiler can't pick one, so v has the type of the switched expression.",
+		},
+		{
+			Prompt:  "A type switch has case Notifier first and case *Email second. Which case does an *Email reach?",
+			Choices: []string{"case Notifier", "case *Email"},
+			Answer:  "case Notifier",
+			Explain: "Cases are tried in order and *Email is a Notifier, so the first case wins and the second is never reached.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (assertionExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "When is x.(T) without the ok safe?",
+			Back:  "When it is a bug for x to hold anything else, so a panic is the right outcome. Otherwise use v, ok := x.(T).",
+		},
+		{
+			Front: "When is a type switch a design smell?",
+			Back:  "When it switches over your own types to decide what each one does: that behavior belongs in a method of the interface. Type switches are fine for data from outside, like decoded JSON, or for optional interfaces like io.WriterTo.",
+		},
+	}
+}
+
+// describe sorts x by its dynamic type. The default case catches every
+// type the switch doesn't list, so nothing goes unhandled without notice.
+func describe(x any) string {
+	switch v := x.(type) {
+	case nil:
+		return "nil"
+	case int:
+		return "int, doubled " + strconv.Itoa(2*v)
+	case float64:
+		return fmt.Sprintf("float64, halved %g", v/2)
+	case string:
+		return fmt.Sprintf("string of %d bytes", len(v))
+	case []int:
+		return fmt.Sprintf("[]int of %d elements", len(v))
+	case error:
+		return "error: " + v.Error()
+	case Notifier:
+		return fmt.Sprintf("Notifier %T", v)
+	case bool, rune:
+		return fmt.Sprintf("bool or rune: %v, still an any", v)
+	default:
+		return fmt.Sprintf("unhandled %T", v)
+	}
+}
+
+// channelSwitch is the smelly way: a new Notifier means a new case here,
+// and in every other switch like it.
+func channelSwitch(n Notifier) string {
+	switch n.(type) {
+	case *Email:
+		return "email"
+	case *SMS:
+		return "sms"
+	default:
+		return "unknown"
+	}
+}
+
+// channeler is the cure: the Notifier says what it is itself.
+type channeler interface {
+	Notifier
+	Channel() string
+}
+
+// webhook is a Notifier added after channelSwitch was written.
+type webhook struct{ url string }
+
+func (h *webhook) Notify(to, msg string) error { return nil }
+func (h *webhook) Channel() string             { return "webhook" }
+
+// Run asserts, panics, recovers and switches.
+func (assertionExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The comma-ok form reports a mismatch instead of panicking.
+	e.Step("comma-ok")
+	var x any = 42
+	n, ok := x.(int)
+	e.Value("n, ok", fmt.Sprint(n, " ", ok), "n, ok := x.(int)")
+	s, ok2 := x.(string)
+	e.Value("s, ok", fmt.Sprintf("%q %v", s, ok2), "s, ok := x.(string)")
+	assert.Equal(check, "x.(int) gets the int back", n+1, 43)
+	check.That(ok && !ok2 && s == "", "asserting the wrong type gives ok false and the zero value")
+	var out any = &Email{}
+	_, isNotifier := out.(Notifier)
+	_, isStringer := out.(fmt.Stringer)
+	e.Value("isNotifier", isNotifier, "out.(Notifier) succeeded, asserting to an interface type")
+	e.Value("isStringer", isStringer, "out.(fmt.Stringer) succeeded")
+	check.That(isNotifier && !isStringer, "asserting to an interface checks the method set")
+
+	// 2. The single-value form panics on a mismatch.
+	e.Step("panic")
+	r := assert.Panics(func() { _ = x.(string) })
+	e.Value("recovered", r, "Panic from x.(string), caught by recover")
+	var tae *runtime.TypeAssertionError
+	err, _ := r.(error)
+	check.That(errors.As(err, &tae), "the panic value is a *runtime.TypeAssertionError")
+	var nilAny any
+	r = assert.Panics(func() { _ = nilAny.(int) })
+	e.Value("recovered", r, "Panic from asserting on a nil interface")
+	check.That(r != nil, "a nil interface holds no type, so every assertion on it fails")
+
+	// 3. A type switch over a mixed []any.
+	e.Step("switch")
+	mixed := []any{7, 2.5, "gopher", []int{1, 2, 3}, nil, errors.New("boom"), &SMS{}, true, 'g', struct{}{}}
+	t := table.New("value", "dynamic type", "case")
+	results := make([]string, len(mixed))
+	for i, v := range mixed {
+		results[i] = describe(v)
+		t.Row(fmt.Sprintf("%v", v), fmt.Sprintf("%T", v), results[i])
+	}
+	e.Diagram(t)
+	assert.Equal(check, "an int reaches case int, typed as an int", results[0], "int, doubled 14")
+	assert.Equal(check, "nil reaches case nil", results[4], "nil")
+	assert.Equal(check, "an *SMS reaches case Notifier", results[6], "Notifier *interfaces.SMS")
+	assert.Equal(check, "a type nobody listed reaches default", results[9], "unhandled struct {}")
+	e.Warn("Go doesn't check that a type switch is exhaustive. A default case that reports the type is how you find out about the ones you missed.")
+
+	// 4. The smell: a switch that has to learn about every new Notifier.
+	e.Step("smell")
+	hook := &webhook{url: "https://example.com/hook"}
+	e.Value("channelSwitch(hook)", channelSwitch(hook), "channelSwitch(webhook), written before webhooks existed")
+	var c channeler = hook
+	e.Value("c.Channel()", c.Channel(), "The webhook's Channel method")
+	assert.Equal(check, "the switch doesn't know the new type", channelSwitch(hook), "unknown")
+	assert.Equal(check, "the method does", c.Channel(), "webhook")
+	e.Say("Type switches are at home with data from outside the program, and with optional methods like io.WriterTo that io.Copy checks for.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkDispatch(b *testing.B) { benchlab.Bench(b, dispatchExample{}) }
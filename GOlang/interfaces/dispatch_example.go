@@ -0,0 +1,204 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(dispatchExample{})
+}
+
+// dispatchExample sends the same message through every Notifier in
+// notifier.go and checks what each one did with it, then hands an Alerter
+// one implementation after another while it runs: the call site never
+// changes, the code it reaches does.
+type dispatchExample struct{}
+
+func (dispatchExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "interfaces/dispatch_example",
+		Topic:         "interfaces",
+		Level:         registry.Beginner,
+		Description:   "a small interface, several implementations, implicit satisfaction and dynamic dispatch",
+		Tags:          []string{"interfaces", "methods", "dispatch", "polymorphism"},
+		Prerequisites: []string{"pointers/receivers_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (dispatchExample) Explain(step string) string {
+	switch step {
+	case "implementations":
+		return "Email, SMS and NotifierFunc have nothing in common but a Notify method with the right signature. That is all it takes to be a Notifier."
+	case "implicit":
+		return "No type says \"implements Notifier\". The compiler checks the method set wherever a value is used as a Notifier, so a type written without knowing about Notifier can satisfy it."
+	case "swap":
+		return "n.Notify(...) looks up Notify in the table the interface value carries for its dynamic type. Put a different type in the interface and the same line runs different code."
+	case "fallback":
+		return "The Alerter doesn't know which Notifiers it has. When the SMS rejects a long message, it tries the email, through the same interface."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz interfaces".
+func (dispatchExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "How does a type declare that it implements Notifier?",
+			Choices: []string{"with an implements clause", "by embedding Notifier", "it doesn't: having the methods is enough"},
+			Answer:  "it doesn't: having the methods is enough",
+			Explain: "Go interfaces are satisfied implicitly. var _ Notifier = (*Email)(nil) is only a compile-time check that it does.",
+		},
+		{
+			Prompt:  "Email's Notify has a pointer receiver. Is an Email value (not a pointer) a Notifier?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "A value's method set only has the value-receiver methods. Only *Email has Notify.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (dispatchExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does var _ Notifier = (*Email)(nil) do?",
+			Back:  "Nothing at run time. It makes the build fail if *Email stops satisfying Notifier, right there instead of at some far-away use.",
+		},
+		{
+			Front: "Where should an interface be declared: next to its implementations, or where it is used?",
+			Back:  "Usually where it is used. The consumer knows which methods it needs, and implementations satisfy it without importing it.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench interfaces".
+func (dispatchExample) Experiments() []benchlab.Experiment {
+	return []benchlab.Experiment{{
+		Name: "calling Notify on a no-op notifier",
+		Approaches: []benchlab.Approach{
+			{Name: "direct call", Bench: func(b *testing.B) {
+				var d discard
+				for range b.N {
+					d.Notify("ada", "build passed")
+				}
+				benchCount = d.n
+			}},
+			{Name: "through the interface", Bench: func(b *testing.B) {
+				d := new(discard)
+				benchNotifier = d
+				for range b.N {
+					benchNotifier.Notify("ada", "build passed")
+				}
+				benchCount = d.n
+			}},
+		},
+		Guidance: "A call through an interface loads the method's address from the " +
+			"interface's table and jumps there, and it usually can't be inlined, so " +
+			"it costs a nanosecond or two where the direct call may cost nothing at " +
+			"all. That matters in the innermost loop of hot code and almost nowhere " +
+			"else; profile-guided optimization can devirtualize the hot calls for you.",
+	}}
+}
+
+// discard is a Notifier that only counts its messages.
+type discard struct{ n int }
+
+func (d *discard) Notify(to, msg string) error {
+	d.n++
+	return nil
+}
+
+// benchNotifier and benchCount keep the benchmarks from being optimized
+// away: the compiler can't tell which type a package variable will hold.
+var (
+	benchNotifier Notifier
+	benchCount    int
+)
+
+// pager was written without Notifier in mind; it happens to have the
+// method Notifier needs.
+type pager struct{ beeps int }
+
+func (p *pager) Notify(to, msg string) error {
+	p.beeps++
+	return nil
+}
+
+// Run notifies through each implementation, then swaps them under an
+// Alerter.
+func (dispatchExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Several implementations of one interface, each checked on its own.
+	e.Step("implementations")
+	email := &Email{From: "ci@example.com"}
+	sms := &SMS{}
+	var called []string
+	fn := NotifierFunc(func(to, msg string) error {
+		called = append(called, to+": "+msg)
+		return nil
+	})
+	for _, n := range []Notifier{email, sms, fn} {
+		err := n.Notify("ada", "build passed")
+		e.Value("type", fmt.Sprintf("%T", n), "Notified through a")
+		check.That(err == nil, fmt.Sprintf("%T delivers a short message", n))
+	}
+	e.Value("email.Sent", email.Sent, "What the Email sent")
+	e.Value("sms.Sent", sms.Sent, "What the SMS sent")
+	e.Value("called", called, "What the NotifierFunc was called with")
+	assert.Equal(check, "Email sends a mail from its address", fmt.Sprint(email.Sent), "[mail from ci@example.com to ada: build passed]")
+	assert.Equal(check, "SMS sends a text", fmt.Sprint(sms.Sent), "[sms to ada: build passed]")
+	assert.Equal(check, "NotifierFunc calls the function", fmt.Sprint(called), "[ada: build passed]")
+	long := strings.Repeat("x", smsLimit+1)
+	err := sms.Notify("ada", long)
+	e.Value("err", err, "SMS.Notify with a 161-byte message")
+	check.That(errors.Is(err, ErrTooLong), "SMS rejects a message longer than one text")
+
+	// 2. Implicit satisfaction: no declaration needed.
+	e.Step("implicit")
+	_, pagerOK := any(&pager{}).(Notifier)
+	_, valueOK := any(Email{}).(Notifier)
+	e.Value("pagerOK", pagerOK, "*pager is a Notifier")
+	e.Value("valueOK", valueOK, "Email (not *Email) is a Notifier")
+	check.That(pagerOK, "a type with the right method satisfies Notifier without saying so")
+	check.That(!valueOK, "Email's pointer-receiver Notify isn't in the method set of an Email value")
+
+	// 3. Swapping implementations while the program runs.
+	e.Step("swap")
+	p := &pager{}
+	alerts := &Alerter{}
+	for _, n := range []Notifier{email, sms, p} {
+		alerts.Notifier = n
+		alerts.Alert("grace", "disk almost full")
+		e.Value("alerts.Notifier", fmt.Sprintf("%T", alerts.Notifier), "alerts.Alert dispatched to")
+	}
+	assert.Equal(check, "the Email got the alert while it was plugged in", email.Sent[len(email.Sent)-1], "mail from ci@example.com to grace: disk almost full")
+	assert.Equal(check, "so did the SMS", sms.Sent[len(sms.Sent)-1], "sms to grace: disk almost full")
+	assert.Equal(check, "and the pager", p.beeps, 1)
+
+	// 4. A fallback, reached through the same interface.
+	e.Step("fallback")
+	alerts = &Alerter{Notifier: sms, Fallback: email}
+	texts, mails := len(sms.Sent), len(email.Sent)
+	err = alerts.Alert("grace", long)
+	e.Value("err", err, "alerts.Alert with a message too long for an SMS")
+	e.Value("texts", len(sms.Sent)-texts, "Texts sent")
+	e.Value("mails", len(email.Sent)-mails, "Mails sent")
+	check.That(err == nil && len(sms.Sent) == texts && len(email.Sent) == mails+1, "the long alert went out by email instead")
+	e.Say("Run \"concepts bench interfaces\" to time a call through an interface against a direct one.")
+	return errors.Join(e.Err(), check.Err())
+}
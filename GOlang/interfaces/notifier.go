@@ -0,0 +1,82 @@
+// Package interfaces contains examples about interfaces: how a type
+// satisfies one without saying so, and how a call through one is
+// dispatched at run time to whichever type is inside. The Notifier in this
+// file and its implementations are what the examples call through.
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Notifier sends a message to someone.
+type Notifier interface {
+	Notify(to, msg string) error
+}
+
+// ErrTooLong is returned by SMS.Notify for a message that doesn't fit in
+// one text.
+var ErrTooLong = errors.New("interfaces: message too long for an SMS")
+
+// smsLimit is how many bytes one text message holds.
+const smsLimit = 160
+
+// Email notifies by email. Sent records every mail it sent.
+type Email struct {
+	From string
+	Sent []string
+}
+
+// Notify sends msg to to by email.
+func (m *Email) Notify(to, msg string) error {
+	m.Sent = append(m.Sent, fmt.Sprintf("mail from %s to %s: %s", m.From, to, msg))
+	return nil
+}
+
+// SMS notifies by text message. Sent records every text it sent.
+type SMS struct {
+	Sent []string
+}
+
+// Notify sends msg to to as a text, or fails with ErrTooLong.
+func (s *SMS) Notify(to, msg string) error {
+	if len(msg) > smsLimit {
+		return fmt.Errorf("texting %s: %w", to, ErrTooLong)
+	}
+	s.Sent = append(s.Sent, fmt.Sprintf("sms to %s: %s", to, msg))
+	return nil
+}
+
+// NotifierFunc lets an ordinary function be a Notifier, the way
+// http.HandlerFunc lets one be an http.Handler.
+type NotifierFunc func(to, msg string) error
+
+// Notify calls f(to, msg).
+func (f NotifierFunc) Notify(to, msg string) error {
+	return f(to, msg)
+}
+
+// The compiler checks these at build time: a type that lost its Notify
+// method, or changed its signature, breaks the build here rather than at
+// some distant call site.
+var (
+	_ Notifier = (*Email)(nil)
+	_ Notifier = (*SMS)(nil)
+	_ Notifier = NotifierFunc(nil)
+)
+
+// Alerter sends alerts through its Notifier, which can be swapped at any
+// time, and falls back to Fallback if that fails.
+type Alerter struct {
+	Notifier Notifier
+	Fallback Notifier // may be nil
+}
+
+// Alert notifies to of msg.
+func (a *Alerter) Alert(to, msg string) error {
+	err := a.Notifier.Notify(to, msg)
+	if err != nil && a.Fallback != nil {
+		return a.Fallback.Notify(to, msg)
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package interfaces
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSMSTooLong(t *testing.T) {
+	var s SMS
+	if err := s.Notify("ann", strings.Repeat("x", smsLimit)); err != nil {
+		t.Fatalf("a text of exactly %d bytes: %v", smsLimit, err)
+	}
+	if err := s.Notify("ann", strings.Repeat("x", smsLimit+1)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("a text of %d bytes: %v, want %v", smsLimit+1, err, ErrTooLong)
+	}
+	if len(s.Sent) != 1 {
+		t.Errorf("%d texts sent, want only the one that fit", len(s.Sent))
+	}
+}
+
+func TestAlerterFallsBack(t *testing.T) {
+	long := strings.Repeat("x", smsLimit+1)
+	tests := []struct {
+		name     string
+		fallback bool
+		msg      string
+		wantErr  error
+		sms      int
+		mail     int
+	}{
+		{"sent", true, "disk full", nil, 1, 0},
+		{"fallen back", true, long, nil, 0, 1},
+		{"no fallback", false, long, ErrTooLong, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sms, mail := &SMS{}, &Email{From: "alerts@example.com"}
+			a := &Alerter{Notifier: sms}
+			if tt.fallback {
+				a.Fallback = mail
+			}
+			err := a.Alert("ann", tt.msg)
+			if !errors.Is(err, tt.wantErr) || len(sms.Sent) != tt.sms || len(mail.Sent) != tt.mail {
+				t.Errorf("Alert = %v with %d texts and %d mails; want %v with %d and %d", err, len(sms.Sent), len(mail.Sent), tt.wantErr, tt.sms, tt.mail)
+			}
+		})
+	}
+}
+
+func TestNotifierFunc(t *testing.T) {
+	var got string
+	a := &Alerter{Notifier: NotifierFunc(func(to, msg string) error {
+		got = to + ": " + msg
+		return nil
+	})}
+	if err := a.Alert("ann", "hi"); err != nil || got != "ann: hi" {
+		t.Errorf("Alert through a NotifierFunc = %v, and the function got %q", err, got)
+	}
+}
@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkEscape(b *testing.B) { benchlab.Bench(b, escapeExample{}) }
+
+func BenchmarkMapInternals(b *testing.B) { benchlab.Bench(b, mapInternalsExample{}) }
+
+func BenchmarkStringHeader(b *testing.B) { benchlab.Bench(b, stringHeaderExample{}) }
+
+func BenchmarkPool(b *testing.B) { benchlab.Bench(b, poolExample{}) }
+
+func BenchmarkSliceGrowth(b *testing.B) { benchlab.Bench(b, sliceGrowthExample{}) }
@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(cleanupExample{})
+}
+
+// cleanupExample attaches callbacks to objects with runtime.AddCleanup and
+// runtime.SetFinalizer and watches when they run: only after the object
+// is unreachable and a collection has noticed, on a goroutine of the
+// runtime's, and for a finalizer in a cycle, never. That makes them a
+// safety net at best; resources are released with an explicit Close.
+type cleanupExample struct{}
+
+func (cleanupExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/cleanup_example",
+		Topic:         "memory",
+		Level:         registry.Advanced,
+		Description:   "runtime.AddCleanup and finalizers: when they run, when they don't, and why Close is better",
+		Tags:          []string{"memory", "gc", "finalizers", "cleanup", "resources"},
+		Prerequisites: []string{"memory/gc_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (cleanupExample) Explain(step string) string {
+	switch step {
+	case "cleanup":
+		return "The cleanup runs once the object is unreachable and a collection has found that out, not when the last reference goes away."
+	case "reachable":
+		return "As long as something refers to the object, no number of collections runs its cleanup."
+	case "cycle":
+		return "A finalizer gets the object itself, so the runtime must keep everything it refers to alive. In a cycle that includes the object, so the finalizer never runs; a cleanup gets a separate argument and has no such problem."
+	case "close":
+		return "Nothing makes the collector run just because descriptors are running out. Waiting for cleanups exhausts them; Close gives each one back at once."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (cleanupExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "f = nil drops the last reference to an object with a cleanup. When does the cleanup run?",
+			Choices: []string{"right away", "some time after a later collection finds the object unreachable", "when the program exits"},
+			Answer:  "some time after a later collection finds the object unreachable",
+			Explain: "Cleanups are queued by the collector and run on a separate goroutine. At exit, pending ones don't run at all.",
+		},
+		{
+			Prompt:  "Why must the cleanup function passed to runtime.AddCleanup(f, fn, arg) not refer to f?",
+			Choices: []string{"it would keep f reachable, so the cleanup would never run", "it would be a data race"},
+			Answer:  "it would keep f reachable, so the cleanup would never run",
+			Explain: "The runtime holds on to fn and arg until the cleanup runs. If either references f, f stays reachable forever.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (cleanupExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "runtime.AddCleanup vs runtime.SetFinalizer?",
+			Back:  "AddCleanup (Go 1.24) runs fn(arg) after the object is freed and works in cycles; an object can have several. SetFinalizer runs fn(obj), resurrecting it, and never runs for objects in a cycle.",
+		},
+		{
+			Front: "Should a file type rely on a cleanup to close its descriptor?",
+			Back:  "No: collections are triggered by heap growth, not by running out of descriptors. Provide Close; a cleanup can at most report a forgotten one.",
+		},
+	}
+}
+
+// collect runs collections until done is closed or timeout has passed,
+// and reports whether done was closed. Cleanups and finalizers are run by
+// a goroutine of their own after the collection that finds their objects
+// unreachable, so a single runtime.GC isn't enough to wait for one.
+func collect(done <-chan struct{}, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		runtime.GC()
+		select {
+		case <-done:
+			return true
+		case <-deadline:
+			return false
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// descriptors is a tiny table of file descriptors, like the operating
+// system's but with room for only a few.
+type descriptors struct {
+	free chan int
+}
+
+func newDescriptors(n int) *descriptors {
+	d := &descriptors{free: make(chan int, n)}
+	for fd := range n {
+		d.free <- fd
+	}
+	return d
+}
+
+// errTooManyFiles is what opening a file returns once every descriptor
+// is taken, like EMFILE.
+var errTooManyFiles = errors.New("too many open files")
+
+// file holds a descriptor from a table.
+type file struct {
+	fd     int
+	closed bool
+	table  *descriptors
+}
+
+// open takes a descriptor for a new file. With cleanup set, it also
+// attaches a cleanup that gives the descriptor back once the file is
+// collected, instead of counting on Close.
+func (d *descriptors) open(cleanup bool) (*file, error) {
+	select {
+	case fd := <-d.free:
+		f := &file{fd: fd, table: d}
+		if cleanup {
+			runtime.AddCleanup(f, func(fd int) { d.free <- fd }, fd)
+		}
+		return f, nil
+	default:
+		return nil, errTooManyFiles
+	}
+}
+
+// Close gives the descriptor back.
+func (f *file) Close() error {
+	if f.closed {
+		return errors.New("file already closed")
+	}
+	f.closed = true
+	f.table.free <- f.fd
+	return nil
+}
+
+// node is an element of a cycle.
+type node struct {
+	next *node
+	_    [64]byte // big enough to get an allocation of its own
+}
+
+// Run watches cleanups and a finalizer, then runs out of descriptors.
+func (cleanupExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. A cleanup runs after the object has become unreachable and been
+	// collected.
+	e.Step("cleanup")
+	ran := make(chan struct{})
+	b := &blob{}
+	runtime.AddCleanup(b, func(ch chan struct{}) { close(ch) }, ran)
+	b = nil
+	e.Say("b = nil: the blob is unreachable, but nothing has happened yet.")
+	cleaned := collect(ran, time.Second)
+	e.Value("cleaned", cleaned, "The cleanup ran after collecting")
+	check.That(cleaned, "a cleanup runs once its object has been collected")
+
+	// 2. While the object is reachable, its cleanup waits.
+	e.Step("reachable")
+	ran = make(chan struct{})
+	kept := &blob{}
+	runtime.AddCleanup(kept, func(ch chan struct{}) { close(ch) }, ran)
+	cleaned = collect(ran, 50*time.Millisecond)
+	e.Value("cleaned", cleaned, "The cleanup ran while the blob was still referenced")
+	check.That(!cleaned, "a reachable object's cleanup doesn't run")
+	runtime.KeepAlive(kept)
+
+	// 3. A cycle: its finalizer never runs, a cleanup does.
+	e.Step("cycle")
+	finalized := make(chan struct{})
+	a := &node{}
+	a.next = &node{next: a}
+	runtime.SetFinalizer(a, func(*node) { close(finalized) })
+	a = nil
+	ranFinalizer := collect(finalized, 100*time.Millisecond)
+	e.Value("finalized", ranFinalizer, "The finalizer of a node in a cycle ran")
+	check.That(!ranFinalizer, "a finalizer in a cycle doesn't run")
+	ran = make(chan struct{})
+	c := &node{}
+	c.next = &node{next: c}
+	runtime.AddCleanup(c, func(ch chan struct{}) { close(ch) }, ran)
+	c = nil
+	cleaned = collect(ran, time.Second)
+	e.Value("cleaned", cleaned, "The cleanup of a node in a cycle ran")
+	check.That(cleaned, "a cleanup runs for a cycle too")
+
+	// 4. Releasing resources: cleanups vs Close. Collections start when
+	// the heap grows, and opening files barely allocates; GOGC=off makes
+	// that certain here.
+	e.Step("close")
+	old := debug.SetGCPercent(-1)
+	fds := newDescriptors(4)
+	opened := 0
+	var openErr error
+	for range 10 {
+		if _, openErr = fds.open(true); openErr != nil {
+			break
+		}
+		opened++ // and the file is dropped without Close
+	}
+	debug.SetGCPercent(old)
+	e.Value("opened", opened, "Files opened before running out, relying on cleanups")
+	e.Value("err", openErr, "The open that failed")
+	assert.Equal(check, "without collections, cleanups give nothing back", opened, 4)
+	check.That(errors.Is(openErr, errTooManyFiles), "the table runs out")
+	e.Warn("The dropped files hold their descriptors until some later collection, which nothing here is asking for.")
+
+	fds = newDescriptors(4)
+	opened = 0
+	for range 10 {
+		f, err := fds.open(false)
+		if err != nil {
+			return fmt.Errorf("open with Close: %w", err)
+		}
+		opened++
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	e.Value("opened", opened, "Files opened with an explicit Close after each")
+	assert.Equal(check, "Close gives each descriptor back at once", opened, 10)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,211 @@
+// Package memory contains examples about where Go keeps values: on the
+// stack of the function that uses them, or on the garbage-collected heap.
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(escapeExample{})
+}
+
+// escapeExample pairs functions whose values stay on the stack with
+// functions whose values have to escape to the heap. The interesting part
+// is not its output but what the compiler says about it: run
+//
+//	concepts escape memory/escape_example
+//
+// to see every decision next to the line it is about.
+type escapeExample struct{}
+
+func (escapeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/escape_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "values that stay on the stack vs values that escape to the heap",
+		Tags:          []string{"memory", "escape-analysis", "stack", "heap"},
+		Prerequisites: []string{"pointers/function_example"},
+	}
+}
+
+// Questions are asked by "concepts quiz memory".
+func (escapeExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "newOnHeap returns &x. Where does x live?",
+			Choices: []string{"on newOnHeap's stack frame", "on the heap"},
+			Answer:  "on the heap",
+			Explain: "The caller keeps using x after newOnHeap returns, so the compiler reports \"moved to heap: x\".",
+		},
+		{
+			Prompt:  "doubleLocal passes &x to double. Does x have to move to the heap?",
+			Choices: []string{"yes, its address is taken", "no, double's parameter does not escape"},
+			Answer:  "no, double's parameter does not escape",
+			Explain: "Taking an address is fine as long as the pointer doesn't outlive the frame; the compiler says \"p does not escape\" for double.",
+		},
+		{
+			Prompt:  "Why does the slice in bigSlice escape even though it is never returned?",
+			Choices: []string{"it is too big for a stack frame", "slices always escape", "range loops force heap allocation"},
+			Answer:  "it is too big for a stack frame",
+			Explain: "fixedSlice makes a small slice the same way and keeps it on the stack; only the 1 MB size makes the difference.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (escapeExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does \"moved to heap: x\" in go build -gcflags=-m mean?",
+			Back:  "x outlives its function (for example because its address is returned), so the compiler allocates it on the heap instead of the stack frame.",
+		},
+		{
+			Front: "Does taking the address of a local variable make it escape?",
+			Back:  "Not by itself. It only escapes if the pointer can outlive the frame, e.g. it is returned or stored somewhere long-lived.",
+		},
+		{
+			Front: "How do you see the compiler's escape-analysis decisions?",
+			Back:  "Build with -gcflags=-m (or run \"concepts escape memory/escape_example\").",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench memory".
+func (escapeExample) Experiments() []benchlab.Experiment {
+	return []benchlab.Experiment{{
+		Name: "an int on the stack vs on the heap",
+		Approaches: []benchlab.Approach{
+			{Name: "doubleLocal (stack)", Bench: func(b *testing.B) {
+				for range b.N {
+					doubleLocal()
+				}
+			}},
+			{Name: "newOnHeap (heap)", Bench: func(b *testing.B) {
+				for range b.N {
+					heapPtr = newOnHeap()
+				}
+			}},
+		},
+		Guidance: "Both functions do about the same work, but newOnHeap has to allocate " +
+			"x on the heap because it returns &x. The allocation itself costs time, and " +
+			"every allocated byte is more work for the garbage collector later, which " +
+			"this benchmark doesn't even show. When a function runs in a hot loop, " +
+			"returning a value instead of a pointer is often the cheapest optimization.",
+	}}
+}
+
+// sumOnStack builds an array, sums it and returns the sum. Nothing outlives
+// the call, so the array lives in sumOnStack's stack frame and disappears
+// when it returns. The compiler prints nothing at all about it.
+//
+//go:noinline
+func sumOnStack() int {
+	numbers := [4]int{1, 2, 3, 4}
+	total := 0
+	for _, n := range numbers {
+		total += n
+	}
+	return total
+}
+
+// double only reads and writes through p while it runs and never stores it
+// anywhere, so the compiler reports "p does not escape": callers may pass
+// the address of a stack variable.
+//
+//go:noinline
+func double(p *int) {
+	*p *= 2
+}
+
+// doubleLocal passes the address of a local to double. Because p doesn't
+// escape from double, x can stay on the stack even though its address is taken.
+//
+//go:noinline
+func doubleLocal() int {
+	x := 21 // escape: stack
+	double(&x)
+	return x
+}
+
+// newOnHeap returns the address of its local variable. The caller keeps
+// using x after newOnHeap's stack frame is gone, so the compiler has to
+// "move x to heap".
+//
+//go:noinline
+func newOnHeap() *int {
+	x := 42 // escape: heap
+	return &x
+}
+
+// fixedSlice makes a slice whose size is known at compile time and doesn't
+// return it, so its backing array can stay on the stack.
+//
+//go:noinline
+func fixedSlice() int {
+	s := make([]int, 8) // escape: stack
+	for i := range s {
+		s[i] = i
+	}
+	return s[7]
+}
+
+// bigSlice makes a slice that is far larger than the compiler is willing
+// to put in a stack frame (64 KB for make). Even though it never leaves the
+// function, its backing array "escapes to heap" because of its size alone.
+//
+//go:noinline
+func bigSlice() int {
+	s := make([]byte, 1<<20) // escape: heap
+	for i := range s {
+		s[i] = byte(i)
+	}
+	return int(s[len(s)-1])
+}
+
+// heapPtr keeps newOnHeap's result alive while its allocations are counted.
+var heapPtr *int
+
+// Run calls every function once. The printed results are plain numbers;
+// the lesson is in "concepts escape memory/escape_example". Run also counts
+// each function's heap allocations, so the split between "stack" and
+// "heap" below is checked rather than just claimed.
+func (escapeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	allocs := func(f func()) int { return int(testing.AllocsPerRun(10, f)) }
+	assert.Equal(check, "sumOnStack doesn't allocate", allocs(func() { sumOnStack() }), 0)
+	assert.Equal(check, "doubleLocal doesn't allocate", allocs(func() { doubleLocal() }), 0)
+	assert.Equal(check, "fixedSlice doesn't allocate", allocs(func() { fixedSlice() }), 0)
+	// Anything else running (a profiler, say) can add to the count while
+	// the slower functions run, so only check that they do allocate.
+	check.That(allocs(func() { heapPtr = newOnHeap() }) >= 1, "newOnHeap allocates x on the heap")
+	check.That(allocs(func() { bigSlice() }) >= 1, "bigSlice allocates its backing array on the heap")
+
+	e.Step("stack")
+	e.Say("These values never outlive the function that creates them, so they stay on the stack:")
+	e.Value("sumOnStack()", sumOnStack(), "sumOnStack()")
+	e.Value("doubleLocal()", doubleLocal(), "doubleLocal()")
+	e.Value("fixedSlice()", fixedSlice(), "fixedSlice()")
+
+	e.Step("heap")
+	e.Say("These have to live on the heap:")
+	e.Value("*newOnHeap()", *newOnHeap(), "*newOnHeap() (x outlives newOnHeap)")
+	e.Value("bigSlice()", bigSlice(), "bigSlice() (1 MB is too big for a stack frame)")
+	e.Warn("Every heap allocation is work for the garbage collector; in hot loops, prefer values that stay on the stack.")
+
+	e.Step("next")
+	e.Say("Run \"concepts escape memory/escape_example\" to see the compiler's decision for each line.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(gcExample{})
+}
+
+// gcExample allocates in waves and reads the runtime's memory statistics
+// between them, so the garbage collector's work shows up as numbers: live
+// memory that stays, garbage that goes, and collections that start on
+// their own once the heap has grown enough. The exact numbers differ
+// from run to run; the claims are about how they move.
+type gcExample struct{}
+
+func (gcExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/gc_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "watching the garbage collector: live vs garbage waves, runtime.GC, GOGC",
+		Tags:          []string{"memory", "gc", "garbage-collector", "runtime", "memstats"},
+		Prerequisites: []string{"memory/escape_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (gcExample) Explain(step string) string {
+	switch step {
+	case "baseline":
+		return "runtime.GC runs a full collection and waits for it, so the numbers start from only what is really live."
+	case "live":
+		return "A slice keeps every chunk reachable. No collection can free them: HeapAlloc grows by the size of the wave."
+	case "drop":
+		return "Once nothing refers to the chunks, they are garbage. The next collection finds them unreachable and frees their memory."
+	case "garbage":
+		return "Chunks that are dropped right away never pile up: every time the heap doubles since the last collection (GOGC=100), the runtime starts another one by itself."
+	case "gc-off":
+		return "debug.SetGCPercent(-1) is GOGC=off: no collection starts on its own, and the garbage stays on the heap until one is asked for."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (gcExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "With the default GOGC=100 and 10 MB live after a collection, when does the next one start?",
+			Choices: []string{"after a fixed time", "when the heap reaches about 20 MB", "only when runtime.GC is called"},
+			Answer:  "when the heap reaches about 20 MB",
+			Explain: "GOGC is the percentage the heap may grow over the live heap before the next cycle: 10 MB + 100% of 10 MB.",
+		},
+		{
+			Prompt:  "After the collector frees memory, does the process's memory use drop at once?",
+			Choices: []string{"yes", "not necessarily"},
+			Answer:  "not necessarily",
+			Explain: "Freed spans become idle heap (HeapIdle) that the runtime reuses; the scavenger returns them to the operating system (HeapReleased) gradually.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (gcExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "HeapAlloc vs HeapInuse vs HeapIdle?",
+			Back:  "HeapAlloc: bytes of allocated objects, live or not yet collected. HeapInuse: bytes in spans holding objects. HeapIdle: bytes in spans that hold nothing and can be reused or returned to the OS.",
+		},
+		{
+			Front: "How do you watch the collector in a running program?",
+			Back:  "GODEBUG=gctrace=1 prints a line per collection; runtime.ReadMemStats and runtime/metrics give the numbers from inside.",
+		},
+	}
+}
+
+const (
+	chunk = 1 << 20 // bytes per allocation
+	wave  = 32      // chunks per wave
+	mb    = 1 << 20
+)
+
+// gcSink receives garbage chunks, so the compiler can't skip allocating
+// them.
+var gcSink []byte
+
+// stats reads the memory statistics after the collections so far.
+func stats() runtime.MemStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms
+}
+
+// report records the heap's state, labelled with the phase it follows.
+func report(e *event.Emitter, phase string, ms runtime.MemStats) {
+	e.Varying("HeapAlloc", ms.HeapAlloc/mb, "HeapAlloc after "+phase+" (MB)")
+	e.Varying("HeapInuse", ms.HeapInuse/mb, "HeapInuse after "+phase+" (MB)")
+	e.Varying("NumGC", ms.NumGC, "NumGC after "+phase)
+}
+
+// Run allocates in waves and reads the statistics in between.
+func (gcExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. A clean start.
+	e.Step("baseline")
+	runtime.GC()
+	base := stats()
+	report(e, "runtime.GC()", base)
+
+	// 2. A wave of live chunks: reachable, so they stay.
+	e.Step("live")
+	live := make([][]byte, wave)
+	for i := range live {
+		live[i] = make([]byte, chunk)
+	}
+	afterLive := stats()
+	report(e, "allocating 32 MB that stays reachable", afterLive)
+	check.That(afterLive.HeapAlloc >= wave*chunk, "the live wave is all on the heap")
+	runtime.GC()
+	kept := stats()
+	check.That(kept.HeapAlloc >= wave*chunk, "a collection can't free chunks that are still reachable")
+	runtime.KeepAlive(live)
+
+	// 3. Drop the references; the next collection frees the wave.
+	e.Step("drop")
+	live = nil
+	runtime.GC()
+	dropped := stats()
+	report(e, "dropping the wave and runtime.GC()", dropped)
+	e.Varying("freed", (kept.HeapAlloc-dropped.HeapAlloc)/mb, "MB freed by the collection")
+	check.That(kept.HeapAlloc-dropped.HeapAlloc >= wave*chunk*3/4, "the collection frees the unreachable wave")
+	check.That(dropped.NumGC > kept.NumGC, "runtime.GC counts as a collection")
+
+	// 4. A wave of garbage: the runtime collects as it goes.
+	e.Step("garbage")
+	before := stats()
+	for range 8 * wave {
+		gcSink = make([]byte, chunk)
+	}
+	gcSink = nil
+	afterGarbage := stats()
+	report(e, "allocating 256 MB of garbage", afterGarbage)
+	e.Varying("cycles", afterGarbage.NumGC-before.NumGC, "Collections started by the runtime itself")
+	check.That(afterGarbage.NumGC > before.NumGC, "allocating enough garbage starts collections without runtime.GC")
+	check.That(afterGarbage.HeapAlloc-before.HeapAlloc < 8*wave*chunk/2, "the heap doesn't grow by all the garbage allocated")
+
+	// 5. GOGC=off: garbage piles up until someone asks for a collection.
+	e.Step("gc-off")
+	old := debug.SetGCPercent(-1)
+	runtime.GC()
+	before = stats()
+	for range wave {
+		gcSink = make([]byte, chunk)
+	}
+	gcSink = nil
+	noGC := stats()
+	debug.SetGCPercent(old)
+	report(e, "allocating 32 MB of garbage with GOGC=off", noGC)
+	assert.Equal(check, "with GOGC=off no collection starts on its own", noGC.NumGC, before.NumGC)
+	check.That(noGC.HeapAlloc-before.HeapAlloc >= wave*chunk, "the garbage stays on the heap")
+	runtime.GC()
+	report(e, "restoring GOGC and runtime.GC()", stats())
+	e.Say("Run with GODEBUG=gctrace=1 to have the runtime print a line for every collection.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(hotspotExample{})
+}
+
+// hotspotExample builds a report in a way that allocates far more than it
+// needs to, on one line, and then the way that doesn't. It is the patient
+// of
+//
+//	concepts profile-walkthrough memory/hotspot_example
+//
+// which captures a heap profile of it, reads it, and points at that line,
+// going through the pprof workflow one step at a time.
+type hotspotExample struct{}
+
+func (hotspotExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/hotspot_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "an allocation hotspot to find with a heap profile, and its fix",
+		Tags:          []string{"memory", "profiling", "pprof", "allocation", "strings"},
+		Prerequisites: []string{"memory/stringheader_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (hotspotExample) Explain(step string) string {
+	switch step {
+	case "slow":
+		return "Each += copies the whole report so far into a new string: the bytes allocated grow with the square of the number of records."
+	case "fast":
+		return "A strings.Builder grown to the final size up front allocates once and copies every record once."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (hotspotExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "In a heap profile, what is the flat value of a line?",
+			Choices: []string{"bytes allocated by that line itself", "bytes allocated by that line and everything it called"},
+			Answer:  "bytes allocated by that line itself",
+			Explain: "That's what makes it point at the culprit. cum adds the callees, which is how the profile finds the functions leading there.",
+		},
+	}
+}
+
+// Variants are profiled one after the other by "concepts
+// profile-walkthrough", and compared by "concepts compare".
+func (hotspotExample) Variants() []registry.Variant {
+	variant := func(name string, build func([]string) string) registry.Variant {
+		return registry.Variant{Name: name, Run: func(ctx context.Context, w io.Writer) error {
+			e := event.From(ctx, w)
+			e.Step(name)
+			out := build(records(reportSize))
+			e.Value("len", len(out), "Length of the report")
+			return e.Err()
+		}}
+	}
+	return []registry.Variant{variant("slow", slowReport), variant("fast", fastReport)}
+}
+
+// reportSize is how many records the report has.
+const reportSize = 2000
+
+// records returns n lines to put in a report.
+func records(n int) []string {
+	rs := make([]string, n)
+	for i := range rs {
+		rs[i] = fmt.Sprintf("record %04d: status=ok latency=%dms\n", i, i%97)
+	}
+	return rs
+}
+
+// slowReport joins the records with +=, the hotspot.
+func slowReport(rs []string) string {
+	report := ""
+	for _, r := range rs {
+		report += r
+	}
+	return report
+}
+
+// fastReport joins them with a strings.Builder of the right size.
+func fastReport(rs []string) string {
+	n := 0
+	for _, r := range rs {
+		n += len(r)
+	}
+	var b strings.Builder
+	b.Grow(n)
+	for _, r := range rs {
+		b.WriteString(r)
+	}
+	return b.String()
+}
+
+// Run builds the report both ways and measures what each allocated.
+func (hotspotExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	rs := records(reportSize)
+	bytesFor := func(build func([]string) string) uint64 {
+		before := allocated()
+		benchString = build(rs)
+		return allocated() - before
+	}
+
+	// 1. The slow way: the report is copied once per record.
+	e.Step("slow")
+	slow := slowReport(rs)
+	slowBytes := bytesFor(slowReport)
+	e.Value("len", len(slow), "Length of the report")
+	e.Varying("bytes", slowBytes, "Bytes allocated by slowReport")
+
+	// 2. The fast way: one allocation of the final size.
+	e.Step("fast")
+	fast := fastReport(rs)
+	fastBytes := bytesFor(fastReport)
+	e.Varying("bytes", fastBytes, "Bytes allocated by fastReport")
+	check.That(fast == slow, "both ways build the same report")
+	check.That(fastBytes < 2*uint64(len(fast)), "fastReport allocates little more than the report itself")
+	check.That(slowBytes > 100*fastBytes, "slowReport allocates over a hundred times more")
+	e.Say("Run \"concepts profile-walkthrough memory/hotspot_example\" to find the hot line with a heap profile.")
+	return errors.Join(e.Err(), check.Err())
+}
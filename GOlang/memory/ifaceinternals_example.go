@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(ifaceInternalsExample{})
+}
+
+// ifaceInternalsExample opens up interface values: two words, one saying
+// what the dynamic type is and one pointing at the dynamic value. Reading
+// the words with unsafe shows when boxing a value allocates, and why an
+// interface holding a nil pointer is not a nil interface.
+type ifaceInternalsExample struct{}
+
+func (ifaceInternalsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/ifaceinternals_example",
+		Topic:         "memory",
+		Level:         registry.Advanced,
+		Description:   "interface values: the type and data words, boxing allocations and the typed nil",
+		Tags:          []string{"memory", "interfaces", "reflection", "unsafe", "nil"},
+		Prerequisites: []string{"memory/stackheap_example", "pointers/nil_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (ifaceInternalsExample) Explain(step string) string {
+	switch step {
+	case "words":
+		return "An interface value is two words. For an empty interface the first points at the dynamic type; for one with methods, at a table of the type and its methods."
+	case "reflect":
+		return "reflect.TypeOf and reflect.ValueOf read the same two words: the type from the first, the value from the second."
+	case "boxing":
+		return "The data word can only hold a pointer. A value that isn't one is copied to the heap, unless it is small enough for the runtime to have a ready-made copy."
+	case "typed-nil":
+		return "An interface is nil only when both words are. A nil pointer in an interface sets the type word, so the interface isn't nil."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (ifaceInternalsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "var p *bytes.Buffer; var w io.Writer = p. Is w == nil?",
+			Choices: []string{"true", "false"},
+			Answer:  "false",
+			Explain: "w's type word says *bytes.Buffer; only its data word is nil. An interface is nil only when it has no type either.",
+		},
+		{
+			Prompt:  "Which of these conversions to any allocates, once the interface escapes?",
+			Choices: []string{"a *point", "an int holding 1000", "a struct{}"},
+			Answer:  "an int holding 1000",
+			Explain: "A pointer goes in the data word as it is and a zero-size value points at a shared zero address. The int has to be copied somewhere for the data word to point at.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (ifaceInternalsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What are the two words of an interface value?",
+			Back:  "A type word (the dynamic type, or for non-empty interfaces an itab: the type plus its method table) and a data word pointing at the value.",
+		},
+		{
+			Front: "How do you avoid returning a non-nil error that holds a nil pointer?",
+			Back:  "Return the literal nil on success, not a nil *MyError variable. Declare the result as error, not as the concrete pointer type.",
+		},
+	}
+}
+
+// words returns the type word and the data word of the interface value
+// *p. T must be an interface type.
+func words[T any](p *T) (typ, data unsafe.Pointer) {
+	w := (*[2]unsafe.Pointer)(unsafe.Pointer(p))
+	return w[0], w[1]
+}
+
+// wordsRow adds an interface value's words to t.
+func wordsRow[T any](t *table.Table, expr string, v T) {
+	typ, data := words(&v)
+	t.Row(expr, fmt.Sprintf("%T", v), word(typ), word(data))
+}
+
+// word formats an interface word, spelling nil out: an address of 0 would
+// be hidden like every other address in the golden files. Addresses are
+// padded to 16 digits so that the table's columns keep their width.
+func word(p unsafe.Pointer) string {
+	if p == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%#016x", uintptr(p))
+}
+
+// boxed is where the boxing step puts its interfaces, so that they escape.
+var boxed any
+
+// notFound is an error type for the typed-nil step.
+type notFound struct{ name string }
+
+func (e *notFound) Error() string { return e.name + " not found" }
+
+// findBuggy returns a nil *notFound as an error when it finds name.
+func findBuggy(name string) error {
+	var err *notFound
+	if name != "gopher" {
+		err = &notFound{name}
+	}
+	return err
+}
+
+// findFixed returns the literal nil when it finds name.
+func findFixed(name string) error {
+	if name != "gopher" {
+		return &notFound{name}
+	}
+	return nil
+}
+
+// Run reads interface values word by word.
+func (ifaceInternalsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Two words: a type word and a data word.
+	e.Step("words")
+	p := &point{3, 4}
+	var a, b any = p, &point{5, 6}
+	var n any = 42
+	e.Value("unsafe.Sizeof(a)", unsafe.Sizeof(a), "Size of an any, in bytes")
+	t := table.New("value", "dynamic type", "type word", "data word")
+	wordsRow(t, "a = p", a)
+	wordsRow(t, "b = &point{5, 6}", b)
+	wordsRow(t, "n = 42", n)
+	var wr io.Writer = new(bytes.Buffer)
+	wordsRow(t, "wr io.Writer", wr)
+	e.Diagram(t)
+	aTyp, aData := words(&a)
+	bTyp, _ := words(&b)
+	nTyp, _ := words(&n)
+	assert.Equal(check, "an interface value is two words", unsafe.Sizeof(a), 2*unsafe.Sizeof(uintptr(0)))
+	check.That(aTyp == bTyp && aTyp != nTyp, "values of the same type share a type word")
+	check.That(aData == unsafe.Pointer(p), "a pointer is stored in the data word as it is")
+
+	// 2. reflect reads the same words.
+	e.Step("reflect")
+	e.Value("reflect.TypeOf(a)", reflect.TypeOf(a), "reflect.TypeOf(a)")
+	e.Value("reflect.ValueOf(a).Elem()", reflect.ValueOf(a).Elem(), "reflect.ValueOf(a).Elem(), the point it points at")
+	e.Value("reflect.TypeOf(wr)", reflect.TypeOf(wr), "reflect.TypeOf(wr), the dynamic type, not io.Writer")
+	e.Value("reflect.ValueOf(n).Kind()", reflect.ValueOf(n).Kind(), "reflect.ValueOf(n).Kind()")
+	check.That(reflect.ValueOf(a).Pointer() == uintptr(aData), "reflect's pointer is the data word")
+	assert.Equal(check, "reflect sees the dynamic type", reflect.TypeOf(wr).String(), "*bytes.Buffer")
+
+	// 3. Boxing: which values have to be copied to the heap.
+	e.Step("boxing")
+	ints := []int{7, 1000}
+	allocs := func(f func()) float64 { return testing.AllocsPerRun(100, f) }
+	pointerAllocs := allocs(func() { boxed = p })
+	smallAllocs := allocs(func() { boxed = ints[0] })
+	bigAllocs := allocs(func() { boxed = ints[1] })
+	structAllocs := allocs(func() { boxed = *p })
+	emptyAllocs := allocs(func() { boxed = struct{}{} })
+	e.Value("pointer", pointerAllocs, "Allocations boxing a *point")
+	e.Varying("small", smallAllocs, "Allocations boxing the int 7")
+	e.Value("big", bigAllocs, "Allocations boxing the int 1000")
+	e.Value("struct", structAllocs, "Allocations boxing a point")
+	e.Value("empty", emptyAllocs, "Allocations boxing a struct{}")
+	assert.Equal(check, "a pointer fits in the data word", pointerAllocs, 0.0)
+	assert.Equal(check, "a zero-size value needs no memory", emptyAllocs, 0.0)
+	assert.Equal(check, "a point has to be copied to the heap", structAllocs, 1.0)
+	assert.Equal(check, "an int of 1000 has to be copied to the heap", bigAllocs, 1.0)
+	check.That(smallAllocs <= bigAllocs, "a small int never costs more to box than a large one")
+	e.Say("Single-byte values like 7 come from a table the runtime keeps, so they don't allocate. See memory/stackheap_example for when boxing doesn't escape at all.")
+
+	// 4. The typed nil: a nil pointer in a non-nil interface.
+	e.Step("typed-nil")
+	var np *point
+	var typedNil any = np
+	var untyped any
+	t = table.New("value", "dynamic type", "type word", "data word")
+	wordsRow(t, "typedNil = (*point)(nil)", typedNil)
+	wordsRow(t, "untyped", untyped)
+	e.Diagram(t)
+	e.Value("typedNil == nil", typedNil == nil, "typedNil == nil")
+	e.Value("untyped == nil", untyped == nil, "untyped == nil")
+	check.That(typedNil != nil && untyped == nil, "only an interface with neither type nor value is nil")
+	buggy, fixed := findBuggy("gopher"), findFixed("gopher")
+	e.Value("findBuggy(\"gopher\") != nil", buggy != nil, "findBuggy(\"gopher\") != nil, though it found the gopher")
+	e.Value("findFixed(\"gopher\") != nil", fixed != nil, "findFixed(\"gopher\") != nil")
+	check.That(buggy != nil && fixed == nil, "returning a nil *notFound gives a non-nil error")
+	e.Warn("Returning a nil concrete pointer as an error makes every err != nil check fail. Return the literal nil instead.")
+	return errors.Join(e.Err(), check.Err())
+}
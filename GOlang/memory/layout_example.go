@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/layout"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(layoutExample{})
+}
+
+// layoutExample lays out structs field by field. Every field starts at a
+// multiple of its alignment, so small fields between big ones leave gaps;
+// sorting the fields by alignment closes them. The same tables are
+// available for any type an example lists in its Layouts method:
+//
+//	concepts layout memory.badOrder
+type layoutExample struct{}
+
+func (layoutExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/layout_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "struct padding and alignment: field offsets, wasted bytes, and reordering to save them",
+		Tags:          []string{"memory", "structs", "alignment", "padding", "reflect"},
+		Prerequisites: []string{"pointers/struct_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (layoutExample) Explain(step string) string {
+	switch step {
+	case "padding":
+		return "An int64 must start at a multiple of 8, so after a 1-byte bool the compiler skips 7 bytes. The struct's size is rounded up to its alignment too."
+	case "reorder":
+		return "Sorted from the most to the least aligned, every field starts right where the previous one ends."
+	case "zero-size":
+		return "A zero-size field at the end gets padding, so that its address still lies inside the struct."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (layoutExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "On a 64-bit platform, how big is struct{ a bool; b int64; c bool }?",
+			Choices: []string{"10 bytes", "16 bytes", "24 bytes"},
+			Answer:  "24 bytes",
+			Explain: "b must start at offset 8, after 7 bytes of padding, and the size rounds up to a multiple of 8: 1+7+8+1+7.",
+		},
+		{
+			Prompt:  "Does the Go compiler reorder struct fields to save space?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "Fields are laid out in the order they are declared, so the order is up to you.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (layoutExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How do you minimize a struct's padding?",
+			Back:  "Declare the fields from the largest alignment to the smallest (e.g. int64s and pointers, then int32s, then bools). The fieldalignment analyzer suggests an order.",
+		},
+	}
+}
+
+// Layouts are shown by "concepts layout".
+func (layoutExample) Layouts() []any {
+	return []any{badOrder{}, goodOrder{}, trailing{}, point{}, blob{}, time.Time{}}
+}
+
+// badOrder declares its fields in an order that wastes space.
+type badOrder struct {
+	a bool
+	b int64
+	c bool
+	d int32
+	e bool
+}
+
+// goodOrder has the same fields as badOrder, sorted by alignment.
+type goodOrder struct {
+	b int64
+	d int32
+	a bool
+	c bool
+	e bool
+}
+
+// trailing ends in a zero-size field.
+type trailing struct {
+	n   int64
+	end struct{}
+}
+
+// Run lays out the badly ordered struct, its reordering and the
+// zero-size case.
+func (layoutExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Declared order: gaps before the aligned fields.
+	e.Step("padding")
+	bad, err := layout.Of(reflect.TypeFor[badOrder]())
+	if err != nil {
+		return err
+	}
+	e.Diagram(bad)
+	assert.Equal(check, "the computed size is the compiler's", bad.Size, unsafe.Sizeof(badOrder{}))
+	assert.Equal(check, "b's offset is the compiler's", bad.Fields[1].Offset, unsafe.Offsetof(badOrder{}.b))
+	check.That(bad.Padding() > 0, "the declared order leaves padding")
+
+	// 2. Sorted by alignment: the same fields in less space.
+	e.Step("reorder")
+	r := bad.Reordered()
+	e.Diagram(r)
+	assert.Equal(check, "the reordered layout is goodOrder's", r.Size, unsafe.Sizeof(goodOrder{}))
+	check.That(r.Size < bad.Size, "sorting the fields by alignment makes the struct smaller")
+	e.Value("saved", bad.Size-r.Size, "Bytes saved per value")
+	e.Say("A slice of a million badOrders wastes that many megabytes, and fewer values fit in each cache line.")
+
+	// 3. A zero-size field at the end costs padding.
+	e.Step("zero-size")
+	tr, err := layout.Of(reflect.TypeFor[trailing]())
+	if err != nil {
+		return err
+	}
+	e.Diagram(tr)
+	check.That(tr.Size > unsafe.Sizeof(int64(0)), "a trailing zero-size field makes the struct bigger")
+	assert.Equal(check, "moving it first removes the padding", tr.Reordered().Size, unsafe.Sizeof(int64(0)))
+	e.Say("Run \"concepts layout\" for the other types registered for layout, such as time.Time.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(mapInternalsExample{})
+}
+
+// mapInternalsExample looks inside a map from the outside. Go's maps are
+// hash tables of groups of 8 slots; the example finds out when a map grows
+// by watching the heap while inserting, works the load factor out from
+// that, and shows three things that follow from hashing: NaN keys, why map
+// elements have no address, and random iteration order.
+type mapInternalsExample struct{}
+
+func (mapInternalsExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/mapinternals_example",
+		Topic:         "memory",
+		Level:         registry.Advanced,
+		Description:   "map internals: growth and load factor, hashing quirks, unaddressable elements, iteration order",
+		Tags:          []string{"memory", "maps", "hashing", "allocation"},
+		Prerequisites: []string{"pointers/newmake_example", "memory/sliceheader_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (mapInternalsExample) Explain(step string) string {
+	switch step {
+	case "growth":
+		return "Each insert that needs a new table shows up as a jump in allocated bytes. The lengths where that happens give away the table sizes and how full they get."
+	case "hashing":
+		return "A key is found by its hash and then compared with ==. NaN is not equal to itself, so a NaN key can be stored but never found again."
+	case "address":
+		return "Growing moves every entry to a new table, so a pointer to an element would dangle. That's why &m[k] doesn't compile."
+	case "iteration":
+		return "Each range over a map starts at a random place, so programs can't come to rely on an order the language doesn't promise."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (mapInternalsExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Why doesn't &m[\"k\"] compile?",
+			Choices: []string{"map elements are on the stack", "the map can move its elements when it grows", "maps are immutable"},
+			Answer:  "the map can move its elements when it grows",
+			Explain: "An insert can move every entry into a new table; a pointer to the old slot would point at freed or reused memory.",
+		},
+		{
+			Prompt:  "m[math.NaN()] = 1 runs twice. What is len(m)?",
+			Choices: []string{"1", "2"},
+			Answer:  "2",
+			Explain: "NaN != NaN, so the second insert can't find the first key and adds another entry. Neither can ever be looked up.",
+		},
+		{
+			Prompt:  "How do you get a map's entries in a stable order?",
+			Choices: []string{"range over it twice", "sort the keys, e.g. slices.Sorted(maps.Keys(m))", "use a map with fewer than 8 entries"},
+			Answer:  "sort the keys, e.g. slices.Sorted(maps.Keys(m))",
+			Explain: "The iteration order is unspecified and deliberately randomized, even for small maps.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (mapInternalsExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How do you change a field of a struct stored in a map?",
+			Back:  "m[k].f = v doesn't compile. Copy it out, change the copy and store it back (p := m[k]; p.f = v; m[k] = p), or store pointers in the map.",
+		},
+		{
+			Front: "What is a map's load factor?",
+			Back:  "How full its table may get before it grows: entries per slot. This runtime grows a table once it is 7/8 full, to twice the size.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench memory".
+func (mapInternalsExample) Experiments() []benchlab.Experiment {
+	lookup := func(keyLen int) func(b *testing.B) {
+		return func(b *testing.B) {
+			m := map[string]int{}
+			var keys []string
+			for i := range 1000 {
+				k := fmt.Sprintf("%0*d", keyLen, i)
+				m[k] = i
+				keys = append(keys, k)
+			}
+			b.ResetTimer()
+			for i := range b.N {
+				benchInt = m[keys[i%len(keys)]]
+			}
+		}
+	}
+	return []benchlab.Experiment{{
+		Name: "looking up string keys, by key length",
+		Approaches: []benchlab.Approach{
+			{Name: "8-byte keys", Bench: lookup(8)},
+			{Name: "1 KB keys", Bench: lookup(1 << 10)},
+		},
+		Guidance: "Every lookup hashes the whole key and then compares it with the key it " +
+			"finds, so the cost grows with the key's length. For long keys that are " +
+			"looked up often, an integer ID or a shorter digest makes a cheaper key.",
+	}}
+}
+
+// benchInt keeps the benchmarked lookups from being optimized away.
+var benchInt int
+
+// allocated returns the bytes allocated on the heap since the program
+// started. ReadMemStats stops the world, so it is exact, if slow.
+func allocated() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.TotalAlloc
+}
+
+// growth is an insert that made a map allocate.
+type growth struct {
+	len   int    // the map's length after the insert
+	bytes uint64 // what the insert allocated
+}
+
+// slotsFor works out how many slots a table of map[int64]int64 that took
+// bytes to allocate has: each slot takes 17 bytes (16 for the key and the
+// value, 1 of control), slot counts are powers of two, and the table's
+// header is smaller than its slots.
+func slotsFor(bytes uint64) int {
+	s := 1
+	for uint64(2*s*17) <= bytes {
+		s *= 2
+	}
+	return s
+}
+
+// growths inserts n keys into an empty map and returns the inserts that
+// allocated.
+func growths(n int) []growth {
+	var gs []growth
+	m := map[int64]int64{}
+	for i := range n {
+		before := allocated()
+		m[int64(i)] = int64(i)
+		if b := allocated() - before; b > 0 {
+			gs = append(gs, growth{len(m), b})
+		}
+	}
+	return gs
+}
+
+// Run grows a map, then pokes at what hashing implies.
+func (mapInternalsExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Growth: insert one key at a time and watch for allocations. Up
+	// to 896 entries the map is a single table, which grows by doubling;
+	// after that it splits into several tables, at points that depend on
+	// the random hash seed.
+	e.Step("growth")
+	gs := growths(896)
+	t := table.New("len after insert", "bytes allocated", "slots allocated", "load factor reached")
+	var lens []int
+	var factors []float64
+	prev := 0
+	for _, g := range gs {
+		slots := slotsFor(g.bytes)
+		lf := "-"
+		if prev > 0 {
+			f := float64(g.len-1) / float64(prev)
+			factors = append(factors, f)
+			lf = fmt.Sprintf("%d / %d = %.3f", g.len-1, prev, f)
+		}
+		t.Row(g.len, g.bytes, slots, lf)
+		lens = append(lens, g.len)
+		prev = slots
+	}
+	e.Diagram(t)
+	e.Value("lens", lens, "Lengths at which the map grew")
+	e.Say("The first table holds up to 8 entries; after that every table grows when it is 7/8 full, to twice the size.")
+	check.That(len(gs) > 0 && gs[0].len == 9, "a map of up to 8 entries needs no new table")
+	doubling := len(gs) > 2
+	for i := 2; i < len(gs); i++ {
+		doubling = doubling && gs[i].len-1 == 2*(gs[i-1].len-1)
+	}
+	check.That(doubling, "each growth doubles the number of entries the map can hold")
+	check.That(len(factors) > 0 && !slices.ContainsFunc(factors, func(f float64) bool { return f != 0.875 }), "every table grows at a load factor of 7/8")
+
+	// 2. Hashing: a key is found by hash, then by ==.
+	e.Step("hashing")
+	f := map[float64]int{}
+	f[math.NaN()] = 1
+	f[math.NaN()] = 2
+	_, found := f[math.NaN()]
+	e.Value("len(f)", len(f), "len(f) after storing two values under NaN")
+	e.Value("found", found, "f[NaN] can be found")
+	assert.Equal(check, "every NaN key is a new entry", len(f), 2)
+	check.That(!found, "a NaN key can't be looked up")
+	f[0.0] = 1
+	f[math.Copysign(0, -1)] = 2
+	e.Value("f[0]", f[0], "f[0] after storing under +0 and then -0")
+	assert.Equal(check, "+0 and -0 are == and so the same key", f[0], 2)
+	clear(f)
+	e.Value("len(f)", len(f), "len(f) after clear, the only way to remove NaN keys")
+
+	// 3. No addresses: a map's entries move when it grows.
+	e.Step("address")
+	people := map[string]point{"a": {1, 2}}
+	e.Say("&people[\"a\"] doesn't compile: invalid operation: cannot take address of people[\"a\"] (map index expression of type point)")
+	p := people["a"]
+	p.x = 10
+	people["a"] = p
+	e.Value("people[\"a\"]", people["a"], "people[\"a\"] after copy, change, store back")
+	assert.Equal(check, "copy-modify-store updates the entry", people["a"].x, 10)
+	ptrs := map[string]*point{"a": {1, 2}}
+	ptrs["a"].x = 10
+	e.Value("*ptrs[\"a\"]", *ptrs["a"], "*ptrs[\"a\"] after ptrs[\"a\"].x = 10")
+	e.Warn("A map of pointers allows changes in place, at the cost of one heap allocation per value.")
+
+	// 4. Iteration order: randomized on every range.
+	e.Step("iteration")
+	m := map[string]int{}
+	for i, k := range strings.Fields("a b c d e f g h i j k l m n o p") {
+		m[k] = i
+	}
+	orders := map[string]bool{}
+	for range 20 {
+		var order []string
+		for k := range m {
+			order = append(order, k)
+		}
+		orders[strings.Join(order, "")] = true
+	}
+	e.Varying("orders", len(orders), "Different orders seen in 20 ranges over the same map")
+	check.That(len(orders) > 1, "ranging over the same map visits its keys in different orders")
+	e.Value("sorted", slices.Sorted(maps.Keys(m)), "slices.Sorted(maps.Keys(m)), the same every time")
+	return errors.Join(e.Err(), check.Err())
+}
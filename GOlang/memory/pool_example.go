@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(poolExample{})
+}
+
+// poolExample formats records in a hot loop, once with a fresh buffer
+// per record and once with buffers reused through a sync.Pool, and counts
+// the allocations. Then it shows what a pool does not promise: items can
+// vanish at any collection, and what comes out is whatever went in.
+type poolExample struct{}
+
+func (poolExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/pool_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "sync.Pool: reusing buffers in a hot loop, and what the pool doesn't promise",
+		Tags:          []string{"memory", "sync", "pool", "allocation", "gc"},
+		Prerequisites: []string{"memory/gc_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (poolExample) Explain(step string) string {
+	switch step {
+	case "reuse":
+		return "A fresh bytes.Buffer per record allocates every time. Getting one from the pool and putting it back lets the next record reuse its memory."
+	case "gc":
+		return "A pool is a cache, not storage: each collection moves its items to a victim cache, and the next one drops them."
+	case "pitfalls":
+		return "Get returns whatever was Put, in whatever state it was left: reset it before use, and don't Put back buffers that grew huge."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (poolExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "A buffer is Put into a sync.Pool and two collections run. What does Get return?",
+			Choices: []string{"the same buffer", "a new one from New (or nil without New)"},
+			Answer:  "a new one from New (or nil without New)",
+			Explain: "The first collection moves pooled items to the victim cache, the second frees them. A pool never keeps anything alive for long.",
+		},
+		{
+			Prompt:  "Why is it a waste to Put a []byte into a sync.Pool, rather than a *[]byte or *bytes.Buffer?",
+			Choices: []string{"converting the slice header to any allocates", "slices can't be pooled"},
+			Answer:  "converting the slice header to any allocates",
+			Explain: "Put takes an any; a 24-byte slice header doesn't fit in an interface's data word and gets boxed on the heap, costing the allocation the pool was meant to save.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (poolExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How does sync.Pool scale across goroutines?",
+			Back:  "It keeps a small cache per P (per CPU the scheduler uses), so Get and Put usually touch no shared lock. A Get on one P may miss an item Put on another and steal or call New.",
+		},
+		{
+			Front: "When is sync.Pool worth it?",
+			Back:  "For short-lived, frequently allocated objects of similar size in a hot path (buffers, encoders). Measure first: for rarely used or tiny objects it only adds complexity.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench memory".
+func (poolExample) Experiments() []benchlab.Experiment {
+	return []benchlab.Experiment{
+		{
+			Name: "formatting a record into a buffer",
+			Approaches: []benchlab.Approach{
+				{Name: "new buffer each time", Bench: func(b *testing.B) {
+					b.ReportAllocs()
+					for i := range b.N {
+						benchInt = formatFresh(i)
+					}
+				}},
+				{Name: "sync.Pool", Bench: func(b *testing.B) {
+					b.ReportAllocs()
+					for i := range b.N {
+						benchInt = formatPooled(i)
+					}
+				}},
+			},
+			Guidance: "The fresh buffer allocates (and grows) its memory for every record, " +
+				"and all of it becomes garbage right away. The pooled buffers keep their " +
+				"grown capacity, so in a steady state formatting allocates nothing and the " +
+				"collector has less to do.",
+		},
+		{
+			Name: "the same, from several goroutines at once",
+			Approaches: []benchlab.Approach{
+				{Name: "new buffer each time", Bench: func(b *testing.B) {
+					b.ReportAllocs()
+					b.RunParallel(func(pb *testing.PB) {
+						for i := 0; pb.Next(); i++ {
+							formatFresh(i)
+						}
+					})
+				}},
+				{Name: "sync.Pool", Bench: func(b *testing.B) {
+					b.ReportAllocs()
+					b.RunParallel(func(pb *testing.PB) {
+						for i := 0; pb.Next(); i++ {
+							formatPooled(i)
+						}
+					})
+				}},
+			},
+			Guidance: "Each P has its own part of the pool, so goroutines running in " +
+				"parallel rarely contend for it and the savings hold up. A pool shared " +
+				"through a mutex-guarded free list would serialize them instead.",
+		},
+	}
+}
+
+// buffers pools the buffers formatPooled writes into.
+var buffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// format writes a record, a line of fieldN=... pairs, into buf. The
+// numbers are appended to the buffer's free space, so that the buffer is
+// the only thing that allocates.
+func format(buf *bytes.Buffer, id int) {
+	for f := range 8 {
+		buf.WriteString("field")
+		buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(f), 10))
+		buf.WriteByte('=')
+		buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(id*f), 10))
+		buf.WriteByte(' ')
+	}
+}
+
+// formatFresh formats record id into a new buffer and returns its length.
+func formatFresh(id int) int {
+	buf := new(bytes.Buffer)
+	format(buf, id)
+	return buf.Len()
+}
+
+// maxPooled is the biggest buffer formatPooled puts back. Pooling a few
+// huge buffers would keep their memory alive for every later record.
+const maxPooled = 64 << 10
+
+// formatPooled formats record id into a buffer from the pool and returns
+// its length.
+func formatPooled(id int) int {
+	buf := buffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	format(buf, id)
+	n := buf.Len()
+	if buf.Cap() <= maxPooled {
+		buffers.Put(buf)
+	}
+	return n
+}
+
+// Run counts allocations with and without the pool, and checks what the
+// pool keeps.
+func (poolExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Reuse: the pool saves the per-record allocations.
+	e.Step("reuse")
+	allocs := func(f func()) float64 { return testing.AllocsPerRun(100, f) }
+	fresh := allocs(func() { benchInt = formatFresh(7) })
+	pooled := allocs(func() { benchInt = formatPooled(7) })
+	e.Varying("fresh", fresh, "Allocations per record with a new buffer")
+	e.Varying("pooled", pooled, "Allocations per record with sync.Pool")
+	check.That(fresh >= 1, "a new buffer per record allocates")
+	check.That(pooled < fresh/2, "the pool saves most of the allocations")
+	e.Say("Run \"concepts bench memory\" for the timings and bytes/op, serially and in parallel.")
+
+	// 2. Collections empty the pool.
+	e.Step("gc")
+	p := sync.Pool{}
+	item := new(bytes.Buffer)
+	p.Put(item)
+	runtime.GC()
+	runtime.GC()
+	got := p.Get()
+	e.Value("got", got, "Get after Put and two collections (the pool has no New)")
+	check.That(got == nil, "two collections drop everything the pool held")
+	p.Put(item)
+	runtime.GC()
+	e.Varying("survived", p.Get() == item, "The item survived one collection, in the victim cache")
+	e.Warn("Never keep anything in a pool that has to be there later, like open connections you count on.")
+
+	// 3. Pitfalls: a pooled item keeps its old contents.
+	e.Step("pitfalls")
+	dirty := sync.Pool{New: func() any { return new(bytes.Buffer) }}
+	buf := dirty.Get().(*bytes.Buffer)
+	buf.WriteString("secret")
+	dirty.Put(buf)
+	again := dirty.Get().(*bytes.Buffer)
+	e.Varying("again", again.String(), "Contents of the next buffer Get returns, without Reset")
+	again.Reset()
+	assert.Equal(check, "after Reset the buffer is empty", again.Len(), 0)
+	e.Say("formatPooled calls Reset first, and doesn't put back buffers over %d KB.", maxPooled>>10)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/memviz"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(sliceAliasExample{})
+}
+
+// sliceAliasExample builds two paths from one shared prefix with append,
+// and the second one overwrites the first. Capping the prefix with a full
+// slice expression, or copying it, makes every path its own; shares
+// checks which of them still alias the prefix's backing array.
+type sliceAliasExample struct{}
+
+func (sliceAliasExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/slicealias_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "the append aliasing bug: one slice's append clobbers another's, and two fixes",
+		Tags:          []string{"memory", "slices", "append", "aliasing", "gotchas"},
+		Prerequisites: []string{"memory/sliceheader_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (sliceAliasExample) Explain(step string) string {
+	switch step {
+	case "clobber":
+		return "The prefix has room for more elements, so both appends write into its backing array, at the same index."
+	case "full-slice":
+		return "prefix[:len:len] is the same elements with no room left: append has to allocate, and each path gets an array of its own."
+	case "copy":
+		return "Copying the prefix into a new slice, with room for the extra element, also gives each path its own array, whatever the prefix's capacity."
+	case "backtracking":
+		return "A recursive walk that appends to a path and keeps the result is the same bug in disguise: every kept path is a view of the one buffer."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (sliceAliasExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "prefix has len 2 and cap 4. a := append(prefix, \"x\"); b := append(prefix, \"y\"). What is a[2]?",
+			Choices: []string{"x", "y"},
+			Answer:  "y",
+			Explain: "Both appends fit in prefix's capacity, so both write element 2 of prefix's array. a and b are views of the same memory.",
+		},
+		{
+			Prompt:  "Does append(prefix[:len(prefix):len(prefix)], \"x\") ever write into prefix's backing array?",
+			Choices: []string{"yes, if prefix has spare capacity", "no, never"},
+			Answer:  "no, never",
+			Explain: "The full slice expression leaves no capacity, so append always allocates a new array and copies the prefix into it.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (sliceAliasExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Why can append(base, x) change a slice you got from an earlier append(base, y)?",
+			Back:  "If base has spare capacity, both appends write the same element of base's backing array, and the two results share it.",
+		},
+		{
+			Front: "Two ways to make append(base, x) safe when base is shared",
+			Back:  "Cap it, append(base[:len(base):len(base)], x), or copy it first: make a slice of len(base)+1, copy base in and set the last element.",
+		},
+	}
+}
+
+// Variants let "concepts compare" build the two paths with each join.
+func (sliceAliasExample) Variants() []registry.Variant {
+	variant := func(name string, join func([]string, string) []string) registry.Variant {
+		return registry.Variant{Name: name, Run: func(ctx context.Context, w io.Writer) error {
+			e := event.From(ctx, w)
+			e.Step(name)
+			prefix := append(make([]string, 0, 4), "home", "amand")
+			docs := join(prefix, "docs")
+			music := join(prefix, "music")
+			e.Value("docs", docs, "docs, after music was joined")
+			e.Value("music", music, "music")
+			e.Value("aliased", shares(docs, music), "docs and music share a backing array")
+			return e.Err()
+		}}
+	}
+	return []registry.Variant{
+		variant("shared", joinShared),
+		variant("full-slice", joinCapped),
+		variant("copy", joinCopy),
+	}
+}
+
+// joinShared appends name to prefix as it is: the bug.
+func joinShared(prefix []string, name string) []string {
+	return append(prefix, name)
+}
+
+// joinCapped appends name to prefix with its capacity cut down to its
+// length, so append always reallocates.
+func joinCapped(prefix []string, name string) []string {
+	return append(prefix[:len(prefix):len(prefix)], name)
+}
+
+// joinCopy copies prefix into a new slice with room for name.
+func joinCopy(prefix []string, name string) []string {
+	path := make([]string, len(prefix), len(prefix)+1)
+	copy(path, prefix)
+	return append(path, name)
+}
+
+// shares reports whether a and b have elements in the same backing array,
+// looking at their whole capacity rather than just their length.
+func shares[T any](a, b []T) bool {
+	if cap(a) == 0 || cap(b) == 0 {
+		return false
+	}
+	size := unsafe.Sizeof(*new(T))
+	aStart, bStart := uintptr(unsafe.Pointer(unsafe.SliceData(a))), uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+	aEnd, bEnd := aStart+uintptr(cap(a))*size, bStart+uintptr(cap(b))*size
+	return aStart < bEnd && bStart < aEnd
+}
+
+// dir is a directory tree for the backtracking step.
+type dir struct {
+	name     string
+	children []dir
+}
+
+// leafPaths returns the path to every leaf below d, appending to path on
+// the way down. keep decides what is stored: the path itself, or a copy.
+func leafPaths(d dir, path []string, keep func([]string) []string, out [][]string) [][]string {
+	path = append(path, d.name)
+	if len(d.children) == 0 {
+		return append(out, keep(path))
+	}
+	for _, c := range d.children {
+		out = leafPaths(c, path, keep, out)
+	}
+	return out
+}
+
+// Run joins paths the buggy way and both fixed ways, then finds the same
+// bug in a tree walk.
+func (sliceAliasExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	newPrefix := func() []string { return append(make([]string, 0, 4), "home", "amand") }
+
+	// 1. The bug: two appends to a prefix with spare capacity.
+	e.Step("clobber")
+	prefix := newPrefix()
+	docs := joinShared(prefix, "docs")
+	e.Value("docs", docs, "docs := append(prefix, \"docs\")")
+	music := joinShared(prefix, "music")
+	d := memviz.New()
+	d.Frame("Run").Var("prefix", &prefix).Var("docs", &docs).Var("music", &music)
+	backing(d, "prefix's backing array", prefix)
+	e.Diagram(d)
+	e.Value("docs", docs, "docs, after music := append(prefix, \"music\")")
+	assert.Equal(check, "joining music overwrote docs' last element", docs[2], "music")
+	check.That(shares(docs, music) && shares(docs, prefix), "docs, music and prefix alias one array")
+	e.Warn("Nothing failed: append returned slices that look right, until the next append to the same prefix.")
+
+	// 2. Fix: a full slice expression leaves append no room.
+	e.Step("full-slice")
+	prefix = newPrefix()
+	docs = joinCapped(prefix, "docs")
+	music = joinCapped(prefix, "music")
+	e.Value("docs", docs, "docs := append(prefix[:2:2], \"docs\")")
+	e.Value("music", music, "music := append(prefix[:2:2], \"music\")")
+	assert.Equal(check, "with the capped prefix, docs keeps its element", docs[2], "docs")
+	check.That(!shares(docs, music) && !shares(docs, prefix), "the capped appends each got a new array")
+
+	// 3. Fix: copy the prefix first.
+	e.Step("copy")
+	prefix = newPrefix()
+	docs = joinCopy(prefix, "docs")
+	music = joinCopy(prefix, "music")
+	e.Value("docs", docs, "docs, built on a copy of prefix")
+	e.Value("music", music, "music, built on a copy of prefix")
+	assert.Equal(check, "with a copy, docs keeps its element", docs[2], "docs")
+	check.That(!shares(docs, music) && !shares(docs, prefix), "the copies alias nothing")
+	assert.Equal(check, "prefix itself was never written", fmt.Sprint(prefix, len(prefix)), "[home amand] 2")
+	t := table.New("join", "docs", "music", "docs aliases music")
+	for _, j := range []struct {
+		name string
+		join func([]string, string) []string
+	}{{"append(prefix, x)", joinShared}, {"append(prefix[:2:2], x)", joinCapped}, {"copy, then append", joinCopy}} {
+		prefix := newPrefix()
+		docs, music := j.join(prefix, "docs"), j.join(prefix, "music")
+		t.Row(j.name, docs, music, shares(docs, music))
+	}
+	e.Diagram(t)
+
+	// 4. The same bug in a walk that reuses one buffer for the path.
+	e.Step("backtracking")
+	tree := dir{"src", []dir{{"cmd", []dir{{"main.go", nil}, {"flags.go", nil}}}, {"util.go", nil}}}
+	buf := make([]string, 0, 8) // one buffer, to save allocations
+	kept := leafPaths(tree, buf, func(p []string) []string { return p }, nil)
+	e.Value("kept", kept, "Leaf paths, keeping path itself")
+	copied := leafPaths(tree, buf, slices.Clone[[]string], nil)
+	e.Value("copied", copied, "Leaf paths, keeping slices.Clone(path)")
+	assert.Equal(check, "the kept paths were overwritten by later leaves", fmt.Sprint(kept[0]), "[src util.go flags.go]")
+	assert.Equal(check, "the cloned paths are right", fmt.Sprint(copied), "[[src cmd main.go] [src cmd flags.go] [src util.go]]")
+	check.That(shares(kept[0], kept[2]) && !shares(copied[0], copied[2]), "kept paths alias the buffer, cloned ones don't")
+	e.Say("Run \"concepts compare memory/slicealias_example shared copy\" to see the two joins side by side.")
+	return errors.Join(e.Err(), check.Err())
+}
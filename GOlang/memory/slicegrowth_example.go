@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(sliceGrowthExample{})
+}
+
+// sliceGrowthExample appends to a slice one element at a time and notes
+// every time append had to move it to a bigger array: the capacities it
+// went through, the factor between them and how many reallocations it
+// took. Then it does the same with the capacity reserved up front.
+type sliceGrowthExample struct{}
+
+func (sliceGrowthExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/slicegrowth_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "how append grows a slice's capacity, and what pre-allocating saves",
+		Tags:          []string{"memory", "slices", "append", "allocation", "performance"},
+		Prerequisites: []string{"memory/sliceheader_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (sliceGrowthExample) Explain(step string) string {
+	switch step {
+	case "grow":
+		return "Each time len reaches cap, append allocates a bigger array, copies every element across and leaves the old array for the garbage collector."
+	case "factors":
+		return "Small slices double. From 256 elements on the factor eases off towards 1.25, and the result is rounded up to a size the allocator has."
+	case "preallocate":
+		return "make([]int, 0, n) reserves the whole array at once: the same appends never reallocate."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (sliceGrowthExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Roughly how many times does append reallocate while a slice grows one element at a time from empty to 10000 ints?",
+			Choices: []string{"about 20", "about 100", "10000"},
+			Answer:  "about 20",
+			Explain: "The capacity grows by a factor each time, so the reallocations grow with the logarithm of the length, not the length.",
+		},
+		{
+			Prompt:  "s := make([]int, 0, 100). How many times does appending 100 elements to s allocate?",
+			Choices: []string{"0", "1", "7"},
+			Answer:  "0",
+			Explain: "make allocated the array already; append only fills it in.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (sliceGrowthExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "How does append pick a slice's new capacity?",
+			Back:  "It doubles small slices. From 256 elements on it grows by a smoothly falling factor, towards 1.25 for large slices, and rounds the size up to an allocator size class.",
+		},
+		{
+			Front: "make([]T, 0, n) or make([]T, n)?",
+			Back:  "Both reserve n elements. The first starts empty for append to fill; the second starts with n zero values to assign by index. Appending to the second adds after the zeros.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench memory".
+func (sliceGrowthExample) Experiments() []benchlab.Experiment {
+	const n = 10000
+	return []benchlab.Experiment{{
+		Name: "filling a slice with 10000 ints",
+		Approaches: []benchlab.Approach{
+			{Name: "append, growing", Bench: func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					var s []int
+					for i := range n {
+						s = append(s, i)
+					}
+					benchSlice = s
+				}
+			}},
+			{Name: "make(0, n), append", Bench: func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					s := make([]int, 0, n)
+					for i := range n {
+						s = append(s, i)
+					}
+					benchSlice = s
+				}
+			}},
+			{Name: "make(n), index", Bench: func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					s := make([]int, n)
+					for i := range s {
+						s[i] = i
+					}
+					benchSlice = s
+				}
+			}},
+		},
+		Guidance: "The growing slice allocates about 20 times and copies every element " +
+			"it has at each of them, about three times the final length in total. Reserving " +
+			"the capacity makes it one allocation and no copies. Appending into the " +
+			"reserved slice and assigning by index cost about the same; append is the " +
+			"one that can't run past the end by mistake. Pre-allocate when you know n, " +
+			"or a good upper bound; otherwise growing is cheap enough.",
+	}}
+}
+
+// benchSlice keeps the benchmarks' slices alive so the compiler can't
+// drop the work.
+var benchSlice []int
+
+// realloc is one move of a slice to a bigger array by append.
+type realloc struct {
+	len              int // length of the slice the append made
+	oldCap, newCap   int
+	oldData, newData *int
+}
+
+// factor is how much bigger the new array is than the old one.
+func (g realloc) factor() float64 { return float64(g.newCap) / float64(g.oldCap) }
+
+// appendAll appends 0 to n-1 to s one at a time and returns the result and
+// every reallocation on the way.
+func appendAll(s []int, n int) ([]int, []realloc) {
+	var gs []realloc
+	for i := range n {
+		oldCap, oldData := cap(s), unsafe.SliceData(s)
+		s = append(s, i)
+		if cap(s) != oldCap {
+			gs = append(gs, realloc{len(s), oldCap, cap(s), oldData, unsafe.SliceData(s)})
+		}
+	}
+	return s, gs
+}
+
+// Run grows a slice of 10000 ints, then fills a pre-allocated one.
+func (sliceGrowthExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	const n = 10000
+
+	// 1. Append one element at a time and watch cap.
+	e.Step("grow")
+	var s []int
+	before := allocated()
+	s, gs := appendAll(s, n)
+	grownBytes := allocated() - before
+	caps := make([]int, len(gs))
+	copied := 0
+	for i, g := range gs {
+		caps[i] = g.newCap
+		copied += g.len - 1
+	}
+	e.Value("len(s)", len(s), "Length after the appends")
+	e.Varying("caps", caps, "Capacities append went through")
+	e.Varying("reallocations", len(gs), "Reallocations")
+	e.Varying("copied", copied, "Elements copied from an old array to a new one")
+	e.Varying("bytes", grownBytes, "Bytes allocated, including the abandoned arrays")
+	moved := true
+	for i, g := range gs {
+		moved = moved && g.newData != g.oldData && g.newCap > g.oldCap && (i == 0 || g.oldCap == gs[i-1].newCap)
+	}
+	check.That(moved, "every change of cap is a move to a bigger array")
+	check.That(len(gs) < 40, "the reallocations are few: the capacity grows by a factor, not a step")
+	// With a factor of at least 1.25, the copies add up to at most 5 times
+	// the last old capacity: a constant amount of copying per append.
+	check.That(copied < 5*n, "over all the moves, append copies fewer than 5 elements per element appended")
+
+	// 2. The factor between one capacity and the next.
+	e.Step("factors")
+	var small, large []string
+	doubled, eased := true, true
+	for _, g := range gs[1:] { // the first realloc is from a cap of 0
+		f := fmt.Sprintf("%d→%d ×%.2f", g.oldCap, g.newCap, g.factor())
+		switch {
+		case g.oldCap < 256:
+			small = append(small, f)
+			doubled = doubled && g.factor() >= 2
+		case g.oldCap >= 1024:
+			large = append(large, f)
+			eased = eased && g.factor() < 2
+		}
+	}
+	e.Varying("small", small, "Growth below 256 elements")
+	e.Varying("large", large, "Growth from 1024 elements on")
+	check.That(doubled, "below 256 elements, the capacity at least doubles")
+	check.That(eased, "from 1024 elements on, it grows by less than double")
+	e.Say("The exact capacities depend on the Go version and on the element size, through the allocator's size classes.")
+
+	// 3. Reserve the capacity up front.
+	e.Step("preallocate")
+	before = allocated()
+	p, pgs := appendAll(make([]int, 0, n), n)
+	preBytes := allocated() - before
+	e.Value("reallocations", len(pgs), "Reallocations with make([]int, 0, n)")
+	e.Value("cap(p)", cap(p), "cap(p)")
+	e.Varying("bytes", preBytes, "Bytes allocated")
+	assert.Equal(check, "the reserved slice never reallocates", len(pgs), 0)
+	assert.Equal(check, "both ways hold the same elements", fmt.Sprint(p[:3], p[n-1]), fmt.Sprint(s[:3], s[n-1]))
+	check.That(preBytes < grownBytes, "reserving allocates less than growing")
+	e.Say("Run \"concepts bench memory\" to time both, and the index-assignment way.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/memviz"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(sliceHeaderExample{})
+}
+
+// sliceHeaderExample opens up a slice: a small header of data pointer, len
+// and cap, pointing into a backing array that other slices may share. The
+// diagrams draw every element of the backing array as a row, so the arrows
+// show which slices look at the same memory.
+type sliceHeaderExample struct{}
+
+func (sliceHeaderExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/sliceheader_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "slice headers: shared backing arrays, sub-slicing and when append reallocates",
+		Tags:          []string{"memory", "slices", "append", "unsafe"},
+		Prerequisites: []string{"pointers/newmake_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (sliceHeaderExample) Explain(step string) string {
+	switch step {
+	case "header":
+		return "A slice value is three words: a pointer to its first element, its length and its capacity. The elements live elsewhere, in the backing array."
+	case "subslice":
+		return "Slicing makes a new header pointing into the same array. Nothing is copied, so a write through one slice shows through the others."
+	case "append-in-place":
+		return "While there is capacity left, append writes into the shared array, possibly over an element another slice is using."
+	case "append-grow":
+		return "When the capacity runs out, append allocates a bigger array, copies the elements across and returns a header pointing at the copy."
+	case "full-slice":
+		return "s[low:high:max] also caps the new slice, so the first append that would reach past max reallocates instead of writing into the shared array."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (sliceHeaderExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "s is []int{1, 2, 3, 4, 5} and a := s[1:3]. What are len(a) and cap(a)?",
+			Choices: []string{"2 and 2", "2 and 4", "3 and 5"},
+			Answer:  "2 and 4",
+			Explain: "len is high-low = 2. cap runs from a's first element to the end of the backing array: 5-1 = 4.",
+		},
+		{
+			Prompt:  "base has len 3 and cap 5. x := append(base, 4); y := append(base, 5). What is x[3]?",
+			Choices: []string{"4", "5"},
+			Answer:  "5",
+			Explain: "Both appends fit in base's capacity, so both write the same element of the same array. The second overwrites the first.",
+		},
+		{
+			Prompt:  "Which unsafe function returns a slice's data pointer?",
+			Choices: []string{"unsafe.Pointer", "unsafe.SliceData", "unsafe.Slice"},
+			Answer:  "unsafe.SliceData",
+			Explain: "unsafe.SliceData(s) is &s[:1][0] for a slice with capacity; unsafe.Slice goes the other way, from a pointer and a length to a slice.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (sliceHeaderExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does a slice value contain?",
+			Back:  "A header of three words: a pointer to the first element, the length and the capacity. Copying a slice copies the header, not the elements.",
+		},
+		{
+			Front: "When does append return a slice sharing the original's backing array?",
+			Back:  "When the result fits in the original's capacity. Otherwise it allocates a new array and copies, and the two no longer share anything.",
+		},
+		{
+			Front: "What does the third index in s[1:3:3] do?",
+			Back:  "It sets the capacity (max - low = 2), so appending to the result reallocates instead of writing into s's backing array.",
+		},
+	}
+}
+
+// backing adds a frame showing every element of s's backing array from
+// s's first element to its capacity, for the slice headers to point at.
+func backing[T any](d *memviz.Diagram, name string, s []T) {
+	f := d.Frame(name)
+	all := s[:cap(s)]
+	for i := range all {
+		f.Var(fmt.Sprintf("[%d]", i), &all[i])
+	}
+}
+
+// Run slices, writes and appends, watching the headers and the arrays.
+func (sliceHeaderExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The header: data pointer, len, cap.
+	e.Step("header")
+	s := []int{1, 2, 3, 4, 5}
+	e.Address("unsafe.SliceData(s)", unsafe.SliceData(s), "Data pointer, from unsafe.SliceData")
+	e.Address("&s[0]", &s[0], "Address of the first element")
+	e.Value("len(s)", len(s), "len(s)")
+	e.Value("cap(s)", cap(s), "cap(s)")
+	e.Value("unsafe.Sizeof(s)", unsafe.Sizeof(s), "Size of the slice value itself, in bytes (three words)")
+	check.That(unsafe.SliceData(s) == &s[0], "the data pointer is the address of the first element")
+	assert.Equal(check, "reflect agrees on the data pointer", reflect.ValueOf(s).Pointer(), uintptr(unsafe.Pointer(&s[0])))
+	assert.Equal(check, "a slice header is three words", unsafe.Sizeof(s), 3*unsafe.Sizeof(uintptr(0)))
+
+	// 2. Sub-slices: new headers, same array.
+	e.Step("subslice")
+	a := s[1:3]
+	b := s[2:5]
+	d := memviz.New()
+	d.Frame("Run").Var("s", &s).Var("a", &a).Var("b", &b)
+	backing(d, "backing array", s)
+	e.Diagram(d)
+	a[1] = 99
+	e.Value("s", s, "s after a[1] = 99")
+	e.Value("b", b, "b after a[1] = 99")
+	assert.Equal(check, "a[1], s[2] and b[0] are one element", fmt.Sprint(s[2], b[0]), "99 99")
+	assert.Equal(check, "a's capacity runs to the end of s's array", cap(a), 4)
+
+	// 3. Appending within capacity writes into the shared array.
+	e.Step("append-in-place")
+	base := append(make([]int, 0, 5), 1, 2, 3)
+	x := append(base, 4)
+	y := append(base, 5)
+	d = memviz.New()
+	d.Frame("Run").Var("base", &base).Var("x", &x).Var("y", &y)
+	backing(d, "backing array", base)
+	e.Diagram(d)
+	e.Value("x", x, "x := append(base, 4), after y := append(base, 5)")
+	assert.Equal(check, "the second append overwrote the first one's element", x[3], 5)
+	e.Warn("Appending to a slice you don't own can overwrite elements someone else appended. Take a copy, or cap it with a full slice expression.")
+
+	// 4. Appending beyond capacity reallocates.
+	e.Step("append-grow")
+	full := []int{1, 2, 3}
+	grown := append(full, 4)
+	d = memviz.New()
+	d.Frame("Run").Var("full", &full).Var("grown", &grown)
+	backing(d, "old array", full)
+	backing(d, "new array", grown)
+	e.Diagram(d)
+	e.Varying("cap(grown)", cap(grown), "cap(grown), picked by the runtime's growth policy")
+	grown[0] = 100
+	e.Value("full", full, "full after grown[0] = 100")
+	check.That(unsafe.SliceData(grown) != unsafe.SliceData(full), "append beyond cap returns a slice with a new data pointer")
+	assert.Equal(check, "the old array is untouched", full[0], 1)
+
+	// 5. A full slice expression caps the sub-slice, forcing the copy.
+	e.Step("full-slice")
+	t := []int{1, 2, 3, 4, 5}
+	c := t[1:3:3]
+	c = append(c, 42)
+	e.Value("t", t, "t after c := t[1:3:3]; c = append(c, 42)")
+	e.Value("cap(t[1:3:3])", cap(t[1:3:3]), "cap of the capped sub-slice")
+	assert.Equal(check, "the capped append left t alone", t[3], 4)
+	check.That(unsafe.SliceData(c) != &t[1], "the capped sub-slice reallocated on append")
+	return errors.Join(e.Err(), check.Err())
+}
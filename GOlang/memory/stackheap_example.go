@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(stackHeapExample{})
+}
+
+// stackHeapExample goes through the three everyday reasons a value ends up
+// on the heap: its address is returned, it is converted to an interface
+// that outlives the call, or a closure that outlives the call captures it.
+// Each reason comes with a twin that looks alike but keeps its value on the
+// stack. The lines where the two differ end in an "escape:" comment saying
+// what the compiler decides, and
+//
+//	concepts escape memory/stackheap_example
+//
+// checks those comments against the compiler's real decisions.
+type stackHeapExample struct{}
+
+func (stackHeapExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/stackheap_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "what moves a value to the heap: returned pointers, interface conversions and closures",
+		Tags:          []string{"memory", "escape-analysis", "stack", "heap", "interfaces", "closures"},
+		Prerequisites: []string{"memory/escape_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (stackHeapExample) Explain(step string) string {
+	switch step {
+	case "pointer":
+		return "Returning a point copies it into the caller's frame. Returning &point makes the caller hold an address into a frame that is about to disappear, so the point goes on the heap."
+	case "interface":
+		return "An interface value holds a pointer to its data. If the interface outlives the call, so must the data, which then goes on the heap."
+	case "closure":
+		return "A closure refers to the variables it captures. One that is only called in place can leave them on the stack; one that is returned takes them along."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (stackHeapExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "areaOfShape converts a point to the interface shape and calls area on it. Does the point move to the heap?",
+			Choices: []string{"yes, every interface conversion allocates", "no, the interface doesn't outlive the call"},
+			Answer:  "no, the interface doesn't outlive the call",
+			Explain: "The compiler sees the concrete type and that nothing keeps the interface, so the point stays on the stack. keepShape, which stores it in a package variable, is what makes it escape.",
+		},
+		{
+			Prompt:  "counter returns a closure that increments n. Where does n live?",
+			Choices: []string{"in counter's stack frame", "on the heap"},
+			Answer:  "on the heap",
+			Explain: "The closure is called long after counter has returned, so the compiler reports \"moved to heap: n\" for it.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (stackHeapExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Three common reasons a value escapes to the heap?",
+			Back:  "Its address is returned or stored somewhere long-lived; it is converted to an interface that outlives the call (including most fmt calls); a closure that outlives the call captures it.",
+		},
+		{
+			Front: "Does returning a struct by value allocate?",
+			Back:  "No: it is copied into the caller's frame. Only returning its address (or otherwise letting it outlive the function) moves it to the heap.",
+		},
+	}
+}
+
+// point is a small value, copied by every assignment.
+type point struct{ x, y int }
+
+// shape is anything with an area.
+type shape interface{ area() int }
+
+func (p point) area() int { return p.x * p.y }
+
+// pointValue returns a point by value: the caller gets a copy, and the
+// original disappears with pointValue's frame.
+//
+//go:noinline
+func pointValue() point {
+	return point{3, 4} // escape: stack
+}
+
+// pointPointer returns the address of a point, so the point has to outlive
+// pointPointer's frame.
+//
+//go:noinline
+func pointPointer() *point {
+	return &point{3, 4} // escape: heap
+}
+
+// areaOfShape puts a point in an interface that nobody keeps.
+//
+//go:noinline
+func areaOfShape(x, y int) int {
+	var s shape = point{x, y} // escape: stack
+	return s.area()
+}
+
+// kept holds the shape keepShape stores.
+var kept shape
+
+// keepShape stores a point in an interface that outlives the call.
+//
+//go:noinline
+func keepShape(x, y int) {
+	kept = point{x, y} // escape: heap
+}
+
+// countInPlace captures n in a closure that it only ever calls itself.
+//
+//go:noinline
+func countInPlace() int {
+	n := 0
+	inc := func() { n++ } // escape: stack
+	inc()
+	inc()
+	return n
+}
+
+// counter returns a closure capturing n, so n lives on after counter
+// returns and every call of the closure sees the same n.
+//
+//go:noinline
+func counter() func() int {
+	n := 0              // escape: heap
+	return func() int { // escape: heap
+		n++
+		return n
+	}
+}
+
+// Run calls each pair and counts its heap allocations, the runtime's side
+// of the story that "concepts escape" tells from the compiler's.
+func (stackHeapExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	allocs := func(f func()) int { return int(testing.AllocsPerRun(10, f)) }
+	var sink *point
+
+	// 1. Returning a value vs returning a pointer.
+	e.Step("pointer")
+	e.Value("pointValue()", pointValue(), "pointValue(), a copy")
+	e.Value("*pointPointer()", *pointPointer(), "*pointPointer(), through the pointer")
+	assert.Equal(check, "returning a point by value doesn't allocate", allocs(func() { pointValue() }), 0)
+	check.That(allocs(func() { sink = pointPointer() }) >= 1, "returning &point allocates it on the heap")
+	_ = sink
+
+	// 2. An interface that is dropped vs one that is kept.
+	e.Step("interface")
+	e.Value("areaOfShape(3, 4)", areaOfShape(3, 4), "areaOfShape(3, 4), interface dropped after the call")
+	keepShape(3, 4)
+	e.Value("kept.area()", kept.area(), "kept.area(), interface stored in a package variable")
+	assert.Equal(check, "an interface that doesn't outlive the call doesn't allocate", allocs(func() { areaOfShape(3, 4) }), 0)
+	check.That(allocs(func() { keepShape(3, 4) }) >= 1, "an interface that outlives the call moves its point to the heap")
+	e.Warn("fmt.Println(x) and friends take ...any and keep nothing, yet x usually escapes: the compiler can't see through their reflection.")
+
+	// 3. A closure called in place vs a closure returned.
+	e.Step("closure")
+	e.Value("countInPlace()", countInPlace(), "countInPlace()")
+	next := counter()
+	next()
+	e.Value("next()", next(), "The second call of counter's closure")
+	assert.Equal(check, "the returned closure keeps its own n between calls", next(), 3)
+	assert.Equal(check, "a closure called in place doesn't allocate", allocs(func() { countInPlace() }), 0)
+	check.That(allocs(func() { counter() }) >= 1, "a returned closure and what it captures go on the heap")
+
+	e.Say("Run \"concepts escape memory/stackheap_example\" to check every \"escape:\" comment against the compiler.")
+	return errors.Join(e.Err(), check.Err())
+}
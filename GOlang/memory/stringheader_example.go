@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(stringHeaderExample{})
+}
+
+// stringHeaderExample opens up a string the way sliceheader_example opens
+// up a slice: a header of data pointer and length, pointing at bytes that
+// never change. Everything else follows from that: substrings can share
+// the bytes, conversions to []byte must copy them, and every + builds a
+// new string.
+type stringHeaderExample struct{}
+
+func (stringHeaderExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/stringheader_example",
+		Topic:         "memory",
+		Level:         registry.Intermediate,
+		Description:   "string headers and immutability: shared substrings, copying conversions, building with + vs strings.Builder",
+		Tags:          []string{"memory", "strings", "immutability", "allocation", "unsafe"},
+		Prerequisites: []string{"memory/sliceheader_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (stringHeaderExample) Explain(step string) string {
+	switch step {
+	case "header":
+		return "A string value is two words: a pointer to its bytes and their count. There is no capacity, because a string never grows."
+	case "bytes":
+		return "A []byte can be changed and a string can't, so []byte(s) has to copy the bytes; string(b) copies them back."
+	case "substring":
+		return "Since nobody can change a string's bytes, a substring can just point into them. No copy, no allocation."
+	case "concat":
+		return "s += \"go\" can't extend s in place: it allocates a new string and copies both halves, on every iteration."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (stringHeaderExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "b := []byte(s); b[0] = 'X'. What happens to s?",
+			Choices: []string{"its first byte becomes 'X'", "nothing, b is a copy"},
+			Answer:  "nothing, b is a copy",
+			Explain: "Strings are immutable, so the conversion copies the bytes into a new array that b owns.",
+		},
+		{
+			Prompt:  "How many allocations does a loop of 100 s += \"go\" make?",
+			Choices: []string{"1", "about 100", "about 7, like append"},
+			Answer:  "about 100",
+			Explain: "Each + makes a new string of the combined length and copies into it. strings.Builder keeps one growing buffer instead.",
+		},
+		{
+			Prompt:  "A 1 MB string is read and only its first 10 bytes are kept as s[:10]. How much memory stays alive?",
+			Choices: []string{"10 bytes", "1 MB"},
+			Answer:  "1 MB",
+			Explain: "The substring points into the big string's bytes and keeps all of them reachable. strings.Clone(s[:10]) copies just the 10.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (stringHeaderExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does a string value contain?",
+			Back:  "A pointer to its bytes and their length: 16 bytes on 64-bit platforms, whatever the string's length.",
+		},
+		{
+			Front: "How do you build a long string from many pieces?",
+			Back:  "With a strings.Builder (Grow it first if you know the size). s += piece in a loop allocates and copies every time.",
+		},
+	}
+}
+
+// Experiments are measured by "concepts bench memory".
+func (stringHeaderExample) Experiments() []benchlab.Experiment {
+	const pieces = 1000
+	return []benchlab.Experiment{{
+		Name: "building a string from 1000 pieces",
+		Approaches: []benchlab.Approach{
+			{Name: "+", Bench: func(b *testing.B) {
+				for range b.N {
+					benchString = concat(pieces)
+				}
+			}},
+			{Name: "strings.Builder", Bench: func(b *testing.B) {
+				for range b.N {
+					benchString = build(pieces)
+				}
+			}},
+			{Name: "bytes.Buffer", Bench: func(b *testing.B) {
+				for range b.N {
+					var buf bytes.Buffer
+					for range pieces {
+						buf.WriteString("go")
+					}
+					benchString = buf.String()
+				}
+			}},
+		},
+		Guidance: "+ copies everything built so far on every iteration, so its time grows " +
+			"with the square of the number of pieces. strings.Builder and bytes.Buffer " +
+			"append to a growing buffer like append does. The Builder then hands its " +
+			"buffer over as the string, while Buffer.String copies it once more, which " +
+			"is why the Builder is the one to use for strings.",
+	}}
+}
+
+// benchString keeps the benchmarked strings from being optimized away.
+var benchString string
+
+// concat builds a string of n "go"s with +.
+func concat(n int) string {
+	s := ""
+	for range n {
+		s += "go"
+	}
+	return s
+}
+
+// build builds the same string with a strings.Builder.
+func build(n int) string {
+	var b strings.Builder
+	for range n {
+		b.WriteString("go")
+	}
+	return b.String()
+}
+
+// Run takes strings apart and puts them together.
+func (stringHeaderExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+	allocs := func(f func()) int { return int(testing.AllocsPerRun(10, f)) }
+
+	// 1. The header: data pointer and length.
+	e.Step("header")
+	s := strings.Repeat("hello, world ", 4)
+	e.Address("unsafe.StringData(s)", unsafe.StringData(s), "Data pointer, from unsafe.StringData")
+	e.Value("len(s)", len(s), "len(s) in bytes")
+	e.Value("unsafe.Sizeof(s)", unsafe.Sizeof(s), "Size of the string value itself, in bytes (two words)")
+	assert.Equal(check, "a string header is two words", unsafe.Sizeof(s), 2*unsafe.Sizeof(uintptr(0)))
+	e.Say("s[0] = 'H' doesn't compile: cannot assign to s[0] (neither addressable nor a map index expression)")
+
+	// 2. []byte(s) copies, so the string stays as it was.
+	e.Step("bytes")
+	b := []byte(s)
+	e.Address("&b[0]", &b[0], "Address of b's first byte")
+	b[0] = 'H'
+	e.Value("s[:5]", s[:5], "s[:5] after b[0] = 'H'")
+	e.Value("string(b[:5])", string(b[:5]), "string(b[:5])")
+	check.That(&b[0] != unsafe.StringData(s), "[]byte(s) copies the bytes into a new array")
+	assert.Equal(check, "changing the copy leaves s alone", s[0], byte('h'))
+
+	// 3. Substrings share the bytes.
+	e.Step("substring")
+	sub := s[7:12]
+	e.Value("sub", sub, "sub := s[7:12]")
+	e.Address("unsafe.StringData(sub)", unsafe.StringData(sub), "sub's data pointer")
+	check.That(unsafe.StringData(sub) == unsafe.StringData(s[7:]), "a substring points into the original's bytes")
+	assert.Equal(check, "taking a substring doesn't allocate", allocs(func() { benchString = s[7:12] }), 0)
+	e.Warn("A small substring keeps its whole original alive. strings.Clone(sub) copies just the bytes it needs.")
+
+	// 4. + in a loop: a new string every time.
+	e.Step("concat")
+	plus := allocs(func() { benchString = concat(100) })
+	builder := allocs(func() { benchString = build(100) })
+	e.Varying("plus", plus, "Allocations for 100 s += \"go\"")
+	e.Varying("builder", builder, "Allocations for 100 strings.Builder WriteString calls")
+	check.That(plus >= 90, "every + in the loop allocates a new string")
+	check.That(builder < plus/5, "strings.Builder allocates only when its buffer grows")
+	e.Say("Run \"concepts bench memory\" to compare +, strings.Builder and bytes.Buffer.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(unsafeExample{})
+}
+
+// unsafeExample is about the unsafe package, and like the package it is
+// not an invitation: everything it does steps outside Go's type and memory
+// safety, and is only correct because it follows the patterns the unsafe
+// documentation lists as valid. It converts between pointer types, reads a
+// field at its offset, and then shows why a uintptr is not a pointer: the
+// garbage collector frees what it points at, and stack growth moves it.
+type unsafeExample struct{}
+
+func (unsafeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "memory/unsafe_example",
+		Topic:         "memory",
+		Level:         registry.Advanced,
+		Description:   "UNSAFE: unsafe.Pointer conversion rules, fields by offset, and why a uintptr doesn't keep memory alive",
+		Tags:          []string{"memory", "unsafe", "uintptr", "gc"},
+		Prerequisites: []string{"pointers/struct_example", "memory/sliceheader_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (unsafeExample) Explain(step string) string {
+	switch step {
+	case "convert":
+		return "unsafe.Pointer converts to and from any pointer type. Reading memory as another type is only valid when the two types share a layout."
+	case "offset":
+		return "unsafe.Add moves a pointer by a number of bytes. With unsafe.Offsetof that reaches a field, as long as the result stays inside the struct."
+	case "uintptr-gc":
+		return "A uintptr is a number. The garbage collector doesn't treat it as a reference, so the object it came from can be freed while the number is still around."
+	case "uintptr-stack":
+		return "Goroutine stacks grow by being copied elsewhere. Pointers into the stack are updated; a uintptr holding an old address is not."
+	case "rules":
+		return "The unsafe documentation lists the patterns that are valid. Anything else may work today and break with the next compiler or garbage collector."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz memory".
+func (unsafeExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Why is u := uintptr(unsafe.Pointer(p)); ...; q := (*T)(unsafe.Pointer(u)) invalid, when the same conversion in one expression is fine?",
+			Choices: []string{"the conversion is too slow", "between the two statements the object can be freed or moved, and u won't follow", "uintptr is smaller than a pointer"},
+			Answer:  "between the two statements the object can be freed or moved, and u won't follow",
+			Explain: "Only unsafe.Pointer and typed pointers keep an object alive and get updated when it moves. A uintptr kept in a variable is just a stale number.",
+		},
+		{
+			Prompt:  "Which is the safer way to get a pointer to a struct field from the struct's address?",
+			Choices: []string{"unsafe.Add(unsafe.Pointer(&s), unsafe.Offsetof(s.f))", "storing uintptr(unsafe.Pointer(&s)) and adding the offset later"},
+			Answer:  "unsafe.Add(unsafe.Pointer(&s), unsafe.Offsetof(s.f))",
+			Explain: "unsafe.Add never leaves unsafe.Pointer, so the object stays referenced throughout.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (unsafeExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "unsafe.Pointer vs uintptr?",
+			Back:  "unsafe.Pointer is a pointer: it keeps its object alive and is updated if the object moves. uintptr is an integer holding an address, which the runtime ignores.",
+		},
+		{
+			Front: "When is (*T2)(unsafe.Pointer(p)) valid for p of type *T1?",
+			Back:  "When T2 is no larger than T1 and the two share an equivalent memory layout, like math.Float64bits does with float64 and uint64.",
+		},
+	}
+}
+
+// blob is big enough to get an allocation of its own, which a cleanup can
+// then watch.
+type blob struct{ data [128]byte }
+
+// freedAfterGC allocates a blob, lets keep hold on to it however it likes,
+// drops every typed reference and collects garbage. It reports whether the
+// blob was freed.
+func freedAfterGC(keep func(b *blob)) bool {
+	b := &blob{}
+	freed := make(chan struct{})
+	runtime.AddCleanup(b, func(ch chan struct{}) { close(ch) }, freed)
+	keep(b)
+	b = nil
+	return collect(freed, 100*time.Millisecond)
+}
+
+// deep grows the calling goroutine's stack by roughly n KB.
+//
+//go:noinline
+func deep(n int) int {
+	var frame [1024]byte
+	frame[n%len(frame)] = byte(n)
+	if n == 0 {
+		return 0
+	}
+	return deep(n-1) + int(frame[n%len(frame)])
+}
+
+// heldAddress keeps the address unsafe_example holds as a number.
+var heldAddress uintptr
+
+// heldPointer keeps the address unsafe_example holds as a pointer.
+var heldPointer unsafe.Pointer
+
+// Run applies the valid patterns, then breaks the uintptr rule in the two
+// ways it goes wrong, without ever dereferencing the stale address.
+func (unsafeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Pattern 1: *T1 to unsafe.Pointer to *T2, for types with the same
+	// layout.
+	e.Step("convert")
+	e.Warn("This example uses package unsafe. Nothing here is checked by the compiler; it is correct only because it follows the rules in the unsafe documentation.")
+	f := 1.5
+	bits := *(*uint64)(unsafe.Pointer(&f))
+	e.Value("bits", bits, "*(*uint64)(unsafe.Pointer(&f)) for f = 1.5")
+	assert.Equal(check, "reading a float64 as a uint64 gives its IEEE 754 bits", bits, math.Float64bits(f))
+
+	// 2. Pattern 3, in its modern form: pointer arithmetic with unsafe.Add
+	// and unsafe.Offsetof, staying inside the struct.
+	e.Step("offset")
+	pt := point{x: 3, y: 4}
+	py := (*int)(unsafe.Add(unsafe.Pointer(&pt), unsafe.Offsetof(pt.y)))
+	e.Value("unsafe.Offsetof(pt.y)", unsafe.Offsetof(pt.y), "Offset of y in point, in bytes")
+	e.Value("*py", *py, "*py, read through the computed pointer")
+	*py = 40
+	e.Value("pt", pt, "pt after *py = 40")
+	check.That(py == &pt.y, "the computed pointer is &pt.y")
+	assert.Equal(check, "writing through it changes the field", pt.y, 40)
+
+	// 3. A uintptr doesn't keep its object alive.
+	e.Step("uintptr-gc")
+	freedNumber := freedAfterGC(func(b *blob) { heldAddress = uintptr(unsafe.Pointer(b)) })
+	freedPointer := freedAfterGC(func(b *blob) { heldPointer = unsafe.Pointer(b) })
+	e.Value("freedNumber", freedNumber, "Blob freed while its address was held as a uintptr")
+	e.Value("freedPointer", freedPointer, "Blob freed while its address was held as an unsafe.Pointer")
+	check.That(freedNumber, "the collector frees an object only a uintptr refers to")
+	check.That(!freedPointer, "an unsafe.Pointer keeps its object alive")
+	e.Warn("heldAddress now holds the address of freed memory. Converting it back to a pointer would read whatever is allocated there next.")
+	heldPointer = nil
+
+	// 4. A uintptr doesn't follow its object when the stack moves. A new
+	// goroutine starts with a small stack, so deep makes it grow.
+	e.Step("uintptr-stack")
+	moved := make(chan bool)
+	go func() {
+		x := 42
+		before := uintptr(unsafe.Pointer(&x))
+		deep(64)
+		moved <- uintptr(unsafe.Pointer(&x)) != before
+	}()
+	stackMoved := <-moved
+	e.Value("moved", stackMoved, "&x changed while a uintptr kept the old address")
+	check.That(stackMoved, "growing the stack moves its variables, and a saved uintptr goes stale")
+
+	// 5. The valid patterns, from the unsafe package documentation.
+	e.Step("rules")
+	t := table.New("pattern", "in this example")
+	t.Row("(1) *T1 to unsafe.Pointer to *T2, equivalent layouts", "convert")
+	t.Row("(2) unsafe.Pointer to uintptr, to print or compare, never back", "uintptr-stack")
+	t.Row("(3) pointer arithmetic in one expression, or unsafe.Add", "offset")
+	t.Row("(4) uintptr conversion in the argument list of syscall.Syscall", "-")
+	t.Row("(5) reflect.Value.Pointer/UnsafeAddr converted at once", "-")
+	t.Row("(6) building a slice or string with unsafe.Slice / unsafe.String", "-")
+	e.Diagram(t)
+	e.Say("go vet's unsafeptr check flags uintptr-to-pointer conversions outside these patterns.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,292 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(boundaryExample{})
+	isolate.Register("panics/goroutine", panicInGoroutine)
+}
+
+// boundaryExample is a small expression parser that panics as soon as the
+// input is wrong, however deep in the recursion it is, and whose exported
+// Eval turns those panics into errors. Panics it didn't cause through its
+// own fail pass through. The last step shows that the boundary has to be
+// in every goroutine: a panic in one that nobody recovers crashes the
+// program, whatever main recovers.
+type boundaryExample struct{}
+
+func (boundaryExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "panics/boundary_example",
+		Topic:         "panics",
+		Level:         registry.Advanced,
+		Description:   "turning panics into errors at an API boundary, letting bugs through, and panics in goroutines",
+		Tags:          []string{"panics", "recover", "errors", "parsing", "goroutines", "isolate"},
+		Prerequisites: []string{"panics/propagation_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (boundaryExample) Explain(step string) string {
+	switch step {
+	case "errors":
+		return "Inside the parser, fail panics with a *parseError from any depth. Eval's deferred function recovers it and returns it as an ordinary error, so no panic leaves the package."
+	case "bugs":
+		return "Eval only converts its own *parseError. Anything else is a bug, and it is panicked again so that it stays visible."
+	case "goroutine":
+		return "recover only sees panics in its own goroutine. A goroutine that panics with nobody recovering in it crashes the whole program."
+	case "fix":
+		return "Recover inside the goroutine and send the error to whoever waits for the result."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz panics".
+func (boundaryExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "main defers a recover, then starts a goroutine that panics. What happens?",
+			Choices: []string{"main recovers the panic", "the program crashes"},
+			Answer:  "the program crashes",
+			Explain: "A panic only unwinds the goroutine it happens in. Every goroutine needs its own recover.",
+		},
+		{
+			Prompt:  "Should a package's exported functions let its internal panics out?",
+			Choices: []string{"yes", "no, callers expect an error"},
+			Answer:  "no, callers expect an error",
+			Explain: "Panicking internally can simplify deeply recursive code, as in encoding/json and text/template, but the exported API returns errors.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (boundaryExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Why should a recover at an API boundary check the panic's type?",
+			Back:  "So that it only turns its own panics into errors. A nil dereference or an index out of range is a bug, and should panic on.",
+		},
+		{
+			Front: "How do you keep a panicking goroutine from crashing the program?",
+			Back:  "Defer a recover at the top of the goroutine's function, and hand the error to whoever waits for it, over a channel or an errgroup.",
+		},
+	}
+}
+
+// parseError is what the parser panics with when the input is wrong.
+type parseError struct {
+	pos int
+	msg string
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("at offset %d: %s", e.pos, e.msg)
+}
+
+// exprParser evaluates integer expressions with +, -, *, / and
+// parentheses, by recursive descent:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { ("*" | "/") factor }
+//	factor = number | "(" expr ")"
+type exprParser struct {
+	src string
+	pos int
+}
+
+// fail stops parsing, however deep in the recursion the parser is.
+func (p *exprParser) fail(format string, args ...any) {
+	panic(&parseError{p.pos, fmt.Sprintf(format, args...)})
+}
+
+// peek returns the next byte, or 0 at the end of the input.
+func (p *exprParser) peek() byte {
+	if p.pos == len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *exprParser) expr() int {
+	n := p.term()
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			n += p.term()
+		case '-':
+			p.pos++
+			n -= p.term()
+		default:
+			return n
+		}
+	}
+}
+
+func (p *exprParser) term() int {
+	n := p.factor()
+	for {
+		switch p.peek() {
+		case '*':
+			p.pos++
+			n *= p.factor()
+		case '/':
+			p.pos++
+			n /= p.factor() // the bug: nobody checks for zero
+		default:
+			return n
+		}
+	}
+}
+
+func (p *exprParser) factor() int {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		n := p.expr()
+		if p.peek() != ')' {
+			p.fail("missing )")
+		}
+		p.pos++
+		return n
+	case c >= '0' && c <= '9':
+		n := 0
+		for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+			n = n*10 + int(c-'0')
+			p.pos++
+		}
+		return n
+	case c == 0:
+		p.fail("unexpected end of input")
+	default:
+		p.fail("unexpected %q", c)
+	}
+	panic("unreachable")
+}
+
+// Eval is the boundary: it evaluates src and returns the parser's panics
+// as errors. Any other panic is a bug and is sent on its way.
+func Eval(src string) (n int, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		pe, ok := r.(*parseError)
+		if !ok {
+			panic(r)
+		}
+		n, err = 0, pe
+	}()
+	p := &exprParser{src: src}
+	n = p.expr()
+	if p.pos < len(src) {
+		p.fail("unexpected %q", src[p.pos])
+	}
+	return n, nil
+}
+
+// work is what the goroutines of the last two steps run.
+func work() {
+	var counts map[string]int
+	counts["jobs"]++
+}
+
+// panicInGoroutine is the program whose worker goroutine panics. main's
+// deferred recover never gets to run.
+func panicInGoroutine() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("main recovered", r)
+		}
+	}()
+	go work()
+	select {}
+}
+
+// safeGo runs f in a goroutine that recovers, and sends what f panicked
+// with to errs as an error, or nil if it didn't.
+func safeGo(errs chan<- error, f func()) {
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("worker panicked: %v", r)
+			}
+			errs <- err
+		}()
+		f()
+	}()
+}
+
+// Run evaluates good and bad expressions and crashes a goroutine.
+func (boundaryExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The parser's panics come out of Eval as errors.
+	e.Step("errors")
+	inputs := []string{"1+2*3", "(1+2)*3", "2*(3+4", "7+", "8x", ""}
+	t := table.New("input", "n", "err")
+	results := make([]error, len(inputs))
+	for i, in := range inputs {
+		n, err := Eval(in)
+		results[i] = err
+		msg := "nil"
+		if err != nil {
+			msg = err.Error()
+		}
+		t.Row(fmt.Sprintf("%q", in), n, msg)
+	}
+	e.Diagram(t)
+	n, _ := Eval("(1+2)*3")
+	assert.Equal(check, "Eval evaluates what parses", n, 9)
+	var pe *parseError
+	check.That(errors.As(results[2], &pe) && pe.msg == "missing )", "a missing ) deep in the recursion comes back as an error")
+	assert.Equal(check, "the error says where", fmt.Sprint(results[4]), "at offset 1: unexpected 'x'")
+
+	// 2. Bugs still panic.
+	e.Step("bugs")
+	r := assert.Panics(func() { Eval("1/0") })
+	e.Value("recovered", r, "What Eval(\"1/0\") panicked with")
+	var re runtime.Error
+	err, _ := r.(error)
+	check.That(errors.As(err, &re), "dividing by zero is a runtime error Eval doesn't convert")
+	e.Say("A recover that turned this into an error too would hide the parser's missing check behind a message about bad input.")
+
+	// 3. A panic in a goroutine nobody recovers crashes the program.
+	e.Step("goroutine")
+	res, err := uncaught(ctx, e, check, "panics/goroutine")
+	if err != nil {
+		return err
+	}
+	assert.Equal(check, "the runtime prints the worker's panic", res.Fatal, "assignment to entry in nil map")
+	check.That(len(res.Stdout) == 0, "main's deferred recover never ran")
+
+	// 4. The fix: recover in the goroutine, and send the error back.
+	e.Step("fix")
+	errs := make(chan error)
+	safeGo(errs, work)
+	werr := <-errs
+	e.Value("err", werr, "What the worker sent back")
+	assert.Equal(check, "the worker's panic arrives as an error", fmt.Sprint(werr), "worker panicked: assignment to entry in nil map")
+	safeGo(errs, func() {})
+	check.That(<-errs == nil, "a worker that doesn't panic sends nil")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,102 @@
+package panics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+)
+
+// TestMain lets the tests run the programs that die of a panic in a
+// process of their own, as the examples do.
+func TestMain(m *testing.M) {
+	// off
+	code := m.Run()
+	isolate.Cleanup()
+	os.Exit(code)
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src     string
+		want    int
+		wantErr string
+	}{
+		{"1+2*3", 7, ""},
+		{"(1+2)*3", 9, ""},
+		{"20/4-1", 4, ""},
+		{"((7))", 7, ""},
+		{"2*(3+", 0, "at offset 5: unexpected end of input"},
+		{"(1+2", 0, "at offset 4: missing )"},
+		{"1+x", 0, "at offset 2: unexpected 'x'"},
+		{"12)", 0, "at offset 2: unexpected ')'"},
+	}
+	for _, tt := range tests {
+		n, err := Eval(tt.src)
+		var pe *parseError
+		switch {
+		case tt.wantErr == "" && (err != nil || n != tt.want):
+			t.Errorf("Eval(%q) = %d, %v; want %d", tt.src, n, err, tt.want)
+		case tt.wantErr != "" && (!errors.As(err, &pe) || err.Error() != tt.wantErr || n != 0):
+			t.Errorf("Eval(%q) = %d, %v; want a parseError %q", tt.src, n, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEvalRepanicsBugs(t *testing.T) {
+	r := assert.Panics(func() { Eval("1/0") })
+	rerr, ok := r.(runtime.Error)
+	if !ok || !strings.Contains(rerr.Error(), "divide by zero") {
+		t.Errorf("Eval(\"1/0\") panicked with %v, want the runtime's divide by zero", r)
+	}
+}
+
+func TestSafeGo(t *testing.T) {
+	errs := make(chan error)
+	safeGo(errs, func() {})
+	if err := <-errs; err != nil {
+		t.Errorf("a worker that returns: %v, want nil", err)
+	}
+	safeGo(errs, work)
+	err := <-errs
+	if err == nil || !strings.Contains(err.Error(), "assignment to entry in nil map") {
+		t.Errorf("a worker that panics: %v, want its panic as an error", err)
+	}
+}
+
+func TestUncaught(t *testing.T) {
+	tests := []struct {
+		program string
+		fatal   string
+	}{
+		{"panics/goroutine", "assignment to entry in nil map"},
+		{"panics/uncaught", "inner failed"},
+		{"panics/repanic", "inner failed [recovered, repanicked]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.program, func(t *testing.T) {
+			var out bytes.Buffer
+			check := assert.New()
+			res, err := uncaught(context.Background(), event.From(context.Background(), &out), check, tt.program)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.ExitCode == 0 || res.Fatal != tt.fatal {
+				t.Errorf("the child exited with status %d and panic %q, want a failure and %q", res.ExitCode, res.Fatal, tt.fatal)
+			}
+			if err := check.Err(); err != nil {
+				t.Error(err)
+			}
+			if want := "panic: " + tt.fatal; !strings.Contains(out.String(), want) {
+				t.Errorf("uncaught printed %q, want the runtime's %q", out.String(), want)
+			}
+		})
+	}
+}
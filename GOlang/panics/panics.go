@@ -0,0 +1,69 @@
+// Package panics contains examples about panic and recover: how a panic
+// unwinds the stack running deferred calls, where recover can stop it,
+// and how a package turns its panics into errors at its API.
+//
+// A panic nobody recovers ends the program, so those cases run in a
+// process of their own (see the isolate package), and the examples read
+// what the runtime printed before it exited.
+package panics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/isolate"
+)
+
+// crashTimeout is how long a panicking program gets to exit before it is
+// killed.
+const crashTimeout = 10 * time.Second
+
+// uncaught runs the program called name in its own process, which is
+// expected to die of a panic nobody recovered, and records the panic
+// message and the line the panicking goroutine was on.
+func uncaught(ctx context.Context, e *event.Emitter, check *assert.Checker, name string) (isolate.Result, error) {
+	res, err := isolate.Run(ctx, name, crashTimeout)
+	if err != nil {
+		return res, err
+	}
+	if res.TimedOut {
+		return res, errors.New(name + " hung instead of panicking")
+	}
+	e.Warn("The program died: panic: %s", res.Fatal)
+	assert.Equal(check, "an unrecovered panic exits with status 2", res.ExitCode, 2)
+	if len(res.Goroutines) > 0 {
+		g := res.Goroutines[0]
+		e.Say("The trace starts with the goroutine that panicked, in %s:", filepath.Base(g.Func))
+		if text, ok := sourceLine(g.File, g.Line); ok {
+			e.Say("    %d  %s", g.Line, text)
+		}
+	}
+	return res, nil
+}
+
+// sourceLine returns line n of file, if the source is around.
+func sourceLine(file string, n int) (string, bool) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", false
+	}
+	lines := strings.Split(string(data), "\n")
+	if n < 1 || n > len(lines) {
+		return "", false
+	}
+	return strings.TrimSpace(lines[n-1]), true
+}
+
+// trail records the order things happen in.
+type trail []string
+
+func (t *trail) add(format string, args ...any) {
+	*t = append(*t, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,225 @@
+package panics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(propagationExample{})
+	isolate.Register("panics/uncaught", panicUncaught)
+	isolate.Register("panics/repanic", panicTwice)
+}
+
+// propagationExample panics three calls deep and follows the panic up the
+// stack: which deferred calls run, which calls to recover stop it and
+// which don't, what re-panicking does, and, in a process of its own, what
+// happens when nobody recovers at all.
+type propagationExample struct{}
+
+func (propagationExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "panics/propagation_example",
+		Topic:         "panics",
+		Level:         registry.Intermediate,
+		Description:   "panic and recover: unwinding through frames, where recover works, re-panicking and crashing",
+		Tags:          []string{"panics", "recover", "defer", "isolate"},
+		Prerequisites: []string{"functions/defer_example"},
+		NeedsProcess:  true,
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (propagationExample) Explain(step string) string {
+	switch step {
+	case "unwind":
+		return "A panic stops the function where it happens and returns from every caller in turn, running each one's deferred calls, until a deferred call recovers."
+	case "recover":
+		return "recover only stops a panic when a deferred function calls it directly. Anywhere else, including a helper the deferred function calls, it returns nil."
+	case "repanic":
+		return "A deferred function that recovers a value it can't handle can panic with it again, and the panic carries on up the stack."
+	case "uncaught":
+		return "When the panic runs out of callers, the program prints the value and every goroutine's stack and exits with status 2. Deferred calls still run on the way."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz panics".
+func (propagationExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Does defer recover() stop a panic?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "recover is then the deferred function itself, not called by one, so it returns nil and the panic goes on.",
+		},
+		{
+			Prompt:  "f defers a call and then calls g, which panics. Does f's deferred call run?",
+			Choices: []string{"yes, even if nobody recovers", "only if something recovers", "no"},
+			Answer:  "yes, even if nobody recovers",
+			Explain: "The panic returns from f like any return would, running its deferred calls, before the program would crash.",
+		},
+		{
+			Prompt:  "What does recover() return when there is no panic?",
+			Choices: []string{"nil", "an error", "it panics"},
+			Answer:  "nil",
+			Explain: "Which is why deferred functions check r != nil before treating it as a panic.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (propagationExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Where does recover work?",
+			Back:  "Called directly by a deferred function, while the goroutine is panicking. It returns the panic value and the function that deferred the call returns normally.",
+		},
+		{
+			Front: "Why re-panic instead of recovering everything?",
+			Back:  "A recover that swallows every panic hides bugs. Recover what you know how to handle, and panic(r) again with the rest.",
+		},
+	}
+}
+
+// outer calls middle, which calls inner, which panics. Each one defers a
+// call that records the unwinding, and outer recovers.
+func outer(t *trail) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.add("outer recovered %v", r)
+		}
+	}()
+	t.add("outer calls middle")
+	middle(t)
+	t.add("outer after middle")
+}
+
+func middle(t *trail) {
+	defer t.add("middle's defer")
+	t.add("middle calls inner")
+	inner(t)
+	t.add("middle after inner")
+}
+
+func inner(t *trail) {
+	defer t.add("inner's defer")
+	t.add("inner panics")
+	panic("inner failed")
+}
+
+// helperRecover calls recover for its caller, which doesn't work.
+func helperRecover() any {
+	return recover()
+}
+
+// viaHelper panics and recovers through helperRecover.
+func viaHelper() (r any) {
+	defer func() { r = helperRecover() }()
+	panic("via a helper")
+}
+
+// deferredRecover panics and defers recover itself.
+func deferredRecover() {
+	defer recover()
+	panic("defer recover()")
+}
+
+// errRetry is a panic value handle knows what to do with.
+var errRetry = errors.New("retry")
+
+// handle runs f and recovers errRetry, the one panic it understands. It
+// panics again with anything else.
+func handle(t *trail, f func()) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if r != errRetry {
+			t.add("handle re-panics %v", r)
+			panic(r)
+		}
+		t.add("handle recovered %v", r)
+	}()
+	f()
+}
+
+// panicUncaught is the program nobody recovers in: its deferred call
+// still prints before the program dies.
+func panicUncaught() {
+	defer fmt.Println("main's deferred call ran")
+	var t trail
+	middle(&t)
+}
+
+// panicTwice re-panics out of handle, with nobody above it to recover.
+func panicTwice() {
+	var t trail
+	handle(&t, func() { panic(errors.New("inner failed")) })
+}
+
+// Run panics, unwinds, recovers, re-panics and crashes.
+func (propagationExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. The panic unwinds through inner, middle and outer.
+	e.Step("unwind")
+	var t trail
+	outer(&t)
+	e.Say("%s", strings.Join(t, "\n"))
+	assert.Equal(check, "the deferred calls run innermost first, and the code after each call doesn't run", fmt.Sprint(t),
+		"[outer calls middle middle calls inner inner panics inner's defer middle's defer outer recovered inner failed]")
+	e.Say("outer returned normally: the panic stopped at its deferred recover.")
+
+	// 2. Where recover works, and where it returns nil.
+	e.Step("recover")
+	e.Value("recover()", recover(), "recover() with no panic going on")
+	var fromHelper any
+	r := assert.Panics(func() { fromHelper = viaHelper() })
+	e.Value("fromHelper", fromHelper, "What helperRecover got, called by the deferred function")
+	e.Value("r", r, "What caught the panic instead, further up")
+	check.That(fromHelper == nil && r == "via a helper", "recover in a helper of the deferred function doesn't stop the panic")
+	r = assert.Panics(deferredRecover)
+	e.Value("r", r, "What got past defer recover()")
+	assert.Equal(check, "defer recover() doesn't stop the panic either", r, any("defer recover()"))
+	check.That(assert.Panics(func() { outer(&trail{}) }) == nil, "a deferred closure that calls recover itself does")
+
+	// 3. Re-panicking what can't be handled.
+	e.Step("repanic")
+	t = nil
+	handle(&t, func() { panic(errRetry) })
+	bug := errors.New("inner failed")
+	r = assert.Panics(func() { handle(&t, func() { panic(bug) }) })
+	e.Say("%s", strings.Join(t, "\n"))
+	e.Value("r", r, "What got past handle")
+	check.That(r == bug, "the re-panic carries the same value on up")
+
+	// 4. Nobody recovers: the program dies.
+	e.Step("uncaught")
+	res, err := uncaught(ctx, e, check, "panics/uncaught")
+	if err != nil {
+		return err
+	}
+	assert.Equal(check, "the runtime prints the panic value", res.Fatal, "inner failed")
+	e.Value("stdout", strings.TrimSpace(string(res.Stdout)), "What the program printed before it died")
+	check.That(strings.Contains(string(res.Stdout), "main's deferred call ran"), "deferred calls run even when nobody recovers")
+	res, err = uncaught(ctx, e, check, "panics/repanic")
+	if err != nil {
+		return err
+	}
+	assert.Equal(check, "a re-panic with the same value is marked as one", res.Fatal, "inner failed [recovered, repanicked]")
+	e.Say("The trace points at the re-panic, in handle's deferred function; the original panic's frames are further down.")
+	return errors.Join(e.Err(), check.Err())
+}
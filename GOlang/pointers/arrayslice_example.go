@@ -0,0 +1,171 @@
+package pointers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(arraySliceExample{})
+}
+
+// arraySliceExample passes an array and a slice to functions that change
+// them, printing addresses the way function_example does for
+// incrementValueNoPtr. An array is a value like an int, and the function
+// gets a copy of every element; a slice is a small header, and the
+// function gets a copy of the header that still points at the caller's
+// elements.
+type arraySliceExample struct{}
+
+func (arraySliceExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "pointers/arrayslice_example",
+		Topic:         "pointers",
+		Level:         registry.Beginner,
+		Description:   "arrays are copied on assignment and calls; slices copy only their header",
+		Tags:          []string{"pointers", "arrays", "slices", "pass-by-value"},
+		Prerequisites: []string{"pointers/function_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (arraySliceExample) Explain(step string) string {
+	switch step {
+	case "initial":
+		return "arr is three ints stored right in the variable; sl is a header pointing at three ints stored elsewhere."
+	case "inside-changeArray":
+		return "a is a new array: a different address and its own three elements, copied from arr."
+	case "after-changeArray":
+		return "Only the copy was changed, so arr still holds what it had before the call."
+	case "inside-changeSlice":
+		return "s is a new header at a new address, but its first element is at the same address as sl's: both look at the same array."
+	case "after-changeSlice":
+		return "The write through s shows in sl. The append didn't: it changed s's length, and sl's header is a separate copy."
+	case "assignment":
+		return "Assignment copies the same way a call does: all of an array, only the header of a slice."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (arraySliceExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "arr is [3]int{1, 2, 3}. What is arr[0] after changeArray(arr), which sets a[0] = 100?",
+			Choices: []string{"1", "100"},
+			Answer:  "1",
+			Explain: "Arrays are values: changeArray gets its own copy of all three elements.",
+		},
+		{
+			Prompt:  "sl is []int{1, 2, 3}. changeSlice sets s[0] = 100 and then s = append(s, 4). What is sl afterwards?",
+			Choices: []string{"[1 2 3]", "[100 2 3]", "[100 2 3 4]"},
+			Answer:  "[100 2 3]",
+			Explain: "s shares sl's elements, so the write shows. The append changes s's own header (and here reallocates), which sl never sees.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (arraySliceExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What is copied when you pass a [1000]int to a function? A []int of 1000 elements?",
+			Back:  "The array: all 1000 ints (8000 bytes). The slice: only its 24-byte header; the elements are shared.",
+		},
+		{
+			Front: "How does a function make a change to a slice's length visible to its caller?",
+			Back:  "By returning the new slice (like append does), or by taking a pointer to the slice. Changing its copy of the header only changes the copy.",
+		},
+	}
+}
+
+// changeArray gets a copy of the caller's array and changes the copy.
+func changeArray(e *event.Emitter, a [3]int) {
+	e.Step("inside-changeArray")
+	e.Address("a", &a, "Address of the array inside the function")
+	e.Address("&a[0]", &a[0], "Address of its first element")
+	a[0] = 100
+	e.Diagram(snapshot().
+		Row("a", &a, fmt.Sprint(a), "changeArray").
+		Row("a[0]", &a[0], a[0], "changeArray"))
+	e.Warn("a is a copy of arr: changing it cannot change arr.")
+}
+
+// changeSlice gets a copy of the caller's slice header, writes through it
+// and appends to it.
+func changeSlice(e *event.Emitter, s []int) {
+	e.Step("inside-changeSlice")
+	e.Address("s", &s, "Address of the slice header inside the function")
+	e.Address("&s[0]", &s[0], "Address of its first element")
+	s[0] = 100
+	e.Diagram(snapshot().
+		Row("s", &s, fmt.Sprint(s), "changeSlice").
+		Row("s[0]", &s[0], s[0], "changeSlice"))
+	s = append(s, 4)
+	e.Value("s", s, "s after s = append(s, 4)")
+}
+
+// Run hands an array and a slice to the two functions, then assigns
+// them.
+func (arraySliceExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. An array and a slice holding the same numbers.
+	e.Step("initial")
+	arr := [3]int{1, 2, 3}
+	sl := []int{1, 2, 3}
+	e.Address("arr", &arr, "Address of arr")
+	e.Address("&arr[0]", &arr[0], "Address of arr's first element (the same: the elements are the array)")
+	e.Address("sl", &sl, "Address of sl's header")
+	e.Address("&sl[0]", &sl[0], "Address of sl's first element (elsewhere)")
+	e.Diagram(snapshot().
+		Row("arr", &arr, fmt.Sprint(arr), "Run").
+		Row("arr[0]", &arr[0], arr[0], "Run").
+		Row("sl", &sl, fmt.Sprint(sl), "Run").
+		Row("sl[0]", &sl[0], sl[0], "Run"))
+	check.That(unsafe.Pointer(&arr) == unsafe.Pointer(&arr[0]), "an array's first element lives at the array's address")
+	check.That(unsafe.Pointer(&sl) != unsafe.Pointer(&sl[0]), "a slice's elements live apart from its header")
+
+	// 2. Passing the array: the function gets every element copied.
+	changeArray(e, arr)
+
+	e.Step("after-changeArray")
+	e.Value("arr", arr, "arr after changeArray")
+	assert.Equal(check, "changeArray(arr) changes a copy", arr[0], 1)
+
+	// 3. Passing the slice: the function gets the header copied.
+	changeSlice(e, sl)
+
+	e.Step("after-changeSlice")
+	e.Value("sl", sl, "sl after changeSlice")
+	assert.Equal(check, "a write through the copied header reaches sl's elements", sl[0], 100)
+	assert.Equal(check, "append on the copied header doesn't change sl's length", len(sl), 3)
+
+	// 4. Assignment copies the same way.
+	e.Step("assignment")
+	arr2 := arr
+	arr2[1] = 7
+	sl2 := sl
+	sl2[1] = 7
+	e.Diagram(snapshot().
+		Row("arr", &arr, fmt.Sprint(arr), "Run").
+		Row("arr2", &arr2, fmt.Sprint(arr2), "Run").
+		Row("sl", &sl, fmt.Sprint(sl), "Run").
+		Row("sl2", &sl2, fmt.Sprint(sl2), "Run").
+		Row("sl[1]", &sl[1], sl[1], "Run").
+		Row("sl2[1]", &sl2[1], sl2[1], "Run"))
+	assert.Equal(check, "arr2 := arr copies the elements", arr[1], 2)
+	assert.Equal(check, "sl2 := sl shares them", sl[1], 7)
+	check.That(&sl2[1] == &sl[1], "sl and sl2 are two headers over the same elements")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,11 @@
+package pointers
+
+import (
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+)
+
+// The experiments "concepts bench" runs, for go test -bench.
+
+func BenchmarkFunction(b *testing.B) { benchlab.Bench(b, functionExample{}) }
@@ -0,0 +1,195 @@
+package pointers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/memviz"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(closureExample{})
+}
+
+// closureExample shows that a closure captures variables, not their
+// values: it prints the address of a captured variable from inside and
+// outside the closure, and they are the same. A closure that outlives its
+// function takes the variable along to the heap, which is what makes a
+// counter generator work. The lines where the compiler decides end in
+// "escape:" comments that
+//
+//	concepts escape pointers/closure_example
+//
+// checks.
+type closureExample struct{}
+
+func (closureExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "pointers/closure_example",
+		Topic:         "pointers",
+		Level:         registry.Intermediate,
+		Description:   "closures capture variables by reference: shared state, loop variables and counter generators",
+		Tags:          []string{"pointers", "closures", "functions", "escape-analysis"},
+		Prerequisites: []string{"pointers/function_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (closureExample) Explain(step string) string {
+	switch step {
+	case "capture":
+		return "The closure refers to x itself. Changing x after the closure was made changes what the closure sees, and the closure can change x too."
+	case "loop":
+		return "Since Go 1.22 each iteration of a for loop has its own loop variable, so each closure captures a different one. A variable declared outside the loop is one variable for all of them."
+	case "outlive":
+		return "n is declared in newCounter, but the closure newCounter returns still uses it. So n can't live in newCounter's frame: the compiler puts it on the heap."
+	case "generator":
+		return "Every call of newCounter makes a new n. The closures from one call share their n; those from another call have their own."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (closureExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "x := 1; f := func() int { return x }; x = 2. What does f() return?",
+			Choices: []string{"1", "2"},
+			Answer:  "2",
+			Explain: "f captured the variable x, not the value it had when f was made. It reads x when it runs.",
+		},
+		{
+			Prompt:  "a, b := newCounter(), newCounter(); a.next(); a.next(); b.next(). What does the next b.next() return?",
+			Choices: []string{"2", "4"},
+			Answer:  "2",
+			Explain: "Each call of newCounter makes its own n. b has been called once before, so this call returns 2.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (closureExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does a closure capture, a variable or its value?",
+			Back:  "The variable. The closure and the enclosing function read and write the same memory, as if the closure held a pointer to it.",
+		},
+		{
+			Front: "Why does a variable captured by a returned closure live on the heap?",
+			Back:  "The closure is used after the function that declared the variable has returned, so the variable must outlive that function's stack frame.",
+		},
+	}
+}
+
+// counter is what newCounter returns: closures over the same n.
+type counter struct {
+	next func() int  // increments n and returns it
+	addr func() *int // returns n's address
+}
+
+// newCounter returns a counter starting at 0. Its closures outlive the
+// call, so n moves to the heap.
+//
+//go:noinline
+func newCounter() counter {
+	n := 0 // escape: heap
+	return counter{
+		next: func() int { // escape: heap
+			n++
+			return n
+		},
+		addr: func() *int { return &n }, // escape: heap
+	}
+}
+
+// sumInPlace captures total in a closure that never leaves it, so total
+// can stay on the stack.
+//
+//go:noinline
+func sumInPlace(nums []int) int {
+	total := 0
+	add := func(n int) { total += n } // escape: stack
+	for _, n := range nums {
+		add(n)
+	}
+	return total
+}
+
+// Run captures, loops, and generates counters.
+func (closureExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. A closure refers to the variable, not a copy of its value.
+	e.Step("capture")
+	x := 1
+	get := func() int { return x }
+	addr := func() *int { return &x }
+	set := func(v int) { x = v }
+	e.Address("&x", &x, "Address of x in Run")
+	e.Address("addr()", addr(), "Address of x seen from inside a closure")
+	check.That(addr() == &x, "the closure refers to Run's x, not a copy")
+	x = 2
+	e.Value("get()", get(), "get() after x = 2")
+	set(3)
+	e.Value("x", x, "x after set(3)")
+	assert.Equal(check, "the closure sees the later write", get(), 3)
+
+	// 2. Loop variables: one per iteration, unless declared outside.
+	e.Step("loop")
+	var perIteration, shared []func() int
+	for i := range 3 {
+		perIteration = append(perIteration, func() int { return i })
+	}
+	j := 0
+	for ; j < 3; j++ {
+		shared = append(shared, func() int { return j })
+	}
+	results := func(fs []func() int) []int {
+		var out []int
+		for _, f := range fs {
+			out = append(out, f())
+		}
+		return out
+	}
+	e.Value("perIteration", results(perIteration), "Closures over the loop variable i")
+	e.Value("shared", results(shared), "Closures over j, declared before the loop")
+	assert.Equal(check, "each iteration's closure has its own i", fmt.Sprint(results(perIteration)), "[0 1 2]")
+	assert.Equal(check, "the closures over j all see its final value", fmt.Sprint(results(shared)), "[3 3 3]")
+	e.Warn("Before Go 1.22 the loop variable was shared too, so goroutines started in a loop often all saw its last value.")
+
+	// 3. Outliving the function: captured variables move to the heap.
+	e.Step("outlive")
+	allocs := func(f func()) int { return int(testing.AllocsPerRun(10, f)) }
+	e.Value("sumInPlace", sumInPlace([]int{1, 2, 3}), "sumInPlace([]int{1, 2, 3})")
+	assert.Equal(check, "a closure that stays inside its function doesn't allocate", allocs(func() { sumInPlace([]int{1, 2, 3}) }), 0)
+	check.That(allocs(func() { newCounter() }) >= 1, "a returned closure and its n are allocated on the heap")
+
+	// 4. A counter generator: each call makes a new n.
+	e.Step("generator")
+	a, b := newCounter(), newCounter()
+	a.next()
+	a.next()
+	b.next()
+	e.Value("a.next()", a.next(), "a.next(), the third call")
+	e.Value("b.next()", b.next(), "b.next(), the second call")
+	d := memviz.New()
+	d.Frame("a's n").Var("n", a.addr())
+	d.Frame("b's n").Var("n", b.addr())
+	e.Diagram(d)
+	e.Address("a.addr()", a.addr(), "Address of a's n, from a.addr")
+	e.Address("b.addr()", b.addr(), "Address of b's n, from b.addr")
+	check.That(a.addr() != b.addr(), "each counter has its own n")
+	assert.Equal(check, "a's closures share one n", *a.addr(), 3)
+	assert.Equal(check, "b's n counts separately", *b.addr(), 2)
+	e.Say("Run \"concepts escape pointers/closure_example\" to see the compiler move n to the heap.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,187 @@
+package pointers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/memviz"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(doublePointerExample{})
+}
+
+// doublePointerExample answers the question function_example can't: how
+// does a function change which variable the caller's pointer points at?
+// By getting a pointer to that pointer, a **int.
+type doublePointerExample struct{}
+
+func (doublePointerExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "pointers/doublepointer_example",
+		Topic:         "pointers",
+		Level:         registry.Intermediate,
+		Description:   "pointers to pointers: redirecting the caller's pointer from inside a function",
+		Tags:          []string{"pointers", "double-pointer", "functions", "pass-by-value"},
+		Prerequisites: []string{"pointers/function_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (doublePointerExample) Explain(step string) string {
+	switch step {
+	case "initial":
+		return "p points at a. The goal: have a function make it point at b instead."
+	case "inside-redirectCopy":
+		return "The function's q is a copy of p. Assigning to q changes where the copy points, and nothing else."
+	case "after-redirectCopy":
+		return "p still points at a: the function only ever had a copy of the pointer."
+	case "inside-redirect":
+		return "pp points at p itself, so *pp is p, and *pp = &b overwrites the caller's pointer."
+	case "after-redirect":
+		return "p now points at b. Neither a nor b changed; only the pointer did."
+	case "triple":
+		return "Every * follows one arrow. ***ppp follows three, from ppp to pp to p to the int."
+	case "list":
+		return "A pointer to the link being examined, rather than to the node, removes the first node the same way as any other."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (doublePointerExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "p points at a. After redirectCopy(p, &b), which sets its parameter q = &b, what does p point at?",
+			Choices: []string{"a", "b"},
+			Answer:  "a",
+			Explain: "The pointer is passed by value: q is a copy of p, and reassigning the copy leaves p alone. Only *q = ... would have reached a.",
+		},
+		{
+			Prompt:  "pp is a **int holding &p. What does *pp = &b change?",
+			Choices: []string{"the int p points at", "p itself, which now points at b", "pp, which now points at b"},
+			Answer:  "p itself, which now points at b",
+			Explain: "*pp is the variable pp points at, which is p. **pp = 5 would change the int instead.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (doublePointerExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "When does a function need a **T parameter?",
+			Back:  "When it must change which T the caller's *T points at (or set it to nil), not just the T itself. Often returning the new *T is the clearer choice.",
+		},
+	}
+}
+
+// redirectCopy tries to redirect the caller's pointer by assigning to its
+// copy.
+func redirectCopy(e *event.Emitter, q, target *int) {
+	e.Step("inside-redirectCopy")
+	q = target
+	e.Address("q", q, "q now points at")
+	e.Warn("q is a copy of p: pointing it at b leaves p pointing at a.")
+}
+
+// redirect redirects the caller's pointer through a pointer to it.
+func redirect(e *event.Emitter, pp **int, target *int) {
+	e.Step("inside-redirect")
+	e.Address("pp", pp, "pp points at (the caller's p)")
+	e.Address("*pp", *pp, "*pp, before")
+	*pp = target
+	e.Address("*pp", *pp, "*pp, after *pp = &b")
+}
+
+// node is an element of a linked list.
+type node struct {
+	value int
+	next  *node
+}
+
+// remove deletes the first node holding v from the list starting at
+// *head. link always points at the pointer that leads to the current
+// node, first head itself and then each node's next, so removing the
+// first node needs no special case.
+func remove(head **node, v int) {
+	for link := head; *link != nil; link = &(*link).next {
+		if (*link).value == v {
+			*link = (*link).next
+			return
+		}
+	}
+}
+
+// values lists the values of the list starting at n.
+func values(n *node) []int {
+	var vs []int
+	for ; n != nil; n = n.next {
+		vs = append(vs, n.value)
+	}
+	return vs
+}
+
+// Run points p at a, then tries two ways to point it at b.
+func (doublePointerExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Two ints and a pointer to the first.
+	e.Step("initial")
+	a, b := 1, 2
+	p := &a
+	e.Address("&a", &a, "Address of a")
+	e.Address("&b", &b, "Address of b")
+	e.Address("p", p, "p points at")
+	d := memviz.New()
+	d.Frame("Run").Var("a", &a).Var("b", &b).Var("p", &p)
+	e.Diagram(d)
+
+	// 2. Passing p itself: the function gets a copy of the pointer.
+	redirectCopy(e, p, &b)
+
+	e.Step("after-redirectCopy")
+	e.Address("p", p, "p points at")
+	check.That(p == &a, "reassigning a copy of the pointer leaves p pointing at a")
+
+	// 3. Passing &p: the function can overwrite p.
+	pp := &p
+	redirect(e, pp, &b)
+
+	e.Step("after-redirect")
+	e.Address("p", p, "p points at")
+	e.Value("*p", *p, "*p")
+	d = memviz.New()
+	d.Frame("Run").Var("a", &a).Var("b", &b).Var("p", &p)
+	d.Frame("redirect").Var("pp", &pp)
+	e.Diagram(d)
+	check.That(p == &b, "redirect(&p, &b) points p at b")
+	assert.Equal(check, "a is untouched", a, 1)
+
+	// 4. One more level: a ***int, three arrows from the int.
+	e.Step("triple")
+	ppp := &pp
+	***ppp = 20
+	e.Value("b", b, "b after ***ppp = 20")
+	d = memviz.New()
+	d.Frame("Run").Var("b", &b).Var("p", &p).Var("pp", &pp).Var("ppp", &ppp)
+	e.Diagram(d)
+	assert.Equal(check, "***ppp reaches the int at the end of the chain", b, 20)
+
+	// 5. Where **T is at home: removing from a linked list.
+	e.Step("list")
+	list := &node{1, &node{2, &node{3, nil}}}
+	remove(&list, 1)
+	remove(&list, 3)
+	e.Value("list", values(list), "The list 1, 2, 3 after removing 1 (the head) and 3")
+	assert.Equal(check, "remove handles the head like any other node", fmt.Sprint(values(list)), "[2]")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,270 @@
+// Package pointers contains examples about pointers: what they point at,
+// and how passing a pointer differs from passing a value.
+package pointers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/memviz"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(functionExample{})
+}
+
+// functionExample shows that a function receiving a pointer can change the
+// caller's variable, while a function receiving a value only changes its copy.
+type functionExample struct{}
+
+func (functionExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:        "pointers/function_example",
+		Topic:       "pointers",
+		Level:       registry.Beginner,
+		Description: "passing an int by pointer vs by value and watching the addresses",
+		Tags:        []string{"pointers", "functions", "pass-by-value"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (functionExample) Explain(step string) string {
+	switch step {
+	case "initial":
+		return "count is a local variable holding 10; its address says where those 8 bytes live."
+	case "inside-incrementValue":
+		return "valPtr holds the address of count, so *valPtr++ writes straight into count's memory."
+	case "after-incrementValue":
+		return "Back in the caller, count itself changed: same address, new value."
+	case "inside-incrementValueNoPtr":
+		return "val is a brand-new variable with its own address; it only starts out as a copy of count."
+	case "after-incrementValueNoPtr":
+		return "Only the copy was incremented, so count still holds the value it had before the call."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (functionExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "count is 10. What is count after incrementValue(&count)?",
+			Answer:  "11",
+			Explain: "incrementValue receives the address of count, and *valPtr++ increments the int stored at that address, which is count itself.",
+		},
+		{
+			Prompt:  "count is 11. Does incrementValueNoPtr(count) change count?",
+			Choices: []string{"yes, count becomes 12", "no, count stays 11"},
+			Answer:  "no, count stays 11",
+			Explain: "incrementValueNoPtr gets a copy of count in its parameter val; val++ only changes the copy.",
+		},
+		{
+			Prompt:  "Inside incrementValue, how do the printed values of valPtr and &*valPtr compare?",
+			Choices: []string{"they are the same address", "&*valPtr is the address of valPtr itself", "they differ by 8 bytes"},
+			Answer:  "they are the same address",
+			Explain: "*valPtr is the int valPtr points at, and & takes its address again, so &*valPtr is just valPtr.",
+		},
+		{
+			Prompt:  "Inside incrementValueNoPtr, is the address of val the same as the address of count?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "val is a new variable in incrementValueNoPtr's frame; the example prints a different address for it than for count.",
+		},
+	}
+}
+
+// Variants let "concepts compare" run the pointer and the value version of
+// the increment side by side, each on a fresh count of 10.
+func (functionExample) Variants() []registry.Variant {
+	variant := func(name string, want int, call func(e *event.Emitter, count *int)) registry.Variant {
+		return registry.Variant{Name: name, Run: func(ctx context.Context, w io.Writer) error {
+			e := event.From(ctx, w)
+			check := assert.New()
+			count := 10
+			e.Step("before")
+			e.Address("count", &count, "Address of count in memory")
+			e.Value("count", count, "Value of count")
+
+			call(e, &count)
+
+			e.Step("after")
+			e.Address("count", &count, "Address of count in memory")
+			e.Value("count", count, "Value of count")
+			assert.Equal(check, "count after "+name, count, want)
+			return errors.Join(e.Err(), check.Err())
+		}}
+	}
+	return []registry.Variant{
+		variant("incrementValue", 11, func(e *event.Emitter, count *int) { incrementValue(e, count) }),
+		variant("incrementValueNoPtr", 10, func(e *event.Emitter, count *int) { incrementValueNoPtr(e, *count) }),
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (functionExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "In incrementValue, what does *valPtr++ modify?",
+			Back:  "The int valPtr points at, which is the caller's count. The pointer itself is unchanged.",
+		},
+		{
+			Front: "Why doesn't incrementValueNoPtr(count) change count?",
+			Back:  "Go passes arguments by value: val is a copy of count, so val++ only changes the copy.",
+		},
+		{
+			Front: "What is &*p for a non-nil pointer p?",
+			Back:  "p itself: *p is the variable p points at, and & takes its address again.",
+		},
+	}
+}
+
+// snapshot starts a table of the variables in play at one moment. Lined up
+// like this, the address column shows at a glance whether two names refer
+// to the same int or to a copy.
+func snapshot() *table.Table {
+	return table.New("variable", "address", "value", "scope")
+}
+
+// Experiments are measured by "concepts bench pointers".
+func (functionExample) Experiments() []benchlab.Experiment {
+	return []benchlab.Experiment{{
+		Name: "passing a 1 KB struct",
+		Approaches: []benchlab.Approach{
+			{Name: "by value", Bench: func(b *testing.B) {
+				var r record
+				for range b.N {
+					benchSink = sumByValue(r)
+				}
+			}},
+			{Name: "by pointer", Bench: func(b *testing.B) {
+				var r record
+				for range b.N {
+					benchSink = sumByPointer(&r)
+				}
+			}},
+		},
+		Guidance: "Passing by value copies all 1024 bytes of the record into every call; " +
+			"passing by pointer copies 8. Neither allocates, because the record never " +
+			"leaves the caller's frame. For small structs (a few words) the copy is as " +
+			"cheap as the pointer, so pick by meaning: use a pointer when the function " +
+			"should change the caller's value, not out of habit.",
+	}}
+}
+
+// record is big enough (128 ints, 1 KB) for copying it to show up in a
+// benchmark.
+type record [128]int
+
+// benchSink keeps the benchmarked calls from being optimized away.
+var benchSink int
+
+//go:noinline
+func sumByValue(r record) int {
+	return r[0] + r[len(r)-1]
+}
+
+//go:noinline
+func sumByPointer(r *record) int {
+	return r[0] + r[len(r)-1]
+}
+
+// incrementValue is a function that takes a pointer to an integer,
+// increments the value it points to, and prints the address and new value.
+func incrementValue(e *event.Emitter, valPtr *int) {
+	e.Step("inside-incrementValue")
+	e.Say("Inside incrementValue function (pointer version):")
+	e.Address("valPtr", valPtr, "Address of variable inside function")
+	e.Address("*valPtr", &*valPtr, "Address where the value is stored (dereferenced pointer)")
+	e.Value("*valPtr", *valPtr, "Value before increment")
+	e.Diagram(snapshot().
+		Row("count", valPtr, *valPtr, "Run").
+		Row("valPtr", &valPtr, valPtr, "incrementValue"))
+
+	*valPtr++
+
+	e.Value("*valPtr", *valPtr, "Value after increment")
+	e.Diagram(snapshot().
+		Row("count", valPtr, *valPtr, "Run").
+		Row("valPtr", &valPtr, valPtr, "incrementValue"))
+	e.Address("valPtr", valPtr, "Address of variable inside function after increment (still same pointer address)")
+	e.Address("*valPtr", &*valPtr, "Address where the value is stored after increment (still same memory location)")
+
+	// Draw the two stack frames involved. valPtr IS the address of the
+	// caller's count, which is exactly what the arrow shows.
+	d := memviz.New()
+	d.Frame("Run").Var("count", valPtr)
+	d.Frame("incrementValue").Var("valPtr", &valPtr)
+	e.Diagram(d)
+}
+
+// incrementValueNoPtr is a function that takes an integer by value,
+// increments it, and prints the address and new value.
+// IMPORTANT: This function operates on a COPY of the original 'count' variable.
+func incrementValueNoPtr(e *event.Emitter, val int) {
+	e.Step("inside-incrementValueNoPtr")
+	e.Say("Inside incrementValueNoPtr function (no pointer version):")
+	e.Address("val", &val, "Address of variable inside function") // Address of the copy 'val'
+	e.Value("val", val, "Value before increment")
+	e.Diagram(snapshot().Row("val", &val, val, "incrementValueNoPtr"))
+
+	val++ // Increment the COPY of the value
+	e.Warn("val is a copy of count: incrementing it cannot change count.")
+
+	e.Value("val", val, "Value after increment")
+	e.Diagram(snapshot().Row("val", &val, val, "incrementValueNoPtr"))
+	e.Address("val", &val, "Address of variable inside function after increment (still same address of copy)")
+
+	// No arrow this time: val is a separate variable that nothing points to.
+	d := memviz.New()
+	d.Frame("incrementValueNoPtr").Var("val", &val)
+	e.Diagram(d)
+}
+
+// Run declares count, then hands it to both increment functions and
+// shows what each one did to it.
+func (functionExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Declare a variable with a hardcoded value
+	count := 10
+
+	// 2. Show initial address and value
+	e.Step("initial")
+	e.Say("Initial state:")
+	e.Say("Variable name: count")
+	e.Address("count", &count, "Address of count in memory")
+	e.Value("count", count, "Value of count")
+	e.Diagram(snapshot().Row("count", &count, count, "Run"))
+
+	// 3. Call the increment function (pointer version), passing the address of 'count'
+	incrementValue(e, &count)
+
+	// 4. Show address and value after incrementing (pointer version)
+	e.Step("after-incrementValue")
+	e.Say("After incrementValue function (pointer version):")
+	e.Address("count", &count, "Address of count in memory (after incrementValue)")
+	e.Value("count", count, "Value of count (after incrementValue)")
+	assert.Equal(check, "incrementValue(&count) increments count through the pointer", count, 11)
+
+	// 5. Call the increment function (no pointer version), passing the value of 'count'
+	incrementValueNoPtr(e, count) // Passing the VALUE of 'count'
+
+	// 6. Show address and value after incrementing (no pointer version)
+	e.Step("after-incrementValueNoPtr")
+	e.Say("After incrementValueNoPtr function (no pointer version):")
+	e.Address("count", &count, "Address of count in memory (after incrementValueNoPtr)") // Address should remain the same as before incrementValueNoPtr
+	e.Value("count", count, "Value of count (after incrementValueNoPtr)")                // Value should NOT be changed by incrementValueNoPtr
+	e.Diagram(snapshot().Row("count", &count, count, "Run"))
+	assert.Equal(check, "incrementValueNoPtr(count) leaves count unchanged", count, 11)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,173 @@
+package pointers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(newMakeExample{})
+}
+
+// newMakeExample builds a slice, a map and a channel with new and with
+// make. new(T) hands back a *T pointing at T's zero value, and the zero
+// value of these three types is nil; make hands back the value itself,
+// initialized and ready to use.
+type newMakeExample struct{}
+
+func (newMakeExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "pointers/newmake_example",
+		Topic:         "pointers",
+		Level:         registry.Beginner,
+		Description:   "new vs make for slices, maps and channels, and the nil-map write panic",
+		Tags:          []string{"pointers", "new", "make", "zero-values", "maps", "panic"},
+		Prerequisites: []string{"pointers/function_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (newMakeExample) Explain(step string) string {
+	switch step {
+	case "new":
+		return "new(int) allocates an int, sets it to 0 and returns its address. It works for any type."
+	case "new-composite":
+		return "new([]int) is a *[]int pointing at a nil slice: new zeroes memory, and the zero slice, map and channel are nil."
+	case "make":
+		return "make only works for slices, maps and channels. It returns the value itself, not a pointer, with its internals set up."
+	case "nil-map":
+		return "Reading a nil map yields zero values, but writing to one panics: there is no table to put the entry in."
+	case "nil-slice":
+		return "A nil slice is fine to append to (append allocates), and a send on a nil channel blocks forever."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (newMakeExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "What is the type of new(map[string]int)?",
+			Choices: []string{"map[string]int", "*map[string]int"},
+			Answer:  "*map[string]int",
+			Explain: "new(T) always returns a *T. Here it points at a nil map, so (*m)[\"k\"] = 1 still panics.",
+		},
+		{
+			Prompt:  "var m map[string]int; what does m[\"k\"] = 1 do?",
+			Choices: []string{"adds the entry", "panics: assignment to entry in nil map", "does nothing"},
+			Answer:  "panics: assignment to entry in nil map",
+			Explain: "The zero map has no storage. Reads and delete are fine; writes need a map from make or a literal.",
+		},
+		{
+			Prompt:  "var s []int; what does s = append(s, 1) do?",
+			Choices: []string{"panics like the nil map", "allocates an array and returns a slice of length 1"},
+			Answer:  "allocates an array and returns a slice of length 1",
+			Explain: "append grows any slice whose capacity is too small, and a nil slice simply has capacity 0.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (newMakeExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "new vs make?",
+			Back:  "new(T) works for any T and returns a *T pointing at T's zero value. make(T, ...) works only for slices, maps and channels and returns an initialized T, not a pointer.",
+		},
+		{
+			Front: "Which operations on a nil map are allowed?",
+			Back:  "Reading (zero value), len (0), delete (no-op) and range (no iterations). Only writing panics.",
+		},
+	}
+}
+
+// kinds starts a table describing values built by new or make, with a
+// last column saying whether the value checked by isNil is nil.
+func kinds(isNil string) *table.Table {
+	return table.New("expression", "type", "points at / holds", isNil)
+}
+
+// Run builds each kind of value with new and with make.
+func (newMakeExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. new works for any type: a pointer to a zeroed value.
+	e.Step("new")
+	n := new(int)
+	pt := new(person)
+	e.Diagram(kinds("p == nil").
+		Row("new(int)", fmt.Sprintf("%T", n), *n, n == nil).
+		Row("new(person)", fmt.Sprintf("%T", pt), fmt.Sprintf("%+v", *pt), pt == nil))
+	*n = 42
+	e.Value("*n", *n, "*n after *n = 42")
+	assert.Equal(check, "new(int) points at a usable int", *n, 42)
+
+	// 2. new for a slice, map or channel: a pointer to a nil one.
+	e.Step("new-composite")
+	ps := new([]int)
+	pm := new(map[string]int)
+	pc := new(chan int)
+	e.Diagram(kinds("*p == nil").
+		Row("new([]int)", fmt.Sprintf("%T", ps), *ps, *ps == nil).
+		Row("new(map[string]int)", fmt.Sprintf("%T", pm), *pm, *pm == nil).
+		Row("new(chan int)", fmt.Sprintf("%T", pc), *pc, *pc == nil))
+	e.Say("fmt prints a nil slice as [] and a nil map as map[], the same as empty ones: only the last column tells them apart.")
+	check.That(*ps == nil && *pm == nil && *pc == nil, "new zeroes memory, and the zero slice, map and channel are nil")
+
+	// 3. make: the value itself, ready to use.
+	e.Step("make")
+	s := make([]int, 3, 5)
+	m := make(map[string]int)
+	c := make(chan int, 2)
+	e.Diagram(kinds("== nil").
+		Row("make([]int, 3, 5)", fmt.Sprintf("%T", s), fmt.Sprintf("%v len=%d cap=%d", s, len(s), cap(s)), s == nil).
+		Row("make(map[string]int)", fmt.Sprintf("%T", m), m, m == nil).
+		Row("make(chan int, 2)", fmt.Sprintf("%T", c), fmt.Sprintf("len=%d cap=%d", len(c), cap(c)), c == nil))
+	m["k"] = 1
+	c <- 1
+	e.Value("m", m, "m after m[\"k\"] = 1")
+	e.Value("len(c)", len(c), "len(c) after c <- 1")
+	check.That(s != nil && m != nil && c != nil, "make returns initialized, non-nil values")
+
+	// 4. The nil map from new: reading works, writing panics.
+	e.Step("nil-map")
+	e.Value("(*pm)[\"k\"]", (*pm)["k"], "Reading a nil map")
+	e.Value("len(*pm)", len(*pm), "len of a nil map")
+	delete(*pm, "k")
+	r := assert.Panics(func() { (*pm)["k"] = 1 })
+	e.Value("recovered", r, "Panic from (*pm)[\"k\"] = 1, caught by recover")
+	_, isRuntime := r.(runtime.Error)
+	check.That(isRuntime, "the nil-map write panics with a runtime.Error")
+	assert.Equal(check, "the panic names the cause", fmt.Sprint(r), "assignment to entry in nil map")
+	e.Warn("A struct field of map type starts out nil too: initialize it (usually in a constructor) before the first write.")
+	*pm = make(map[string]int)
+	(*pm)["k"] = 1
+	e.Value("*pm", *pm, "*pm after *pm = make(map[string]int) and the same write")
+
+	// 5. The other nil values: a nil slice can be appended to, a nil
+	// channel blocks.
+	e.Step("nil-slice")
+	*ps = append(*ps, 1, 2)
+	e.Value("*ps", *ps, "*ps after appending to the nil slice")
+	assert.Equal(check, "append allocates for a nil slice", len(*ps), 2)
+	sent := false
+	select {
+	case *pc <- 1:
+		sent = true
+	default:
+	}
+	e.Value("sent", sent, "A send on the nil channel could proceed")
+	check.That(!sent, "a send on a nil channel never proceeds")
+	return errors.Join(e.Err(), check.Err())
+}
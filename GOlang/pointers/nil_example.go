@@ -0,0 +1,168 @@
+package pointers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+func init() {
+	registry.Register(nilExample{})
+}
+
+// nilExample dereferences a nil pointer on purpose, with recover standing
+// by, and then shows the ways code avoids doing it by accident: methods
+// that work on a nil receiver, guards that check before they follow a
+// pointer, and zero-valued structs that need no pointer at all.
+type nilExample struct{}
+
+func (nilExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "pointers/nil_example",
+		Topic:         "pointers",
+		Level:         registry.Beginner,
+		Description:   "nil pointers: the dereference panic, nil-safe receivers, guards and zero-valued structs",
+		Tags:          []string{"pointers", "nil", "panic", "recover", "zero-values"},
+		Prerequisites: []string{"pointers/struct_example", "pointers/newmake_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (nilExample) Explain(step string) string {
+	switch step {
+	case "dereference":
+		return "A nil pointer points at nothing. Following it to read or write a field is a run-time panic, not a compile error."
+	case "nil-receiver":
+		return "Calling a pointer method on nil is fine: the method gets nil as its receiver, and can check for it before touching any field."
+	case "guard":
+		return "Anything that may return nil says so, usually with an ok or error result, and the caller checks before dereferencing."
+	case "zero-value":
+		return "A nil *person and a zero person are different things: the first has no person at all, the second is a person with empty fields."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (nilExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "var p *person; what does fmt.Println(p.Name) do?",
+			Choices: []string{"prints an empty line", "doesn't compile", "panics: invalid memory address or nil pointer dereference"},
+			Answer:  "panics: invalid memory address or nil pointer dereference",
+			Explain: "p.Name is (*p).Name, and *p follows a nil pointer. The compiler can't know p is nil, so it's a run-time error.",
+		},
+		{
+			Prompt:  "var t *tree; t.Sum(), where Sum has a pointer receiver and starts with if t == nil { return 0 }. Result?",
+			Choices: []string{"0", "a nil pointer panic"},
+			Answer:  "0",
+			Explain: "Calling the method doesn't dereference t; only reading a field would. The nil check comes first.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (nilExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "nil *T vs T{}?",
+			Back:  "A nil *T has no T behind it: reading a field panics. T{} is a real value whose fields are zero, and is ready to use.",
+		},
+		{
+			Front: "How can a method be safe to call on a nil pointer?",
+			Back:  "Give it a pointer receiver and check the receiver for nil before using any field, like a tree's Sum returning 0 for an empty subtree.",
+		},
+	}
+}
+
+// tree is a binary tree; a nil *tree is the empty tree.
+type tree struct {
+	value       int
+	left, right *tree
+}
+
+// Sum adds up the tree's values. It works on nil, the empty tree, which
+// spares every caller (and the recursion) from checking.
+func (t *tree) Sum() int {
+	if t == nil {
+		return 0
+	}
+	return t.value + t.left.Sum() + t.right.Sum()
+}
+
+// errNoPerson is returned by lookup for names it doesn't know.
+var errNoPerson = errors.New("no such person")
+
+// lookup returns the person called name, or an error if there is none;
+// the *person is only non-nil when the error is nil.
+func lookup(people map[string]*person, name string) (*person, error) {
+	p, ok := people[name]
+	if !ok {
+		return nil, fmt.Errorf("lookup %q: %w", name, errNoPerson)
+	}
+	return p, nil
+}
+
+// Run panics once on purpose and then avoids it three ways.
+func (nilExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Following a nil pointer panics; recover catches it here so the
+	// example can carry on.
+	e.Step("dereference")
+	var p *person
+	e.Value("p", p, "p, a *person nobody set")
+	r := assert.Panics(func() { _ = p.Name })
+	e.Value("recovered", r, "Panic from reading p.Name, caught by recover")
+	_, isRuntime := r.(runtime.Error)
+	check.That(isRuntime, "a nil dereference panics with a runtime.Error")
+	assert.Equal(check, "the panic names the cause", fmt.Sprint(r), "runtime error: invalid memory address or nil pointer dereference")
+	e.Warn("In a real program nothing recovers this panic: it crashes with a stack trace pointing at the dereference.")
+
+	// 2. A nil-safe method: the receiver may be nil.
+	e.Step("nil-receiver")
+	var empty *tree
+	full := &tree{value: 1, left: &tree{value: 2}, right: &tree{value: 3, left: &tree{value: 4}}}
+	e.Value("empty.Sum()", empty.Sum(), "empty.Sum() on a nil *tree")
+	e.Value("full.Sum()", full.Sum(), "full.Sum(), which calls Sum on nil subtrees on the way")
+	assert.Equal(check, "Sum on a nil tree is 0", empty.Sum(), 0)
+	assert.Equal(check, "Sum adds up every value", full.Sum(), 10)
+
+	// 3. Guards: check the error, then use the pointer.
+	e.Step("guard")
+	people := map[string]*person{"alice": {Name: "Alice", Age: 30}}
+	for _, name := range []string{"alice", "bob"} {
+		p, err := lookup(people, name)
+		if err != nil {
+			e.Value("err", err, "lookup("+name+") failed, and p is not touched")
+			check.That(errors.Is(err, errNoPerson) && p == nil, "a failed lookup returns nil and errNoPerson")
+			continue
+		}
+		e.Value("p.Age", p.Age, "lookup("+name+") succeeded: p.Age")
+	}
+
+	// 4. A nil pointer, a zero struct and a pointer to a zero struct.
+	e.Step("zero-value")
+	var nilPtr *person
+	var zero person
+	ptrToZero := &person{}
+	t := table.New("expression", "== nil", "Name", "Age")
+	t.Row("var nilPtr *person", nilPtr == nil, "(panics)", "(panics)")
+	t.Row("var zero person", "(not a pointer)", fmt.Sprintf("%q", zero.Name), zero.Age)
+	t.Row("ptrToZero := &person{}", ptrToZero == nil, fmt.Sprintf("%q", ptrToZero.Name), ptrToZero.Age)
+	e.Diagram(t)
+	ptrToZero.Age++
+	zero.Age++
+	assert.Equal(check, "a zero struct is usable at once", zero.Age, 1)
+	assert.Equal(check, "so is a pointer to one", ptrToZero.Age, 1)
+	return errors.Join(e.Err(), check.Err())
+}
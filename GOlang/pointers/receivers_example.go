@@ -0,0 +1,163 @@
+package pointers
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(receiversExample{})
+}
+
+// receiversExample gives one struct a value-receiver method and a
+// pointer-receiver method that both try to increment it. A receiver is
+// just the method's first parameter, so the same rule as in
+// function_example decides which one changes the original; method sets
+// then decide which of them an interface can call.
+type receiversExample struct{}
+
+func (receiversExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "pointers/receivers_example",
+		Topic:         "pointers",
+		Level:         registry.Intermediate,
+		Description:   "pointer vs value receivers: mutation, addressability and method sets",
+		Tags:          []string{"pointers", "methods", "receivers", "interfaces", "method-sets"},
+		Prerequisites: []string{"pointers/function_example", "pointers/struct_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (receiversExample) Explain(step string) string {
+	switch step {
+	case "value-receiver":
+		return "A value receiver is a copy of t, made at the call, so IncCopy changes the copy."
+	case "pointer-receiver":
+		return "t.Inc() is short for (&t).Inc(): the receiver is t's address, so Inc changes t."
+	case "addressable":
+		return "The shorthand needs an address to take. Map elements and the results of calls have none, so pointer methods can't be called on them."
+	case "method-sets":
+		return "A *tally has both methods, a tally only the value-receiver ones, so only a *tally satisfies an interface that needs Inc."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (receiversExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "t := tally{}; t.Inc(), where Inc has a pointer receiver. Does it compile, and does t change?",
+			Choices: []string{"it doesn't compile: t isn't a pointer", "it compiles and t changes", "it compiles and a copy changes"},
+			Answer:  "it compiles and t changes",
+			Explain: "t is a variable, so it is addressable, and Go calls (&t).Inc() for you.",
+		},
+		{
+			Prompt:  "m is map[string]tally. Does m[\"a\"].Inc() compile?",
+			Choices: []string{"yes", "no"},
+			Answer:  "no",
+			Explain: "cannot call pointer method Inc on tally: a map element is not addressable. Copy it out, call Inc on the copy, store it back.",
+		},
+		{
+			Prompt:  "Which of tally and *tally implement interface{ Inc() }, when Inc has a pointer receiver?",
+			Choices: []string{"both", "only *tally", "only tally"},
+			Answer:  "only *tally",
+			Explain: "The method set of tally holds only its value-receiver methods. An interface can't take the address of the value it holds, so it can't call Inc on a tally.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (receiversExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Method set of T vs *T?",
+			Back:  "T: the methods with value receivers. *T: those plus the methods with pointer receivers. It decides which interfaces each type satisfies.",
+		},
+		{
+			Front: "When should a method have a pointer receiver?",
+			Back:  "When it changes the receiver, when the struct is large, or when it holds something that must not be copied (like a sync.Mutex). Keep a type's receivers consistent.",
+		},
+	}
+}
+
+// tally is a count with one method of each receiver kind.
+type tally struct{ n int }
+
+// Value reads the count; a value receiver is enough for that.
+func (t tally) Value() int { return t.n }
+
+// IncCopy tries to increment the count through a value receiver.
+func (t tally) IncCopy(e *event.Emitter) {
+	e.Address("t", &t, "Address of the receiver inside IncCopy (a copy)")
+	t.n++
+}
+
+// Inc increments the count through a pointer receiver.
+func (t *tally) Inc(e *event.Emitter) {
+	e.Address("t", t, "The receiver inside Inc (the address of the variable it was called on)")
+	t.n++
+}
+
+// incrementer is satisfied by types whose method set has Inc.
+type incrementer interface{ Inc(e *event.Emitter) }
+
+// valuer is satisfied by types whose method set has Value.
+type valuer interface{ Value() int }
+
+// Run calls both methods on a tally, then tries them where there's no
+// address and through interfaces.
+func (receiversExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. A value receiver gets a copy.
+	e.Step("value-receiver")
+	t := tally{n: 10}
+	e.Address("&t", &t, "Address of t in Run")
+	t.IncCopy(e)
+	e.Value("t.n", t.n, "t.n after t.IncCopy()")
+	assert.Equal(check, "a value receiver changes a copy", t.n, 10)
+
+	// 2. A pointer receiver gets t's address, taken implicitly.
+	e.Step("pointer-receiver")
+	t.Inc(e)
+	e.Value("t.n", t.n, "t.n after t.Inc()")
+	assert.Equal(check, "t.Inc() is (&t).Inc() and changes t", t.n, 11)
+	p := &t
+	e.Value("p.Value()", p.Value(), "p.Value() on a *tally, which is (*p).Value()")
+
+	// 3. No address, no pointer method.
+	e.Step("addressable")
+	e.Say("tally{}.Inc(), m[\"a\"].Inc() and f().Inc() for an f returning a tally all fail to compile: cannot call pointer method Inc on tally")
+	m := map[string]tally{"a": {n: 1}}
+	a := m["a"]
+	a.Inc(e)
+	m["a"] = a
+	e.Value("m[\"a\"].n", m["a"].n, "m[\"a\"].n after copy, Inc, store back")
+	assert.Equal(check, "copy-Inc-store updates the map element", m["a"].n, 2)
+
+	// 4. Method sets: which types satisfy which interfaces.
+	e.Step("method-sets")
+	_, valueInc := any(t).(incrementer)
+	_, ptrInc := any(&t).(incrementer)
+	_, valueVal := any(t).(valuer)
+	_, ptrVal := any(&t).(valuer)
+	e.Value("tally is an incrementer", valueInc, "tally satisfies incrementer")
+	e.Value("*tally is an incrementer", ptrInc, "*tally satisfies incrementer")
+	e.Value("tally is a valuer", valueVal, "tally satisfies valuer")
+	e.Value("*tally is a valuer", ptrVal, "*tally satisfies valuer")
+	check.That(!valueInc && ptrInc, "only *tally has Inc in its method set")
+	check.That(valueVal && ptrVal, "both tally and *tally have Value in their method set")
+	e.Say("var _ incrementer = tally{} fails to compile: tally does not implement incrementer (method Inc has pointer receiver)")
+	var i incrementer = &t
+	i.Inc(e)
+	assert.Equal(check, "calling Inc through the interface changes t", t.n, 12)
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,172 @@
+package pointers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"unsafe"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/memviz"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(structExample{})
+}
+
+// structExample does for a struct what functionExample does for an int:
+// it changes a field through a pointer to the struct, then through a copy
+// of it, and shows where the struct and each of its fields live.
+type structExample struct{}
+
+func (structExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "pointers/struct_example",
+		Topic:         "pointers",
+		Level:         registry.Beginner,
+		Description:   "changing struct fields through a pointer vs a copy, and where each field lives",
+		Tags:          []string{"pointers", "structs", "pass-by-value", "auto-dereference"},
+		Prerequisites: []string{"pointers/function_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (structExample) Explain(step string) string {
+	switch step {
+	case "initial":
+		return "A struct's fields sit next to each other in the struct's memory; the first one starts at the struct's own address."
+	case "inside-birthday":
+		return "p is a *person, yet p.Age needs no star: Go dereferences pointers to structs for you, so p.Age means (*p).Age."
+	case "after-birthday":
+		return "The pointer led to the caller's struct, so the caller's alice is a year older."
+	case "inside-birthdayCopy":
+		return "p is a whole new person, with every field copied, at addresses of its own."
+	case "after-birthdayCopy":
+		return "Only the copy had its birthday; alice is as old as before the call."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz pointers".
+func (structExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "p is a *person. Which is the same as p.Age++?",
+			Choices: []string{"(*p).Age++", "*p.Age++", "&p.Age++"},
+			Answer:  "(*p).Age++",
+			Explain: "Selecting a field through a pointer dereferences it automatically. *p.Age would mean *(p.Age), and Age isn't a pointer.",
+		},
+		{
+			Prompt:  "How do &alice and &alice.Name compare?",
+			Choices: []string{"they are the same address", "&alice.Name is 8 bytes further", "they are unrelated"},
+			Answer:  "they are the same address",
+			Explain: "Name is the first field, at offset 0 of the struct. Age comes after it, at unsafe.Offsetof(alice.Age).",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (structExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "What does p.Field mean when p is a pointer to a struct?",
+			Back:  "(*p).Field: Go dereferences the pointer automatically when selecting a field (and when calling a method).",
+		},
+		{
+			Front: "What does passing a struct by value copy?",
+			Back:  "Every field. The function's parameter is a separate struct at a separate address; changing its fields doesn't affect the caller's.",
+		},
+	}
+}
+
+// person is the struct being changed.
+type person struct {
+	Name string
+	Age  int
+}
+
+// fields draws a person and its two fields in a frame of its own.
+func fields(d *memviz.Diagram, frame, name string, p *person) {
+	d.Frame(frame).
+		Var(name, p).
+		Var(name+".Name", &p.Name).
+		Var(name+".Age", &p.Age)
+}
+
+// birthday takes a pointer to a person and makes them a year older.
+func birthday(e *event.Emitter, p *person) {
+	e.Step("inside-birthday")
+	e.Say("Inside birthday (pointer version):")
+	e.Address("p", p, "Address p points to")
+	e.Address("&p.Age", &p.Age, "Address of p.Age (the caller's field)")
+	e.Value("p.Age", p.Age, "p.Age before")
+
+	p.Age++ // the same as (*p).Age++
+
+	e.Value("p.Age", p.Age, "p.Age after p.Age++")
+	d := memviz.New()
+	fields(d, "Run", "alice", p)
+	d.Frame("birthday").Var("p", &p)
+	e.Diagram(d)
+}
+
+// birthdayCopy takes a person by value and makes the copy a year older.
+func birthdayCopy(e *event.Emitter, p person) {
+	e.Step("inside-birthdayCopy")
+	e.Say("Inside birthdayCopy (value version):")
+	e.Address("&p", &p, "Address of p (a new struct)")
+	e.Address("&p.Age", &p.Age, "Address of p.Age (a new field)")
+	e.Value("p.Age", p.Age, "p.Age before")
+
+	p.Age++
+	e.Warn("p is a copy of alice, fields and all: changing p.Age cannot change alice.Age.")
+
+	e.Value("p.Age", p.Age, "p.Age after p.Age++")
+	d := memviz.New()
+	fields(d, "birthdayCopy", "p", &p)
+	e.Diagram(d)
+}
+
+// Run makes a person, then gives it a birthday through a pointer and
+// through a copy.
+func (structExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Declare a struct and show it and its fields.
+	e.Step("initial")
+	alice := person{Name: "Alice", Age: 30}
+	e.Say("Initial state:")
+	e.Address("&alice", &alice, "Address of alice")
+	e.Address("&alice.Name", &alice.Name, "Address of alice.Name")
+	e.Address("&alice.Age", &alice.Age, "Address of alice.Age")
+	e.Value("alice", alice, "Value of alice")
+	e.Value("offset", unsafe.Offsetof(alice.Age), "unsafe.Offsetof(alice.Age) (bytes; Name's string header comes first)")
+	d := memviz.New()
+	fields(d, "Run", "alice", &alice)
+	e.Diagram(d)
+	check.That(unsafe.Pointer(&alice) == unsafe.Pointer(&alice.Name), "the first field starts at the struct's address")
+
+	// 2. Pass a pointer: the function changes alice.
+	birthday(e, &alice)
+
+	e.Step("after-birthday")
+	e.Say("After birthday (pointer version):")
+	e.Address("&alice", &alice, "Address of alice (unchanged)")
+	e.Value("alice", alice, "Value of alice")
+	assert.Equal(check, "birthday(&alice) changes alice.Age through the pointer", alice.Age, 31)
+
+	// 3. Pass a copy: the function changes its copy only.
+	birthdayCopy(e, alice)
+
+	e.Step("after-birthdayCopy")
+	e.Say("After birthdayCopy (value version):")
+	e.Address("&alice", &alice, "Address of alice (unchanged)")
+	e.Value("alice", alice, "Value of alice")
+	assert.Equal(check, "birthdayCopy(alice) leaves alice.Age unchanged", alice.Age, 31)
+	return errors.Join(e.Err(), check.Err())
+}
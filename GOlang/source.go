@@ -0,0 +1,11 @@
+// Package golang gives tools access to the source code of the examples.
+// The example packages themselves live in the subdirectories.
+package golang
+
+import "embed"
+
+// Sources holds every file below the GOlang directory. The source of an
+// example is at "<name>.go", where name is its registry name.
+//
+//go:embed *
+var Sources embed.FS
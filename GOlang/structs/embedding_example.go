@@ -0,0 +1,294 @@
+// Package structs contains examples about struct types: what embedding
+// one type in another promotes, what it doesn't, and how embedding an
+// interface gives a type methods it never implemented.
+package structs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/assert"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func init() {
+	registry.Register(embeddingExample{})
+}
+
+// embeddingExample embeds structs in structs and an interface in a
+// struct. Every method records its own name when it runs, so that each
+// step can check which of several methods with the same name a call
+// reached.
+type embeddingExample struct{}
+
+func (embeddingExample) Describe() registry.Metadata {
+	return registry.Metadata{
+		Name:          "structs/embedding_example",
+		Topic:         "structs",
+		Level:         registry.Intermediate,
+		Description:   "struct embedding: promoted fields and methods, shadowing, ambiguous names and partial mocks",
+		Tags:          []string{"structs", "embedding", "methods", "interfaces", "testing"},
+		Prerequisites: []string{"interfaces/dispatch_example"},
+	}
+}
+
+// Explain gives step-through mode a sentence to read before each step.
+func (embeddingExample) Explain(step string) string {
+	switch step {
+	case "promotion":
+		return "An embedded field is a field named after its type. Its fields and methods are promoted: c.ID is short for c.Entity.ID, the same variable."
+	case "shadowing":
+		return "A field or method of the outer type hides a promoted one of the same name. The embedded type's own methods still call its own methods: embedding is not inheritance."
+	case "collision":
+		return "Two embedded types with the same name at the same depth cancel out. Using the name is a compile error, and the method is in neither type's method set."
+	case "partial-mock":
+		return "A struct that embeds an interface has all its methods, forwarded to whatever value the field holds. Override the ones a test needs; calling the rest with a nil field panics."
+	}
+	return ""
+}
+
+// Questions are asked by "concepts quiz structs".
+func (embeddingExample) Questions() []quiz.Question {
+	return []quiz.Question{
+		{
+			Prompt:  "Customer embeds Entity and declares its own Describe. Entity.Summary calls e.Describe(). Which Describe runs for c.Summary()?",
+			Choices: []string{"Customer's", "Entity's"},
+			Answer:  "Entity's",
+			Explain: "c.Summary() is c.Entity.Summary(), whose receiver is an Entity. Go has no virtual methods: Entity never knows it is inside a Customer.",
+		},
+		{
+			Prompt:  "Order embeds Entity and Audit, which both have a Name field. Does o.Name compile?",
+			Choices: []string{"yes, Entity's comes first", "no, it is ambiguous"},
+			Answer:  "no, it is ambiguous",
+			Explain: "Both are at depth one, so neither wins. o.Entity.Name and o.Audit.Name are fine.",
+		},
+		{
+			Prompt:  "type mock struct{ Store }; m := mock{}. What does m.Delete(\"k\") do, if mock declares no Delete?",
+			Choices: []string{"it doesn't compile", "it does nothing", "it panics"},
+			Answer:  "it panics",
+			Explain: "The call is m.Store.Delete(\"k\"), and m.Store is a nil interface.",
+		},
+	}
+}
+
+// Flashcards are reviewed by "concepts review".
+func (embeddingExample) Flashcards() []flashcard.Card {
+	return []flashcard.Card{
+		{
+			Front: "Which of an embedded T's methods does the outer struct S get?",
+			Back:  "S has T's value-receiver methods; *S has those and T's pointer-receiver ones too. Embed *T and both S and *S get them all.",
+		},
+		{
+			Front: "Why embed an interface in a test double?",
+			Back:  "To satisfy a large interface while implementing only the methods the code under test calls. A call to any other method panics, which shows a test it relies on more than it thought.",
+		},
+	}
+}
+
+// trail records which methods ran, in order.
+type trail []string
+
+func (t *trail) add(s string) { *t = append(*t, s) }
+
+// ran is the trail every method of this file adds itself to.
+var ran trail
+
+// Entity is what customers and orders have in common.
+type Entity struct {
+	ID   int
+	Name string
+}
+
+// Describe says which entity e is.
+func (e Entity) Describe() string {
+	ran.add("Entity.Describe")
+	return fmt.Sprintf("entity %d", e.ID)
+}
+
+// Summary calls Describe on its own receiver, which is always an Entity.
+func (e Entity) Summary() string {
+	ran.add("Entity.Summary")
+	return "summary of " + e.Describe()
+}
+
+// Rename changes e's name, through a pointer receiver.
+func (e *Entity) Rename(name string) {
+	ran.add("Entity.Rename")
+	e.Name = name
+}
+
+// Audit says who changed something last.
+type Audit struct {
+	Name string
+	By   string
+}
+
+// Describe says who changed a.
+func (a Audit) Describe() string {
+	ran.add("Audit.Describe")
+	return "changed by " + a.By
+}
+
+// Customer embeds an Entity, and shadows its Describe with one of its own.
+type Customer struct {
+	Entity
+	Email string
+}
+
+// Describe says who c is, hiding Entity's Describe.
+func (c Customer) Describe() string {
+	ran.add("Customer.Describe")
+	return fmt.Sprintf("customer %s <%s>", c.Name, c.Email)
+}
+
+// Order embeds Entity and Audit, whose Name fields and Describe methods
+// collide.
+type Order struct {
+	Entity
+	Audit
+	Total int
+}
+
+// Store is the large interface a greeter needs only one method of.
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+	Delete(key string) error
+}
+
+// greet is the code under test: it only ever calls Get.
+func greet(s Store, user string) string {
+	name, err := s.Get("name:" + user)
+	if err != nil {
+		return "hello, stranger"
+	}
+	return "hello, " + name
+}
+
+// memStore is a real Store, kept in a map.
+type memStore map[string]string
+
+func (m memStore) Get(key string) (string, error) {
+	ran.add("memStore.Get")
+	v, ok := m[key]
+	if !ok {
+		return "", errors.New("no " + key)
+	}
+	return v, nil
+}
+
+func (m memStore) Put(key, value string) error {
+	ran.add("memStore.Put")
+	m[key] = value
+	return nil
+}
+
+func (m memStore) Delete(key string) error {
+	ran.add("memStore.Delete")
+	delete(m, key)
+	return nil
+}
+
+// getOnly is a partial mock: it implements Get and leaves the rest of
+// Store to the embedded interface, which it never sets.
+type getOnly struct {
+	Store
+	names map[string]string
+}
+
+func (g getOnly) Get(key string) (string, error) {
+	ran.add("getOnly.Get")
+	return g.names[strings.TrimPrefix(key, "name:")], nil
+}
+
+// fullDisk wraps a real Store and overrides Put to fail; Get and Delete
+// go to the Store inside.
+type fullDisk struct {
+	Store
+}
+
+func (fullDisk) Put(key, value string) error {
+	ran.add("fullDisk.Put")
+	return errors.New("disk full")
+}
+
+// hasMethod reports whether v's method set has a method called name.
+func hasMethod(v any, name string) bool {
+	_, ok := reflect.TypeOf(v).MethodByName(name)
+	return ok
+}
+
+// Run embeds, promotes, shadows and mocks, and checks which method ran.
+func (embeddingExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	check := assert.New()
+
+	// 1. Promoted fields and methods.
+	e.Step("promotion")
+	c := Customer{Entity: Entity{ID: 7, Name: "Ada"}, Email: "ada@example.com"}
+	e.Value("c.ID", c.ID, "c.ID, promoted from c.Entity.ID")
+	check.That(&c.ID == &c.Entity.ID, "the promoted field is the embedded one, not a copy")
+	c.Rename("Grace")
+	e.Value("c.Name", c.Name, "c.Name after c.Rename(\"Grace\")")
+	assert.Equal(check, "Rename's pointer receiver is &c.Entity", c.Entity.Name, "Grace")
+	e.Value("Customer has Rename", hasMethod(c, "Rename"), "Rename is in Customer's method set")
+	e.Value("*Customer has Rename", hasMethod(&c, "Rename"), "and in *Customer's")
+	check.That(!hasMethod(c, "Rename") && hasMethod(&c, "Rename"), "a pointer-receiver method is only promoted to *Customer")
+
+	// 2. The outer type's names win, but the inner type doesn't know it.
+	e.Step("shadowing")
+	ran = nil
+	e.Value("c.Describe()", c.Describe(), "c.Describe()")
+	e.Value("c.Entity.Describe()", c.Entity.Describe(), "c.Entity.Describe(), named explicitly")
+	assert.Equal(check, "Customer's Describe shadows Entity's", fmt.Sprint(ran), "[Customer.Describe Entity.Describe]")
+	ran = nil
+	e.Value("c.Summary()", c.Summary(), "c.Summary(), promoted from Entity")
+	e.Value("ran", fmt.Sprint(ran), "What ran")
+	assert.Equal(check, "Entity.Summary calls Entity.Describe, not the Customer's", fmt.Sprint(ran), "[Entity.Summary Entity.Describe]")
+	e.Warn("Embedding is not inheritance: an embedded type's methods can't call the outer type's.")
+
+	// 3. Colliding names at the same depth.
+	e.Step("collision")
+	o := Order{Entity: Entity{ID: 42, Name: "order"}, Audit: Audit{Name: "audit", By: "ops"}, Total: 99}
+	// o.Name       // ambiguous selector o.Name
+	// o.Describe() // ambiguous selector o.Describe
+	e.Value("o.Entity.Name", o.Entity.Name, "o.Entity.Name")
+	e.Value("o.Audit.Name", o.Audit.Name, "o.Audit.Name")
+	e.Value("Order has Describe", hasMethod(o, "Describe"), "Describe is in Order's method set")
+	e.Value("Order has Summary", hasMethod(o, "Summary"), "Summary is, from Entity alone")
+	check.That(!hasMethod(o, "Describe"), "two Describes at the same depth leave Order with neither")
+	ran = nil
+	o.Summary()
+	assert.Equal(check, "Summary is promoted from Entity and uses Entity's Describe", fmt.Sprint(ran), "[Entity.Summary Entity.Describe]")
+	_, ok := any(o).(interface{ Describe() string })
+	check.That(!ok, "so Order doesn't satisfy an interface with Describe")
+
+	// 4. Embedding an interface: partial mocks.
+	e.Step("partial-mock")
+	ran = nil
+	mock := getOnly{names: map[string]string{"ada": "Ada"}}
+	e.Value("greet(mock)", greet(mock, "ada"), "greet with a mock that only implements Get")
+	assert.Equal(check, "greet reached the mock's Get", fmt.Sprint(ran), "[getOnly.Get]")
+	r := assert.Panics(func() { mock.Put("k", "v") })
+	e.Value("recovered", r, "What mock.Put panicked with")
+	check.That(r != nil, "Put goes to the embedded Store, which is nil")
+	ran = nil
+	store := memStore{"name:ada": "Ada"}
+	disk := fullDisk{store}
+	err := disk.Put("name:tim", "Tim")
+	e.Value("err", err, "disk.Put, overridden to fail")
+	e.Value("greet(disk)", greet(disk, "ada"), "greet with the same wrapper")
+	_ = disk.Delete("name:ada")
+	e.Value("ran", fmt.Sprint(ran), "What ran")
+	assert.Equal(check, "the override ran for Put and the real store for the rest", fmt.Sprint(ran), "[fullDisk.Put memStore.Get memStore.Delete]")
+	check.That(len(store) == 0, "Delete reached the map inside")
+	e.Say("A nil embedded interface turns an unexpected call into a panic whose stack trace names the method. In a test that is exactly what you want to see.")
+	return errors.Join(e.Err(), check.Err())
+}
@@ -0,0 +1,116 @@
+//go:build js && wasm
+
+// Command concepts-wasm runs the example catalog inside a web browser, so
+// learners without a Go toolchain (on a Chromebook, say) can still run the
+// examples. A browser has no processes, so the examples that start one of
+// their own or signal themselves (registry.Metadata.NeedsProcess) are left
+// out. Build it and serve the directory with any static file server:
+//
+//	GOOS=js GOARCH=wasm go build -o cmd/concepts-wasm/concepts.wasm ./cmd/concepts-wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" cmd/concepts-wasm/
+//	python3 -m http.server -d cmd/concepts-wasm
+//
+// The program exposes two functions to JavaScript: concepts.list() returns
+// the examples' metadata as JSON, and concepts.run(name) returns a Promise
+// of the example's events as JSON (the format of "concepts run
+// -format=json"). index.html is a minimal page built on them.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"syscall/js"
+
+	golang "github.com/amandm/programming-concepts/GOlang"
+	_ "github.com/amandm/programming-concepts/GOlang/all"
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// example is what concepts.list reports per example.
+type example struct {
+	Name        string   `json:"name"`
+	Topic       string   `json:"topic"`
+	Level       string   `json:"level"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Source      string   `json:"source"`
+}
+
+func main() {
+	js.Global().Set("concepts", js.ValueOf(map[string]any{
+		"list": js.FuncOf(list),
+		"run":  js.FuncOf(run),
+	}))
+	// Signal the page that the functions are in place, then keep the
+	// program alive for as long as the page is open.
+	js.Global().Call("dispatchEvent", js.Global().Get("Event").New("concepts-ready"))
+	select {}
+}
+
+func list(this js.Value, args []js.Value) any {
+	var all []example
+	for _, c := range registry.All() {
+		md := c.Describe()
+		if md.NeedsProcess {
+			continue
+		}
+		src, _ := fs.ReadFile(golang.Sources, md.Name+".go")
+		all = append(all, example{md.Name, md.Topic, md.Level.String(), md.Description, md.Tags, string(src)})
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return js.Global().Get("Error").New(err.Error())
+	}
+	return string(data)
+}
+
+// run returns a Promise instead of running the example right away: a
+// function called from JavaScript must not block, and examples that sleep
+// or start goroutines need the event loop to make progress.
+func run(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return js.Global().Get("Error").New("concepts.run wants one example name")
+	}
+	name := args[0].String()
+	var handler js.Func
+	handler = js.FuncOf(func(this js.Value, p []js.Value) any {
+		resolve, reject := p[0], p[1]
+		go func() {
+			defer handler.Release()
+			out, err := runExample(name)
+			if err != nil {
+				reject.Invoke(js.Global().Get("Error").New(err.Error()))
+				return
+			}
+			resolve.Invoke(out)
+		}()
+		return nil
+	})
+	return js.Global().Get("Promise").New(handler)
+}
+
+// runExample runs an example and returns its events as a JSON array.
+func runExample(name string) (string, error) {
+	c, ok := registry.Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("unknown example %q", name)
+	}
+	if c.Describe().NeedsProcess {
+		return "", fmt.Errorf("%s needs a process of its own, which a browser can't start: run it with the concepts command", name)
+	}
+	var events []event.Event
+	ctx := event.WithSink(context.Background(), event.SinkFunc(func(ev event.Event) error {
+		events = append(events, ev)
+		return nil
+	}))
+	var out bytes.Buffer
+	if err := c.Run(ctx, &out); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(events)
+	return string(data), err
+}
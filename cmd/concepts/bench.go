@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amandm/programming-concepts/internal/benchlab"
+	"github.com/amandm/programming-concepts/internal/style"
+)
+
+// runBench measures the experiments of the examples of a topic.
+func runBench(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	labs := benchlab.Find(args[0])
+	if len(labs) == 0 {
+		return fmt.Errorf("no benchmarks for topic %q", args[0])
+	}
+	width := min(style.Width(os.Stdout, 80), 100)
+	for _, l := range labs {
+		fmt.Printf("=== %s\n\n", l.Describe().Name)
+		for _, ex := range l.Experiments() {
+			fmt.Fprintf(os.Stderr, "measuring %s...\n", ex.Name)
+			if err := benchlab.Write(os.Stdout, ex, benchlab.Run(ex), width); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
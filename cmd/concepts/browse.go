@@ -0,0 +1,16 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/amandm/programming-concepts/internal/tui"
+)
+
+// runBrowse opens the interactive terminal browser.
+func runBrowse(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	return tui.Run(context.Background(), os.Stdin, os.Stdout, sources)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/exercise"
+	"github.com/amandm/programming-concepts/internal/progress"
+)
+
+// runCheck checks the learner's solution to an exercise. Without an
+// argument it lists the exercises.
+func runCheck(args []string) error {
+	if len(args) == 0 {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, ex := range exercise.All() {
+			fmt.Fprintf(tw, "%s\t%s\n", ex.Name, ex.Summary)
+		}
+		return tw.Flush()
+	}
+	if len(args) != 1 {
+		return errUsage
+	}
+	ex, ok := exercise.Lookup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown exercise %q (run \"concepts check\" to list them)", args[0])
+	}
+	root, err := findRoot()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checking %s (edit %s)\n\n", ex.Name, ex.Dir(root))
+	rep, err := exercise.Check(context.Background(), root, ex)
+	if err != nil {
+		return err
+	}
+	recordProgress(func(s *progress.Store, now time.Time) { s.RecordExercise(ex.Name, rep.Passed(), now) })
+	if rep.BuildError != "" {
+		fmt.Printf("Your solution doesn't compile:\n\n%s\n", rep.BuildError)
+		return errors.New("not solved yet")
+	}
+	passed := 0
+	for _, c := range rep.Cases {
+		if c.Passed {
+			passed++
+			fmt.Println("  ok  ", c.Name)
+		} else {
+			fmt.Printf("  FAIL %s\n       %s\n", c.Name, c.Detail)
+		}
+	}
+	fmt.Printf("\n%d of %d checks passed\n", passed, len(rep.Cases))
+	if !rep.Passed() {
+		return errors.New("not solved yet")
+	}
+	fmt.Println("Solved!")
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/compare"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/style"
+)
+
+// runCompare runs two variants of an example and prints them side by side.
+// An example with exactly two variants doesn't need them named.
+func runCompare(args []string) error {
+	flagSet := flag.NewFlagSet("compare", flag.ContinueOnError)
+	width := flagSet.Int("width", 0, "output width in `columns` (default the terminal's width, or 160)")
+	color := flagSet.String("color", defaultColor(), "when to highlight differences: auto, always or never")
+	if err := flagSet.Parse(args); err != nil || (flagSet.NArg() != 1 && flagSet.NArg() != 3) {
+		return errUsage
+	}
+	c, ok := registry.Lookup(flagSet.Arg(0))
+	if !ok {
+		return fmt.Errorf("unknown example %q (see \"concepts list\")", flagSet.Arg(0))
+	}
+	cmp, ok := c.(registry.Comparer)
+	if !ok {
+		return fmt.Errorf("%s has no variants to compare", flagSet.Arg(0))
+	}
+
+	variants := cmp.Variants()
+	var pair []registry.Variant
+	if flagSet.NArg() == 3 {
+		for _, name := range flagSet.Args()[1:] {
+			v, err := findVariant(variants, name)
+			if err != nil {
+				return err
+			}
+			pair = append(pair, v)
+		}
+	} else if len(variants) == 2 {
+		pair = variants
+	} else {
+		return fmt.Errorf("%s has %d variants; name the two to compare", flagSet.Arg(0), len(variants))
+	}
+
+	var sides [2]compare.Side
+	for i, v := range pair {
+		side, err := compare.Collect(context.Background(), v.Name, v.Run)
+		if err != nil {
+			return fmt.Errorf("%s: %w", v.Name, err)
+		}
+		sides[i] = side
+	}
+
+	st, err := style.ForMode(os.Stdout, *color)
+	if err != nil {
+		return err
+	}
+	opts := compare.Options{Width: *width, Style: st}
+	if opts.Width == 0 {
+		opts.Width = style.Width(os.Stdout, 160)
+	}
+	return compare.Render(os.Stdout, sides[0], sides[1], opts)
+}
+
+// findVariant returns the variant called name.
+func findVariant(variants []registry.Variant, name string) (registry.Variant, error) {
+	var names []string
+	for _, v := range variants {
+		if v.Name == name {
+			return v, nil
+		}
+		names = append(names, v.Name)
+	}
+	return registry.Variant{}, fmt.Errorf("unknown variant %q (have %s)", name, strings.Join(names, ", "))
+}
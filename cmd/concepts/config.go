@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/amandm/programming-concepts/internal/config"
+	"github.com/amandm/programming-concepts/internal/i18n"
+)
+
+// settings holds the config file (see the config package), loaded by main
+// before any command runs. Commands use it for the defaults of their flags.
+var settings config.Config
+
+// loadSettings reads the config file at path, or at its default location
+// if path is empty.
+func loadSettings(path string) error {
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			// Without a config directory there is no config file either.
+			return nil
+		}
+	}
+	c, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	settings = c
+	return nil
+}
+
+// defaultLanguage is the default of the -lang flags.
+func defaultLanguage() string {
+	if settings.Language != "" {
+		return settings.Language
+	}
+	return i18n.English
+}
+
+// defaultColor is the default of the -color flags.
+func defaultColor() string {
+	if settings.Color != "" {
+		return settings.Color
+	}
+	return "auto"
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/progress"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runDaily picks the concept of the day, explains it and records it. The
+// pick only depends on the date and on what the learner has done before,
+// so it stays the same all day, even after it has been run.
+func runDaily(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	s, err := openProgress()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	c, err := dailyPick(s, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Concept of the day (%s): %s\n\n", now.Format(time.DateOnly), c.Describe().Name)
+	if err := explainExample(c, os.Stdout); err != nil {
+		return err
+	}
+	name := c.Describe().Name
+	recordProgress(func(s *progress.Store, now time.Time) {
+		s.RecordDaily(name, now)
+		s.RecordRun(name, now)
+	})
+	return nil
+}
+
+// dailyPick returns the concept of the day for t: the one already picked
+// today, or else one of the examples that haven't been run yet (from the
+// preferred topics if any of them is left), chosen by hashing the date and
+// the examples already run. Once everything has been run, every example is
+// a candidate again.
+func dailyPick(s *progress.Store, t time.Time) (registry.Concept, error) {
+	date := t.Format(time.DateOnly)
+	if name, ok := s.Daily[date]; ok {
+		if c, ok := registry.Lookup(name); ok {
+			return c, nil
+		}
+	}
+
+	var todo, preferred, done []string
+	for _, c := range registry.All() {
+		md := c.Describe()
+		switch {
+		case s.Runs[md.Name].Count > 0:
+			done = append(done, md.Name)
+		case slices.Contains(settings.Topics, md.Topic):
+			preferred = append(preferred, md.Name)
+			fallthrough
+		default:
+			todo = append(todo, md.Name)
+		}
+	}
+	candidates := todo
+	switch {
+	case len(preferred) > 0:
+		candidates = preferred
+	case len(todo) == 0:
+		candidates = done
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("there are no examples to pick from")
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(date))
+	for _, name := range done {
+		h.Write([]byte("\x00" + name))
+	}
+	c, _ := registry.Lookup(candidates[h.Sum64()%uint64(len(candidates))])
+	return c, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/amandm/programming-concepts/internal/goldentest"
+	"github.com/amandm/programming-concepts/internal/lesson"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runDocs writes a Markdown lesson for every example, plus an index page.
+func runDocs(args []string) error {
+	flagSet := flag.NewFlagSet("docs", flag.ContinueOnError)
+	out := flagSet.String("out", "", "output `directory` (default docs in the repository root)")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() != 0 {
+		return errUsage
+	}
+	if *out == "" {
+		root, err := findRoot()
+		if err != nil {
+			return err
+		}
+		*out = filepath.Join(root, "docs")
+	}
+
+	var index bytes.Buffer
+	index.WriteString("# Lessons\n\n")
+	for _, c := range registry.All() {
+		md := c.Describe()
+		src, err := fs.ReadFile(sources, md.Name+".go")
+		if err != nil {
+			return err
+		}
+		var output bytes.Buffer
+		if err := c.Run(context.Background(), &output); err != nil {
+			return fmt.Errorf("running %s: %w", md.Name, err)
+		}
+		page, err := lesson.Generate(md, src, goldentest.Normalize(output.Bytes()))
+		if err != nil {
+			return fmt.Errorf("%s: %w", md.Name, err)
+		}
+
+		path := filepath.Join(*out, filepath.FromSlash(md.Name)+".md")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, page, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(&index, "- [%s](%s.md) – %s\n", md.Name, md.Name, md.Description)
+		fmt.Println("wrote", path)
+	}
+	return os.WriteFile(filepath.Join(*out, "README.md"), index.Bytes(), 0o644)
+}
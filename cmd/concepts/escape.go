@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	golang "github.com/amandm/programming-concepts/GOlang"
+	"github.com/amandm/programming-concepts/internal/escape"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runEscape compiles the package of each named example with -gcflags=-m
+// and prints the example's source annotated with the compiler's escape
+// analysis decisions. If the source has "escape:" comments saying what the
+// compiler should decide, it checks them too, and fails if any is wrong.
+func runEscape(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	root, err := findRoot()
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for i, name := range args {
+		c, ok := registry.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown example %q (see \"concepts list\")", name)
+		}
+		md := c.Describe()
+		src, err := fs.ReadFile(golang.Sources, md.Name+".go")
+		if err != nil {
+			return err
+		}
+		ds, err := escape.Analyze(context.Background(), root, "./"+path.Join(examplesDir, path.Dir(md.Name)))
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("=== %s ([heap]: moved to the heap, [stack]: stays on the stack, [leaks]: the pointer flows out)\n\n", md.Name)
+		ds = escape.ForFile(ds, md.Name+".go")
+		if err := escape.Annotate(os.Stdout, src, ds); err != nil {
+			return err
+		}
+		n, mismatches := escape.Check(src, ds)
+		if n == 0 {
+			continue
+		}
+		fmt.Println()
+		for _, m := range mismatches {
+			fmt.Printf("MISMATCH %s\n", m)
+		}
+		fmt.Printf("%d of %d \"escape:\" comments match the compiler\n", n-len(mismatches), n)
+		if len(mismatches) > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("some \"escape:\" comments don't match the compiler's decisions")
+	}
+	return nil
+}
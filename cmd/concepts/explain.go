@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/explain"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runExplain prints an example's source, step by step, with the values an
+// instrumented run observed.
+func runExplain(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	c, ok := registry.Lookup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown example %q (see \"concepts list\")", args[0])
+	}
+	return explainExample(c, os.Stdout)
+}
+
+// explainExample runs c with caller information and writes the explained
+// source to w.
+func explainExample(c registry.Concept, w io.Writer) error {
+	md := c.Describe()
+	src, err := fs.ReadFile(sources, md.Name+".go")
+	if err != nil {
+		return err
+	}
+
+	var events []event.Event
+	ctx := event.WithCallers(event.WithSink(context.Background(), event.SinkFunc(func(ev event.Event) error {
+		events = append(events, ev)
+		return nil
+	})))
+	if err := c.Run(ctx, io.Discard); err != nil {
+		return err
+	}
+
+	var why func(string) string
+	if ex, ok := c.(registry.Explainer); ok {
+		why = ex.Explain
+	}
+	fmt.Fprintf(w, "=== %s: %s\n\n", md.Name, md.Description)
+	return explain.Write(w, path.Join(examplesDir, md.Name+".go"), src, events, why)
+}
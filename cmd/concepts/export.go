@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/amandm/programming-concepts/internal/anki"
+)
+
+// runExport writes the quiz questions and flashcards in another tool's
+// format. Anki is the only format so far.
+func runExport(args []string) error {
+	flagSet := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := flagSet.String("o", "", "output `file` (default standard output)")
+	base := flagSet.String("base", "https://"+modulePath+"/blob/main", "`URL` of the repository tree that source links point into")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() != 1 {
+		return errUsage
+	}
+	if flagSet.Arg(0) != "anki" {
+		return fmt.Errorf("unknown export format %q (supported: anki)", flagSet.Arg(0))
+	}
+
+	notes := anki.Notes(func(name string) string {
+		return *base + "/" + path.Join(examplesDir, name+".go")
+	})
+	if *out == "" {
+		return anki.WriteCSV(os.Stdout, notes)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := anki.WriteCSV(f, notes); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d notes to %s\n", len(notes), *out)
+	return nil
+}
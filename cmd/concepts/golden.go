@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/amandm/programming-concepts/internal/goldentest"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runGolden compares the output of examples with their golden files, or
+// records new golden files with -update. Without example names it checks
+// every registered example, which is what CI runs.
+func runGolden(args []string) error {
+	fs := flag.NewFlagSet("golden", flag.ContinueOnError)
+	update := fs.Bool("update", false, "rewrite the golden files instead of comparing against them")
+	dir := fs.String("dir", "", "`directory` holding the golden files (default testdata/golden in the repository root)")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *dir == "" {
+		root, err := findRoot()
+		if err != nil {
+			return err
+		}
+		*dir = filepath.Join(root, "testdata", "golden")
+	}
+
+	var concepts []registry.Concept
+	if fs.NArg() == 0 {
+		concepts = registry.All()
+	}
+	for _, name := range fs.Args() {
+		c, ok := registry.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown example %q (see \"concepts list\")", name)
+		}
+		concepts = append(concepts, c)
+	}
+
+	failed := 0
+	for _, c := range concepts {
+		err := goldentest.Check(context.Background(), c, *dir, *update)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "FAIL", err)
+			failed++
+			continue
+		}
+		if *update {
+			fmt.Println("updated", c.Describe().Name)
+		} else {
+			fmt.Println("ok", c.Describe().Name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d examples failed", failed, len(concepts))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"path/filepath"
+
+	"github.com/amandm/programming-concepts/internal/grade"
+)
+
+// runGrade starts the grading server.
+func runGrade(args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8081", "`address` to listen on")
+	results := fs.String("results", "grades.json", "`file` the results are kept in")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return errUsage
+	}
+	path, err := filepath.Abs(*results)
+	if err != nil {
+		return err
+	}
+	store, err := grade.OpenStore(path)
+	if err != nil {
+		return err
+	}
+	log.Printf("grading submissions on http://%s/, results in %s", *addr, path)
+	return http.ListenAndServe(*addr, grade.NewHandler(store))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amandm/programming-concepts/internal/exercise"
+	"github.com/amandm/programming-concepts/internal/goldentest"
+)
+
+// runHint reveals the next hint of an exercise. After the last hint it
+// shows how the learner's file differs from the reference solution. The
+// number of hints used is kept in the progress store.
+func runHint(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	ex, ok := exercise.Lookup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown exercise %q (run \"concepts check\" to list them)", args[0])
+	}
+	s, err := openProgress()
+	if err != nil {
+		return err
+	}
+	n := min(s.Exercises[ex.Name].Hints+1, len(ex.Hints)+1)
+
+	for i, h := range ex.Hints[:min(n, len(ex.Hints))] {
+		fmt.Printf("Hint %d of %d: %s\n\n", i+1, len(ex.Hints), h)
+	}
+	if n > len(ex.Hints) {
+		if err := showSolution(ex); err != nil {
+			return err
+		}
+	} else {
+		next := "the next hint"
+		if n == len(ex.Hints) {
+			next = "the reference solution"
+		}
+		fmt.Printf("Still stuck? \"concepts hint %s\" again shows %s.\n", ex.Name, next)
+	}
+
+	s.RecordHint(ex.Name, n)
+	return s.Save()
+}
+
+// showSolution prints the changes that turn the learner's file into the
+// reference solution.
+func showSolution(ex exercise.Exercise) error {
+	root, err := findRoot()
+	if err != nil {
+		return err
+	}
+	path := ex.File(root)
+	mine, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	solution, err := ex.Solution()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The reference solution (lines starting with - are yours, + the solution's):\n\n")
+	fmt.Println(goldentest.Diff(mine, solution))
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/amandm/programming-concepts/internal/layout"
+)
+
+// runLayout prints the memory layout of each named struct type, and how
+// much reordering its fields would save. Without arguments it lists the
+// types the examples make available.
+func runLayout(args []string) error {
+	types := layout.All()
+	if len(args) == 0 {
+		for _, name := range slices.Sorted(maps.Keys(types)) {
+			fmt.Printf("%-24s (from %s)\n", name, types[name].Example)
+		}
+		return nil
+	}
+	for i, name := range args {
+		entry, ok := types[name]
+		if !ok {
+			return fmt.Errorf("unknown type %q (run \"concepts layout\" to list them)", name)
+		}
+		l, err := layout.Of(entry.Type)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(l)
+		r := l.Reordered()
+		if r.Size >= l.Size {
+			fmt.Println("\nNo other field order makes it smaller.")
+			continue
+		}
+		fmt.Printf("\nSorted by alignment, the fields take %d bytes instead of %d:\n\n%s\n", r.Size, l.Size, r)
+	}
+	return nil
+}
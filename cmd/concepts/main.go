@@ -0,0 +1,171 @@
+// Command concepts is a single entry point for every example in this
+// repository. Instead of running each example file by hand, you can list
+// them and run any one of them by name:
+//
+//	concepts list
+//	concepts run pointers/function_example
+//	concepts run pointers/function_example -- -some-flag value
+//	concepts run -format=json pointers/function_example
+//	concepts run -step pointers/function_example
+//	concepts run -lang=es pointers/function_example
+//	concepts run -record=replay.html pointers/function_example
+//	concepts run -trace=trace.out -view pointers/function_example
+//	concepts run -profile=heap memory/escape_example
+//	concepts profile-walkthrough memory/hotspot_example
+//	concepts -config classroom.yaml run pointers/function_example
+//	concepts explain pointers/function_example
+//	concepts bench pointers
+//	concepts race concurrency/races/racy_counter
+//	concepts layout memory.badOrder
+//	concepts compare pointers/function_example
+//	concepts review
+//	concepts daily
+//	concepts export -o deck.csv anki
+//	concepts new pointers/nil_example
+//	concepts browse
+//	concepts serve -addr localhost:8080
+//	concepts grpc -addr localhost:9090
+//
+// The examples come from the registry; importing GOlang/all links every
+// example package into the binary. Example packs from other modules (see
+// the concepts package) are loaded from the pack directories at startup.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+
+	golang "github.com/amandm/programming-concepts/GOlang"
+	_ "github.com/amandm/programming-concepts/GOlang/all"
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/plugin"
+)
+
+// command is one subcommand of the CLI, e.g. "list" or "run".
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+// commands returns every subcommand in the order they are shown in the help text.
+func commands() []command {
+	return []command{
+		{"list", "list available examples (-topic, -level, -tag filter them)", runList},
+		{"run", "run an example by name, passing any extra arguments through", runRun},
+		{"explain", "read an example's source step by step, with the values a run observed", runExplain},
+		{"compare", "run two variants of an example side by side, e.g. \"concepts compare pointers/function_example\"", runCompare},
+		{"search", "find examples by words in their name, description, comments and code", runSearch},
+		{"repl", "try out Go snippets, optionally next to an example: \"concepts repl pointers/function_example\"", runREPL},
+		{"quiz", "answer questions about the examples of a topic, e.g. \"concepts quiz pointers\"", runQuiz},
+		{"hint", "reveal the next hint for an exercise; after the last one, the solution", runHint},
+		{"review", "go through the flashcards that are due today (spaced repetition)", runReview},
+		{"export", "export the quiz questions and flashcards as an Anki deck: \"concepts export -o deck.csv anki\"", runExport},
+		{"check", "check your solution to an exercise (without arguments: list the exercises)", runCheck},
+		{"grade", "run a grading server that checks uploaded exercise solutions (-addr, -results)", runGrade},
+		{"progress", "show which examples, quizzes and exercises you have done", runProgress},
+		{"daily", "explain the concept of the day, picked from the examples you haven't run yet", runDaily},
+		{"next", "suggest the next example whose prerequisites you have done", runNext},
+		{"path", "print the learning path with every example's prerequisites", runPath},
+		{"share", "upload an example to the Go Playground and print its link (-print just shows it)", runShare},
+		{"new", "create the skeleton of a new example, e.g. \"concepts new pointers/nil_example\"", runNew},
+		{"golden", "check example output against the golden files (-update rewrites them)", runGolden},
+		{"docs", "generate a Markdown lesson per example into docs/ (-out changes the directory)", runDocs},
+		{"bench", "benchmark the approaches the examples of a topic compare, e.g. \"concepts bench pointers\"", runBench},
+		{"race", "run a racy example normally and with -race, and explain the race report", runRace},
+		{"escape", "show the compiler's escape-analysis decisions next to an example's source", runEscape},
+		{"profile-walkthrough", "find the line that allocates the most with a heap profile, step by step (default memory/hotspot_example)", runProfileWalkthrough},
+		{"layout", "show a struct's fields, offsets and padding, e.g. \"concepts layout memory.badOrder\"", runLayout},
+		{"browse", "explore the examples in an interactive terminal browser", runBrowse},
+		{"grpc", "serve the Concepts gRPC service (see internal/rpc/conceptspb/concepts.proto; -addr sets the address)", runGRPC},
+		{"serve", "browse and run the examples in a web browser, with a JSON API under /api/ (-addr sets the address)", runServe},
+	}
+}
+
+// errUsage is returned when the arguments don't make sense; main prints the help text for it.
+var errUsage = errors.New("usage")
+
+func main() {
+	isolate.Main()
+	code := run()
+	// Examples that crash a process of their own may have left a copy of
+	// the executable behind.
+	isolate.Cleanup()
+	os.Exit(code)
+}
+
+// run runs the subcommand on the command line and returns the exit status.
+func run() int {
+	flag.Usage = usage
+	configPath := flag.String("config", "", "read settings from `file` instead of concepts/config.yaml in the user config directory")
+	progressPath := flag.String("progress", "", "record progress in `file` (overrides the config file)")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		return 2
+	}
+
+	if err := loadSettings(*configPath); err != nil {
+		fmt.Fprintln(os.Stderr, "concepts:", err)
+		return 1
+	}
+	if *progressPath != "" {
+		settings.Progress = *progressPath
+	}
+	loadPacks()
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, cmd := range commands() {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(args); err != nil {
+			if errors.Is(err, errUsage) {
+				usage()
+				return 2
+			}
+			fmt.Fprintf(os.Stderr, "concepts %s: %v\n", name, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "concepts: unknown command %q\n\n", name)
+	usage()
+	return 2
+}
+
+// sources holds the source file of every example, built-in or from a pack.
+var sources fs.FS = golang.Sources
+
+// loadPacks registers the examples of the installed packs. A broken pack
+// only produces a warning, so it can't lock the learner out of the
+// built-in examples.
+func loadPacks() {
+	dirs, err := plugin.DefaultDirs()
+	if err == nil {
+		err = plugin.Load(context.Background(), dirs)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "concepts: warning: loading example packs:", err)
+	}
+	sources = plugin.Sources(golang.Sources)
+}
+
+// usage prints the list of subcommands to stderr.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: concepts [-config file] [-progress file] <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	tw := tabwriter.NewWriter(os.Stderr, 0, 4, 1, ' ', 0)
+	for _, cmd := range commands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.usage)
+	}
+	tw.Flush()
+	fmt.Fprintln(os.Stderr, "\nDefaults for the flags can be set in concepts/config.yaml in the user config directory.")
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/amandm/programming-concepts/internal/scaffold"
+)
+
+// runNew creates the skeleton of a new example and records its golden
+// file, so that "concepts golden" covers it from the start.
+func runNew(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	name := args[0]
+	root, err := findRoot()
+	if err != nil {
+		return err
+	}
+	written, err := scaffold.Create(filepath.Join(root, examplesDir), name)
+	for _, path := range written {
+		fmt.Println("wrote", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// This binary doesn't contain the new example yet, so the golden file
+	// is recorded by a freshly built one.
+	cmd := exec.Command("go", "run", "./cmd/concepts", "golden", "-update", name)
+	cmd.Dir = root
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("recording the golden file: %w", err)
+	}
+	fmt.Printf("\nNow fill in the TODOs in %s, then run\n\n\tconcepts golden -update %s\n\nto record its real output.\n", written[0], name)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/curriculum"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// learningPath loads the curriculum and the learner's progress. An example
+// counts as done once it has been run.
+func learningPath() (*curriculum.Path, func(string) bool, error) {
+	p, err := curriculum.New(registry.All())
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := openProgress()
+	if err != nil {
+		return nil, nil, err
+	}
+	done := func(name string) bool { return s.Runs[name].Count > 0 }
+	return p, done, nil
+}
+
+// runNext suggests the next example to look at.
+func runNext(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	p, done, err := learningPath()
+	if err != nil {
+		return err
+	}
+	n, ok := nextPreferred(p, done, settings.Topics)
+	if !ok {
+		fmt.Println("You have been through every example. Well done!")
+		return nil
+	}
+	fmt.Printf("Next up: %s\n  %s\n\nRun it with: concepts run %s\n", n.Name, n.Description, n.Name)
+	if len(n.Prerequisites) > 0 {
+		fmt.Printf("(unlocked by %s)\n", strings.Join(n.Prerequisites, ", "))
+	}
+	return nil
+}
+
+// nextPreferred is like p.Next, but suggests an example of one of the
+// preferred topics (see the config file) while any of them is unlocked.
+func nextPreferred(p *curriculum.Path, done func(string) bool, topics []string) (curriculum.Node, bool) {
+	for _, n := range p.Nodes() {
+		if slices.Contains(topics, n.Topic) && !done(n.Name) && p.Unlocked(n.Name, done) {
+			return n, true
+		}
+	}
+	return p.Next(done)
+}
+
+// runPath prints the whole learning path, level by level, marking what is
+// done ([x]), what is unlocked ([>]) and what is still locked ([ ]).
+func runPath(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	p, done, err := learningPath()
+	if err != nil {
+		return err
+	}
+
+	depth := -1
+	for _, n := range p.Nodes() {
+		if n.Depth != depth {
+			depth = n.Depth
+			fmt.Printf("\nLevel %d\n", depth)
+		}
+		mark := "[ ]"
+		switch {
+		case done(n.Name):
+			mark = "[x]"
+		case p.Unlocked(n.Name, done):
+			mark = "[>]"
+		}
+		line := fmt.Sprintf("  %s %s", mark, n.Name)
+		if len(n.Prerequisites) > 0 {
+			line += "  <- " + strings.Join(n.Prerequisites, ", ")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amandm/programming-concepts/internal/profiling"
+)
+
+// profileRun runs run while capturing a profile of the given kind, then
+// prints a summary of it to stderr, below the example's own output.
+func profileRun(kind, dir, name string, run func() error) error {
+	k, err := profiling.ParseKind(kind)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		if dir, err = profiling.DefaultDir(); err != nil {
+			return err
+		}
+	}
+	prof, err := profiling.Capture(k, dir, name, run)
+	if prof == nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr)
+	if sumErr := prof.WriteSummary(os.Stderr, 10); err == nil {
+		err = sumErr
+	}
+	return err
+}
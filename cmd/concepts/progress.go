@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/exercise"
+	"github.com/amandm/programming-concepts/internal/progress"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// openProgress opens the learner's progress store: the one the settings
+// name, or the default one.
+func openProgress() (*progress.Store, error) {
+	if settings.Progress != "" {
+		return progress.Open(settings.Progress)
+	}
+	path, err := progress.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return progress.Open(path)
+}
+
+// recordProgress applies update to the progress store and saves it.
+// Progress is a convenience, so problems are reported as warnings instead
+// of failing the command that was actually asked for.
+func recordProgress(update func(s *progress.Store, now time.Time)) {
+	s, err := openProgress()
+	if err == nil {
+		update(s, time.Now())
+		err = s.Save()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "concepts: warning: could not record progress:", err)
+	}
+}
+
+// runProgress shows what the learner has covered so far.
+func runProgress(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	s, err := openProgress()
+	if err != nil {
+		return err
+	}
+
+	all := registry.All()
+	ran := 0
+	for _, c := range all {
+		if s.Runs[c.Describe().Name].Count > 0 {
+			ran++
+		}
+	}
+	fmt.Printf("Examples run: %d of %d\n", ran, len(all))
+	for _, c := range all {
+		name := c.Describe().Name
+		if r := s.Runs[name]; r.Count > 0 {
+			fmt.Printf("  [x] %-40s %d run(s), last %s\n", name, r.Count, r.Last.Format(time.DateOnly))
+		} else {
+			fmt.Printf("  [ ] %s\n", name)
+		}
+	}
+
+	fmt.Println("\nQuizzes:")
+	for _, topic := range quizTopics() {
+		q, ok := s.Quizzes[topic]
+		switch {
+		case !ok:
+			fmt.Printf("  [ ] %s\n", topic)
+		case q.Passed:
+			fmt.Printf("  [x] %-40s best %d/%d\n", topic, q.BestCorrect, q.BestTotal)
+		default:
+			fmt.Printf("  [ ] %-40s best %d/%d, not passed yet\n", topic, q.BestCorrect, q.BestTotal)
+		}
+	}
+
+	fmt.Println("\nExercises:")
+	for _, ex := range exercise.All() {
+		p := s.Exercises[ex.Name]
+		hints := ""
+		switch {
+		case p.Hints > len(ex.Hints):
+			hints = ", looked at the solution"
+		case p.Hints > 0:
+			hints = fmt.Sprintf(", %d hint(s)", p.Hints)
+		}
+		switch {
+		case p.Solved:
+			fmt.Printf("  [x] %-40s solved %s%s\n", ex.Name, p.SolvedAt.Format(time.DateOnly), hints)
+		case p.Attempts > 0 || p.Hints > 0:
+			fmt.Printf("  [ ] %-40s %d attempt(s)%s\n", ex.Name, p.Attempts, hints)
+		default:
+			fmt.Printf("  [ ] %s\n", ex.Name)
+		}
+	}
+	fmt.Printf("\n(stored in %s)\n", s.Path())
+	return nil
+}
+
+// quizTopics returns the topics that have at least one quiz question.
+func quizTopics() []string {
+	seen := map[string]bool{}
+	for _, c := range registry.All() {
+		if _, ok := c.(quiz.Quizzer); ok {
+			seen[c.Describe().Topic] = true
+		}
+	}
+	topics := make([]string, 0, len(seen))
+	for t := range seen {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+	return topics
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/progress"
+	"github.com/amandm/programming-concepts/internal/quiz"
+)
+
+// runQuiz asks the questions of every example in a topic and prints the
+// score at the end.
+func runQuiz(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	items := quiz.ForTopic(args[0])
+	if len(items) == 0 {
+		return errors.New("no questions for topic " + args[0])
+	}
+
+	res, err := quiz.NewSession(os.Stdin, os.Stdout).Ask(items)
+	if err != nil {
+		return err
+	}
+	if res.Total > 0 {
+		recordProgress(func(s *progress.Store, now time.Time) { s.RecordQuiz(args[0], res.Correct, res.Total, now) })
+	}
+	fmt.Printf("\nScore: %d of %d\n", res.Correct, res.Total)
+	for _, it := range res.Missed {
+		fmt.Printf("  review %s: %s\n", it.Example, it.Prompt)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/datarace"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runRace runs a racy example twice, normally and rebuilt with -race, and
+// explains the race detector's report. Without arguments it lists the
+// examples that have a race to find.
+func runRace(args []string) error {
+	if len(args) == 0 {
+		for _, c := range registry.All() {
+			if _, ok := c.(datarace.Demo); ok {
+				md := c.Describe()
+				fmt.Printf("%-40s %s\n", md.Name, md.Description)
+			}
+		}
+		return nil
+	}
+	if len(args) != 1 {
+		return errUsage
+	}
+	c, ok := registry.Lookup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown example %q (see \"concepts list\")", args[0])
+	}
+	demo, ok := c.(datarace.Demo)
+	if !ok {
+		return fmt.Errorf("%s has no data race to find (see \"concepts race\")", args[0])
+	}
+	root, err := findRoot()
+	if err != nil {
+		return err
+	}
+	name := c.Describe().Name
+
+	fmt.Printf("=== %s, normal build\n\n", name)
+	if err := c.Run(context.Background(), os.Stdout); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== %s, built with -race\n\n", name)
+	fmt.Fprintln(os.Stderr, "building with -race (the first build takes a while)...")
+	res, err := datarace.Run(context.Background(), root, name)
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(res.Output)
+	fmt.Println()
+	if len(res.Reports) == 0 {
+		fmt.Println("The race detector found no data race this time. It only sees races in code that actually ran concurrently; run it again.")
+		return nil
+	}
+	return datarace.Annotate(os.Stdout, res.Reports, demo.RaceNotes(), sourceLine)
+}
+
+// sourceLine returns line n of an example's source file, given the file's
+// path as the race detector prints it.
+func sourceLine(file string, n int) (string, bool) {
+	_, rel, ok := strings.Cut(file, "/"+examplesDir+"/")
+	if !ok {
+		return "", false
+	}
+	src, err := fs.ReadFile(sources, rel)
+	if err != nil {
+		return "", false
+	}
+	lines := bytes.Split(src, []byte("\n"))
+	if n < 1 || n > len(lines) {
+		return "", false
+	}
+	return string(lines[n-1]), true
+}
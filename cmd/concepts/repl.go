@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/repl"
+)
+
+// replHelp lists the REPL's own commands.
+const replHelp = `Type Go statements or declarations; they run as the body of main.
+  :explain   show the example's steps and explanations again
+  :show      show the program built from your snippets so far
+  :reset     forget every snippet
+  :quit      leave (Ctrl-D works too)`
+
+// runREPL starts an interactive Go session, optionally next to an example
+// whose steps and explanations are shown first.
+func runREPL(args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+	var c registry.Concept
+	if len(args) == 1 {
+		var ok bool
+		if c, ok = registry.Lookup(args[0]); !ok {
+			return fmt.Errorf("unknown example %q (see \"concepts list\")", args[0])
+		}
+	}
+
+	s, err := repl.NewSession()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if c != nil {
+		if err := explainConcept(os.Stdout, c); err != nil {
+			return err
+		}
+	}
+	fmt.Println(replHelp)
+
+	in := bufio.NewScanner(os.Stdin)
+	for {
+		snippet, ok := readSnippet(in)
+		if !ok {
+			fmt.Println()
+			return in.Err()
+		}
+		switch strings.TrimSpace(snippet) {
+		case ":quit", ":q":
+			return nil
+		case ":reset":
+			s.Reset()
+			fmt.Println("(forgot every snippet)")
+			continue
+		case ":show":
+			for i, line := range strings.Split(strings.TrimSuffix(s.Source(), "\n"), "\n") {
+				fmt.Printf("%3d  %s\n", i+1, line)
+			}
+			continue
+		case ":explain":
+			if c == nil {
+				fmt.Println("(no example selected; start with \"concepts repl <example>\")")
+			} else if err := explainConcept(os.Stdout, c); err != nil {
+				return err
+			}
+			continue
+		case ":help", ":h":
+			fmt.Println(replHelp)
+			continue
+		}
+
+		out, err := s.Eval(context.Background(), snippet)
+		if err != nil {
+			fmt.Println("error:", err)
+			continue
+		}
+		fmt.Print(out)
+	}
+}
+
+// readSnippet reads one snippet, continuing over several lines while
+// braces, brackets or parentheses are still open.
+func readSnippet(in *bufio.Scanner) (string, bool) {
+	var lines []string
+	depth := 0
+	fmt.Print(">>> ")
+	for in.Scan() {
+		line := in.Text()
+		lines = append(lines, line)
+		for _, r := range line {
+			switch r {
+			case '{', '(', '[':
+				depth++
+			case '}', ')', ']':
+				depth--
+			}
+		}
+		if depth <= 0 {
+			return strings.Join(lines, "\n"), true
+		}
+		fmt.Print("... ")
+	}
+	return "", false
+}
+
+// explainConcept runs c once and prints its output step by step, each
+// step preceded by the example's explanation for it.
+func explainConcept(w io.Writer, c registry.Concept) error {
+	md := c.Describe()
+	fmt.Fprintf(w, "=== %s: %s\n", md.Name, md.Description)
+
+	var events []event.Event
+	ctx := event.WithSink(context.Background(), event.SinkFunc(func(ev event.Event) error {
+		events = append(events, ev)
+		return nil
+	}))
+	if err := c.Run(ctx, io.Discard); err != nil {
+		return err
+	}
+	ex, _ := c.(registry.Explainer)
+	step := ""
+	var b bytes.Buffer
+	for i, ev := range events {
+		if i == 0 || ev.Step != step {
+			step = ev.Step
+			fmt.Fprintf(&b, "\n[%s]\n", step)
+			if ex != nil {
+				if blurb := ex.Explain(step); blurb != "" {
+					fmt.Fprintf(&b, "  » %s\n", blurb)
+				}
+			}
+		}
+		fmt.Fprintf(&b, "  %s\n", strings.ReplaceAll(ev.Message, "\n", "\n  "))
+	}
+	b.WriteString("\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/flashcard"
+)
+
+// runReview goes through the flashcards that are due today and schedules
+// each one again according to how well it was remembered.
+func runReview(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	s, err := openProgress()
+	if err != nil {
+		return err
+	}
+	all := flashcard.All()
+	due := flashcard.Due(all, s.Cards, time.Now())
+	if len(due) == 0 {
+		fmt.Printf("No cards due today (%d in total). Come back tomorrow!\n", len(all))
+		return nil
+	}
+
+	grades, err := flashcard.NewSession(os.Stdin, os.Stdout).Review(due)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, g := range grades {
+		id := g.ID()
+		s.Cards[id] = flashcard.Review(s.Cards[id], g.Quality, now)
+	}
+	if err := s.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\nReviewed %d of %d due card(s).\n", len(grades), len(due))
+	return nil
+}
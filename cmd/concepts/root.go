@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// findRoot walks up from the current directory until it finds the directory
+// containing go.mod, which is the repository root. Commands that read or
+// write files in the repository resolve their default paths against it.
+func findRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("could not find the repository root (no go.mod in any parent directory)")
+		}
+		dir = parent
+	}
+}
+
+// examplesDir is the directory (relative to the repository root) that
+// holds the example packages.
+const examplesDir = "GOlang"
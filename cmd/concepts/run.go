@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/i18n"
+	"github.com/amandm/programming-concepts/internal/progress"
+	"github.com/amandm/programming-concepts/internal/record"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/style"
+)
+
+// runList prints every registered example, optionally filtered by topic,
+// difficulty level or tag.
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	topic := fs.String("topic", "", "only list examples of this `topic`, e.g. pointers")
+	level := fs.String("level", "", "only list examples of this `level`: beginner, intermediate or advanced")
+	tag := fs.String("tag", "", "only list examples carrying this `tag`")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 0 {
+		return errUsage
+	}
+
+	q := registry.Query{Topic: *topic, Tag: *tag}
+	if *level != "" {
+		l, err := registry.ParseLevel(*level)
+		if err != nil {
+			return err
+		}
+		q.Level = l
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, c := range registry.Find(q) {
+		md := c.Describe()
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", md.Name, md.Level, md.Description)
+	}
+	return tw.Flush()
+}
+
+// runRun runs a single example. Flags before the example name configure
+// the runner itself; every argument after it is parsed with the example's
+// own flags (see registry.Configurable). A leading "--" is dropped so that
+// those flags are easy to spell out. Interrupting the program cancels the
+// example's context.
+func runRun(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	format := fs.String("format", "text", "output `format`: text or json (one event per line)")
+	step := fs.Bool("step", settings.Step, "pause after each observation until Enter is pressed")
+	lang := fs.String("lang", defaultLanguage(), "`language` of the explanations, e.g. es or hi")
+	color := fs.String("color", defaultColor(), "when to color the text output: auto, always or never")
+	recordTo := fs.String("record", "", "also write an HTML page replaying the run to `file`")
+	traceTo := fs.String("trace", "", "write a runtime/trace execution trace of the run to `file`")
+	viewIt := fs.Bool("view", false, "with -trace, open the trace with \"go tool trace\" afterwards")
+	profileKind := fs.String("profile", "", "capture a `kind` of profile of the run, cpu or heap, and summarize it")
+	profileDir := fs.String("profile-dir", "", "store profiles below `dir` (default concepts/profiles in the user cache directory)")
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 || *viewIt && *traceTo == "" {
+		return errUsage
+	}
+	name, rest := fs.Arg(0), fs.Args()[1:]
+	if len(rest) > 0 && rest[0] == "--" {
+		rest = rest[1:]
+	}
+
+	c, ok := registry.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown example %q (see \"concepts list\")", name)
+	}
+	if err := parseConceptFlags(c, rest); err != nil {
+		return err
+	}
+
+	translate, err := i18n.Translator(*lang)
+	if err != nil {
+		return err
+	}
+	st, err := style.ForMode(os.Stdout, *color)
+	if err != nil {
+		return err
+	}
+	sink, err := event.NewSink(*format, os.Stdout, st)
+	if err != nil {
+		return err
+	}
+	if *step {
+		var explain func(string) string
+		if ex, ok := c.(registry.Explainer); ok {
+			explain = func(step string) string { return translate(ex.Explain(step)) }
+		}
+		sink = event.NewStepSink(sink, os.Stdin, os.Stderr, explain)
+	}
+	var rec *record.Recorder
+	if *recordTo != "" {
+		rec = record.New(sink)
+		sink = rec
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	run := func(ctx context.Context, sink event.Sink) error {
+		return c.Run(event.WithTranslator(event.WithSink(ctx, sink), translate), os.Stdout)
+	}
+	traced := func() error {
+		if *traceTo != "" {
+			return traceRun(ctx, *traceTo, name, sink, run)
+		}
+		return run(ctx, sink)
+	}
+	if *profileKind != "" {
+		err = profileRun(*profileKind, *profileDir, name, traced)
+	} else {
+		err = traced()
+	}
+	if err != nil {
+		return err
+	}
+	if rec != nil {
+		if err := writeReplay(*recordTo, rec, c.Describe()); err != nil {
+			return err
+		}
+	}
+	recordProgress(func(s *progress.Store, now time.Time) { s.RecordRun(name, now) })
+	if *viewIt {
+		return viewTrace(*traceTo)
+	}
+	return nil
+}
+
+// writeReplay saves the HTML replay of a recorded run.
+func writeReplay(path string, rec *record.Recorder, md registry.Metadata) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := rec.WriteHTML(f, md); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "wrote replay to", path)
+	return nil
+}
+
+// parseConceptFlags hands args to the concept's flags. Concepts that don't
+// implement registry.Configurable don't take any arguments at all.
+func parseConceptFlags(c registry.Concept, args []string) error {
+	name := c.Describe().Name
+	cfg, ok := c.(registry.Configurable)
+	if !ok {
+		if len(args) != 0 {
+			return fmt.Errorf("%s takes no arguments, got %q", name, args)
+		}
+		return nil
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cfg.SetFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("%s: unexpected arguments %q", name, fs.Args())
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/search"
+)
+
+// runSearch prints the examples matching a query, best first, with the
+// source lines that matched.
+func runSearch(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	query := strings.Join(args, " ")
+	matches := search.New(registry.All(), sources).Search(query)
+	if len(matches) == 0 {
+		fmt.Printf("No example matches %q.\n", query)
+		return nil
+	}
+	for i, m := range matches {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s  (%s)\n  %s\n", m.Name, m.Level, m.Description)
+		for _, s := range m.Snippets {
+			fmt.Printf("    %s\n", s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/amandm/programming-concepts/internal/api"
+	"github.com/amandm/programming-concepts/internal/web"
+)
+
+// runServe serves the browser UI, with the JSON API under /api/, until
+// interrupted.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "`address` to listen on")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return errUsage
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", web.NewHandler(sources))
+	mux.Handle("/api/", api.NewHandler(sources))
+	log.Printf("serving the examples on http://%s/ (API under /api/)", *addr)
+	srv := &http.Server{Addr: *addr, Handler: mux}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
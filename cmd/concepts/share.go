@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/share"
+)
+
+// modulePath is the import path prefix of every package in this repository.
+const modulePath = "github.com/amandm/programming-concepts"
+
+// runShare uploads an example to the Go Playground and prints its URL.
+// With -print it only writes the flattened program to stdout.
+func runShare(args []string) error {
+	fs := flag.NewFlagSet("share", flag.ContinueOnError)
+	printOnly := fs.Bool("print", false, "print the flattened program instead of uploading it")
+	endpoint := fs.String("endpoint", share.DefaultEndpoint, "share API `URL`")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return errUsage
+	}
+	c, ok := registry.Lookup(fs.Arg(0))
+	if !ok {
+		return fmt.Errorf("unknown example %q (see \"concepts list\")", fs.Arg(0))
+	}
+	md := c.Describe()
+	root, err := findRoot()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	pkg := path.Join(modulePath, examplesDir, path.Dir(md.Name))
+	bundle, err := share.Bundle(ctx, root, pkg, md)
+	if err != nil {
+		return err
+	}
+	if *printOnly {
+		_, err := os.Stdout.Write(bundle)
+		return err
+	}
+	url, err := share.Upload(ctx, *endpoint, bundle)
+	if err != nil {
+		return err
+	}
+	fmt.Println(url)
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime/trace"
+
+	"github.com/amandm/programming-concepts/internal/event"
+)
+
+// traceRun runs run with the execution tracer writing to path. The run is
+// a trace task named after the example, and every step it enters is
+// logged, so "go tool trace" shows where the example was in its story
+// next to the goroutine timeline.
+func traceRun(ctx context.Context, path, name string, sink event.Sink, run func(ctx context.Context, sink event.Sink) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := trace.Start(f); err != nil {
+		f.Close()
+		return err
+	}
+	ctx, task := trace.NewTask(ctx, name)
+	step := ""
+	runErr := run(ctx, event.SinkFunc(func(ev event.Event) error {
+		if ev.Step != step {
+			step = ev.Step
+			trace.Log(ctx, "step", step)
+		}
+		return sink.Emit(ev)
+	}))
+	task.End()
+	trace.Stop()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "wrote execution trace to", path)
+	return runErr
+}
+
+// viewTrace opens the trace in the browser with "go tool trace" and waits
+// until it is stopped.
+func viewTrace(path string) error {
+	cmd := exec.Command("go", "tool", "trace", path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
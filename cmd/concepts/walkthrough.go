@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/profiling"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runProfileWalkthrough goes through the pprof workflow on an example,
+// memory/hotspot_example unless another one is named: capture a heap
+// profile of a run, read its top functions, find the source line that
+// allocates the most, and, if the example has variants, profile each of
+// them to confirm the fix.
+func runProfileWalkthrough(args []string) error {
+	flagSet := flag.NewFlagSet("profile-walkthrough", flag.ContinueOnError)
+	dir := flagSet.String("profile-dir", "", "store profiles below `dir` (default concepts/profiles in the user cache directory)")
+	if err := flagSet.Parse(args); err != nil || flagSet.NArg() > 1 {
+		return errUsage
+	}
+	name := "memory/hotspot_example"
+	if flagSet.NArg() == 1 {
+		name = flagSet.Arg(0)
+	}
+	c, ok := registry.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown example %q (see \"concepts list\")", name)
+	}
+	md := c.Describe()
+	if *dir == "" {
+		var err error
+		if *dir, err = profiling.DefaultDir(); err != nil {
+			return err
+		}
+	}
+
+	// The example's own output would get in the way of the profile's.
+	capture := func(name string, run func(context.Context, io.Writer) error) (*profiling.Profile, error) {
+		return profiling.Capture(profiling.Heap, *dir, name, func() error {
+			return run(context.Background(), io.Discard)
+		})
+	}
+
+	fmt.Printf("Step 1: capture a heap profile of %s.\n\n", md.Name)
+	prof, err := capture(md.Name, c.Run)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Every allocation of the run was recorded, not one every 512 KB as by default.")
+	fmt.Println("In your own programs, \"go test -memprofile mem.out\" or the /debug/pprof/allocs handler of net/http/pprof does the same.")
+
+	fmt.Printf("\nStep 2: read the top functions.\n\n")
+	if err := prof.WriteSummary(os.Stdout, 5); err != nil {
+		return err
+	}
+	fmt.Println("\nflat is what a function allocated itself, cum adds everything it called. A function with a big")
+	fmt.Println("cum and a small flat only leads to the allocations; look for the big flat values.")
+
+	fmt.Printf("\nStep 3: find the line.\n\n")
+	file := md.Name + ".go"
+	lines := prof.Lines(3, func(f string) bool { return strings.HasSuffix(f, "/"+file) })
+	if len(lines) == 0 {
+		fmt.Printf("Nothing was allocated by a line of %s.\n", file)
+		return nil
+	}
+	_, total := prof.Top(0)
+	for _, l := range lines {
+		fmt.Printf("%10s %5.1f%%  %s:%d (%s)\n", prof.Format(l.Flat), percent(l.Flat, total), file, l.Line, l.Func)
+	}
+	src, err := fs.ReadFile(sources, file)
+	if err != nil {
+		return err
+	}
+	hot := lines[0]
+	fmt.Printf("\nThe hottest line (what the runtime or a library allocated on its behalf counts against it):\n\n")
+	printAround(src, int(hot.Line), 2)
+	fmt.Printf("\n%s of the %s allocated during the run come from line %d.\n", prof.Format(hot.Flat), prof.Format(total), hot.Line)
+
+	if cmp, ok := c.(registry.Comparer); ok {
+		fmt.Printf("\nStep 4: profile each variant to confirm the fix.\n\n")
+		for _, v := range cmp.Variants() {
+			vprof, err := capture(md.Name+"/"+v.Name, v.Run)
+			if err != nil {
+				return fmt.Errorf("%s: %w", v.Name, err)
+			}
+			_, vtotal := vprof.Top(0)
+			fmt.Printf("  %-10s %10s allocated\n", v.Name, vprof.Format(vtotal))
+		}
+	}
+
+	fmt.Printf("\nStep 5: the same with pprof itself, which shows every line of the function:\n\n")
+	fmt.Printf("  go tool pprof -sample_index=alloc_space -list '%s' %s\n", hot.Func, prof.Path)
+	return nil
+}
+
+// percent returns v as a percentage of total.
+func percent(v, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(v) / float64(total)
+}
+
+// printAround prints the lines of src within context of line, numbered,
+// with an arrow at line itself.
+func printAround(src []byte, line, context int) {
+	lines := strings.Split(string(src), "\n")
+	for i := max(line-context, 1); i <= min(line+context, len(lines)); i++ {
+		marker := "  "
+		if i == line {
+			marker = "=>"
+		}
+		fmt.Printf("%s %4d  %s\n", marker, i, lines[i-1])
+	}
+}
@@ -0,0 +1,104 @@
+// Package concepts is the public API for example packs: sets of examples
+// that instructors publish in their own Go modules and that show up in the
+// concepts CLI (listing, running, browsing, quizzes, flashcards) without
+// forking this repository.
+//
+// A pack is a small program. Its examples are written exactly like the
+// built-in ones, using the types re-exported here:
+//
+//	type hello struct{}
+//
+//	func (hello) Describe() concepts.Metadata {
+//		return concepts.Metadata{Name: "mypack/hello", Topic: "mypack", Level: concepts.Beginner}
+//	}
+//
+//	func (hello) Run(ctx context.Context, w io.Writer) error {
+//		e := concepts.From(ctx, w)
+//		e.Step("greet")
+//		e.Say("hello from a pack")
+//		return e.Err()
+//	}
+//
+//	func main() {
+//		concepts.Serve(concepts.NewPack("mypack", sources, hello{}))
+//	}
+//
+// Installing it is a matter of putting the binary into a pack directory:
+// "concepts/packs" in the user's configuration directory, or any of the
+// directories listed in $CONCEPTS_PACKS.
+package concepts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/plugin"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// The types an example is built from. See the registry, event, quiz and
+// flashcard packages of this repository for their documentation.
+type (
+	Concept  = registry.Concept
+	Metadata = registry.Metadata
+	Level    = registry.Level
+	Emitter  = event.Emitter
+	Question = quiz.Question
+	Card     = flashcard.Card
+)
+
+const (
+	Beginner     = registry.Beginner
+	Intermediate = registry.Intermediate
+	Advanced     = registry.Advanced
+)
+
+// From returns the Emitter an example records its events with.
+func From(ctx context.Context, w io.Writer) *Emitter {
+	return event.From(ctx, w)
+}
+
+// Pack is a set of examples published together. Examples may implement
+// Questions() []Question and Flashcards() []Card, like built-in ones.
+type Pack interface {
+	// Name identifies the pack in error messages.
+	Name() string
+	// Concepts returns the pack's examples.
+	Concepts() []Concept
+	// Sources returns each example's source file as <name>.go, or nil.
+	Sources() fs.FS
+}
+
+// NewPack returns a Pack made of the given examples. sources may be nil.
+func NewPack(name string, sources fs.FS, examples ...Concept) Pack {
+	return pack{name, sources, examples}
+}
+
+type pack struct {
+	name     string
+	sources  fs.FS
+	examples []Concept
+}
+
+func (p pack) Name() string        { return p.name }
+func (p pack) Concepts() []Concept { return p.examples }
+func (p pack) Sources() fs.FS      { return p.sources }
+
+// Serve is the main function of a pack program. It answers the request
+// the concepts CLI started it with and exits.
+func Serve(p Pack) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := plugin.Serve(ctx, p.Name(), p.Concepts(), p.Sources(), os.Args[1:], os.Stdout)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", p.Name(), err)
+		os.Exit(1)
+	}
+}
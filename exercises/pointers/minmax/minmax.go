@@ -0,0 +1,25 @@
+// Package minmax is an exercise. Edit this file, then run
+//
+//	concepts check pointers/minmax
+//
+// to see whether it works.
+package minmax
+
+// MinMax should find the smallest and largest number in nums and store
+// them in the ints that min and max point to. nums is never empty.
+//
+// Right now it computes the right numbers but the caller never sees them.
+// Why do the assignments at the end not reach the caller's variables?
+func MinMax(nums []int, min, max *int) {
+	lo, hi := nums[0], nums[0]
+	for _, n := range nums[1:] {
+		if n < lo {
+			lo = n
+		}
+		if n > hi {
+			hi = n
+		}
+	}
+	min = &lo
+	max = &hi
+}
@@ -0,0 +1,21 @@
+// Package swap is an exercise. Edit this file, then run
+//
+//	concepts check pointers/swap
+//
+// to see whether it works.
+package swap
+
+// Swap should exchange the values of the two ints that a and b point to,
+// so that after
+//
+//	x, y := 1, 2
+//	Swap(&x, &y)
+//
+// x is 2 and y is 1.
+//
+// Right now it compiles but doesn't swap anything. Compare with
+// incrementValue in GOlang/pointers/function_example.go: what does this
+// version actually exchange?
+func Swap(a, b *int) {
+	a, b = b, a
+}
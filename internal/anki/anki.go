@@ -0,0 +1,109 @@
+// Package anki exports the quiz questions and flashcards of the examples as
+// a deck that Anki can import.
+//
+// The deck is written in Anki's text import format: a CSV file whose
+// leading "#" lines tell Anki the separator, that fields contain HTML and
+// which column holds the tags. (An .apkg file is an SQLite database, which
+// would need a cgo or third-party driver; the CSV imports the same notes.)
+package anki
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Note is one Anki note with HTML fields.
+type Note struct {
+	Front  string
+	Back   string
+	Source string
+	Tags   []string
+}
+
+// Notes turns every quiz question and flashcard into a note. source maps
+// an example name to the URL its Source field links to.
+func Notes(source func(name string) string) []Note {
+	var notes []Note
+	for _, c := range registry.All() {
+		md := c.Describe()
+		link := fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(source(md.Name)), html.EscapeString(md.Name))
+		tags := append([]string{md.Topic, md.Level.String()}, md.Tags...)
+
+		if q, ok := c.(quiz.Quizzer); ok {
+			for _, question := range q.Questions() {
+				notes = append(notes, Note{
+					Front:  questionFront(question),
+					Back:   paragraphs(question.Answer, question.Explain),
+					Source: link,
+					Tags:   tags,
+				})
+			}
+		}
+		if d, ok := c.(flashcard.Deck); ok {
+			for _, card := range d.Flashcards() {
+				notes = append(notes, Note{
+					Front:  paragraphs(card.Front),
+					Back:   paragraphs(card.Back),
+					Source: link,
+					Tags:   tags,
+				})
+			}
+		}
+	}
+	return notes
+}
+
+// questionFront renders a question's prompt and, for multiple choice, its
+// numbered choices.
+func questionFront(q quiz.Question) string {
+	front := paragraphs(q.Prompt)
+	if len(q.Choices) == 0 {
+		return front
+	}
+	var b strings.Builder
+	b.WriteString(front + "<ol>")
+	for _, choice := range q.Choices {
+		b.WriteString("<li>" + html.EscapeString(choice) + "</li>")
+	}
+	b.WriteString("</ol>")
+	return b.String()
+}
+
+// paragraphs escapes each non-empty text and wraps it in a <p>.
+func paragraphs(texts ...string) string {
+	var b strings.Builder
+	for _, t := range texts {
+		if t != "" {
+			b.WriteString("<p>" + html.EscapeString(t) + "</p>")
+		}
+	}
+	return b.String()
+}
+
+// WriteCSV writes notes as an Anki text import file.
+func WriteCSV(w io.Writer, notes []Note) error {
+	header := "#separator:Comma\n#html:true\n#columns:Front,Back,Source,Tags\n#tags column:4\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	for _, n := range notes {
+		// Anki tags are separated by spaces, so a tag can't contain one.
+		tags := make([]string, len(n.Tags))
+		for i, t := range n.Tags {
+			tags[i] = strings.ReplaceAll(t, " ", "_")
+		}
+		if err := cw.Write([]string{n.Front, n.Back, n.Source, strings.Join(tags, " ")}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,276 @@
+// Package api is a JSON HTTP API over the example catalog, for LMS
+// integrations and custom front-ends that want to drive the examples
+// without scraping CLI output.
+//
+// Examples are identified by their name with the slash escaped, e.g.
+// "pointers%2Ffunction_example", so that every name is a single path
+// segment:
+//
+//	GET  /api/examples                 list (?topic=, ?level=, ?tag= filter it)
+//	GET  /api/examples/{id}            one example, with its source
+//	POST /api/examples/{id}/run        run it (?lang= translates) and return its events
+//	GET  /api/quiz/{id}                the example's quiz questions, without answers
+//	POST /api/quiz/{id}                grade {"answers": [...]}, one per question
+//
+// Errors are reported as {"error": "..."} with a matching status code.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/i18n"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// runTimeout bounds how long a single run may take.
+const runTimeout = 10 * time.Second
+
+// server holds what the handlers need.
+type server struct {
+	sources fs.FS
+}
+
+// NewHandler returns the API's HTTP handler. sources must contain the
+// example source files laid out as described by registry.Metadata.Name.
+func NewHandler(sources fs.FS) http.Handler {
+	s := &server{sources: sources}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/examples", s.list)
+	mux.HandleFunc("GET /api/examples/{id}", s.example)
+	mux.HandleFunc("POST /api/examples/{id}/run", s.run)
+	mux.HandleFunc("GET /api/quiz/{id}", s.questions)
+	mux.HandleFunc("POST /api/quiz/{id}", s.grade)
+	return mux
+}
+
+// Example is an example as the API returns it.
+type Example struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Topic         string   `json:"topic"`
+	Level         string   `json:"level"`
+	Description   string   `json:"description"`
+	Tags          []string `json:"tags"`
+	Prerequisites []string `json:"prerequisites"`
+	HasQuiz       bool     `json:"has_quiz"`
+	Source        string   `json:"source,omitempty"`
+}
+
+// ID returns the API identifier of the example called name.
+func ID(name string) string {
+	return url.PathEscape(name)
+}
+
+func newExample(c registry.Concept) Example {
+	md := c.Describe()
+	_, hasQuiz := c.(quiz.Quizzer)
+	return Example{
+		ID:            ID(md.Name),
+		Name:          md.Name,
+		Topic:         md.Topic,
+		Level:         md.Level.String(),
+		Description:   md.Description,
+		Tags:          nonNil(md.Tags),
+		Prerequisites: nonNil(md.Prerequisites),
+		HasQuiz:       hasQuiz,
+	}
+}
+
+// nonNil makes empty lists encode as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// list returns every example matching the query parameters.
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	q := registry.Query{Topic: r.FormValue("topic"), Tag: r.FormValue("tag")}
+	if level := r.FormValue("level"); level != "" {
+		l, err := registry.ParseLevel(level)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		q.Level = l
+	}
+	examples := []Example{}
+	for _, c := range registry.Find(q) {
+		examples = append(examples, newExample(c))
+	}
+	writeJSON(w, http.StatusOK, examples)
+}
+
+// lookup finds the example named by the {id} path segment, replying with
+// 404 if there is none.
+func lookup(w http.ResponseWriter, r *http.Request) (registry.Concept, bool) {
+	c, ok := registry.Lookup(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown example %q", r.PathValue("id")))
+	}
+	return c, ok
+}
+
+// example returns one example with its source.
+func (s *server) example(w http.ResponseWriter, r *http.Request) {
+	c, ok := lookup(w, r)
+	if !ok {
+		return
+	}
+	ex := newExample(c)
+	if src, err := fs.ReadFile(s.sources, ex.Name+".go"); err == nil {
+		ex.Source = string(src)
+	}
+	writeJSON(w, http.StatusOK, ex)
+}
+
+// RunResult is the reply to a run. Events are in the format of
+// "concepts run -format=json".
+type RunResult struct {
+	Events []event.Event `json:"events"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// run executes an example and replies with the events it recorded. A
+// failed run still returns the events recorded before the failure.
+func (s *server) run(w http.ResponseWriter, r *http.Request) {
+	c, ok := lookup(w, r)
+	if !ok {
+		return
+	}
+	lang := r.FormValue("lang")
+	if lang == "" {
+		lang = i18n.English
+	}
+	translate, err := i18n.Translator(lang)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), runTimeout)
+	defer cancel()
+	res := RunResult{Events: []event.Event{}}
+	ctx = event.WithSink(ctx, event.SinkFunc(func(ev event.Event) error {
+		res.Events = append(res.Events, ev)
+		return nil
+	}))
+	ctx = event.WithTranslator(ctx, translate)
+
+	status := http.StatusOK
+	if err := c.Run(ctx, io.Discard); err != nil {
+		res.Error = err.Error()
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, res)
+}
+
+// Question is a quiz question without its answer.
+type Question struct {
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices,omitempty"`
+}
+
+// quizOf returns the questions of the example named by {id}, replying
+// with an error if it has none.
+func quizOf(w http.ResponseWriter, r *http.Request) ([]quiz.Question, bool) {
+	c, ok := lookup(w, r)
+	if !ok {
+		return nil, false
+	}
+	q, ok := c.(quiz.Quizzer)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s has no quiz", c.Describe().Name))
+		return nil, false
+	}
+	return q.Questions(), true
+}
+
+// questions returns an example's quiz questions, leaving out the answers
+// so that a front-end can't give them away.
+func (s *server) questions(w http.ResponseWriter, r *http.Request) {
+	qs, ok := quizOf(w, r)
+	if !ok {
+		return
+	}
+	out := make([]Question, len(qs))
+	for i, q := range qs {
+		out[i] = Question{Prompt: q.Prompt, Choices: q.Choices}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// Answers is the body of a grading request: the learner's answer to each
+// question, in order. An answer is a choice's text or its 1-based number,
+// as in "concepts quiz".
+type Answers struct {
+	Answers []string `json:"answers"`
+}
+
+// Grade is the graded answer to one question.
+type Grade struct {
+	Correct bool   `json:"correct"`
+	Answer  string `json:"answer"`
+	Explain string `json:"explain,omitempty"`
+}
+
+// Grades is the reply to a grading request.
+type Grades struct {
+	Correct int     `json:"correct"`
+	Total   int     `json:"total"`
+	Results []Grade `json:"results"`
+}
+
+// grade checks the learner's answers to an example's quiz.
+func (s *server) grade(w http.ResponseWriter, r *http.Request) {
+	qs, ok := quizOf(w, r)
+	if !ok {
+		return
+	}
+	var body Answers
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decoding answers: %w", err))
+		return
+	}
+	if len(body.Answers) != len(qs) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("got %d answers for %d questions", len(body.Answers), len(qs)))
+		return
+	}
+	res := Grades{Total: len(qs), Results: make([]Grade, len(qs))}
+	for i, q := range qs {
+		g := Grade{Correct: q.Correct(body.Answers[i]), Answer: q.Answer, Explain: q.Explain}
+		if g.Correct {
+			res.Correct++
+		}
+		res.Results[i] = g
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("api: encoding reply: %v", err)
+		status, data = http.StatusInternalServerError, []byte(`{"error":"internal error"}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
@@ -0,0 +1,67 @@
+// Package assert lets examples check the claims their output makes, so an
+// example doubles as an executable fact-check: if a function that the
+// narration says changes count stops changing it, the run fails instead
+// of printing prose that is no longer true.
+//
+// A Checker collects the failed claims of a run; the example returns
+// Checker.Err from Run, next to the Emitter's error.
+package assert
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Failure is a claim that didn't hold.
+type Failure struct {
+	Claim  string
+	Detail string
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("claim does not hold: %s (%s)", f.Claim, f.Detail)
+}
+
+// Checker records the claims of one run.
+type Checker struct {
+	failures []error
+}
+
+// New returns a Checker with nothing recorded.
+func New() *Checker {
+	return &Checker{}
+}
+
+// That records a claim that is true when cond is.
+func (c *Checker) That(cond bool, claim string) {
+	if !cond {
+		c.failures = append(c.failures, &Failure{Claim: claim, Detail: "it is false"})
+	}
+}
+
+// Equal records the claim that got equals want.
+func Equal[T comparable](c *Checker, claim string, got, want T) {
+	if got != want {
+		c.failures = append(c.failures, &Failure{Claim: claim, Detail: fmt.Sprintf("got %v, want %v", got, want)})
+	}
+}
+
+// NotEqual records the claim that got differs from other.
+func NotEqual[T comparable](c *Checker, claim string, got, other T) {
+	if got == other {
+		c.failures = append(c.failures, &Failure{Claim: claim, Detail: fmt.Sprintf("both are %v", got)})
+	}
+}
+
+// Err returns every failed claim joined together, or nil if all held.
+func (c *Checker) Err() error {
+	return errors.Join(c.failures...)
+}
+
+// Panics runs f and returns what it panicked with, or nil if it returned
+// normally.
+func Panics(f func()) (r any) {
+	defer func() { r = recover() }()
+	f()
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package benchlab measures the approaches an example compares, so a
+// learner can see what "a pointer avoids the copy" is worth in nanoseconds
+// and allocations instead of taking it on faith.
+//
+// An example opts in by implementing Lab. Each Experiment is a handful of
+// testing.B benchmarks doing the same job in different ways; Run measures
+// them with testing.Benchmark and Write prints them next to each other,
+// followed by the experiment's guidance on when the difference matters.
+// Bench runs the same benchmarks under go test -bench.
+package benchlab
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/style"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+// Approach is one way of doing an experiment's job.
+type Approach struct {
+	Name string
+	// Bench is a standard benchmark function: it does the job b.N times.
+	Bench func(b *testing.B)
+}
+
+// Experiment compares approaches to the same job.
+type Experiment struct {
+	// Name is a short title, e.g. "passing a 1 KB struct".
+	Name       string
+	Approaches []Approach
+	// Guidance explains the results: why one approach is faster and when
+	// that is worth caring about.
+	Guidance string
+}
+
+// Lab is implemented by concepts that come with benchmarks.
+type Lab interface {
+	registry.Concept
+	Experiments() []Experiment
+}
+
+// Find returns the labs of the examples of topic, sorted by example name.
+func Find(topic string) []Lab {
+	var labs []Lab
+	for _, c := range registry.Find(registry.Query{Topic: topic}) {
+		if l, ok := c.(Lab); ok {
+			labs = append(labs, l)
+		}
+	}
+	return labs
+}
+
+// Result is the measurement of one approach.
+type Result struct {
+	Approach string
+	testing.BenchmarkResult
+}
+
+// Run measures every approach of ex, in order.
+func Run(ex Experiment) []Result {
+	results := make([]Result, len(ex.Approaches))
+	for i, a := range ex.Approaches {
+		results[i] = Result{Approach: a.Name, BenchmarkResult: testing.Benchmark(a.Bench)}
+	}
+	return results
+}
+
+// Bench runs the experiments of l as sub-benchmarks of b, one for each
+// approach, so that a Benchmark function in the example's package lets
+// go test -bench measure them too.
+func Bench(b *testing.B, l Lab) {
+	for _, ex := range l.Experiments() {
+		b.Run(ex.Name, func(b *testing.B) {
+			for _, a := range ex.Approaches {
+				b.Run(a.Name, a.Bench)
+			}
+		})
+	}
+}
+
+// Write prints the results of ex as a table, with each approach's time
+// relative to the fastest one, and then the guidance wrapped to width.
+func Write(w io.Writer, ex Experiment, results []Result, width int) error {
+	fastest := 0.0
+	for _, r := range results {
+		if ns := r.nsPerOp(); ns > 0 && (fastest == 0 || ns < fastest) {
+			fastest = ns
+		}
+	}
+	t := table.New("approach", "time/op", "bytes/op", "allocs/op", "relative")
+	for _, r := range results {
+		t.Row(r.Approach, formatNs(r.nsPerOp()), r.AllocedBytesPerOp(), r.AllocsPerOp(), relative(r.nsPerOp(), fastest))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "── %s\n\n%s\n\n", ex.Name, t)
+	if ex.Guidance != "" {
+		b.WriteString(strings.Join(style.Wrap(ex.Guidance, width), "\n") + "\n\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// nsPerOp is like NsPerOp, but doesn't round fast operations to 0 or 1 ns.
+func (r Result) nsPerOp() float64 {
+	if r.N <= 0 {
+		return 0
+	}
+	return float64(r.T.Nanoseconds()) / float64(r.N)
+}
+
+// formatNs formats a time per operation in the unit that suits it.
+func formatNs(ns float64) string {
+	switch {
+	case ns < 1e3:
+		return fmt.Sprintf("%.1f ns", ns)
+	case ns < 1e6:
+		return fmt.Sprintf("%.2f µs", ns/1e3)
+	case ns < 1e9:
+		return fmt.Sprintf("%.2f ms", ns/1e6)
+	}
+	return fmt.Sprintf("%.2f s", ns/1e9)
+}
+
+// relative describes ns compared to the fastest time.
+func relative(ns, fastest float64) string {
+	switch {
+	case fastest == 0:
+		return "?"
+	case ns == fastest:
+		return "fastest"
+	}
+	return fmt.Sprintf("%.1fx slower", ns/fastest)
+}
@@ -0,0 +1,224 @@
+// Package compare shows two runs of an example next to each other.
+//
+// The events of each run are grouped by step, and the n-th step of one run
+// is lined up with the n-th step of the other. Addresses are numbered per
+// run before the sides are compared (as in the goldentest package), so a
+// row only counts as different when it says something different: a
+// different value, or an address that is a different variable than the
+// other side's.
+package compare
+
+import (
+	"context"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amandm/programming-concepts/internal/style"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/goldentest"
+)
+
+// Side is one run: a name for the column header and its events.
+type Side struct {
+	Name   string
+	Events []event.Event
+}
+
+// Collect runs run and returns the events it recorded.
+func Collect(ctx context.Context, name string, run func(context.Context, io.Writer) error) (Side, error) {
+	side := Side{Name: name}
+	ctx = event.WithSink(ctx, event.SinkFunc(func(ev event.Event) error {
+		side.Events = append(side.Events, ev)
+		return nil
+	}))
+	err := run(ctx, io.Discard)
+	return side, err
+}
+
+// entry is one event of a side, split into lines, with the normalized
+// lines used for comparing.
+type entry struct {
+	kind        string
+	lines, norm []string
+}
+
+// step is the entries of one step.
+type step struct {
+	name    string
+	entries []entry
+}
+
+// kind classifies an event for lining the two sides up.
+func kind(ev event.Event) string {
+	switch {
+	case ev.Diagram != "":
+		return "diagram"
+	case ev.Address != "":
+		return "address"
+	case ev.Value != "":
+		return "value"
+	}
+	return "say"
+}
+
+// steps groups a side's events by step.
+func (s Side) steps() []step {
+	normalize := goldentest.Normalizer()
+	var steps []step
+	for i, ev := range s.Events {
+		if i == 0 || ev.Step != s.Events[i-1].Step {
+			steps = append(steps, step{name: ev.Step})
+		}
+		st := &steps[len(steps)-1]
+		lines := strings.Split(ev.Message, "\n")
+		norm := strings.Split(string(normalize([]byte(ev.Message))), "\n")
+		st.entries = append(st.entries, entry{kind(ev), lines, norm})
+	}
+	return steps
+}
+
+// align pairs up the entries of two steps: entries of the same kind are
+// matched along a longest common subsequence, and the rest get a row of
+// their own with nothing on the other side.
+func align(l, r []entry) [][2]*entry {
+	// lcs[i][j] is the length of the LCS of l[i:] and r[j:].
+	lcs := make([][]int, len(l)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(r)+1)
+	}
+	for i := len(l) - 1; i >= 0; i-- {
+		for j := len(r) - 1; j >= 0; j-- {
+			if l[i].kind == r[j].kind {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var pairs [][2]*entry
+	i, j := 0, 0
+	for i < len(l) || j < len(r) {
+		switch {
+		case i < len(l) && j < len(r) && l[i].kind == r[j].kind:
+			pairs = append(pairs, [2]*entry{&l[i], &r[j]})
+			i, j = i+1, j+1
+		case j == len(r) || (i < len(l) && lcs[i+1][j] >= lcs[i][j+1]):
+			pairs = append(pairs, [2]*entry{&l[i], nil})
+			i++
+		default:
+			pairs = append(pairs, [2]*entry{nil, &r[j]})
+			j++
+		}
+	}
+	return pairs
+}
+
+// Options control how Render lays out the columns.
+type Options struct {
+	// Width is the total width of the output, in columns.
+	Width int
+	// Style highlights differing rows as warnings, in addition to the "≠"
+	// marker between the columns.
+	Style style.Styler
+}
+
+// Render writes left and right in two columns. Long lines are wrapped to
+// fit their column.
+func Render(w io.Writer, left, right Side, opts Options) error {
+	col := max((opts.Width-3)/2, 20)
+	var b strings.Builder
+	row := func(l, mid, r string, differ bool) {
+		lw, rw := wrap(l, col), wrap(r, col)
+		for i := range max(len(lw), len(rw)) {
+			var a, c string
+			if i < len(lw) {
+				a = lw[i]
+			}
+			if i < len(rw) {
+				c = rw[i]
+			}
+			a = pad(a, col)
+			if differ {
+				a, c = opts.Style.Warning(a), opts.Style.Warning(c)
+			}
+			b.WriteString(strings.TrimRight(a+mid+c, " ") + "\n")
+		}
+	}
+
+	row(left.Name, " │ ", right.Name, false)
+	row(strings.Repeat("═", col), "═╪═", strings.Repeat("═", col), false)
+
+	ls, rs := left.steps(), right.steps()
+	for i := range max(len(ls), len(rs)) {
+		var l, r step
+		if i < len(ls) {
+			l = ls[i]
+		}
+		if i < len(rs) {
+			r = rs[i]
+		}
+		if i > 0 {
+			row("", " │ ", "", false)
+		}
+		row(header(l.name, col), " │ ", header(r.name, col), false)
+		for _, p := range align(l.entries, r.entries) {
+			var a, c entry
+			if p[0] != nil {
+				a = *p[0]
+			}
+			if p[1] != nil {
+				c = *p[1]
+			}
+			for k := range max(len(a.lines), len(c.lines)) {
+				differ := p[0] == nil || p[1] == nil || lineAt(a.norm, k) != lineAt(c.norm, k)
+				mid := " │ "
+				if differ {
+					mid = " ≠ "
+				}
+				row(lineAt(a.lines, k), mid, lineAt(c.lines, k), differ)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// lineAt returns lines[i], or "" past the end.
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+// header is the rule above a step, e.g. "── initial ─────".
+func header(name string, width int) string {
+	if name == "" {
+		return ""
+	}
+	h := "── " + name + " "
+	return h + strings.Repeat("─", max(width-utf8.RuneCountInString(h), 0))
+}
+
+// wrap splits s into pieces of at most width runes. Tabs become spaces so
+// that diagrams line up.
+func wrap(s string, width int) []string {
+	r := []rune(strings.ReplaceAll(s, "\t", "    "))
+	if len(r) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	for len(r) > width {
+		lines = append(lines, string(r[:width]))
+		r = r[width:]
+	}
+	return append(lines, string(r))
+}
+
+// pad fills s with spaces up to width runes.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
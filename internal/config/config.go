@@ -0,0 +1,84 @@
+// Package config reads the settings file of the concepts CLI,
+// concepts/config.yaml in the user's config directory (e.g.
+// ~/.config/concepts/config.yaml on Linux). It lets a classroom machine
+// come with sensible defaults:
+//
+//	language: es          # language of the explanations
+//	color: never          # auto (the default), always or never
+//	step: true            # "concepts run" pauses after each observation
+//	topics: [pointers]    # topics to suggest first
+//	progress: /srv/concepts/progress.json
+//
+// Every setting is optional, and command-line flags override them.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the contents of the settings file.
+type Config struct {
+	// Language is the default language of the explanations, e.g. "es".
+	Language string `yaml:"language"`
+	// Color says when output is styled: "auto", "always" or "never".
+	Color string `yaml:"color"`
+	// Step makes "concepts run" pause after each observation by default.
+	Step bool `yaml:"step"`
+	// Topics are the topics the learner should focus on. Suggestions of
+	// what to do next come from these topics first.
+	Topics []string `yaml:"topics"`
+	// Progress is where progress is recorded instead of the default
+	// location. A leading "~/" stands for the home directory.
+	Progress string `yaml:"progress"`
+}
+
+// DefaultPath returns the settings file location: concepts/config.yaml
+// inside the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "concepts", "config.yaml"), nil
+}
+
+// Load reads the settings file at path. A missing file is not an error: it
+// just means every setting has its default. Unknown keys are an error, so
+// that a misspelt setting doesn't go unnoticed.
+func Load(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return c, nil
+	case err != nil:
+		return c, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return c, fmt.Errorf("config: %s: %w", path, err)
+	}
+	switch c.Color {
+	case "", "auto", "always", "never":
+	default:
+		return c, fmt.Errorf("config: %s: color is %q, want auto, always or never", path, c.Color)
+	}
+	if rest, ok := strings.CutPrefix(c.Progress, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return c, err
+		}
+		c.Progress = filepath.Join(home, rest)
+	}
+	return c, nil
+}
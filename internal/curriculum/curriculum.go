@@ -0,0 +1,125 @@
+// Package curriculum orders the examples into a learning path using the
+// prerequisites each example declares in its metadata.
+package curriculum
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Node is one example in the path.
+type Node struct {
+	registry.Metadata
+	// Depth is 0 for examples without prerequisites, otherwise one more
+	// than the deepest prerequisite.
+	Depth int
+}
+
+// Path is the examples in an order where every example comes after all of
+// its prerequisites.
+type Path struct {
+	nodes []Node
+	index map[string]int
+}
+
+// New builds the path from a set of concepts. It fails if an example names
+// a prerequisite that doesn't exist or if the prerequisites form a cycle.
+func New(concepts []registry.Concept) (*Path, error) {
+	byName := map[string]registry.Metadata{}
+	for _, c := range concepts {
+		md := c.Describe()
+		byName[md.Name] = md
+	}
+	for _, md := range byName {
+		for _, pre := range md.Prerequisites {
+			if _, ok := byName[pre]; !ok {
+				return nil, fmt.Errorf("curriculum: %s needs %s, which is not registered", md.Name, pre)
+			}
+		}
+	}
+
+	// Depth-first search; "visiting" catches cycles.
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	depth := map[string]int{}
+	var visit func(name string, stack []string) error
+	visit = func(name string, stack []string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("curriculum: prerequisites form a cycle: %s", strings.Join(append(stack, name), " -> "))
+		}
+		state[name] = visiting
+		d := 0
+		for _, pre := range byName[name].Prerequisites {
+			if err := visit(pre, append(stack, name)); err != nil {
+				return err
+			}
+			d = max(d, depth[pre]+1)
+		}
+		depth[name] = d
+		state[name] = done
+		return nil
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sorting by depth, then name, is a valid topological order because
+	// every prerequisite is strictly shallower than what depends on it.
+	p := &Path{index: map[string]int{}}
+	for _, name := range names {
+		p.nodes = append(p.nodes, Node{Metadata: byName[name], Depth: depth[name]})
+	}
+	sort.SliceStable(p.nodes, func(i, j int) bool { return p.nodes[i].Depth < p.nodes[j].Depth })
+	for i, n := range p.nodes {
+		p.index[n.Name] = i
+	}
+	return p, nil
+}
+
+// Nodes returns the examples in path order.
+func (p *Path) Nodes() []Node {
+	return p.nodes
+}
+
+// Unlocked reports whether every prerequisite of name is done.
+func (p *Path) Unlocked(name string, done func(name string) bool) bool {
+	i, ok := p.index[name]
+	if !ok {
+		return false
+	}
+	for _, pre := range p.nodes[i].Prerequisites {
+		if !done(pre) {
+			return false
+		}
+	}
+	return true
+}
+
+// Next returns the first example in path order that isn't done yet but
+// whose prerequisites all are.
+func (p *Path) Next(done func(name string) bool) (Node, bool) {
+	for _, n := range p.nodes {
+		if !done(n.Name) && p.Unlocked(n.Name, done) {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
@@ -0,0 +1,207 @@
+// Package datarace runs an example under the race detector and explains
+// its report.
+//
+// A program built with -race prints a report for every data race it runs
+// into: the two conflicting accesses, each with the goroutine that made it
+// and its stack, and where those goroutines were started. The report is
+// precise but dense, so this package parses it and prints each access next
+// to the source line it happened on, together with the example's own
+// explanation of why that line races.
+package datarace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Note explains the accesses made by one function.
+type Note struct {
+	// Func is the function's name without its package, e.g. "increment"
+	// or "counter.Add".
+	Func    string
+	Explain string
+}
+
+// Demo is implemented by concepts that contain a data race on purpose.
+// "concepts race" runs them under the race detector.
+type Demo interface {
+	registry.Concept
+	RaceNotes() []Note
+}
+
+// Frame is one line of a stack.
+type Frame struct {
+	Func string // fully qualified, e.g. "github.com/x/y.increment"
+	File string
+	Line int
+}
+
+// Access is one side of a data race.
+type Access struct {
+	// Op is how the report describes the access, e.g. "Write" or
+	// "Previous read".
+	Op        string
+	Addr      string
+	Goroutine int
+	Stack     []Frame
+}
+
+// Report is one data race: the access that was caught and the earlier,
+// conflicting one.
+type Report struct {
+	Accesses []Access
+}
+
+var (
+	accessRE = regexp.MustCompile(`^((?:Previous )?(?:[Rr]ead|[Ww]rite)(?:\s+\(atomic\))?) at (0x[0-9a-f]+) by (goroutine (\d+)|main goroutine):$`)
+	frameRE  = regexp.MustCompile(`^\s+(\S+)\(.*\)$`)
+	fileRE   = regexp.MustCompile(`^\s+(\S+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// Parse extracts the data race reports from the standard error of a
+// program built with -race. Everything else is ignored.
+func Parse(r io.Reader) ([]Report, error) {
+	var (
+		reports []Report
+		cur     *Report
+		acc     *Access
+	)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case line == "WARNING: DATA RACE":
+			reports = append(reports, Report{})
+			cur, acc = &reports[len(reports)-1], nil
+		case cur == nil:
+		case line == "==================":
+			cur, acc = nil, nil
+		case accessRE.MatchString(line):
+			m := accessRE.FindStringSubmatch(line)
+			g, _ := strconv.Atoi(m[4]) // 0 for the main goroutine
+			cur.Accesses = append(cur.Accesses, Access{Op: m[1], Addr: m[2], Goroutine: g})
+			acc = &cur.Accesses[len(cur.Accesses)-1]
+		case line == "":
+			acc = nil
+		case acc == nil:
+			// "Goroutine 7 (running) created at:" and the stacks below it.
+		case frameRE.MatchString(line):
+			acc.Stack = append(acc.Stack, Frame{Func: frameRE.FindStringSubmatch(line)[1]})
+		case fileRE.MatchString(line) && len(acc.Stack) > 0:
+			m := fileRE.FindStringSubmatch(line)
+			f := &acc.Stack[len(acc.Stack)-1]
+			f.File = m[1]
+			f.Line, _ = strconv.Atoi(m[2])
+		}
+	}
+	return reports, sc.Err()
+}
+
+// Result is the outcome of running an example under the race detector.
+type Result struct {
+	// Output is what the example printed.
+	Output []byte
+	// Reports are the data races the detector found.
+	Reports []Report
+}
+
+// Run rebuilds the concepts command in dir (the repository root) with
+// -race and runs the example called name with it.
+func Run(ctx context.Context, dir, name string) (Result, error) {
+	tmp, err := os.MkdirTemp("", "concepts-race-")
+	if err != nil {
+		return Result{}, err
+	}
+	defer os.RemoveAll(tmp)
+	bin := filepath.Join(tmp, "concepts")
+	build := exec.CommandContext(ctx, "go", "build", "-race", "-o", bin, "./cmd/concepts")
+	build.Dir = dir
+	if out, err := build.CombinedOutput(); err != nil {
+		return Result{}, fmt.Errorf("go build -race: %v\n%s", err, out)
+	}
+
+	cmd := exec.CommandContext(ctx, bin, "run", "-color=never", name)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	// A program that found races exits with status 66.
+	var exit *exec.ExitError
+	if err != nil && !(errors.As(err, &exit) && exit.ExitCode() == 66) {
+		return Result{}, fmt.Errorf("%s built with -race: %v\n%s", name, err, stderr.Bytes())
+	}
+	reports, err := Parse(&stderr)
+	return Result{Output: stdout.Bytes(), Reports: reports}, err
+}
+
+// Annotate writes every report in a form meant for reading: each access
+// with its goroutine, the line of source it happened on (found with
+// source, which may return false for code outside the examples) and the
+// note for the function it happened in.
+func Annotate(w io.Writer, reports []Report, notes []Note, source func(file string, line int) (string, bool)) error {
+	var b strings.Builder
+	for i, r := range reports {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "Data race %d of %d:\n", i+1, len(reports))
+		said := ""
+		for _, a := range r.Accesses {
+			who := "the main goroutine"
+			if a.Goroutine != 0 {
+				who = "goroutine " + strconv.Itoa(a.Goroutine)
+			}
+			f, note := frameFor(a.Stack, notes)
+			fmt.Fprintf(&b, "\n  %s at %s by %s, in %s\n", a.Op, a.Addr, who, shortFunc(f.Func))
+			if text, ok := source(f.File, f.Line); ok {
+				fmt.Fprintf(&b, "    %d  %s\n", f.Line, strings.TrimSpace(text))
+			}
+			// Both sides of a race are often the same line; explain it once.
+			if note != "" && note != said {
+				fmt.Fprintf(&b, "    ⇒ %s\n", note)
+				said = note
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// frameFor returns the innermost frame of stack that a note is about, and
+// the note. Without one it returns the innermost frame.
+func frameFor(stack []Frame, notes []Note) (Frame, string) {
+	for _, f := range stack {
+		for _, n := range notes {
+			if shortFunc(f.Func) == n.Func {
+				return f, n.Explain
+			}
+		}
+	}
+	if len(stack) == 0 {
+		return Frame{Func: "?"}, ""
+	}
+	return stack[0], ""
+}
+
+// shortFunc strips the import path and package name from a function name:
+// "github.com/x/races.increment" becomes "increment".
+func shortFunc(name string) string {
+	name = name[strings.LastIndex(name, "/")+1:]
+	_, rest, ok := strings.Cut(name, ".")
+	if !ok {
+		return name
+	}
+	return rest
+}
@@ -0,0 +1,203 @@
+// Package escape runs the compiler's escape analysis on a package and lines
+// its decisions up with the source code.
+//
+// "go build -gcflags=-m" makes the compiler explain, line by line, which
+// values it moved to the heap and which it proved can stay on the stack.
+// The raw output is hard to read next to the code, so this package parses
+// it and prints each decision right under the line it is about.
+//
+// An example can also say in its source what it expects the compiler to
+// decide, with a comment such as "// escape: heap" at the end of a line,
+// and Check holds those comments against the real decisions.
+package escape
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Decision is one escape-analysis verdict of the compiler.
+type Decision struct {
+	File    string // as printed by the compiler, relative to the build directory
+	Line    int
+	Col     int
+	Message string // e.g. "moved to heap: x" or "p does not escape"
+}
+
+// Kind classifies the decision: "heap" if the value was put on the heap,
+// "stack" if the compiler proved it can stay on the stack, or "leaks" for a
+// parameter whose pointer flows out of the function (which means the
+// caller's argument may have to be on the heap).
+func (d Decision) Kind() string {
+	switch {
+	case strings.HasPrefix(d.Message, "leaking param"):
+		return "leaks"
+	case strings.Contains(d.Message, "does not escape"):
+		return "stack"
+	}
+	return "heap"
+}
+
+// diagRE matches compiler diagnostics such as "dir/f.go:12:2: moved to heap: x".
+var diagRE = regexp.MustCompile(`^(.+\.go):(\d+):(\d+): (.*)$`)
+
+// relevant reports whether a compiler message is about escape analysis, as
+// opposed to inlining or other optimizer chatter.
+func relevant(msg string) bool {
+	return strings.HasSuffix(msg, "escapes to heap") ||
+		strings.HasSuffix(msg, "does not escape") ||
+		strings.HasPrefix(msg, "moved to heap") ||
+		strings.HasPrefix(msg, "leaking param")
+}
+
+// Analyze builds pkg (an import path or a "./dir" pattern) in dir with
+// -gcflags=-m and returns its escape decisions sorted by position. The
+// build cache replays earlier compiler output, so repeated runs are fast.
+func Analyze(ctx context.Context, dir, pkg string) ([]Decision, error) {
+	cmd := exec.CommandContext(ctx, "go", "build", "-gcflags=-m", "-o", os.DevNull, pkg)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go build -gcflags=-m %s: %v\n%s", pkg, err, stderr.Bytes())
+	}
+	return Parse(&stderr)
+}
+
+// Parse extracts escape decisions from the compiler's -m output.
+func Parse(r io.Reader) ([]Decision, error) {
+	var ds []Decision
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		m := diagRE.FindStringSubmatch(sc.Text())
+		if m == nil || !relevant(m[4]) {
+			continue
+		}
+		line, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		ds = append(ds, Decision{File: filepath.ToSlash(m[1]), Line: line, Col: col, Message: m[4]})
+	}
+	sort.SliceStable(ds, func(i, j int) bool {
+		if ds[i].File != ds[j].File {
+			return ds[i].File < ds[j].File
+		}
+		if ds[i].Line != ds[j].Line {
+			return ds[i].Line < ds[j].Line
+		}
+		return ds[i].Col < ds[j].Col
+	})
+	return ds, sc.Err()
+}
+
+// ForFile returns the decisions about the file whose slash-separated path
+// ends in name, e.g. "pointers/function_example.go".
+func ForFile(ds []Decision, name string) []Decision {
+	var out []Decision
+	for _, d := range ds {
+		if d.File == name || strings.HasSuffix(d.File, "/"+name) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Annotate writes src with line numbers, and under every line that has
+// decisions, one marker per decision pointing at its column:
+//
+//	12  	x := 42
+//	    	^ moved to heap: x
+//
+// Lines without decisions are kept so the code still reads as a whole.
+func Annotate(w io.Writer, src []byte, ds []Decision) error {
+	byLine := map[int][]Decision{}
+	for _, d := range ds {
+		byLine[d.Line] = append(byLine[d.Line], d)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+	width := len(strconv.Itoa(len(lines)))
+
+	bw := bufio.NewWriter(w)
+	for i, line := range lines {
+		n := i + 1
+		fmt.Fprintf(bw, "%*d  %s\n", width, n, line)
+		for _, d := range byLine[n] {
+			fmt.Fprintf(bw, "%*s  %s^ [%s] %s\n", width, "", indentTo(line, d.Col-1), d.Kind(), d.Message)
+		}
+	}
+	return bw.Flush()
+}
+
+// indentTo returns whitespace as wide as the first col bytes of line,
+// keeping tabs so the marker lines up with the code above it.
+func indentTo(line string, col int) string {
+	col = min(col, len(line))
+	var b strings.Builder
+	for _, r := range line[:col] {
+		if r == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// wantRE matches the comment an example puts at the end of a line to say
+// what the compiler decides there: "// escape: heap" or "// escape: stack".
+var wantRE = regexp.MustCompile(`//\s*escape:\s*(heap|stack)\s*$`)
+
+// Mismatch is an "escape:" comment the compiler disagrees with.
+type Mismatch struct {
+	Line int
+	Want string     // "heap" or "stack"
+	Got  []Decision // the decisions about the line, if any
+}
+
+func (m Mismatch) String() string {
+	if len(m.Got) == 0 {
+		return fmt.Sprintf("line %d: want %s, the compiler says nothing", m.Line, m.Want)
+	}
+	msgs := make([]string, len(m.Got))
+	for i, d := range m.Got {
+		msgs[i] = d.Message
+	}
+	return fmt.Sprintf("line %d: want %s, the compiler says %s", m.Line, m.Want, strings.Join(msgs, "; "))
+}
+
+// Check compares the "escape:" comments in src with the decisions about
+// it. A line that wants heap must have a heap decision; a line that wants
+// stack must have none (the compiler prints nothing at all for many values
+// that stay on the stack). Check returns the number of comments and the
+// ones that don't match.
+func Check(src []byte, ds []Decision) (n int, mismatches []Mismatch) {
+	byLine := map[int][]Decision{}
+	for _, d := range ds {
+		byLine[d.Line] = append(byLine[d.Line], d)
+	}
+	for i, line := range strings.Split(string(src), "\n") {
+		m := wantRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		n++
+		got := byLine[i+1]
+		heap := false
+		for _, d := range got {
+			heap = heap || d.Kind() == "heap"
+		}
+		if heap != (m[1] == "heap") {
+			mismatches = append(mismatches, Mismatch{Line: i + 1, Want: m[1], Got: got})
+		}
+	}
+	return n, mismatches
+}
@@ -0,0 +1,168 @@
+// Package event is how examples report what they observe.
+//
+// Instead of printing free-form text, an example records a stream of
+// events: narration lines, and observations of a variable's address or
+// value. A Sink decides how the stream is shown, as plain text for people
+// or as JSON lines for front-ends, graders and diffing tools.
+package event
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// Event is one thing an example reports.
+type Event struct {
+	// Step names the part of the example the event belongs to, e.g. "initial".
+	Step string `json:"step"`
+	// Variable is the expression being observed, e.g. "count" or "*valPtr".
+	Variable string `json:"variable,omitempty"`
+	// Address is the observed address, formatted with %p.
+	Address string `json:"address,omitempty"`
+	// Value is the observed value, formatted with %v.
+	Value string `json:"value,omitempty"`
+	// Varies marks a value that is expected to differ from run to run,
+	// such as a timing or the result of a data race.
+	Varies bool `json:"varies,omitempty"`
+	// Diagram is a multi-line drawing, e.g. from the memviz package.
+	Diagram string `json:"diagram,omitempty"`
+	// Warning marks narration the learner should watch out for.
+	Warning bool `json:"warning,omitempty"`
+	// Message is the human-readable line for this event.
+	Message string `json:"message"`
+	// File and Line say which line of the example recorded the event. They
+	// are only set in runs whose context came from WithCallers.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// Sink receives the events of a run.
+type Sink interface {
+	Emit(Event) error
+}
+
+type (
+	sinkKey       struct{}
+	translatorKey struct{}
+	callersKey    struct{}
+)
+
+// WithSink returns a context that makes From use s.
+func WithSink(ctx context.Context, s Sink) context.Context {
+	return context.WithValue(ctx, sinkKey{}, s)
+}
+
+// WithTranslator returns a context in which the text of every event is
+// passed through translate before it is formatted (see the i18n package).
+func WithTranslator(ctx context.Context, translate func(string) string) context.Context {
+	return context.WithValue(ctx, translatorKey{}, translate)
+}
+
+// WithCallers returns a context in which every event records the file and
+// line of the code that recorded it (see the explain command). It costs a
+// stack walk per event, so it is off by default.
+func WithCallers(ctx context.Context) context.Context {
+	return context.WithValue(ctx, callersKey{}, true)
+}
+
+// Emitter is the handle examples use to record events.
+type Emitter struct {
+	sink    Sink
+	tr      func(string) string
+	callers bool
+	step    string
+	err     error
+}
+
+// From returns an Emitter for an example run. It uses the Sink stored in
+// ctx by WithSink, or plain text written to w if there is none, so an
+// example behaves sensibly no matter who runs it.
+func From(ctx context.Context, w io.Writer) *Emitter {
+	s, ok := ctx.Value(sinkKey{}).(Sink)
+	if !ok {
+		s = NewTextSink(w)
+	}
+	tr, ok := ctx.Value(translatorKey{}).(func(string) string)
+	if !ok {
+		tr = func(s string) string { return s }
+	}
+	callers, _ := ctx.Value(callersKey{}).(bool)
+	return &Emitter{sink: s, tr: tr, callers: callers}
+}
+
+// Step starts a new step. Every event recorded afterwards belongs to it.
+func (e *Emitter) Step(name string) {
+	e.step = name
+}
+
+// Say records a narration line. The format (not the formatted result) is
+// what gets translated, so translations can move the arguments around.
+func (e *Emitter) Say(format string, args ...any) {
+	e.emit(Event{Message: fmt.Sprintf(e.tr(format), args...)})
+}
+
+// Warn records a narration line like Say, marked as something the learner
+// should watch out for, such as a common mistake.
+func (e *Emitter) Warn(format string, args ...any) {
+	e.emit(Event{Warning: true, Message: fmt.Sprintf(e.tr(format), args...)})
+}
+
+// Address records the address of variable, shown to people as "label: 0x...".
+// ptr is usually &variable, or the pointer variable itself.
+func (e *Emitter) Address(variable string, ptr any, label string) {
+	addr := fmt.Sprintf("%p", ptr)
+	e.emit(Event{Variable: variable, Address: addr, Message: e.tr(label) + ": " + addr})
+}
+
+// Value records the value of variable, shown to people as "label: value".
+func (e *Emitter) Value(variable string, v any, label string) {
+	val := fmt.Sprint(v)
+	e.emit(Event{Variable: variable, Value: val, Message: e.tr(label) + ": " + val})
+}
+
+// Varying records a value like Value, for values that are expected to
+// differ from run to run: timings, the order goroutines ran in, what a
+// data race left behind. Golden files (see the goldentest package) don't
+// compare them.
+func (e *Emitter) Varying(variable string, v any, label string) {
+	val := fmt.Sprint(v)
+	e.emit(Event{Variable: variable, Value: val, Varies: true, Message: e.tr(label) + ": " + val})
+}
+
+// Diagram records a multi-line drawing such as a memviz diagram. People
+// see the drawing itself; JSON consumers also get it in the Diagram field.
+func (e *Emitter) Diagram(d fmt.Stringer) {
+	text := d.String()
+	e.emit(Event{Diagram: text, Message: text})
+}
+
+// Forward records an event that was recorded somewhere else, such as an
+// example running in another process (see the plugin package). The event
+// is passed on as it is, but its step also becomes the current step.
+func (e *Emitter) Forward(ev Event) {
+	e.step = ev.Step
+	if e.err == nil {
+		e.err = e.sink.Emit(ev)
+	}
+}
+
+// Err returns the first error the sink reported, if any. Examples return
+// it from Run so that, say, a closed pipe stops the run with an error.
+func (e *Emitter) Err() error {
+	return e.err
+}
+
+// emit must be called directly by the exported methods, so that the
+// caller two frames up is the example.
+func (e *Emitter) emit(ev Event) {
+	if e.err != nil {
+		return
+	}
+	ev.Step = e.step
+	if e.callers {
+		_, ev.File, ev.Line, _ = runtime.Caller(2)
+	}
+	e.err = e.sink.Emit(ev)
+}
@@ -0,0 +1,117 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/style"
+)
+
+// NewSink returns the sink for an output format: "text" or "json". Text is
+// styled with st if it is enabled (see the style package).
+func NewSink(format string, w io.Writer, st style.Styler) (Sink, error) {
+	switch format {
+	case "text", "":
+		if st.Enabled() {
+			return NewStyledTextSink(w, st, style.Width(w, 0)), nil
+		}
+		return NewTextSink(w), nil
+	case "json":
+		return NewJSONSink(w), nil
+	}
+	return nil, fmt.Errorf("unknown output format %q (want text or json)", format)
+}
+
+// SinkFunc adapts an ordinary function to the Sink interface.
+type SinkFunc func(Event) error
+
+// Emit calls f(ev).
+func (f SinkFunc) Emit(ev Event) error {
+	return f(ev)
+}
+
+// textSink prints each event's message on its own line, with a blank line
+// between steps so that the output reads as paragraphs.
+type textSink struct {
+	w    io.Writer
+	step string
+	any  bool
+}
+
+// NewTextSink returns a Sink that writes events as plain text lines.
+func NewTextSink(w io.Writer) Sink {
+	return &textSink{w: w}
+}
+
+func (s *textSink) Emit(ev Event) error {
+	if s.any && ev.Step != s.step {
+		if _, err := io.WriteString(s.w, "\n"); err != nil {
+			return err
+		}
+	}
+	s.step, s.any = ev.Step, true
+	_, err := io.WriteString(s.w, ev.Message+"\n")
+	return err
+}
+
+// styledTextSink is a textSink for terminals: addresses, values and
+// warnings stand out, each step gets a dim header, and narration is
+// wrapped to the terminal's width.
+type styledTextSink struct {
+	w     io.Writer
+	st    style.Styler
+	width int
+	step  string
+	any   bool
+}
+
+// NewStyledTextSink returns a Sink that writes events as text styled with
+// st, wrapping narration at width columns (0 means no wrapping).
+func NewStyledTextSink(w io.Writer, st style.Styler, width int) Sink {
+	return &styledTextSink{w: w, st: st, width: width}
+}
+
+func (s *styledTextSink) Emit(ev Event) error {
+	var b strings.Builder
+	if !s.any || ev.Step != s.step {
+		if s.any {
+			b.WriteString("\n")
+		}
+		if ev.Step != "" {
+			b.WriteString(s.st.Dim("── "+ev.Step) + "\n")
+		}
+	}
+	s.step, s.any = ev.Step, true
+
+	switch {
+	case ev.Address != "" && strings.HasSuffix(ev.Message, ev.Address):
+		b.WriteString(strings.TrimSuffix(ev.Message, ev.Address) + s.st.Address(ev.Address))
+	case ev.Value != "" && strings.HasSuffix(ev.Message, ev.Value):
+		b.WriteString(strings.TrimSuffix(ev.Message, ev.Value) + s.st.Value(ev.Value))
+	case ev.Diagram != "":
+		b.WriteString(ev.Message)
+	case ev.Warning:
+		b.WriteString(s.st.Warning(strings.Join(style.Wrap("! "+ev.Message, s.width), "\n  ")))
+	default:
+		b.WriteString(strings.Join(style.Wrap(ev.Message, s.width), "\n"))
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(s.w, b.String())
+	return err
+}
+
+// jsonSink writes one JSON object per event.
+type jsonSink struct {
+	enc *json.Encoder
+}
+
+// NewJSONSink returns a Sink that writes events as JSON lines.
+func NewJSONSink(w io.Writer) Sink {
+	return jsonSink{enc: json.NewEncoder(w)}
+}
+
+func (s jsonSink) Emit(ev Event) error {
+	return s.enc.Encode(ev)
+}
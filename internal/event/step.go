@@ -0,0 +1,56 @@
+package event
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// stepSink forwards events to another sink and pauses after every
+// observation until the learner presses Enter.
+type stepSink struct {
+	next    Sink
+	in      *bufio.Reader
+	prompt  io.Writer
+	explain func(step string) string
+	step    string
+	eof     bool
+}
+
+// NewStepSink wraps next for step-through mode. After each event that
+// observes a variable it writes a prompt to prompt and waits for a line on
+// in. When a new step begins, explain(step) is shown first if it returns
+// a non-empty blurb; explain may be nil. Once in is exhausted the sink
+// stops pausing, so piped input doesn't hang a run.
+//
+// The prompt goes to its own writer so it never mixes into JSON output.
+func NewStepSink(next Sink, in io.Reader, prompt io.Writer, explain func(step string) string) Sink {
+	return &stepSink{next: next, in: bufio.NewReader(in), prompt: prompt, explain: explain}
+}
+
+func (s *stepSink) Emit(ev Event) error {
+	if ev.Step != s.step {
+		s.step = ev.Step
+		if s.explain != nil {
+			if blurb := s.explain(ev.Step); blurb != "" {
+				fmt.Fprintf(s.prompt, "\n  » %s\n", blurb)
+			}
+		}
+	}
+	if err := s.next.Emit(ev); err != nil {
+		return err
+	}
+	if ev.Variable == "" || s.eof {
+		return nil
+	}
+	fmt.Fprint(s.prompt, "  [press Enter to continue]")
+	if _, err := s.in.ReadString('\n'); err != nil {
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+		s.eof = true
+		fmt.Fprintln(s.prompt)
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+// Package exercise checks the learner's solutions to the exercises in the
+// exercises directory.
+//
+// Every exercise is a small package with a stub the learner edits. Its
+// checks are "hidden": they live in this package, not next to the stub, so
+// the stub only says what to do. To check a solution, the stub is copied
+// into a scratch module together with its checks, and the checks run as a
+// separate program. That way a stub that doesn't even compile is reported
+// as a failed check instead of breaking the concepts tool itself.
+//
+// The stub runs in the same program as its checks, so the checks report
+// through a channel the stub can't write to: every result line carries a
+// nonce the checker makes up for the run, the reporter lives at an import
+// path the stub can't know (see hidden/report), and a solution only passes
+// when exactly the exercise's Cases were reported, followed by the
+// end-of-run marker.
+package exercise
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+//go:embed hidden/*.go hidden/report/report.go
+var hidden embed.FS
+
+//go:embed solutions/*.go
+var solutions embed.FS
+
+// Exercise is one exercise.
+type Exercise struct {
+	// Name identifies the exercise, e.g. "pointers/swap". The stub lives in
+	// exercises/<Name> below the repository root.
+	Name string
+	// Summary is a one-line description of the task.
+	Summary string
+	// Hints nudge a stuck learner towards the solution, vaguest first.
+	Hints []string
+	// Cases names every check the hidden checks report, in order.
+	Cases []string
+}
+
+// all lists every exercise.
+var all = []Exercise{
+	{
+		Name:    "pointers/swap",
+		Summary: "make Swap(a, b *int) actually swap the two ints",
+		Hints: []string{
+			"a and b are copies of the caller's pointers. Swapping the copies changes nothing the caller can see.",
+			"Swap the ints the pointers point to, not the pointers: *a and *b can be assigned to, just like *valPtr in incrementValue.",
+		},
+		Cases: []string{
+			"Swap(&x, &y) with x=1, y=2",
+			"Swap(&x, &y) with x=-7, y=7",
+			"Swap(&x, &y) with x=5, y=5",
+			"Swap(&x, &y) with x=0, y=42",
+			"Swap(&x, &x) leaves x unchanged",
+		},
+	},
+	{
+		Name:    "pointers/minmax",
+		Summary: "make MinMax report its results through the min and max pointers",
+		Hints: []string{
+			"min and max are local variables holding addresses. Assigning a new address to them doesn't touch the caller's ints.",
+			"Write through the pointers: *min = ... stores into the int that min points to.",
+		},
+		Cases: []string{
+			"MinMax([3 1 2])",
+			"MinMax([7])",
+			"MinMax([-5 10 0 10 -5])",
+		},
+	},
+}
+
+// All returns every exercise, sorted by name.
+func All() []Exercise {
+	out := append([]Exercise(nil), all...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Lookup returns the exercise with the given name.
+func Lookup(name string) (Exercise, bool) {
+	for _, ex := range all {
+		if ex.Name == name {
+			return ex, true
+		}
+	}
+	return Exercise{}, false
+}
+
+// Dir returns the directory of the exercise's stub below the repository root.
+func (ex Exercise) Dir(root string) string {
+	return filepath.Join(root, "exercises", filepath.FromSlash(ex.Name))
+}
+
+// File returns the path of the stub's Go file below the repository root.
+func (ex Exercise) File(root string) string {
+	return filepath.Join(ex.Dir(root), path.Base(ex.Name)+".go")
+}
+
+// Solution returns the reference solution: the stub's file as it looks
+// once the exercise is solved.
+func (ex Exercise) Solution() ([]byte, error) {
+	src, err := solutions.ReadFile("solutions/" + strings.ReplaceAll(ex.Name, "/", "_") + ".go")
+	if err != nil {
+		return nil, err
+	}
+	return withoutIgnore(src), nil
+}
+
+// withoutIgnore drops the build constraint that keeps the embedded
+// sources out of this package.
+func withoutIgnore(src []byte) []byte {
+	return bytes.TrimPrefix(src, []byte("//go:build ignore\n\n"))
+}
+
+// harness returns the source of the exercise's hidden checks.
+func (ex Exercise) harness() ([]byte, error) {
+	return hidden.ReadFile("hidden/" + strings.ReplaceAll(ex.Name, "/", "_") + ".go")
+}
+
+// Case is the outcome of one hidden check.
+type Case struct {
+	Name   string
+	Passed bool
+	Detail string // why it failed
+}
+
+// Report is the outcome of checking an exercise.
+type Report struct {
+	// Cases holds one entry for each of the exercise's cases, in order,
+	// and one for anything else that went wrong.
+	Cases []Case
+	// BuildError is set when the stub and its checks didn't compile; Cases
+	// is empty in that case.
+	BuildError string
+	// Finished is set when the checks reported that they all ran.
+	Finished bool `json:",omitempty"`
+}
+
+// Passed reports whether the solution compiled, the checks ran to the
+// end, and every check passed.
+func (r Report) Passed() bool {
+	if r.BuildError != "" || !r.Finished || len(r.Cases) == 0 {
+		return false
+	}
+	for _, c := range r.Cases {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Check runs the hidden checks of ex against the learner's stub in root.
+// The error is only set when the check couldn't run at all; a failing or
+// non-compiling solution is described by the Report.
+func Check(ctx context.Context, root string, ex Exercise) (Report, error) {
+	return CheckDir(ctx, ex.Dir(root), ex)
+}
+
+// CheckDir is like Check, but takes the solution's Go files from dir
+// instead of from the stub's place in the repository.
+func CheckDir(ctx context.Context, dir string, ex Exercise) (Report, error) {
+	harness, err := ex.harness()
+	if err != nil {
+		return Report{}, fmt.Errorf("no hidden checks for %s: %w", ex.Name, err)
+	}
+
+	scratch, err := os.MkdirTemp("", "concepts-check-")
+	if err != nil {
+		return Report{}, err
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := copyStub(dir, filepath.Join(scratch, "stub")); err != nil {
+		return Report{}, err
+	}
+	report, err := hidden.ReadFile("hidden/report/report.go")
+	if err != nil {
+		return Report{}, err
+	}
+	// A stub that imported the reporter could report every case passed, so
+	// it goes in a directory the stub can't name.
+	dirName, err := newNonce()
+	if err != nil {
+		return Report{}, err
+	}
+	reportDir := "internal/" + dirName + "/report"
+	files := map[string][]byte{
+		"go.mod":                 []byte("module check\n\ngo 1.22\n"),
+		"main.go":                bytes.ReplaceAll(harness, []byte(`"check/internal/report"`), []byte(`"check/`+reportDir+`"`)),
+		reportDir + "/report.go": withoutIgnore(report),
+	}
+	for name, content := range files {
+		file := filepath.Join(scratch, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return Report{}, err
+		}
+		if err := os.WriteFile(file, content, 0o644); err != nil {
+			return Report{}, err
+		}
+	}
+
+	env := append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	build := exec.CommandContext(ctx, "go", "build", "-o", "check.exe", "main.go")
+	build.Dir, build.Env = scratch, env
+	if out, err := build.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(strings.ReplaceAll(string(out), scratch+string(filepath.Separator), ""))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return Report{BuildError: msg}, nil
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return Report{}, err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return Report{}, err
+	}
+	cmd := exec.CommandContext(ctx, filepath.Join(scratch, "check.exe"))
+	cmd.Dir, cmd.Env = scratch, env
+	cmd.ExtraFiles = []*os.File{r}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return Report{}, err
+	}
+	r.Close()
+	io.WriteString(w, nonce)
+	w.Close()
+	runErr := cmd.Wait()
+
+	rep := parse(&stdout, nonce, ex.Cases)
+	if runErr != nil {
+		rep.Cases = append(rep.Cases, Case{Name: "the checks finish without crashing", Detail: strings.TrimSpace(stderr.String())})
+	}
+	return rep, nil
+}
+
+// newNonce returns a random string to tell the checks' result lines from
+// anything the stub prints, or to name the reporter's directory.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// copyStub copies the Go files of the stub package (not its subdirectories).
+func copyStub(from, to string) error {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(to, 0o755); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(from, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(to, e.Name()), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse reads the "<nonce> PASS name", "<nonce> FAIL name: detail" and
+// "<nonce> DONE" lines that the hidden checks print, ignoring every line
+// without the nonce. It returns a Case for each of want, in order: a case
+// that wasn't reported, or was reported more than once, fails, and so does
+// a report of a case that isn't in want.
+func parse(out *bytes.Buffer, nonce string, want []string) Report {
+	var rep Report
+	got := map[string][]Case{}
+	var unknown []Case
+	sc := bufio.NewScanner(out)
+	for sc.Scan() {
+		line, ok := strings.CutPrefix(sc.Text(), nonce+" ")
+		if !ok || rep.Finished {
+			continue
+		}
+		var c Case
+		switch {
+		case line == "DONE":
+			rep.Finished = true
+			continue
+		case strings.HasPrefix(line, "PASS "):
+			c = Case{Name: strings.TrimPrefix(line, "PASS "), Passed: true}
+		case strings.HasPrefix(line, "FAIL "):
+			name, detail, _ := strings.Cut(strings.TrimPrefix(line, "FAIL "), ": ")
+			c = Case{Name: name, Detail: detail}
+		default:
+			continue
+		}
+		if !slices.Contains(want, c.Name) {
+			c.Passed, c.Detail = false, "not one of the exercise's checks"
+			unknown = append(unknown, c)
+			continue
+		}
+		got[c.Name] = append(got[c.Name], c)
+	}
+	for _, name := range want {
+		switch cs := got[name]; len(cs) {
+		case 0:
+			rep.Cases = append(rep.Cases, Case{Name: name, Detail: "never reported"})
+		case 1:
+			rep.Cases = append(rep.Cases, cs[0])
+		default:
+			rep.Cases = append(rep.Cases, Case{Name: name, Detail: fmt.Sprintf("reported %d times", len(cs))})
+		}
+	}
+	rep.Cases = append(rep.Cases, unknown...)
+	if !rep.Finished {
+		rep.Cases = append(rep.Cases, Case{Name: "the checks run to the end", Detail: "the program stopped before every check had run"})
+	}
+	return rep
+}
@@ -0,0 +1,180 @@
+package exercise
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// checkSource checks src as the whole of ex's stub.
+func checkSource(t *testing.T, ex Exercise, src string) Report {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, filepath.Base(ex.Name)+".go")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := CheckDir(context.Background(), dir, ex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rep
+}
+
+func lookup(t *testing.T, name string) Exercise {
+	t.Helper()
+	ex, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("no exercise %s", name)
+	}
+	return ex
+}
+
+func TestSolutionsPass(t *testing.T) {
+	for _, ex := range All() {
+		t.Run(ex.Name, func(t *testing.T) {
+			src, err := ex.Solution()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rep := checkSource(t, ex, string(src))
+			if !rep.Passed() {
+				t.Fatalf("the reference solution doesn't pass: %+v", rep)
+			}
+			if len(rep.Cases) != len(ex.Cases) {
+				t.Errorf("%d cases reported, want %d", len(rep.Cases), len(ex.Cases))
+			}
+		})
+	}
+}
+
+func TestStubsFail(t *testing.T) {
+	for _, ex := range All() {
+		t.Run(ex.Name, func(t *testing.T) {
+			rep, err := Check(context.Background(), filepath.Join("..", ".."), ex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rep.Passed() {
+				t.Fatal("the unsolved stub passes")
+			}
+		})
+	}
+}
+
+func TestForgedResultsFail(t *testing.T) {
+	ex := lookup(t, "pointers/swap")
+	forgeries := map[string]string{
+		"PASS lines": `package swap
+
+import (
+	"fmt"
+	"os"
+)
+
+func init() {
+	fmt.Println("PASS Swap(&x, &y) with x=1, y=2")
+	os.Exit(0)
+}
+
+func Swap(a, b *int) {}
+`,
+		"the nonce's descriptor": `package swap
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func init() {
+	data, _ := io.ReadAll(os.NewFile(3, "nonce"))
+	nonce := strings.TrimSpace(string(data))
+	for _, c := range []string{"Swap(&x, &y) with x=1, y=2", "Swap(&x, &y) with x=-7, y=7", "Swap(&x, &y) with x=5, y=5", "Swap(&x, &y) with x=0, y=42", "Swap(&x, &x) leaves x unchanged"} {
+		fmt.Println(nonce, "PASS", c)
+	}
+	fmt.Println(nonce, "DONE")
+	os.Exit(0)
+}
+
+func Swap(a, b *int) {}
+`,
+		"importing the reporter": `package swap
+
+import (
+	"os"
+
+	"check/internal/report"
+)
+
+func init() {
+	for _, c := range []string{"Swap(&x, &y) with x=1, y=2", "Swap(&x, &y) with x=-7, y=7", "Swap(&x, &y) with x=5, y=5", "Swap(&x, &y) with x=0, y=42", "Swap(&x, &x) leaves x unchanged"} {
+		report.Pass(c)
+	}
+	report.Done()
+	os.Exit(0)
+}
+
+func Swap(a, b *int) {}
+`,
+		"exiting before the checks end": `package swap
+
+import "os"
+
+func Swap(a, b *int) {
+	*a, *b = *b, *a
+	if *a == 2 {
+		os.Exit(0)
+	}
+}
+`,
+	}
+	for name, src := range forgeries {
+		t.Run(name, func(t *testing.T) {
+			if rep := checkSource(t, ex, src); rep.Passed() {
+				t.Fatalf("a forged run passes: %+v", rep)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	want := []string{"a", "b"}
+	tests := []struct {
+		name   string
+		out    string
+		passed bool
+		cases  int
+	}{
+		{"every case", "n PASS a\nn PASS b\nn DONE\n", true, 2},
+		{"no marker", "n PASS a\nn PASS b\n", false, 3},
+		{"a missing case", "n PASS a\nn DONE\n", false, 2},
+		{"a case twice", "n PASS a\nn PASS a\nn PASS b\nn DONE\n", false, 2},
+		{"an unknown case", "n PASS a\nn PASS b\nn PASS c\nn DONE\n", false, 3},
+		{"lines without the nonce", "PASS a\nPASS b\nDONE\n", false, 3},
+		{"lines after the marker", "n PASS a\nn DONE\nn PASS b\n", false, 2},
+		{"a failure", "n PASS a\nn FAIL b: wrong\nn DONE\n", false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := parse(bytes.NewBufferString(tt.out), "n", want)
+			if rep.Passed() != tt.passed || len(rep.Cases) != tt.cases {
+				t.Errorf("Passed() = %v with %d cases, want %v with %d: %+v", rep.Passed(), len(rep.Cases), tt.passed, tt.cases, rep)
+			}
+		})
+	}
+}
+
+func TestBuildError(t *testing.T) {
+	rep := checkSource(t, lookup(t, "pointers/swap"), "package swap\n\nfunc Swap(a, b *int) { return 1 }\n")
+	if rep.BuildError == "" || rep.Passed() {
+		t.Fatalf("a stub that doesn't compile: %+v", rep)
+	}
+	if !strings.Contains(rep.BuildError, "too many return values") {
+		t.Errorf("BuildError = %q", rep.BuildError)
+	}
+}
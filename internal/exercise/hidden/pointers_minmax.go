@@ -0,0 +1,35 @@
+//go:build ignore
+
+// Hidden checks for exercises/pointers/minmax. The checker copies the
+// learner's package to ./stub next to this file, builds them, and runs
+// the result.
+package main
+
+import (
+	"fmt"
+
+	"check/internal/report"
+	stub "check/stub"
+)
+
+func main() {
+	cases := []struct {
+		nums     []int
+		min, max int
+	}{
+		{[]int{3, 1, 2}, 1, 3},
+		{[]int{7}, 7, 7},
+		{[]int{-5, 10, 0, 10, -5}, -5, 10},
+	}
+	for _, c := range cases {
+		lo, hi := -999, -999
+		stub.MinMax(c.nums, &lo, &hi)
+		name := fmt.Sprintf("MinMax(%v)", c.nums)
+		if lo == c.min && hi == c.max {
+			report.Pass(name)
+		} else {
+			report.Fail(name, "got min=%d, max=%d, want min=%d, max=%d", lo, hi, c.min, c.max)
+		}
+	}
+	report.Done()
+}
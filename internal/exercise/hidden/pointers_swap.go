@@ -0,0 +1,36 @@
+//go:build ignore
+
+// Hidden checks for exercises/pointers/swap. The checker copies the
+// learner's package to ./stub next to this file, builds them, and runs
+// the result.
+package main
+
+import (
+	"fmt"
+
+	"check/internal/report"
+	stub "check/stub"
+)
+
+func main() {
+	for _, c := range []struct{ x, y int }{{1, 2}, {-7, 7}, {5, 5}, {0, 42}} {
+		x, y := c.x, c.y
+		stub.Swap(&x, &y)
+		name := fmt.Sprintf("Swap(&x, &y) with x=%d, y=%d", c.x, c.y)
+		if x == c.y && y == c.x {
+			report.Pass(name)
+		} else {
+			report.Fail(name, "got x=%d, y=%d, want x=%d, y=%d", x, y, c.y, c.x)
+		}
+	}
+
+	// Swapping a variable with itself must leave it alone.
+	x := 3
+	stub.Swap(&x, &x)
+	if x == 3 {
+		report.Pass("Swap(&x, &x) leaves x unchanged")
+	} else {
+		report.Fail("Swap(&x, &x) leaves x unchanged", "got x=%d, want 3", x)
+	}
+	report.Done()
+}
@@ -0,0 +1,97 @@
+// Package explain renders the guided-reading view of an example: its
+// source cut into numbered blocks, one per step of a run, each introduced
+// by the step's explanation and with the values the run actually observed
+// printed under the lines that observed them.
+//
+// The events must come from a run with event.WithCallers, so that each
+// one knows its line. A block spans the lines of its step's events, plus
+// the lines just above the first one (up to the previous blank line or
+// closing brace), which is where a step usually sets up what it shows.
+package explain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/event"
+)
+
+// block is the part of the source belonging to one step.
+type block struct {
+	step     string
+	from, to int                   // 1-based, inclusive
+	events   map[int][]event.Event // by line
+}
+
+// Write renders src, the source of file, as explained by events. Events
+// recorded outside file (by a helper package, say) are left out. explain
+// may be nil.
+func Write(w io.Writer, file string, src []byte, events []event.Event, explain func(step string) string) error {
+	lines := strings.Split(strings.TrimRight(string(src), "\n"), "\n")
+	blocks := split(file, lines, events)
+	if len(blocks) == 0 {
+		return fmt.Errorf("no events were recorded in %s", file)
+	}
+
+	var b strings.Builder
+	for i, bl := range blocks {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%d] %s (lines %d-%d)\n", i+1, bl.step, bl.from, bl.to)
+		if explain != nil {
+			if text := explain(bl.step); text != "" {
+				fmt.Fprintf(&b, "    %s\n", text)
+			}
+		}
+		b.WriteString("\n")
+		for n := bl.from; n <= bl.to; n++ {
+			fmt.Fprintf(&b, "%4d  %s\n", n, strings.ReplaceAll(lines[n-1], "\t", "    "))
+			for _, ev := range bl.events[n] {
+				for j, text := range strings.Split(ev.Message, "\n") {
+					marker := "⇒"
+					if j > 0 {
+						marker = " "
+					}
+					fmt.Fprintf(&b, "        %s %s\n", marker, text)
+				}
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// split groups consecutive events of the same step into blocks.
+func split(file string, lines []string, events []event.Event) []block {
+	var blocks []block
+	for _, ev := range events {
+		if ev.Line < 1 || ev.Line > len(lines) || !sameFile(ev.File, file) {
+			continue
+		}
+		if len(blocks) == 0 || blocks[len(blocks)-1].step != ev.Step {
+			blocks = append(blocks, block{step: ev.Step, from: ev.Line, to: ev.Line, events: map[int][]event.Event{}})
+		}
+		bl := &blocks[len(blocks)-1]
+		bl.from, bl.to = min(bl.from, ev.Line), max(bl.to, ev.Line)
+		bl.events[ev.Line] = append(bl.events[ev.Line], ev)
+	}
+	for i := range blocks {
+		bl := &blocks[i]
+		for bl.from > 1 {
+			prev := strings.TrimSpace(lines[bl.from-2])
+			if prev == "" || prev == "}" {
+				break
+			}
+			bl.from--
+		}
+	}
+	return blocks
+}
+
+// sameFile reports whether path, as recorded by the runtime, is file: the
+// same path, or one ending in "/" + file.
+func sameFile(path, file string) bool {
+	return path == file || strings.HasSuffix(path, "/"+file)
+}
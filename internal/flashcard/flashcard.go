@@ -0,0 +1,115 @@
+// Package flashcard schedules flashcard reviews with the SM-2 algorithm.
+//
+// An example opts in by implementing Deck. Each card's review history is
+// kept in the progress store; after every review the learner grades how
+// well they remembered (0 = blackout, 5 = perfect) and SM-2 decides how
+// many days to wait before showing the card again.
+package flashcard
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/progress"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Card is one question/answer pair.
+type Card struct {
+	Front string
+	Back  string
+}
+
+// Deck is implemented by concepts that come with flashcards.
+type Deck interface {
+	registry.Concept
+	Flashcards() []Card
+}
+
+// Item is a card together with the example it belongs to.
+type Item struct {
+	Example string
+	Card
+}
+
+// ID identifies the card in the progress store. It is derived from the
+// card's question, so reordering an example's cards keeps their history.
+func (it Item) ID() string {
+	return it.Example + ": " + it.Front
+}
+
+// All returns the cards of every registered example.
+func All() []Item {
+	var items []Item
+	for _, c := range registry.All() {
+		d, ok := c.(Deck)
+		if !ok {
+			continue
+		}
+		for _, card := range d.Flashcards() {
+			items = append(items, Item{Example: c.Describe().Name, Card: card})
+		}
+	}
+	return items
+}
+
+// Due returns the items that are due on or before now, most overdue first.
+// Cards that were never reviewed are always due and come last.
+func Due(items []Item, state map[string]progress.Card, now time.Time) []Item {
+	var due []Item
+	for _, it := range items {
+		if st, ok := state[it.ID()]; !ok || !st.Due.After(now) {
+			due = append(due, it)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		a, aok := state[due[i].ID()]
+		b, bok := state[due[j].ID()]
+		if aok != bok {
+			return aok
+		}
+		return a.Due.Before(b.Due)
+	})
+	return due
+}
+
+// initialEase is SM-2's starting ease factor; minEase is its floor.
+const (
+	initialEase = 2.5
+	minEase     = 1.3
+)
+
+// Review applies one SM-2 review with the given quality (0..5) to a card's
+// state and returns the new state. The next due date is counted in whole
+// days from the start of now's day, so everything reviewed today comes up
+// again on the same morning.
+func Review(st progress.Card, quality int, now time.Time) progress.Card {
+	quality = min(max(quality, 0), 5)
+	if st.Ease == 0 {
+		st.Ease = initialEase
+	}
+
+	if quality < 3 {
+		// Forgotten: start the repetitions over, but keep the ease change.
+		st.Reps = 0
+		st.Interval = 1
+	} else {
+		switch st.Reps {
+		case 0:
+			st.Interval = 1
+		case 1:
+			st.Interval = 6
+		default:
+			st.Interval = int(math.Round(float64(st.Interval) * st.Ease))
+		}
+		st.Reps++
+	}
+
+	q := float64(5 - quality)
+	st.Ease = max(minEase, st.Ease+0.1-q*(0.08+q*0.02))
+
+	y, m, d := now.Date()
+	st.Due = time.Date(y, m, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, st.Interval)
+	return st
+}
@@ -0,0 +1,69 @@
+package flashcard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Grade is how well the learner remembered one card.
+type Grade struct {
+	Item
+	Quality int
+}
+
+// Session shows cards on out and reads the learner's key presses, one per
+// line, from in.
+type Session struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewSession returns a session reading from in and writing to out.
+func NewSession(in io.Reader, out io.Writer) *Session {
+	return &Session{in: bufio.NewReader(in), out: out}
+}
+
+// Review shows every item's front, waits for Enter, shows the back and asks
+// for a grade. It stops early, returning the grades so far, if in runs out
+// of lines.
+func (s *Session) Review(items []Item) ([]Grade, error) {
+	var grades []Grade
+	for i, it := range items {
+		fmt.Fprintf(s.out, "\nCard %d of %d (from %s)\n%s\n(press Enter to see the answer) ", i+1, len(items), it.Example, it.Front)
+		if _, ok, err := s.line(); !ok {
+			return grades, err
+		}
+		fmt.Fprintf(s.out, "%s\n", it.Back)
+
+		for {
+			fmt.Fprint(s.out, "How well did you remember it? 0 (not at all) to 5 (perfectly): ")
+			answer, ok, err := s.line()
+			if !ok {
+				return grades, err
+			}
+			q, err := strconv.Atoi(strings.TrimSpace(answer))
+			if err != nil || q < 0 || q > 5 {
+				continue
+			}
+			grades = append(grades, Grade{Item: it, Quality: q})
+			break
+		}
+	}
+	return grades, nil
+}
+
+// line reads one line. ok is false once in is exhausted or fails.
+func (s *Session) line() (string, bool, error) {
+	l, err := s.in.ReadString('\n')
+	if err == io.EOF && l == "" {
+		fmt.Fprintln(s.out)
+		return "", false, nil
+	}
+	if err != nil && err != io.EOF {
+		return "", false, err
+	}
+	return l, true, nil
+}
@@ -0,0 +1,47 @@
+package goldentest
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Diff returns a line diff from want to got: lines only in want start with
+// "-", lines only in got with "+", and shared lines with " ". The output of
+// an example is a few dozen lines, so the quadratic LCS table is fine.
+func Diff(want, got []byte) string {
+	a := strings.Split(strings.TrimSuffix(string(want), "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(string(got), "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			buf.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			buf.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			buf.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,115 @@
+// Package goldentest checks that an example still prints what it printed
+// when its golden file was last recorded.
+//
+// Addresses change from run to run, so before comparing, every "0x..."
+// address in the output is replaced by a numbered placeholder. Equal
+// addresses get the same placeholder, even when one is zero-padded (as in
+// memviz diagrams) and the other isn't, which keeps the interesting part
+// of the output (which things share memory) under test. Values an example
+// records with event.Emitter.Varying are replaced by "<varies>".
+package goldentest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// varies stands in for values that differ from run to run.
+const varies = "<varies>"
+
+var addrRE = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+
+// Normalize replaces each distinct address in out with "<addr1>",
+// "<addr2>", ... in order of first appearance.
+func Normalize(out []byte) []byte {
+	return Normalizer()(out)
+}
+
+// Normalizer returns a function that normalizes like Normalize, but keeps
+// numbering addresses across calls. That lets output that arrives piece
+// by piece be normalized as if it were one block.
+func Normalizer() func([]byte) []byte {
+	seen := map[uint64]string{}
+	return func(out []byte) []byte {
+		return addrRE.ReplaceAllFunc(out, func(addr []byte) []byte {
+			n, err := strconv.ParseUint(string(addr[2:]), 16, 64)
+			if err != nil {
+				return addr // too long to be an address
+			}
+			p, ok := seen[n]
+			if !ok {
+				p = "<addr" + strconv.Itoa(len(seen)+1) + ">"
+				seen[n] = p
+			}
+			return []byte(p)
+		})
+	}
+}
+
+// Path returns the golden file of an example inside dir.
+func Path(dir, name string) string {
+	return filepath.Join(dir, filepath.FromSlash(name)+".golden")
+}
+
+// ErrMissing is returned by Check when an example has no golden file yet.
+var ErrMissing = errors.New("no golden file (run with -update to record one)")
+
+// MismatchError is returned by Check when the output changed.
+type MismatchError struct {
+	Name string
+	Diff string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s: output differs from its golden file:\n%s", e.Name, e.Diff)
+}
+
+// Check runs c and compares its normalized output with its golden file in
+// dir. With update set, it (re)writes the golden file instead.
+func Check(ctx context.Context, c registry.Concept, dir string, update bool) error {
+	name := c.Describe().Name
+	var out bytes.Buffer
+	text := event.NewTextSink(&out)
+	ctx = event.WithSink(ctx, event.SinkFunc(func(ev event.Event) error {
+		if ev.Varies {
+			ev.Message = strings.TrimSuffix(ev.Message, ev.Value) + varies
+			ev.Value = varies
+		}
+		return text.Emit(ev)
+	}))
+	if err := c.Run(ctx, &out); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	got := Normalize(out.Bytes())
+	path := Path(dir, name)
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, got, 0o644)
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: %w", name, ErrMissing)
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, want) {
+		return &MismatchError{Name: name, Diff: Diff(want, got)}
+	}
+	return nil
+}
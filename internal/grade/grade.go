@@ -0,0 +1,238 @@
+// Package grade is a grading server for running the exercises as
+// coursework: students upload their solution to an exercise, the server
+// checks it against the exercise's hidden checks and keeps every
+// student's results, and the instructor sees them all on one dashboard.
+//
+// Every submission is checked in a scratch directory of its own (see
+// exercise.CheckDir), with a time limit, and only a few at a time. The
+// checks report through a per-run nonce, so a submission can't pass by
+// printing results of its own. It is not isolated, though: it runs with
+// the server's permissions, and can read and change whatever the server
+// can, other submissions included. When grading code from people you
+// don't trust, run the server in a container or a throwaway VM.
+//
+//	GET  /                          the dashboard
+//	GET  /submit                    an upload form for students
+//	POST /submit/{exercise...}      upload: form fields "student" and "files" (.go files)
+//	GET  /results.json              every result, for exporting
+package grade
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amandm/programming-concepts/internal/exercise"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+const (
+	// checkTimeout bounds how long checking one submission may take,
+	// compiling included.
+	checkTimeout = time.Minute
+	// maxUpload is the largest submission accepted, all files together.
+	maxUpload = 1 << 20
+	// parallelChecks is how many submissions are checked at the same time.
+	parallelChecks = 2
+)
+
+// server holds what the handlers need.
+type server struct {
+	store *Store
+	slots chan struct{}
+}
+
+// NewHandler returns the grading server's HTTP handler, recording results
+// in store.
+func NewHandler(store *Store) http.Handler {
+	s := &server{store: store, slots: make(chan struct{}, parallelChecks)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.dashboard)
+	mux.HandleFunc("GET /submit", s.form)
+	mux.HandleFunc("POST /submit/{exercise...}", s.submit)
+	mux.HandleFunc("GET /results.json", s.results)
+	return mux
+}
+
+// row is one student on the dashboard.
+type row struct {
+	Student string
+	Cells   []cell
+}
+
+// cell is one student's result for one exercise.
+type cell struct {
+	Tried    bool
+	Result   Result
+	Exercise string
+}
+
+// Checks summarizes the latest attempt, e.g. "2/3".
+func (c cell) Checks() string {
+	if c.Result.Report.BuildError != "" {
+		return "doesn't compile"
+	}
+	passed := 0
+	for _, k := range c.Result.Report.Cases {
+		if k.Passed {
+			passed++
+		}
+	}
+	return fmt.Sprintf("%d/%d", passed, len(c.Result.Report.Cases))
+}
+
+// dashboard shows a table of every student's results.
+func (s *server) dashboard(w http.ResponseWriter, r *http.Request) {
+	exercises := exercise.All()
+	var rows []row
+	for _, student := range s.store.Students() {
+		rw := row{Student: student}
+		for _, ex := range exercises {
+			res, ok := s.store.Result(student, ex.Name)
+			rw.Cells = append(rw.Cells, cell{Tried: ok, Result: res, Exercise: ex.Name})
+		}
+		rows = append(rows, rw)
+	}
+	s.render(w, "dashboard.html", struct {
+		Exercises []exercise.Exercise
+		Rows      []row
+	}{exercises, rows})
+}
+
+// form shows the upload form.
+func (s *server) form(w http.ResponseWriter, r *http.Request) {
+	s.render(w, "submit.html", exercise.All())
+}
+
+// Submission is the reply to an upload.
+type Submission struct {
+	Student  string          `json:"student"`
+	Exercise string          `json:"exercise"`
+	Attempts int             `json:"attempts"`
+	Passed   bool            `json:"passed"`
+	Report   exercise.Report `json:"report"`
+}
+
+// submit checks an uploaded solution and records the result. It replies
+// with JSON, or with a readable page when the upload came from the form.
+func (s *server) submit(w http.ResponseWriter, r *http.Request) {
+	ex, ok := exercise.Lookup(r.PathValue("exercise"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown exercise %q", r.PathValue("exercise")), http.StatusNotFound)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+	if err := r.ParseMultipartForm(maxUpload); err != nil {
+		http.Error(w, "reading the upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	student := strings.TrimSpace(r.FormValue("student"))
+	if student == "" || utf8.RuneCountInString(student) > 64 {
+		http.Error(w, "the student field must hold a name of at most 64 characters", http.StatusBadRequest)
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "concepts-grade-")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(dir)
+	if err := saveFiles(r, dir); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-r.Context().Done():
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+	rep, err := exercise.CheckDir(ctx, dir, ex)
+	if err != nil {
+		http.Error(w, "checking: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		rep.Cases = append(rep.Cases, exercise.Case{Name: "the checks finish in time", Detail: "stopped after " + checkTimeout.String()})
+	}
+
+	res, err := s.store.Record(student, ex.Name, rep, time.Now())
+	if err != nil {
+		log.Printf("grade: saving the result of %s for %s: %v", ex.Name, student, err)
+		http.Error(w, "the result could not be saved", http.StatusInternalServerError)
+		return
+	}
+	sub := Submission{student, ex.Name, res.Attempts, rep.Passed(), rep}
+	if r.FormValue("html") != "" {
+		s.render(w, "result.html", sub)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// saveFiles writes the uploaded .go files into dir. Only the base name of
+// each file is used, so an upload can't write outside dir.
+func saveFiles(r *http.Request, dir string) error {
+	files := r.MultipartForm.File["files"]
+	if len(files) == 0 {
+		return fmt.Errorf("no files uploaded (use the form field \"files\")")
+	}
+	for _, fh := range files {
+		name := filepath.Base(fh.Filename)
+		if !strings.HasSuffix(name, ".go") || strings.HasPrefix(name, ".") {
+			return fmt.Errorf("%s: only .go files can be submitted", fh.Filename)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// results returns every result as JSON.
+func (s *server) results(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.store)
+}
+
+// render executes a template into a buffer first, so that a template error
+// turns into a clean 500 instead of a half-written page.
+func (s *server) render(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("grade: rendering %s: %v", name, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,114 @@
+package grade
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/exercise"
+)
+
+// Result is one student's record for one exercise.
+type Result struct {
+	Attempts int       `json:"attempts"`
+	Passed   bool      `json:"passed"` // some attempt passed every check
+	Last     time.Time `json:"last"`
+	// Report is the outcome of the latest attempt.
+	Report exercise.Report `json:"report"`
+}
+
+// Store keeps every student's results in a JSON file. It is safe for
+// concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	students map[string]map[string]Result // student → exercise → result
+}
+
+// OpenStore loads the results file at path. A missing file is not an
+// error: nobody has submitted anything yet.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, students: map[string]map[string]Result{}}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.students); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Record adds an attempt and saves the file.
+func (s *Store) Record(student, ex string, rep exercise.Report, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.students[student] == nil {
+		s.students[student] = map[string]Result{}
+	}
+	r := s.students[student][ex]
+	r.Attempts++
+	r.Passed = r.Passed || rep.Passed()
+	r.Last = now
+	r.Report = rep
+	s.students[student][ex] = r
+	return r, s.save()
+}
+
+// Students returns the names of everyone who submitted something, sorted.
+func (s *Store) Students() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.students))
+	for name := range s.students {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Result returns a student's result for an exercise.
+func (s *Store) Result(student, ex string) (Result, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.students[student][ex]
+	return r, ok
+}
+
+// MarshalJSON returns every result, for exporting.
+func (s *Store) MarshalJSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Marshal(s.students)
+}
+
+// save writes the file atomically, like the progress store. s.mu must be held.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.students, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".grades-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
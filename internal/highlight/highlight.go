@@ -0,0 +1,123 @@
+// Package highlight adds syntax highlighting to Go source code.
+//
+// It uses go/scanner, so it understands Go's real token rules (raw strings,
+// runes, comments) without pulling in a third-party highlighter.
+package highlight
+
+import (
+	"go/scanner"
+	"go/token"
+	"html"
+	"strings"
+)
+
+// Class is the kind of a highlighted token. It is also used as the CSS class
+// name in HTML output.
+type Class string
+
+const (
+	Plain   Class = ""
+	Keyword Class = "kw"
+	String  Class = "str"
+	Number  Class = "num"
+	Comment Class = "com"
+	Builtin Class = "builtin"
+)
+
+// builtins are the predeclared identifiers worth calling out.
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true,
+	"max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+	"any": true, "bool": true, "byte": true, "comparable": true, "error": true,
+	"float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"true": true, "false": true, "iota": true, "nil": true,
+}
+
+// Span is a piece of the source with a single class. Concatenating the Text
+// of all spans returned by Spans gives back the original source.
+type Span struct {
+	Class Class
+	Text  string
+}
+
+// Spans splits src into classified spans. Whitespace and anything the
+// scanner doesn't classify ends up in Plain spans.
+func Spans(src []byte) []Span {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	// Errors are ignored on purpose: a half-typed snippet should still be shown.
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	var spans []Span
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// Semicolons inserted automatically at line ends aren't in the source.
+		if tok == token.SEMICOLON && lit != ";" {
+			continue
+		}
+		start := file.Offset(pos)
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+		end := start + len(text)
+		if start < last || end > len(src) {
+			continue
+		}
+
+		if start > last {
+			spans = append(spans, Span{Plain, string(src[last:start])})
+		}
+		spans = append(spans, Span{classOf(tok, lit), string(src[start:end])})
+		last = end
+	}
+	if last < len(src) {
+		spans = append(spans, Span{Plain, string(src[last:])})
+	}
+	return spans
+}
+
+// classOf maps a scanned token to its highlighting class.
+func classOf(tok token.Token, lit string) Class {
+	switch {
+	case tok.IsKeyword():
+		return Keyword
+	case tok == token.COMMENT:
+		return Comment
+	case tok == token.STRING || tok == token.CHAR:
+		return String
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return Number
+	case tok == token.IDENT && builtins[lit]:
+		return Builtin
+	}
+	return Plain
+}
+
+// HTML returns src as escaped HTML in which every classified token is
+// wrapped in <span class="...">. The result is meant to go inside a <pre>.
+func HTML(src []byte) string {
+	var b strings.Builder
+	for _, sp := range Spans(src) {
+		if sp.Class == Plain {
+			b.WriteString(html.EscapeString(sp.Text))
+			continue
+		}
+		b.WriteString(`<span class="`)
+		b.WriteString(string(sp.Class))
+		b.WriteString(`">`)
+		b.WriteString(html.EscapeString(sp.Text))
+		b.WriteString(`</span>`)
+	}
+	return b.String()
+}
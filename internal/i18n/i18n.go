@@ -0,0 +1,67 @@
+// Package i18n translates the text that examples show to learners.
+//
+// Catalogs are keyed by the English text itself, gettext style: an example
+// keeps calling e.Say("Initial state:") and the catalog for "es" maps that
+// string to "Estado inicial:". Anything missing from a catalog is shown in
+// English, so a partial translation is still useful.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed catalogs/*.json
+var catalogs embed.FS
+
+// English is the language the examples are written in. It needs no catalog.
+const English = "en"
+
+// Languages returns the codes of every available language, English first.
+func Languages() []string {
+	langs := []string{English}
+	entries, _ := fs.ReadDir(catalogs, "catalogs")
+	var others []string
+	for _, e := range entries {
+		others = append(others, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(others)
+	return append(langs, others...)
+}
+
+// Translator returns the function translating English text into lang. A
+// region suffix is ignored, so "es_MX.UTF-8" and "es-MX" both select "es".
+func Translator(lang string) (func(string) string, error) {
+	lang = base(lang)
+	if lang == English || lang == "" {
+		return func(s string) string { return s }, nil
+	}
+	data, err := catalogs.ReadFile(path.Join("catalogs", lang+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("no translation for language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	var catalog map[string]string
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("catalog %s: %w", lang, err)
+	}
+	return func(s string) string {
+		if t, ok := catalog[s]; ok && t != "" {
+			return t
+		}
+		return s
+	}, nil
+}
+
+// base strips the region and encoding from a locale name.
+func base(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
@@ -0,0 +1,298 @@
+// Package isolate runs small programs in a process of their own, so that
+// they can do what no example may do inside the runner: crash it with a
+// fatal error such as "all goroutines are asleep - deadlock!", or hang.
+//
+// A program is registered by name, usually in the init function of the
+// example that shows it. Run starts the current executable again with
+// the program's name in $CONCEPTS_ISOLATE; the executable's main calls
+// Main first thing, which sees the variable, runs the program and exits.
+// Run then parses what the runtime printed on the way down.
+//
+// The runtime can't tell that a program linked with cgo is deadlocked
+// (the C side might still wake it up), and the concepts command usually
+// is: the net package uses cgo where a C compiler is around. So programs
+// registered with RegisterDeadlock run from a copy of the executable built
+// without cgo, with "go test -c" if it is a test binary. The copy is built
+// by the first Run that needs it and reused by the rest; that takes the go
+// command and the repository, like "concepts race", and Cleanup removes
+// the copy before the program exits.
+//
+// Every other program runs from the current executable itself, so a
+// program started from an executable built with -race runs under the race
+// detector too. Run passes the race detector's reports from the program's
+// standard error on to its own, where "concepts race" reads them.
+package isolate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// envVar names the program a re-executed process should run.
+const envVar = "CONCEPTS_ISOLATE"
+
+// ErrUnsupported is returned by Run where a program can't start a process
+// of its own, such as in a browser under js/wasm.
+var ErrUnsupported = errors.New("isolate: running a program in a process of its own is unsupported on " + runtime.GOOS)
+
+var (
+	mu       sync.Mutex
+	programs = map[string]program{}
+	// tmpDir holds the executable rebuilt without cgo, if there is one.
+	tmpDir string
+)
+
+// program is a registered program.
+type program struct {
+	main func()
+	// deadlocks is set for programs registered with RegisterDeadlock.
+	deadlocks bool
+}
+
+// Register makes main runnable by Run under name. It panics if name is
+// already registered.
+func Register(name string, main func()) {
+	register(name, program{main: main})
+}
+
+// RegisterDeadlock is like Register, for a program that is meant to
+// deadlock: Run starts it from an executable built without cgo, so that
+// the runtime notices.
+func RegisterDeadlock(name string, main func()) {
+	register(name, program{main: main, deadlocks: true})
+}
+
+func register(name string, p program) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := programs[name]; dup {
+		panic("isolate: program " + name + " registered twice")
+	}
+	programs[name] = p
+}
+
+// Main runs the program named in $CONCEPTS_ISOLATE and exits, if the
+// variable is set; otherwise it returns at once. Programs that use Run
+// must call it at the start of main, before anything else has started
+// goroutines of its own.
+func Main() {
+	name := os.Getenv(envVar)
+	if name == "" {
+		return
+	}
+	mu.Lock()
+	p, ok := programs[name]
+	mu.Unlock()
+	if !ok {
+		fmt.Fprintf(os.Stderr, "isolate: no program %q\n", name)
+		os.Exit(2)
+	}
+	p.main()
+	os.Exit(0)
+}
+
+// Goroutine is one goroutine of a crash report.
+type Goroutine struct {
+	ID int
+	// State is what the goroutine was doing, e.g. "chan send" or
+	// "sync.Mutex.Lock".
+	State string
+	// Func, File and Line say where it was: the innermost frame outside
+	// the runtime and the standard library's sync packages.
+	Func string
+	File string
+	Line int
+}
+
+// Result is the outcome of running a program.
+type Result struct {
+	// Stdout is what the program printed.
+	Stdout []byte
+	// Fatal is the runtime's fatal error without its "fatal error: "
+	// prefix, or the panic message without "panic: ", if there was one.
+	Fatal string
+	// Goroutines are the goroutines the runtime listed, in its order.
+	Goroutines []Goroutine
+	// ExitCode is the process's exit status, or -1 if it was killed.
+	ExitCode int
+	// TimedOut reports that the program was killed for running too long.
+	TimedOut bool
+}
+
+// Run runs the program called name in a new process, and kills it if it
+// hasn't finished after timeout. env are extra "key=value" environment
+// variables for the process, such as GOMAXPROCS or GODEBUG settings. A
+// program that crashes or hangs is not an error; not being able to start
+// it is.
+func Run(ctx context.Context, name string, timeout time.Duration, env ...string) (Result, error) {
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		return Result{}, ErrUnsupported
+	}
+	mu.Lock()
+	p, ok := programs[name]
+	mu.Unlock()
+	if !ok {
+		return Result{}, fmt.Errorf("isolate: no program %q", name)
+	}
+	exe, err := os.Executable()
+	if p.deadlocks {
+		exe, err = withoutCgo()
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("isolate: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, exe)
+	cmd.Env = append(append(os.Environ(), env...), envVar+"="+name)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	var exit *exec.ExitError
+	if err != nil && !errors.As(err, &exit) {
+		return Result{}, fmt.Errorf("isolate: running %s: %v", name, err)
+	}
+	os.Stderr.Write(raceReports(stderr.Bytes()))
+	fatal, goroutines := Parse(stderr.Bytes())
+	return Result{
+		Stdout:     stdout.Bytes(),
+		Fatal:      fatal,
+		Goroutines: goroutines,
+		ExitCode:   cmd.ProcessState.ExitCode(),
+		TimedOut:   errors.Is(ctx.Err(), context.DeadlineExceeded),
+	}, nil
+}
+
+// withoutCgo returns an executable that can run the registered programs
+// and detect their deadlocks: the current one, or a copy of it built
+// without cgo. The copy is built once per process, and not with the
+// context of the Run that happens to come first, since every later Run
+// starts it too.
+var withoutCgo = sync.OnceValues(func() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok || !cgo(info) {
+		return exe, nil
+	}
+	tmp, err := os.MkdirTemp("", "concepts-isolate-")
+	if err != nil {
+		return "", err
+	}
+	bin := filepath.Join(tmp, filepath.Base(exe))
+	build := exec.Command("go", "build", "-o", bin, info.Path)
+	if pkg, ok := strings.CutSuffix(info.Path, ".test"); ok {
+		// A test binary, such as the golden test's, whose TestMain calls
+		// Main.
+		build = exec.Command("go", "test", "-c", "-o", bin, pkg)
+	}
+	build.Env = append(os.Environ(), "CGO_ENABLED=0")
+	if out, err := build.CombinedOutput(); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("rebuilding %s without cgo (run from inside the repository): %v\n%s", info.Path, err, out)
+	}
+	mu.Lock()
+	tmpDir = tmp
+	mu.Unlock()
+	return bin, nil
+})
+
+// Cleanup removes the copy of the executable that Run built, if it built
+// one. Programs that use Run call it before they exit; Run fails after it.
+func Cleanup() {
+	mu.Lock()
+	defer mu.Unlock()
+	if tmpDir != "" {
+		os.RemoveAll(tmpDir)
+		tmpDir = ""
+	}
+}
+
+// cgo reports whether the executable described by info was built with cgo.
+func cgo(info *debug.BuildInfo) bool {
+	for _, s := range info.Settings {
+		if s.Key == "CGO_ENABLED" {
+			return s.Value == "1"
+		}
+	}
+	return false
+}
+
+// raceReports returns the race detector's reports in a program's standard
+// error, each between its two lines of "=".
+func raceReports(stderr []byte) []byte {
+	var out bytes.Buffer
+	in := false
+	for line := range bytes.Lines(stderr) {
+		sep := string(line) == "==================\n"
+		if in || sep {
+			out.Write(line)
+		}
+		if sep {
+			in = !in
+		}
+	}
+	return out.Bytes()
+}
+
+var (
+	headerRE = regexp.MustCompile(`^goroutine (\d+) \[([^\],]+)`)
+	frameRE  = regexp.MustCompile(`^(\S+)\(.*\)$`)
+	fileRE   = regexp.MustCompile(`^\s+(\S+):(\d+)`)
+)
+
+// Parse reads a crashing program's standard error: the fatal error or
+// panic message, and the goroutines listed after it.
+func Parse(stderr []byte) (fatal string, goroutines []Goroutine) {
+	var g *Goroutine
+	fn := ""
+	sc := bufio.NewScanner(bytes.NewReader(stderr))
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case fatal == "" && strings.HasPrefix(line, "fatal error: "):
+			fatal = strings.TrimPrefix(line, "fatal error: ")
+		case fatal == "" && strings.HasPrefix(line, "panic: "):
+			fatal = strings.TrimPrefix(line, "panic: ")
+		case headerRE.MatchString(line):
+			m := headerRE.FindStringSubmatch(line)
+			id, _ := strconv.Atoi(m[1])
+			goroutines = append(goroutines, Goroutine{ID: id, State: m[2]})
+			g = &goroutines[len(goroutines)-1]
+		case g == nil || g.Func != "":
+		case frameRE.MatchString(line):
+			fn = frameRE.FindStringSubmatch(line)[1]
+		case fileRE.MatchString(line) && fn != "" && !library(fn):
+			m := fileRE.FindStringSubmatch(line)
+			g.Func, g.File = fn, m[1]
+			g.Line, _ = strconv.Atoi(m[2])
+		}
+	}
+	return fatal, goroutines
+}
+
+// library reports whether fn belongs to the runtime or the sync
+// packages, whose frames sit on top of every blocked goroutine.
+func library(fn string) bool {
+	for _, pkg := range []string{"runtime.", "sync.", "internal/", "time."} {
+		if strings.HasPrefix(fn, pkg) {
+			return true
+		}
+	}
+	return false
+}
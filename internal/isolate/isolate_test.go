@@ -0,0 +1,29 @@
+package isolate
+
+import "testing"
+
+func TestRaceReports(t *testing.T) {
+	stderr := "starting\n" +
+		"==================\n" +
+		"WARNING: DATA RACE\n" +
+		"Read at 0x00c000012345 by goroutine 7:\n" +
+		"==================\n" +
+		"between\n" +
+		"==================\n" +
+		"WARNING: DATA RACE\n" +
+		"==================\n" +
+		"Found 2 data race(s)\n"
+	want := "==================\n" +
+		"WARNING: DATA RACE\n" +
+		"Read at 0x00c000012345 by goroutine 7:\n" +
+		"==================\n" +
+		"==================\n" +
+		"WARNING: DATA RACE\n" +
+		"==================\n"
+	if got := string(raceReports([]byte(stderr))); got != want {
+		t.Errorf("raceReports kept\n%s\nwant\n%s", got, want)
+	}
+	if got := raceReports([]byte("panic: boom\n")); len(got) != 0 {
+		t.Errorf("raceReports of a panic = %q, want nothing", got)
+	}
+}
@@ -0,0 +1,158 @@
+// Package layout shows how the compiler lays a struct out in memory: where
+// each field starts, how many bytes of padding alignment puts between
+// them, and how much smaller the struct would be with its fields in a
+// better order.
+//
+// An example makes its types available to "concepts layout" by
+// implementing Catalog, the way it makes its benchmarks available to
+// "concepts bench" by implementing benchlab.Lab.
+package layout
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/table"
+)
+
+// Catalog is implemented by concepts whose struct types are worth laying
+// out.
+type Catalog interface {
+	registry.Concept
+	// Layouts returns a value of each struct type, usually its zero value.
+	Layouts() []any
+}
+
+// All returns the struct types of every Catalog in the registry by name,
+// e.g. "memory.badOrder", together with the example each comes from.
+func All() map[string]Entry {
+	types := map[string]Entry{}
+	for _, c := range registry.All() {
+		cat, ok := c.(Catalog)
+		if !ok {
+			continue
+		}
+		for _, v := range cat.Layouts() {
+			t := reflect.TypeOf(v)
+			types[t.String()] = Entry{Type: t, Example: c.Describe().Name}
+		}
+	}
+	return types
+}
+
+// Entry is a type from a Catalog.
+type Entry struct {
+	Type    reflect.Type
+	Example string
+}
+
+// Field is one field of a struct layout.
+type Field struct {
+	Name   string
+	Type   string
+	Offset uintptr
+	Size   uintptr
+	Align  uintptr
+	// Padding is the number of unused bytes between the end of this
+	// field and the start of the next one (or the end of the struct).
+	Padding uintptr
+}
+
+// Layout is where a struct's fields are.
+type Layout struct {
+	Type   string
+	Fields []Field
+	Size   uintptr
+	Align  uintptr
+}
+
+// Of returns the layout of the struct type t, as the compiler chose it.
+func Of(t reflect.Type) (Layout, error) {
+	if t.Kind() != reflect.Struct {
+		return Layout{}, fmt.Errorf("layout: %s is not a struct", t)
+	}
+	l := Layout{Type: t.String(), Size: t.Size(), Align: uintptr(t.Align())}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		l.Fields = append(l.Fields, Field{
+			Name:   f.Name,
+			Type:   f.Type.String(),
+			Offset: f.Offset,
+			Size:   f.Type.Size(),
+			Align:  uintptr(f.Type.Align()),
+		})
+	}
+	l.pad()
+	return l, nil
+}
+
+// pad fills in each field's Padding from the offsets and the size.
+func (l *Layout) pad() {
+	for i := range l.Fields {
+		next := l.Size
+		if i+1 < len(l.Fields) {
+			next = l.Fields[i+1].Offset
+		}
+		l.Fields[i].Padding = next - l.Fields[i].Offset - l.Fields[i].Size
+	}
+}
+
+// Padding returns the total number of padding bytes in the struct.
+func (l Layout) Padding() uintptr {
+	var n uintptr
+	for _, f := range l.Fields {
+		n += f.Padding
+	}
+	return n
+}
+
+// Reordered returns the layout the same fields would have sorted by
+// decreasing alignment, which leaves padding only at the end, if at all.
+// Zero-size fields go first: at the end of a struct the compiler pads
+// them, so that a pointer to one doesn't point past the struct.
+func (l Layout) Reordered() Layout {
+	r := Layout{Type: l.Type + " (reordered)", Fields: slices.Clone(l.Fields), Align: l.Align}
+	slices.SortStableFunc(r.Fields, func(a, b Field) int {
+		if (a.Size == 0) != (b.Size == 0) {
+			if a.Size == 0 {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.Align, a.Align)
+	})
+	var off uintptr
+	for i := range r.Fields {
+		off = roundUp(off, r.Fields[i].Align)
+		r.Fields[i].Offset = off
+		off += r.Fields[i].Size
+	}
+	r.Size = roundUp(off, r.Align)
+	r.pad()
+	return r
+}
+
+// roundUp rounds n up to a multiple of align.
+func roundUp(n, align uintptr) uintptr {
+	return (n + align - 1) / align * align
+}
+
+// String draws the layout as a table with a row per field and per run of
+// padding, followed by the totals.
+func (l Layout) String() string {
+	t := table.New("offset", "field", "type", "size", "align")
+	for _, f := range l.Fields {
+		t.Row(f.Offset, f.Name, f.Type, f.Size, f.Align)
+		if f.Padding > 0 {
+			t.Row(f.Offset+f.Size, "(padding)", "", f.Padding, "")
+		}
+	}
+	var b strings.Builder
+	b.WriteString(t.String())
+	fmt.Fprintf(&b, "\n%s: %d bytes, aligned to %d, of which %d are padding", l.Type, l.Size, l.Align, l.Padding())
+	return b.String()
+}
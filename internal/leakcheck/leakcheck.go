@@ -0,0 +1,104 @@
+// Package leakcheck finds goroutines that were started and never ended.
+//
+// Take a Snapshot before the code under suspicion runs and call Leaked
+// after it returns: every goroutine that exists then but didn't before,
+// and doesn't end within a grace period, is reported with the state and
+// line it is stuck on. Examples use it to check the claim that a fixed
+// version leaks nothing.
+package leakcheck
+
+import (
+	"bytes"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Goroutine is a goroutine found in a stack dump.
+type Goroutine struct {
+	ID int
+	// State is what the goroutine is doing, e.g. "chan send".
+	State string
+	// Func, File and Line are its innermost frame outside the runtime.
+	Func string
+	File string
+	Line int
+}
+
+// Snapshot is the set of goroutines that existed at some moment.
+type Snapshot struct {
+	ids map[int]bool
+}
+
+// Take records the goroutines that exist now.
+func Take() Snapshot {
+	s := Snapshot{ids: map[int]bool{}}
+	for _, g := range goroutines() {
+		s.ids[g.ID] = true
+	}
+	return s
+}
+
+// Leaked returns the goroutines that exist now but not in s, sorted by
+// ID. Goroutines that are about to end get up to grace to do so.
+func (s Snapshot) Leaked(grace time.Duration) []Goroutine {
+	deadline := time.Now().Add(grace)
+	for {
+		var leaked []Goroutine
+		for _, g := range goroutines() {
+			if !s.ids[g.ID] {
+				leaked = append(leaked, g)
+			}
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+var (
+	headerRE = regexp.MustCompile(`^goroutine (\d+) \[([^\],]+)`)
+	frameRE  = regexp.MustCompile(`^(\S+)\(.*\)$`)
+	fileRE   = regexp.MustCompile(`^\s+(\S+):(\d+)`)
+)
+
+// goroutines returns every goroutine but the calling one.
+func goroutines() []Goroutine {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	var gs []Goroutine
+	// The first block is the calling goroutine, which is running this.
+	for i, block := range bytes.Split(buf, []byte("\n\n")) {
+		lines := strings.Split(string(block), "\n")
+		m := headerRE.FindStringSubmatch(lines[0])
+		if i == 0 || m == nil {
+			continue
+		}
+		g := Goroutine{State: m[2]}
+		g.ID, _ = strconv.Atoi(m[1])
+		for j := 1; j+1 < len(lines); j += 2 {
+			fn := frameRE.FindStringSubmatch(lines[j])
+			file := fileRE.FindStringSubmatch(lines[j+1])
+			if fn == nil || file == nil || strings.HasPrefix(fn[1], "runtime.") {
+				continue
+			}
+			g.Func, g.File = fn[1], file[1]
+			g.Line, _ = strconv.Atoi(file[2])
+			break
+		}
+		gs = append(gs, g)
+	}
+	slices.SortFunc(gs, func(a, b Goroutine) int { return a.ID - b.ID })
+	return gs
+}
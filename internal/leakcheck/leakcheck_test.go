@@ -0,0 +1,56 @@
+package leakcheck
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// blocked waits on ch, the line the leak is reported at.
+func blocked(ch chan int) {
+	<-ch
+}
+
+func TestLeaked(t *testing.T) {
+	before := Take()
+	ch := make(chan int)
+	go blocked(ch)
+	leaked := before.Leaked(10 * time.Millisecond)
+	if len(leaked) != 1 {
+		t.Fatalf("Leaked() = %+v, want the one blocked goroutine", leaked)
+	}
+	g := leaked[0]
+	if !strings.HasSuffix(g.Func, ".blocked") || g.State != "chan receive" || !strings.HasSuffix(g.File, "leakcheck_test.go") || g.Line != 11 {
+		t.Errorf("the leak is reported as %+v, want blocked, on a chan receive at leakcheck_test.go:11", g)
+	}
+
+	close(ch)
+	if leaked := before.Leaked(time.Second); len(leaked) > 0 {
+		t.Errorf("the goroutine has ended, but Leaked() = %+v", leaked)
+	}
+}
+
+func TestGracePeriod(t *testing.T) {
+	before := Take()
+	go time.Sleep(20 * time.Millisecond)
+	if leaked := before.Leaked(time.Second); len(leaked) > 0 {
+		t.Errorf("a goroutine that ends within the grace period is reported: %+v", leaked)
+	}
+}
+
+func TestNothingStarted(t *testing.T) {
+	if leaked := Take().Leaked(0); len(leaked) > 0 {
+		t.Errorf("Leaked() = %+v with no goroutine started", leaked)
+	}
+}
+
+func TestGoroutinesSkipsTheCaller(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() { <-done }()
+	for _, g := range goroutines() {
+		if g.Func == "github.com/amandm/programming-concepts/internal/leakcheck.TestGoroutinesSkipsTheCaller" {
+			t.Errorf("goroutines() lists the goroutine calling it: %+v", g)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package lesson turns an example's source code into a Markdown handout.
+//
+// The handout is built from the code itself: the package comment becomes
+// the introduction, every top-level declaration becomes a section made of
+// its doc comment followed by its code, and the example's captured output
+// closes the lesson. Regenerating it after a code change can't drift.
+package lesson
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// boilerplate lists declarations that only wire an example into the tools
+// (registration, metadata, step explanations, quiz questions, benchmarks)
+// and would distract in a lesson.
+var boilerplate = map[string]bool{
+	"init":        true,
+	"Describe":    true,
+	"Explain":     true,
+	"Questions":   true,
+	"Flashcards":  true,
+	"Variants":    true,
+	"Experiments": true,
+	"RaceNotes":   true,
+}
+
+// Generate writes the lesson for the example described by md. src is the
+// example's source file and output is what running it printed.
+func Generate(md registry.Metadata, src, output []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, md.Name+".go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", md.Name)
+	fmt.Fprintf(&b, "> %s\n\n", md.Description)
+	fmt.Fprintf(&b, "**Topic:** %s · **Level:** %s", md.Topic, md.Level)
+	if len(md.Tags) > 0 {
+		fmt.Fprintf(&b, " · **Tags:** %s", strings.Join(md.Tags, ", "))
+	}
+	b.WriteString("\n\n")
+	if f.Doc != nil {
+		b.WriteString(f.Doc.Text() + "\n")
+	}
+
+	for _, decl := range f.Decls {
+		title, doc, ok := describeDecl(decl)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "## %s\n\n", title)
+		if doc != nil {
+			b.WriteString(doc.Text() + "\n")
+		}
+		// Slicing the source keeps comments inside the code exactly as written.
+		code := src[fset.Position(decl.Pos()).Offset:fset.Position(decl.End()).Offset]
+		fmt.Fprintf(&b, "```go\n%s\n```\n\n", code)
+	}
+
+	b.WriteString("## Sample output\n\n")
+	b.WriteString("Addresses differ on every run, so they are shown as `<addr1>`, `<addr2>`, ...;\n")
+	b.WriteString("the same placeholder always means the same address.\n\n")
+	fmt.Fprintf(&b, "```\n%s```\n", output)
+	return b.Bytes(), nil
+}
+
+// describeDecl returns the section title and doc comment for a top-level
+// declaration, or ok == false if it shouldn't be part of the lesson.
+func describeDecl(decl ast.Decl) (title string, doc *ast.CommentGroup, ok bool) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if boilerplate[d.Name.Name] {
+			return "", nil, false
+		}
+		title = d.Name.Name
+		if d.Recv != nil && len(d.Recv.List) == 1 {
+			title = receiverName(d.Recv.List[0].Type) + "." + title
+		}
+		return title, d.Doc, true
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return "", nil, false
+		}
+		var names []string
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					names = append(names, n.Name)
+				}
+			}
+		}
+		doc = d.Doc
+		if doc == nil && len(d.Specs) == 1 {
+			if s, ok := d.Specs[0].(*ast.TypeSpec); ok {
+				doc = s.Doc
+			}
+		}
+		return strings.Join(names, ", "), doc, true
+	}
+	return "", nil, false
+}
+
+// receiverName returns the type name of a method receiver, e.g. "T" for (t *T).
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.IndexExpr: // generic receiver T[P]
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return "?"
+}
@@ -0,0 +1,204 @@
+// Package memviz draws box-and-arrow ASCII diagrams of variables in memory.
+//
+// A diagram is a list of frames (think: stack frames of the functions
+// involved), each holding variables. A variable that holds a pointer gets
+// an arrow to the variable it points at, so a learner can see that valPtr
+// points at count instead of having to compare hex addresses by eye:
+//
+//	┌─ Run ───────────────────────────────────────────────┐
+//	│ count   0x000000c000012345   11                     │◄──┐
+//	└─────────────────────────────────────────────────────┘   │
+//	┌─ incrementValue ────────────────────────────────────┐   │
+//	│ valPtr  0x000000c000012388   0x000000c000012345     │───┘
+//	└─────────────────────────────────────────────────────┘
+package memviz
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// addrWidth is the width of a formatted address ("0x" and 16 digits).
+// Addresses are always padded to 64 bits so a diagram has the same shape
+// on every run, which keeps golden files stable.
+const addrWidth = 18
+
+// Diagram is a set of frames to draw.
+type Diagram struct {
+	frames []*Frame
+}
+
+// Frame is one box in the diagram, usually a function's stack frame.
+type Frame struct {
+	name string
+	vars []variable
+}
+
+type variable struct {
+	name     string
+	addr     uintptr
+	value    string
+	pointsTo uintptr // 0 if the variable isn't a non-nil pointer
+}
+
+// New returns an empty diagram.
+func New() *Diagram {
+	return &Diagram{}
+}
+
+// Frame adds a frame called name below the existing ones and returns it.
+func (d *Diagram) Frame(name string) *Frame {
+	f := &Frame{name: name}
+	d.frames = append(d.frames, f)
+	return f
+}
+
+// Var adds a variable to the frame. ptr must be a pointer to the variable,
+// e.g. &count: its address is where the variable lives, and what it points
+// at is the variable's value. If that value is itself a non-nil pointer,
+// the diagram draws an arrow to whichever variable lives at that address.
+// A non-nil slice is drawn as its header, data pointer, len and cap, with
+// the arrow going to its first element, so slices sharing one backing
+// array point into the same column of element rows. Var returns f so
+// calls can be chained.
+func (f *Frame) Var(name string, ptr any) *Frame {
+	p := reflect.ValueOf(ptr)
+	if p.Kind() != reflect.Pointer || p.IsNil() {
+		panic(fmt.Sprintf("memviz: Var(%q) needs a non-nil pointer to the variable, got %T", name, ptr))
+	}
+	v := variable{name: name, addr: p.Pointer()}
+	elem := p.Elem()
+	switch {
+	case elem.Kind() == reflect.Pointer && elem.IsNil():
+		v.value = "nil"
+	case elem.Kind() == reflect.Pointer:
+		v.pointsTo = elem.Pointer()
+		v.value = formatAddr(v.pointsTo)
+	case elem.Kind() == reflect.Slice && !elem.IsNil():
+		v.pointsTo = elem.Pointer()
+		v.value = fmt.Sprintf("data %s len %d cap %d", formatAddr(v.pointsTo), elem.Len(), elem.Cap())
+	default:
+		v.value = fmt.Sprint(elem.Interface())
+	}
+	f.vars = append(f.vars, v)
+	return f
+}
+
+// formatAddr formats an address as a fixed-width hex number.
+func formatAddr(a uintptr) string {
+	return fmt.Sprintf("0x%016x", a)
+}
+
+// row is one line of the drawing, remembering which variable it shows.
+type row struct {
+	text string
+	v    *variable // nil for box borders
+}
+
+// String renders the diagram.
+func (d *Diagram) String() string {
+	nameWidth, valueWidth := 0, 0
+	for _, f := range d.frames {
+		for _, v := range f.vars {
+			nameWidth = max(nameWidth, utf8.RuneCountInString(v.name))
+			valueWidth = max(valueWidth, utf8.RuneCountInString(v.value))
+		}
+	}
+	inner := nameWidth + 2 + addrWidth + 3 + valueWidth
+	for _, f := range d.frames {
+		inner = max(inner, utf8.RuneCountInString(f.name)+4)
+	}
+
+	var rows []row
+	for _, f := range d.frames {
+		title := "─ " + f.name + " "
+		rows = append(rows, row{text: "┌" + title + strings.Repeat("─", inner+2-utf8.RuneCountInString(title)) + "┐"})
+		for i := range f.vars {
+			v := &f.vars[i]
+			line := pad(v.name, nameWidth) + "  " + formatAddr(v.addr) + "   " + pad(v.value, valueWidth)
+			rows = append(rows, row{text: "│ " + pad(line, inner) + " │", v: v})
+		}
+		rows = append(rows, row{text: "└" + strings.Repeat("─", inner+2) + "┘"})
+	}
+
+	gutters := drawArrows(rows)
+	var b strings.Builder
+	for i, r := range rows {
+		b.WriteString(strings.TrimRight(r.text+string(gutters[i]), " "))
+		if i < len(rows)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// drawArrows returns, for every row, the characters to the right of the
+// boxes. Each pointer gets its own vertical lane so arrows never overlap:
+// the arrow leaves the pointer's row, runs along its lane and comes back
+// in at the pointee's row with an arrowhead.
+func drawArrows(rows []row) [][]rune {
+	type arrow struct{ from, to int }
+	var arrows []arrow
+	for from, r := range rows {
+		if r.v == nil || r.v.pointsTo == 0 {
+			continue
+		}
+		for to, t := range rows {
+			if t.v != nil && t.v.addr == r.v.pointsTo {
+				arrows = append(arrows, arrow{from, to})
+				break
+			}
+		}
+	}
+
+	width := 0
+	if len(arrows) > 0 {
+		width = 3*len(arrows) + 1
+	}
+	gutters := make([][]rune, len(rows))
+	for i := range gutters {
+		gutters[i] = []rune(strings.Repeat(" ", width))
+	}
+
+	for lane, a := range arrows {
+		col := 3 * (lane + 1)
+		top, bottom := min(a.from, a.to), max(a.from, a.to)
+		for r := top + 1; r < bottom; r++ {
+			put(gutters[r], col, '│')
+		}
+		for c := 0; c < col; c++ {
+			put(gutters[a.from], c, '─')
+			put(gutters[a.to], c, '─')
+		}
+		gutters[a.to][0] = '◄'
+
+		switch {
+		case a.from == a.to: // a pointer to itself
+			gutters[a.to][col] = '┘'
+		case a.from < a.to:
+			gutters[a.from][col] = '┐'
+			gutters[a.to][col] = '┘'
+		default:
+			gutters[a.from][col] = '┘'
+			gutters[a.to][col] = '┐'
+		}
+	}
+	return gutters
+}
+
+// put draws r at g[col], turning a line crossing another line into '┼'.
+func put(g []rune, col int, r rune) {
+	switch {
+	case g[col] == ' ':
+		g[col] = r
+	case g[col] != r && (g[col] == '│' || g[col] == '─'):
+		g[col] = '┼'
+	}
+}
+
+// pad right-pads s with spaces to width runes.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
@@ -0,0 +1,198 @@
+// Package plugin loads third-party example packs that run as separate
+// programs.
+//
+// A pack is an executable (usually built with concepts.Serve) placed in a
+// pack directory. The CLI starts every pack with the argument "describe"
+// and reads a JSON Description of its examples from stdout; the examples
+// are then registered like built-in ones. Running one starts the pack
+// again with "run <name>", and the pack writes the example's events to
+// stdout as JSON lines, the same format as "concepts run -format=json".
+// A run that fails prints its error to stderr and exits with a non-zero
+// status.
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Description is what a pack prints for "describe".
+type Description struct {
+	Pack     string    `json:"pack"`
+	Examples []Example `json:"examples"`
+}
+
+// Example describes one example of a pack.
+type Example struct {
+	Metadata  registry.Metadata `json:"metadata"`
+	Source    string            `json:"source,omitempty"`
+	Questions []quiz.Question   `json:"questions,omitempty"`
+	Cards     []flashcard.Card  `json:"cards,omitempty"`
+}
+
+// describeTimeout bounds how long a pack may take to describe itself, so
+// that a broken pack can't hang every command.
+const describeTimeout = 10 * time.Second
+
+// DefaultDirs returns the directories packs are loaded from: the list in
+// $CONCEPTS_PACKS if it is set, otherwise "concepts/packs" in the user's
+// configuration directory.
+func DefaultDirs() ([]string, error) {
+	if env := os.Getenv("CONCEPTS_PACKS"); env != "" {
+		return filepath.SplitList(env), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return []string{filepath.Join(dir, "concepts", "packs")}, nil
+}
+
+var (
+	mu      sync.RWMutex
+	sources = map[string][]byte{} // by example name + ".go"
+)
+
+// Load registers the examples of every executable in dirs. Directories
+// that don't exist are skipped. A pack that fails to load doesn't stop the
+// others; all the errors are returned joined together.
+func Load(ctx context.Context, dirs []string) error {
+	var errs []error
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+				continue
+			}
+			if err := loadPack(ctx, filepath.Join(dir, e.Name())); err != nil {
+				errs = append(errs, fmt.Errorf("pack %s: %w", e.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadPack describes and registers one pack.
+func loadPack(ctx context.Context, path string) error {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "describe")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("describe: %w%s", err, stderrSuffix(&stderr))
+	}
+	var d Description
+	if err := json.Unmarshal(out, &d); err != nil {
+		return fmt.Errorf("describe: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, ex := range d.Examples {
+		md := ex.Metadata
+		if md.Name == "" || md.Topic == "" {
+			return errors.New("an example without Name or Topic")
+		}
+		if seen[md.Name] {
+			return fmt.Errorf("example %s is described twice", md.Name)
+		}
+		seen[md.Name] = true
+		if _, dup := registry.Lookup(md.Name); dup {
+			return fmt.Errorf("example %s is already registered", md.Name)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, ex := range d.Examples {
+		registry.Register(&packExample{path: path, pack: d.Pack, ex: ex})
+		if ex.Source != "" {
+			sources[ex.Metadata.Name+".go"] = []byte(ex.Source)
+		}
+	}
+	return nil
+}
+
+// stderrSuffix formats what a pack wrote to stderr for an error message.
+func stderrSuffix(stderr *bytes.Buffer) string {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return ": " + msg
+	}
+	return ""
+}
+
+// packExample is a registered example that runs inside its pack.
+type packExample struct {
+	path string
+	pack string
+	ex   Example
+}
+
+func (p *packExample) Describe() registry.Metadata { return p.ex.Metadata }
+
+func (p *packExample) Questions() []quiz.Question { return p.ex.Questions }
+
+func (p *packExample) Flashcards() []flashcard.Card { return p.ex.Cards }
+
+// Run starts the pack and forwards the events it prints.
+func (p *packExample) Run(ctx context.Context, w io.Writer) error {
+	e := event.From(ctx, w)
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path, "run", p.ex.Metadata.Name)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var ev event.Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return fmt.Errorf("pack %s: bad event: %w", p.pack, err)
+		}
+		e.Forward(ev)
+	}
+	if err := sc.Err(); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("pack %s: %w", p.pack, err)
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("pack %s: %w%s", p.pack, err, stderrSuffix(&stderr))
+	}
+	return e.Err()
+}
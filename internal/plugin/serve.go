@@ -0,0 +1,51 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/flashcard"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Serve answers one request of the pack protocol: args are the pack's
+// command-line arguments, "describe" or "run <name>". sources may be nil;
+// otherwise it holds each example's source as <name>.go, laid out like
+// the GOlang directory.
+func Serve(ctx context.Context, pack string, examples []registry.Concept, sources fs.FS, args []string, stdout io.Writer) error {
+	switch {
+	case len(args) == 1 && args[0] == "describe":
+		d := Description{Pack: pack}
+		for _, c := range examples {
+			ex := Example{Metadata: c.Describe()}
+			if sources != nil {
+				if src, err := fs.ReadFile(sources, ex.Metadata.Name+".go"); err == nil {
+					ex.Source = string(src)
+				}
+			}
+			if q, ok := c.(quiz.Quizzer); ok {
+				ex.Questions = q.Questions()
+			}
+			if d, ok := c.(flashcard.Deck); ok {
+				ex.Cards = d.Flashcards()
+			}
+			d.Examples = append(d.Examples, ex)
+		}
+		return json.NewEncoder(stdout).Encode(d)
+
+	case len(args) == 2 && args[0] == "run":
+		for _, c := range examples {
+			if c.Describe().Name == args[1] {
+				return c.Run(event.WithSink(ctx, event.NewJSONSink(stdout)), stdout)
+			}
+		}
+		return fmt.Errorf("pack %s has no example %q", pack, args[1])
+	}
+	return errors.New(`want "describe" or "run <name>"; this program is a concepts example pack`)
+}
@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// Sources returns base with the source files of the loaded packs added, so
+// that the browsers and the search index can show pack examples too.
+func Sources(base fs.FS) fs.FS {
+	return overlay{base}
+}
+
+// overlay serves pack sources first and falls back to base.
+type overlay struct {
+	base fs.FS
+}
+
+func (o overlay) Open(name string) (fs.File, error) {
+	mu.RLock()
+	src, ok := sources[name]
+	mu.RUnlock()
+	if !ok {
+		return o.base.Open(name)
+	}
+	return &sourceFile{Reader: bytes.NewReader(src), name: name, size: int64(len(src))}, nil
+}
+
+// sourceFile is an open pack source file.
+type sourceFile struct {
+	*bytes.Reader
+	name string
+	size int64
+}
+
+func (f *sourceFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *sourceFile) Close() error               { return nil }
+
+// sourceFile is its own FileInfo. Name is the base name, as fs.FileInfo
+// requires; f.name is the whole path it was opened by.
+func (f *sourceFile) Name() string       { return path.Base(f.name) }
+func (f *sourceFile) Size() int64        { return f.size }
+func (f *sourceFile) Mode() fs.FileMode  { return 0o444 }
+func (f *sourceFile) ModTime() time.Time { return time.Time{} }
+func (f *sourceFile) IsDir() bool        { return false }
+func (f *sourceFile) Sys() any           { return nil }
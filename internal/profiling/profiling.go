@@ -0,0 +1,302 @@
+// Package profiling captures a CPU or heap profile of one example run and
+// summarizes it, so a learner can see where the time or the memory went
+// without learning pprof first. The profile is kept on disk for a closer
+// look with "go tool pprof".
+package profiling
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/pprof/profile"
+)
+
+// Kind is the kind of profile to capture.
+type Kind string
+
+const (
+	// CPU samples where the run spends its time.
+	CPU Kind = "cpu"
+	// Heap records every allocation the run makes.
+	Heap Kind = "heap"
+)
+
+// ParseKind is the inverse of converting a Kind to a string.
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case CPU, Heap:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown profile kind %q (want cpu or heap)", s)
+}
+
+// DefaultDir returns the directory profiles are stored in by default:
+// concepts/profiles inside the user's cache directory.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "concepts", "profiles"), nil
+}
+
+// Profile is a captured profile.
+type Profile struct {
+	Kind Kind
+	// Path is the file the profile was written to.
+	Path string
+	// Elapsed is how long the run took.
+	Elapsed time.Duration
+	p       *profile.Profile
+}
+
+// Capture runs run while profiling it and writes the profile to
+// <dir>/<example>/<kind>.pprof. A heap profile only contains what run
+// allocated, not what the program allocated before. The error from run is
+// returned as it is, together with the profile of the failed run.
+func Capture(kind Kind, dir, example string, run func() error) (*Profile, error) {
+	prof := &Profile{Kind: kind, Path: filepath.Join(dir, filepath.FromSlash(example), string(kind)+".pprof")}
+	var runErr error
+	switch kind {
+	case CPU:
+		var buf bytes.Buffer
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		runErr = run()
+		prof.Elapsed = time.Since(start)
+		pprof.StopCPUProfile()
+		p, err := profile.Parse(&buf)
+		if err != nil {
+			return nil, err
+		}
+		prof.p = p
+	case Heap:
+		// Record every allocation rather than one every 512 KB, which would
+		// miss nearly all of a small example's.
+		defer func(rate int) { runtime.MemProfileRate = rate }(runtime.MemProfileRate)
+		runtime.MemProfileRate = 1
+		before, err := allocs()
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		runErr = run()
+		prof.Elapsed = time.Since(start)
+		after, err := allocs()
+		if err != nil {
+			return nil, err
+		}
+		before.Scale(-1)
+		p, err := profile.Merge([]*profile.Profile{after, before})
+		if err != nil {
+			return nil, err
+		}
+		// Taking the first snapshot allocates too; that isn't the example's doing.
+		p.FilterSamplesByName(nil, profilerFrames, nil, nil)
+		prof.p = p.Compact()
+	default:
+		return nil, fmt.Errorf("unknown profile kind %q", kind)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(prof.Path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(prof.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := prof.p.Write(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return prof, runErr
+}
+
+// profilerFrames matches the functions that take and parse profiles.
+var profilerFrames = regexp.MustCompile(`^(runtime/pprof|github\.com/google/pprof/profile)\.`)
+
+// allocs returns the allocations made so far. The heap profile is only
+// brought up to date by a garbage collection.
+func allocs() (*profile.Profile, error) {
+	runtime.GC()
+	var buf bytes.Buffer
+	if err := pprof.Lookup("allocs").WriteTo(&buf, 0); err != nil {
+		return nil, err
+	}
+	return profile.Parse(&buf)
+}
+
+// Func is one line of the summary.
+type Func struct {
+	Name string
+	// Flat is the time or memory spent in the function itself, Cum
+	// includes the functions it called.
+	Flat, Cum int64
+}
+
+// Top returns the n functions with the largest flat value, and the total
+// over every sample: CPU time in nanoseconds, or allocated bytes.
+func (p *Profile) Top(n int) (funcs []Func, total int64) {
+	index := p.sampleIndex()
+	byName := map[string]*Func{}
+	for _, s := range p.p.Sample {
+		v := s.Value[index]
+		total += v
+		seen := map[string]bool{}
+		for i, loc := range s.Location {
+			for _, line := range loc.Line {
+				if line.Function == nil {
+					continue
+				}
+				name := line.Function.Name
+				f := byName[name]
+				if f == nil {
+					f = &Func{Name: name}
+					byName[name] = f
+				}
+				// The first line of the first location is the leaf.
+				if i == 0 && line == loc.Line[0] {
+					f.Flat += v
+				}
+				if !seen[name] {
+					f.Cum += v
+					seen[name] = true
+				}
+			}
+		}
+	}
+	for _, f := range byName {
+		funcs = append(funcs, *f)
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		if funcs[i].Flat != funcs[j].Flat {
+			return funcs[i].Flat > funcs[j].Flat
+		}
+		return funcs[i].Cum > funcs[j].Cum
+	})
+	return funcs[:min(n, len(funcs))], total
+}
+
+// Line is a source line and what was spent on it.
+type Line struct {
+	Func string
+	File string
+	Line int64
+	// Flat is the time or memory spent on the line itself.
+	Flat int64
+}
+
+// Lines returns the n source lines with the largest flat value among the
+// files keep accepts. Each sample is counted against the innermost of its
+// lines in such a file, so that time spent in the runtime or a library,
+// or memory allocated there, is charged to the line that led to it.
+func (p *Profile) Lines(n int, keep func(file string) bool) []Line {
+	index := p.sampleIndex()
+	type key struct {
+		file string
+		line int64
+	}
+	byLine := map[key]*Line{}
+	for _, s := range p.p.Sample {
+	locations:
+		for _, loc := range s.Location {
+			for _, line := range loc.Line {
+				if line.Function == nil || !keep(line.Function.Filename) {
+					continue
+				}
+				k := key{line.Function.Filename, line.Line}
+				l := byLine[k]
+				if l == nil {
+					l = &Line{Func: line.Function.Name, File: k.file, Line: k.line}
+					byLine[k] = l
+				}
+				l.Flat += s.Value[index]
+				break locations
+			}
+		}
+	}
+	lines := make([]Line, 0, len(byLine))
+	for _, l := range byLine {
+		lines = append(lines, *l)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Flat != lines[j].Flat {
+			return lines[i].Flat > lines[j].Flat
+		}
+		return lines[i].Line < lines[j].Line
+	})
+	return lines[:min(n, len(lines))]
+}
+
+// sampleIndex returns the index of the sample value Top adds up: CPU
+// nanoseconds, or allocated bytes.
+func (p *Profile) sampleIndex() int {
+	want := "cpu"
+	if p.Kind == Heap {
+		want = "alloc_space"
+	}
+	for i, st := range p.p.SampleType {
+		if st.Type == want {
+			return i
+		}
+	}
+	return len(p.p.SampleType) - 1
+}
+
+// WriteSummary prints the top n functions and the total.
+func (p *Profile) WriteSummary(w io.Writer, n int) error {
+	funcs, total := p.Top(n)
+	format := p.Format
+	var b strings.Builder
+	switch p.Kind {
+	case CPU:
+		fmt.Fprintf(&b, "CPU profile: %s sampled during a %s run\n", format(total), p.Elapsed.Round(time.Microsecond))
+		if total == 0 {
+			b.WriteString("The run was too short for the profiler to take a single sample (it samples every 10ms).\n")
+		}
+	case Heap:
+		fmt.Fprintf(&b, "Heap profile: %s allocated in total during a %s run\n", format(total), p.Elapsed.Round(time.Microsecond))
+	}
+	if len(funcs) > 0 && total != 0 {
+		b.WriteString("\n")
+		tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintf(tw, "flat\tflat%%\tcum\tcum%%\t\n")
+		for _, f := range funcs {
+			fmt.Fprintf(tw, "%s\t%.1f%%\t%s\t%.1f%%\t  %s\n", format(f.Flat), 100*float64(f.Flat)/float64(total), format(f.Cum), 100*float64(f.Cum)/float64(total), f.Name)
+		}
+		tw.Flush()
+	}
+	fmt.Fprintf(&b, "\nProfile written to %s\nExplore it with: go tool pprof -http=localhost:0 %s\n", p.Path, p.Path)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Format formats a sample value: a duration for CPU profiles, a byte
+// count for heap profiles.
+func (p *Profile) Format(v int64) string {
+	if p.Kind == CPU {
+		return time.Duration(v).String()
+	}
+	switch {
+	case v >= 1<<20 || v <= -1<<20:
+		return fmt.Sprintf("%.1f MB", float64(v)/(1<<20))
+	case v >= 1<<10 || v <= -1<<10:
+		return fmt.Sprintf("%.1f KB", float64(v)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", v)
+}
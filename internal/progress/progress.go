@@ -0,0 +1,186 @@
+// Package progress remembers what a learner has done across sessions:
+// which examples they ran, how they did in quizzes and which exercises
+// they solved. It is a single JSON file in the user's config directory.
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PassMark is the share of correct answers needed to pass a quiz.
+const PassMark = 0.8
+
+// Run records how often an example was run.
+type Run struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// Quiz records the best result for a quiz topic.
+type Quiz struct {
+	BestCorrect int       `json:"best_correct"`
+	BestTotal   int       `json:"best_total"`
+	Passed      bool      `json:"passed"`
+	Attempts    int       `json:"attempts"`
+	Last        time.Time `json:"last"`
+}
+
+// Exercise records the attempts at an exercise.
+type Exercise struct {
+	Attempts int       `json:"attempts"`
+	Solved   bool      `json:"solved"`
+	SolvedAt time.Time `json:"solved_at,omitzero"`
+	// Hints is how many hints were revealed; one more than the exercise
+	// has hints means the reference solution was shown.
+	Hints int `json:"hints,omitempty"`
+}
+
+// Card is the spaced-repetition state of a flashcard (see the flashcard
+// package, which updates it).
+type Card struct {
+	Ease     float64   `json:"ease"`
+	Interval int       `json:"interval_days"`
+	Reps     int       `json:"reps"`
+	Due      time.Time `json:"due"`
+}
+
+// Data is everything the store remembers. The maps are keyed by example
+// name, quiz topic, exercise name and flashcard ID; Daily is keyed by date
+// (YYYY-MM-DD) and holds that day's concept of the day.
+type Data struct {
+	Runs      map[string]Run      `json:"runs"`
+	Quizzes   map[string]Quiz     `json:"quizzes"`
+	Exercises map[string]Exercise `json:"exercises"`
+	Cards     map[string]Card     `json:"cards,omitempty"`
+	Daily     map[string]string   `json:"daily,omitempty"`
+}
+
+// Store is the progress file loaded into memory. Changes are kept in
+// memory until Save is called.
+type Store struct {
+	path string
+	Data
+}
+
+// DefaultPath returns the progress file location: concepts/progress.json
+// inside the user's config directory (e.g. ~/.config on Linux).
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "concepts", "progress.json"), nil
+}
+
+// Open loads the progress file at path. A missing file is not an error:
+// it just means nothing has been recorded yet.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &s.Data); err != nil {
+			return nil, errors.New("progress: " + path + " is corrupt: " + err.Error())
+		}
+	}
+	if s.Runs == nil {
+		s.Runs = map[string]Run{}
+	}
+	if s.Quizzes == nil {
+		s.Quizzes = map[string]Quiz{}
+	}
+	if s.Exercises == nil {
+		s.Exercises = map[string]Exercise{}
+	}
+	if s.Cards == nil {
+		s.Cards = map[string]Card{}
+	}
+	if s.Daily == nil {
+		s.Daily = map[string]string{}
+	}
+	return s, nil
+}
+
+// Path returns the file the store was opened from.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// RecordRun notes that an example was run at t.
+func (s *Store) RecordRun(example string, t time.Time) {
+	r := s.Runs[example]
+	r.Count++
+	r.Last = t
+	s.Runs[example] = r
+}
+
+// RecordQuiz notes a quiz result for a topic. The best score is kept, and
+// a topic stays passed once it has been passed.
+func (s *Store) RecordQuiz(topic string, correct, total int, t time.Time) {
+	q := s.Quizzes[topic]
+	q.Attempts++
+	q.Last = t
+	if total > 0 && (q.BestTotal == 0 || float64(correct)/float64(total) > float64(q.BestCorrect)/float64(q.BestTotal)) {
+		q.BestCorrect, q.BestTotal = correct, total
+	}
+	if total > 0 && float64(correct) >= PassMark*float64(total) {
+		q.Passed = true
+	}
+	s.Quizzes[topic] = q
+}
+
+// RecordExercise notes an attempt at an exercise.
+func (s *Store) RecordExercise(name string, solved bool, t time.Time) {
+	ex := s.Exercises[name]
+	ex.Attempts++
+	if solved && !ex.Solved {
+		ex.Solved, ex.SolvedAt = true, t
+	}
+	s.Exercises[name] = ex
+}
+
+// RecordDaily notes that example was the concept of the day on t's date.
+func (s *Store) RecordDaily(example string, t time.Time) {
+	s.Daily[t.Format(time.DateOnly)] = example
+}
+
+// RecordHint notes that hint number n (counting from 1) of an exercise
+// was revealed.
+func (s *Store) RecordHint(name string, n int) {
+	ex := s.Exercises[name]
+	ex.Hints = max(ex.Hints, n)
+	s.Exercises[name] = ex
+}
+
+// Save writes the store back to its file. It writes a temporary file and
+// renames it, so a crash never leaves a half-written progress file behind.
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.Data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".progress-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,127 @@
+// Package quiz asks learners questions about the examples.
+//
+// An example opts in by implementing Quizzer. Questions are either
+// multiple choice, or "predict the output" questions where the learner
+// types the answer. Every answer is followed by an explanation that points
+// back at the example's code.
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Question is one quiz question.
+type Question struct {
+	// Prompt is the question itself.
+	Prompt string
+	// Choices are the options of a multiple-choice question. When empty,
+	// the learner types the answer instead.
+	Choices []string
+	// Answer is the correct choice (it must be one of Choices) or, for a
+	// typed question, the expected answer. Typed answers are compared
+	// ignoring case and surrounding space.
+	Answer string
+	// Explain says why the answer is right, referring to the example code.
+	Explain string
+}
+
+// Quizzer is implemented by concepts that come with questions.
+type Quizzer interface {
+	registry.Concept
+	Questions() []Question
+}
+
+// Item is a question together with the example it belongs to.
+type Item struct {
+	Example string
+	Question
+}
+
+// ForTopic returns the questions of every example in a topic, in registry
+// order. An empty topic means every example.
+func ForTopic(topic string) []Item {
+	var items []Item
+	for _, c := range registry.Find(registry.Query{Topic: topic}) {
+		q, ok := c.(Quizzer)
+		if !ok {
+			continue
+		}
+		for _, question := range q.Questions() {
+			items = append(items, Item{Example: c.Describe().Name, Question: question})
+		}
+	}
+	return items
+}
+
+// Correct reports whether answer is the right answer to q. For a
+// multiple-choice question the learner may type either the number of the
+// choice or its text.
+func (q Question) Correct(answer string) bool {
+	answer = strings.TrimSpace(answer)
+	if len(q.Choices) > 0 {
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(q.Choices) {
+			answer = q.Choices[n-1]
+		}
+	}
+	return strings.EqualFold(answer, strings.TrimSpace(q.Answer))
+}
+
+// Result is the outcome of a session.
+type Result struct {
+	Correct, Total int
+	// Missed lists the questions that were answered wrongly.
+	Missed []Item
+}
+
+// Session asks questions on out and reads the answers, one per line, from in.
+type Session struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewSession returns a session reading answers from in and writing to out.
+func NewSession(in io.Reader, out io.Writer) *Session {
+	return &Session{in: bufio.NewReader(in), out: out}
+}
+
+// Ask asks every item in turn and returns the score. It stops early, with
+// the score so far, if in runs out of lines.
+func (s *Session) Ask(items []Item) (Result, error) {
+	var res Result
+	for i, it := range items {
+		fmt.Fprintf(s.out, "\nQuestion %d of %d (from %s)\n%s\n", i+1, len(items), it.Example, it.Prompt)
+		for n, choice := range it.Choices {
+			fmt.Fprintf(s.out, "  %d) %s\n", n+1, choice)
+		}
+		fmt.Fprint(s.out, "> ")
+
+		answer, err := s.in.ReadString('\n')
+		if err == io.EOF && answer == "" {
+			fmt.Fprintln(s.out)
+			return res, nil
+		}
+		if err != nil && err != io.EOF {
+			return res, err
+		}
+
+		res.Total++
+		if it.Correct(answer) {
+			res.Correct++
+			fmt.Fprintln(s.out, "Correct!")
+		} else {
+			res.Missed = append(res.Missed, it)
+			fmt.Fprintf(s.out, "Not quite, the answer is: %s\n", it.Answer)
+		}
+		if it.Explain != "" {
+			fmt.Fprintf(s.out, "%s\n", it.Explain)
+		}
+		fmt.Fprintf(s.out, "(see \"concepts run %s\")\n", it.Example)
+	}
+	return res, nil
+}
@@ -0,0 +1,75 @@
+// Package record captures the events of a run so that it can be replayed
+// later, e.g. by students who missed the live demo.
+//
+// A Recorder is a Sink that remembers every event, with the time it was
+// recorded, before passing it on. WriteHTML turns the recording into a
+// single self-contained page with a timeline slider and a play button that
+// replays the events at their original pace.
+package record
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+//go:embed templates/replay.html
+var templateFS embed.FS
+
+var replay = template.Must(template.ParseFS(templateFS, "templates/replay.html"))
+
+// Entry is one recorded event.
+type Entry struct {
+	// At is how long after the start of the recording the event happened.
+	At time.Duration
+	event.Event
+}
+
+// Recorder records the events it passes on to the next sink.
+type Recorder struct {
+	next    event.Sink
+	start   time.Time
+	entries []Entry
+}
+
+// New returns a Recorder that forwards every event to next. The clock
+// starts now.
+func New(next event.Sink) *Recorder {
+	return &Recorder{next: next, start: time.Now()}
+}
+
+// Emit records ev and passes it on.
+func (r *Recorder) Emit(ev event.Event) error {
+	r.entries = append(r.entries, Entry{At: time.Since(r.start), Event: ev})
+	return r.next.Emit(ev)
+}
+
+// Entries returns what has been recorded so far.
+func (r *Recorder) Entries() []Entry {
+	return r.entries
+}
+
+// frame is an entry as the replay page's script sees it.
+type frame struct {
+	Millis  int64  `json:"ms"`
+	Step    string `json:"step"`
+	Message string `json:"message"`
+	Diagram bool   `json:"diagram,omitempty"`
+}
+
+// WriteHTML writes a page that replays the recording of the example md.
+func (r *Recorder) WriteHTML(w io.Writer, md registry.Metadata) error {
+	frames := make([]frame, len(r.entries))
+	for i, e := range r.entries {
+		frames[i] = frame{e.At.Milliseconds(), e.Step, e.Message, e.Diagram != ""}
+	}
+	return replay.Execute(w, struct {
+		registry.Metadata
+		Recorded time.Time
+		Frames   []frame
+	}{md, r.start, frames})
+}
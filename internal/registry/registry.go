@@ -0,0 +1,185 @@
+// Package registry is the catalog of every example in the repository.
+//
+// Each example package registers its examples from an init function, so
+// anything that imports the example packages (the concepts CLI, a UI, ...)
+// can list, filter and run them without scanning the filesystem.
+package registry
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Level says how much background an example expects from the learner.
+type Level int
+
+const (
+	Beginner Level = iota + 1
+	Intermediate
+	Advanced
+)
+
+// String returns the lower-case name of the level, e.g. "beginner".
+func (l Level) String() string {
+	switch l {
+	case Beginner:
+		return "beginner"
+	case Intermediate:
+		return "intermediate"
+	case Advanced:
+		return "advanced"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel is the inverse of Level.String.
+func ParseLevel(s string) (Level, error) {
+	for _, l := range []Level{Beginner, Intermediate, Advanced} {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown level %q (want beginner, intermediate or advanced)", s)
+}
+
+// Metadata describes one example.
+type Metadata struct {
+	// Name uniquely identifies the example, e.g. "pointers/function_example".
+	// It is also the path of the example's source file relative to the
+	// GOlang directory, without the ".go" extension.
+	Name string
+	// Topic groups related examples, e.g. "pointers" or "concurrency".
+	Topic string
+	// Level is the difficulty of the example.
+	Level Level
+	// Description is a one-line summary shown in listings.
+	Description string
+	// Tags are free-form keywords used for filtering.
+	Tags []string
+	// Prerequisites are the names of the examples a learner should have
+	// gone through before this one.
+	Prerequisites []string
+	// NeedsProcess is set for examples that start a process of their own
+	// (see the isolate package) or send their own process a signal. They
+	// can't run where there are no processes, such as in a browser.
+	NeedsProcess bool
+}
+
+// Concept is a runnable example. Implementations write everything they
+// want the learner to see to w instead of to os.Stdout, so the same
+// example can be run from the CLI, captured in a test, or embedded in
+// another tool.
+type Concept interface {
+	// Describe returns the example's metadata.
+	Describe() Metadata
+	// Run executes the example. It should return early with ctx.Err()
+	// if ctx is cancelled while it runs.
+	Run(ctx context.Context, w io.Writer) error
+}
+
+// Configurable is implemented by concepts that accept command-line flags.
+// SetFlags is called on a fresh FlagSet before the arguments are parsed,
+// and Run is called afterwards.
+type Configurable interface {
+	Concept
+	SetFlags(fs *flag.FlagSet)
+}
+
+// Explainer is implemented by concepts that have a short explanation for
+// some of their steps (see the event package). Step-through mode shows the
+// explanation when the step begins; Explain returns "" for steps that
+// don't need one.
+type Explainer interface {
+	Concept
+	Explain(step string) string
+}
+
+// Variant is one way of doing what a concept demonstrates, e.g. passing a
+// value by pointer rather than by value.
+type Variant struct {
+	Name string
+	// Run behaves like Concept.Run, but only does this variant.
+	Run func(ctx context.Context, w io.Writer) error
+}
+
+// Comparer is implemented by concepts whose point is the contrast between
+// a few variants. "concepts compare" runs two of them side by side.
+type Comparer interface {
+	Concept
+	Variants() []Variant
+}
+
+var (
+	mu       sync.RWMutex
+	concepts = map[string]Concept{}
+)
+
+// Register adds an example to the registry. It is meant to be called from
+// an init function and panics if the metadata is incomplete or if the name
+// is already taken, because both are programming mistakes.
+func Register(c Concept) {
+	md := c.Describe()
+	if md.Name == "" || md.Topic == "" {
+		panic("registry: Register needs a concept with at least a Name and a Topic")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := concepts[md.Name]; dup {
+		panic("registry: Register called twice for " + md.Name)
+	}
+	concepts[md.Name] = c
+}
+
+// Lookup returns the example with the given name.
+func Lookup(name string) (Concept, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := concepts[name]
+	return c, ok
+}
+
+// Query selects a subset of the registry. Zero-valued fields match everything.
+type Query struct {
+	Topic string
+	Level Level
+	Tag   string
+}
+
+// matches reports whether md satisfies every non-zero field of q.
+func (q Query) matches(md Metadata) bool {
+	if q.Topic != "" && q.Topic != md.Topic {
+		return false
+	}
+	if q.Level != 0 && q.Level != md.Level {
+		return false
+	}
+	if q.Tag != "" && !slices.Contains(md.Tags, q.Tag) {
+		return false
+	}
+	return true
+}
+
+// Find returns all examples matching q, sorted by name.
+func Find(q Query) []Concept {
+	mu.RLock()
+	defer mu.RUnlock()
+	var found []Concept
+	for _, c := range concepts {
+		if q.matches(c.Describe()) {
+			found = append(found, c)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Describe().Name < found[j].Describe().Name })
+	return found
+}
+
+// All returns every registered example, sorted by name.
+func All() []Concept {
+	return Find(Query{})
+}
@@ -0,0 +1,222 @@
+// Package repl evaluates small Go snippets for the concepts REPL.
+//
+// There is no Go interpreter in the standard library, so a session keeps
+// every snippet that compiled so far, writes them out as one program and
+// runs it with "go run". Only the output that the newest snippet added is
+// shown. Top-level declarations (func, type, var, const, import) are kept
+// apart from statements, which go into main.
+//
+// Because the whole program runs again for every input, anything that
+// differs between runs, addresses in particular, can differ from what an
+// earlier line showed.
+package repl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeout bounds a single evaluation, so an endless loop can't hang the REPL.
+const Timeout = 10 * time.Second
+
+// stdPackages are imported automatically when a snippet uses them.
+var stdPackages = map[string]string{
+	"errors": "errors", "fmt": "fmt", "math": "math", "os": "os",
+	"reflect": "reflect", "runtime": "runtime", "sort": "sort",
+	"strconv": "strconv", "strings": "strings", "sync": "sync",
+	"atomic": "sync/atomic", "time": "time", "unsafe": "unsafe",
+	"slices": "slices", "maps": "maps", "context": "context",
+}
+
+var pkgUseRE = regexp.MustCompile(`\b([a-z]+)\.`)
+
+// Session is one REPL session.
+type Session struct {
+	dir     string
+	decls   []string
+	stmts   []string
+	printed int // bytes of output already shown
+}
+
+// NewSession creates a session with its own scratch module. Call Close
+// when done to delete it.
+func NewSession() (*Session, error) {
+	dir, err := os.MkdirTemp("", "concepts-repl-")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module repl\n\ngo 1.22\n"), 0o644); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &Session{dir: dir}, nil
+}
+
+// Close removes the session's scratch module.
+func (s *Session) Close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// Reset forgets every snippet.
+func (s *Session) Reset() {
+	s.decls, s.stmts, s.printed = nil, nil, 0
+}
+
+// Source returns the program the session currently runs.
+func (s *Session) Source() string {
+	return s.program(s.decls, s.stmts)
+}
+
+// isDecl reports whether a snippet is a top-level declaration.
+func isDecl(src string) bool {
+	for _, kw := range []string{"func ", "type ", "import ", "const ", "var ("} {
+		if strings.HasPrefix(src, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// Eval adds a snippet and runs the program. It returns the new output.
+// A snippet that fails to compile or run is not kept, and the compiler's
+// or program's complaint is returned as the error.
+func (s *Session) Eval(ctx context.Context, snippet string) (string, error) {
+	snippet = strings.TrimSpace(snippet)
+	if snippet == "" {
+		return "", nil
+	}
+	decls, stmts := s.decls, s.stmts
+	if isDecl(snippet) {
+		decls = append(decls[:len(decls):len(decls)], snippet)
+	} else {
+		stmts = append(stmts[:len(stmts):len(stmts)], snippet+silenceUnused(snippet))
+	}
+
+	out, err := s.run(ctx, s.program(decls, stmts))
+	if err != nil {
+		return "", err
+	}
+	s.decls, s.stmts = decls, stmts
+	if len(out) < s.printed {
+		// The program printed less than last time (e.g. different addresses
+		// made a line shorter); show everything rather than guess.
+		s.printed = 0
+	}
+	delta := out[s.printed:]
+	s.printed = len(out)
+	return delta, nil
+}
+
+// silenceUnused returns "; _ = x" for every variable a statement declares,
+// so the compiler doesn't reject a snippet like "x := 1" as unused.
+func silenceUnused(stmt string) string {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p; func _() {\n"+stmt+"\n}", 0)
+	if err != nil {
+		return ""
+	}
+	var names []string
+	body := f.Decls[0].(*ast.FuncDecl).Body
+	for _, st := range body.List {
+		switch st := st.(type) {
+		case *ast.AssignStmt:
+			if st.Tok != token.DEFINE {
+				continue
+			}
+			for _, lhs := range st.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && id.Name != "_" {
+					names = append(names, id.Name)
+				}
+			}
+		case *ast.DeclStmt:
+			if gd, ok := st.Decl.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+				for _, spec := range gd.Specs {
+					for _, id := range spec.(*ast.ValueSpec).Names {
+						names = append(names, id.Name)
+					}
+				}
+			}
+		}
+	}
+	var b strings.Builder
+	for _, n := range names {
+		b.WriteString("; _ = " + n)
+	}
+	return b.String()
+}
+
+// program assembles the Go source for the given snippets.
+func (s *Session) program(decls, stmts []string) string {
+	body := strings.Join(append(append([]string(nil), decls...), stmts...), "\n")
+	var imports []string
+	for _, m := range pkgUseRE.FindAllStringSubmatch(body, -1) {
+		if path, ok := stdPackages[m[1]]; ok && !strings.Contains(body, `"`+path+`"`) {
+			imports = append(imports, path)
+		}
+	}
+	sort.Strings(imports)
+	imports = compact(imports)
+
+	var b strings.Builder
+	b.WriteString("package main\n\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&b, "import %q\n", imp)
+	}
+	for _, d := range decls {
+		b.WriteString("\n" + d + "\n")
+	}
+	b.WriteString("\nfunc main() {\n")
+	for _, st := range stmts {
+		b.WriteString(st + "\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// compact removes adjacent duplicates from a sorted slice.
+func compact(s []string) []string {
+	out := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// run writes src to the scratch module and runs it.
+func (s *Session) run(ctx context.Context, src string) (string, error) {
+	if err := os.WriteFile(filepath.Join(s.dir, "main.go"), []byte(src), 0o644); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "go", "run", ".")
+	cmd.Dir = s.dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("the snippet ran for more than %s and was stopped", Timeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		msg = strings.ReplaceAll(msg, "./main.go:", "line ")
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", errors.New(msg)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,673 @@
+// The Concepts service runs the examples of the programming-concepts
+// repository for other programs. The generated Go code lives next to this
+// file; regenerate it from the repository root with
+//
+//	buf generate
+//
+// (see buf.gen.yaml), which needs protoc-gen-go and protoc-gen-go-grpc on
+// the PATH.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: concepts.proto
+
+package conceptspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListExamplesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty fields match every example.
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	// "beginner", "intermediate" or "advanced".
+	Level         string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Tag           string `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExamplesRequest) Reset() {
+	*x = ListExamplesRequest{}
+	mi := &file_concepts_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExamplesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExamplesRequest) ProtoMessage() {}
+
+func (x *ListExamplesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExamplesRequest.ProtoReflect.Descriptor instead.
+func (*ListExamplesRequest) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListExamplesRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ListExamplesRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *ListExamplesRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type ListExamplesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Examples      []*Example             `protobuf:"bytes,1,rep,name=examples,proto3" json:"examples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExamplesResponse) Reset() {
+	*x = ListExamplesResponse{}
+	mi := &file_concepts_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExamplesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExamplesResponse) ProtoMessage() {}
+
+func (x *ListExamplesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExamplesResponse.ProtoReflect.Descriptor instead.
+func (*ListExamplesResponse) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListExamplesResponse) GetExamples() []*Example {
+	if x != nil {
+		return x.Examples
+	}
+	return nil
+}
+
+type Example struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Topic         string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Level         string                 `protobuf:"bytes,3,opt,name=level,proto3" json:"level,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Prerequisites []string               `protobuf:"bytes,6,rep,name=prerequisites,proto3" json:"prerequisites,omitempty"`
+	// The example's quiz, without the answers.
+	Questions     []*Question `protobuf:"bytes,7,rep,name=questions,proto3" json:"questions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Example) Reset() {
+	*x = Example{}
+	mi := &file_concepts_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Example) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Example) ProtoMessage() {}
+
+func (x *Example) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Example.ProtoReflect.Descriptor instead.
+func (*Example) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Example) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Example) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *Example) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *Example) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Example) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Example) GetPrerequisites() []string {
+	if x != nil {
+		return x.Prerequisites
+	}
+	return nil
+}
+
+func (x *Example) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+type Question struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Prompt string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	// Empty for questions where the answer is typed in.
+	Choices       []string `protobuf:"bytes,2,rep,name=choices,proto3" json:"choices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Question) Reset() {
+	*x = Question{}
+	mi := &file_concepts_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Question) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Question) ProtoMessage() {}
+
+func (x *Question) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Question.ProtoReflect.Descriptor instead.
+func (*Question) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Question) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *Question) GetChoices() []string {
+	if x != nil {
+		return x.Choices
+	}
+	return nil
+}
+
+type RunExampleRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Language of the explanations, e.g. "es"; empty means English.
+	Lang          string `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunExampleRequest) Reset() {
+	*x = RunExampleRequest{}
+	mi := &file_concepts_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunExampleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunExampleRequest) ProtoMessage() {}
+
+func (x *RunExampleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunExampleRequest.ProtoReflect.Descriptor instead.
+func (*RunExampleRequest) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RunExampleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RunExampleRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+// Event mirrors the JSON events of "concepts run -format=json".
+type Event struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Step     string                 `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`
+	Variable string                 `protobuf:"bytes,2,opt,name=variable,proto3" json:"variable,omitempty"`
+	Address  string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Value    string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Diagram  string                 `protobuf:"bytes,5,opt,name=diagram,proto3" json:"diagram,omitempty"`
+	Message  string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	// The message is something the learner should watch out for.
+	Warning bool `protobuf:"varint,7,opt,name=warning,proto3" json:"warning,omitempty"`
+	// The value is expected to differ from run to run, e.g. a timing.
+	Varies        bool `protobuf:"varint,8,opt,name=varies,proto3" json:"varies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_concepts_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Event) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *Event) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *Event) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Event) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Event) GetDiagram() string {
+	if x != nil {
+		return x.Diagram
+	}
+	return ""
+}
+
+func (x *Event) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Event) GetWarning() bool {
+	if x != nil {
+		return x.Warning
+	}
+	return false
+}
+
+func (x *Event) GetVaries() bool {
+	if x != nil {
+		return x.Varies
+	}
+	return false
+}
+
+type SubmitQuizAnswerRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Example string                 `protobuf:"bytes,1,opt,name=example,proto3" json:"example,omitempty"`
+	// Index of the question in Example.questions, starting at 0.
+	Question int32 `protobuf:"varint,2,opt,name=question,proto3" json:"question,omitempty"`
+	// A choice's text or its 1-based number, or the typed answer.
+	Answer        string `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitQuizAnswerRequest) Reset() {
+	*x = SubmitQuizAnswerRequest{}
+	mi := &file_concepts_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitQuizAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitQuizAnswerRequest) ProtoMessage() {}
+
+func (x *SubmitQuizAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitQuizAnswerRequest.ProtoReflect.Descriptor instead.
+func (*SubmitQuizAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubmitQuizAnswerRequest) GetExample() string {
+	if x != nil {
+		return x.Example
+	}
+	return ""
+}
+
+func (x *SubmitQuizAnswerRequest) GetQuestion() int32 {
+	if x != nil {
+		return x.Question
+	}
+	return 0
+}
+
+func (x *SubmitQuizAnswerRequest) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+type SubmitQuizAnswerResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Correct bool                   `protobuf:"varint,1,opt,name=correct,proto3" json:"correct,omitempty"`
+	// The right answer and why, shown after answering.
+	Answer        string `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	Explain       string `protobuf:"bytes,3,opt,name=explain,proto3" json:"explain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitQuizAnswerResponse) Reset() {
+	*x = SubmitQuizAnswerResponse{}
+	mi := &file_concepts_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitQuizAnswerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitQuizAnswerResponse) ProtoMessage() {}
+
+func (x *SubmitQuizAnswerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_concepts_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitQuizAnswerResponse.ProtoReflect.Descriptor instead.
+func (*SubmitQuizAnswerResponse) Descriptor() ([]byte, []int) {
+	return file_concepts_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SubmitQuizAnswerResponse) GetCorrect() bool {
+	if x != nil {
+		return x.Correct
+	}
+	return false
+}
+
+func (x *SubmitQuizAnswerResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *SubmitQuizAnswerResponse) GetExplain() string {
+	if x != nil {
+		return x.Explain
+	}
+	return ""
+}
+
+var File_concepts_proto protoreflect.FileDescriptor
+
+const file_concepts_proto_rawDesc = "" +
+	"\n" +
+	"\x0econcepts.proto\x12\vconcepts.v1\"S\n" +
+	"\x13ListExamplesRequest\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x10\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\"H\n" +
+	"\x14ListExamplesResponse\x120\n" +
+	"\bexamples\x18\x01 \x03(\v2\x14.concepts.v1.ExampleR\bexamples\"\xda\x01\n" +
+	"\aExample\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\tR\x05level\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12$\n" +
+	"\rprerequisites\x18\x06 \x03(\tR\rprerequisites\x123\n" +
+	"\tquestions\x18\a \x03(\v2\x15.concepts.v1.QuestionR\tquestions\"<\n" +
+	"\bQuestion\x12\x16\n" +
+	"\x06prompt\x18\x01 \x01(\tR\x06prompt\x12\x18\n" +
+	"\achoices\x18\x02 \x03(\tR\achoices\";\n" +
+	"\x11RunExampleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04lang\x18\x02 \x01(\tR\x04lang\"\xcd\x01\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\tR\x04step\x12\x1a\n" +
+	"\bvariable\x18\x02 \x01(\tR\bvariable\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\x12\x18\n" +
+	"\adiagram\x18\x05 \x01(\tR\adiagram\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\x12\x18\n" +
+	"\awarning\x18\a \x01(\bR\awarning\x12\x16\n" +
+	"\x06varies\x18\b \x01(\bR\x06varies\"g\n" +
+	"\x17SubmitQuizAnswerRequest\x12\x18\n" +
+	"\aexample\x18\x01 \x01(\tR\aexample\x12\x1a\n" +
+	"\bquestion\x18\x02 \x01(\x05R\bquestion\x12\x16\n" +
+	"\x06answer\x18\x03 \x01(\tR\x06answer\"f\n" +
+	"\x18SubmitQuizAnswerResponse\x12\x18\n" +
+	"\acorrect\x18\x01 \x01(\bR\acorrect\x12\x16\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\x12\x18\n" +
+	"\aexplain\x18\x03 \x01(\tR\aexplain2\x84\x02\n" +
+	"\bConcepts\x12S\n" +
+	"\fListExamples\x12 .concepts.v1.ListExamplesRequest\x1a!.concepts.v1.ListExamplesResponse\x12B\n" +
+	"\n" +
+	"RunExample\x12\x1e.concepts.v1.RunExampleRequest\x1a\x12.concepts.v1.Event0\x01\x12_\n" +
+	"\x10SubmitQuizAnswer\x12$.concepts.v1.SubmitQuizAnswerRequest\x1a%.concepts.v1.SubmitQuizAnswerResponseB@Z>github.com/amandm/programming-concepts/internal/rpc/conceptspbb\x06proto3"
+
+var (
+	file_concepts_proto_rawDescOnce sync.Once
+	file_concepts_proto_rawDescData []byte
+)
+
+func file_concepts_proto_rawDescGZIP() []byte {
+	file_concepts_proto_rawDescOnce.Do(func() {
+		file_concepts_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_concepts_proto_rawDesc), len(file_concepts_proto_rawDesc)))
+	})
+	return file_concepts_proto_rawDescData
+}
+
+var file_concepts_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_concepts_proto_goTypes = []any{
+	(*ListExamplesRequest)(nil),      // 0: concepts.v1.ListExamplesRequest
+	(*ListExamplesResponse)(nil),     // 1: concepts.v1.ListExamplesResponse
+	(*Example)(nil),                  // 2: concepts.v1.Example
+	(*Question)(nil),                 // 3: concepts.v1.Question
+	(*RunExampleRequest)(nil),        // 4: concepts.v1.RunExampleRequest
+	(*Event)(nil),                    // 5: concepts.v1.Event
+	(*SubmitQuizAnswerRequest)(nil),  // 6: concepts.v1.SubmitQuizAnswerRequest
+	(*SubmitQuizAnswerResponse)(nil), // 7: concepts.v1.SubmitQuizAnswerResponse
+}
+var file_concepts_proto_depIdxs = []int32{
+	2, // 0: concepts.v1.ListExamplesResponse.examples:type_name -> concepts.v1.Example
+	3, // 1: concepts.v1.Example.questions:type_name -> concepts.v1.Question
+	0, // 2: concepts.v1.Concepts.ListExamples:input_type -> concepts.v1.ListExamplesRequest
+	4, // 3: concepts.v1.Concepts.RunExample:input_type -> concepts.v1.RunExampleRequest
+	6, // 4: concepts.v1.Concepts.SubmitQuizAnswer:input_type -> concepts.v1.SubmitQuizAnswerRequest
+	1, // 5: concepts.v1.Concepts.ListExamples:output_type -> concepts.v1.ListExamplesResponse
+	5, // 6: concepts.v1.Concepts.RunExample:output_type -> concepts.v1.Event
+	7, // 7: concepts.v1.Concepts.SubmitQuizAnswer:output_type -> concepts.v1.SubmitQuizAnswerResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_concepts_proto_init() }
+func file_concepts_proto_init() {
+	if File_concepts_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_concepts_proto_rawDesc), len(file_concepts_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_concepts_proto_goTypes,
+		DependencyIndexes: file_concepts_proto_depIdxs,
+		MessageInfos:      file_concepts_proto_msgTypes,
+	}.Build()
+	File_concepts_proto = out.File
+	file_concepts_proto_goTypes = nil
+	file_concepts_proto_depIdxs = nil
+}
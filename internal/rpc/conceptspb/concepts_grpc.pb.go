@@ -0,0 +1,216 @@
+// The Concepts service runs the examples of the programming-concepts
+// repository for other programs. The generated Go code lives next to this
+// file; regenerate it from the repository root with
+//
+//	buf generate
+//
+// (see buf.gen.yaml), which needs protoc-gen-go and protoc-gen-go-grpc on
+// the PATH.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             (unknown)
+// source: concepts.proto
+
+package conceptspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Concepts_ListExamples_FullMethodName     = "/concepts.v1.Concepts/ListExamples"
+	Concepts_RunExample_FullMethodName       = "/concepts.v1.Concepts/RunExample"
+	Concepts_SubmitQuizAnswer_FullMethodName = "/concepts.v1.Concepts/SubmitQuizAnswer"
+)
+
+// ConceptsClient is the client API for Concepts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConceptsClient interface {
+	// ListExamples returns the examples matching the request's filters.
+	ListExamples(ctx context.Context, in *ListExamplesRequest, opts ...grpc.CallOption) (*ListExamplesResponse, error)
+	// RunExample runs an example and streams its events as they happen.
+	RunExample(ctx context.Context, in *RunExampleRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// SubmitQuizAnswer grades the answer to one quiz question.
+	SubmitQuizAnswer(ctx context.Context, in *SubmitQuizAnswerRequest, opts ...grpc.CallOption) (*SubmitQuizAnswerResponse, error)
+}
+
+type conceptsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConceptsClient(cc grpc.ClientConnInterface) ConceptsClient {
+	return &conceptsClient{cc}
+}
+
+func (c *conceptsClient) ListExamples(ctx context.Context, in *ListExamplesRequest, opts ...grpc.CallOption) (*ListExamplesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListExamplesResponse)
+	err := c.cc.Invoke(ctx, Concepts_ListExamples_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *conceptsClient) RunExample(ctx context.Context, in *RunExampleRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Concepts_ServiceDesc.Streams[0], Concepts_RunExample_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RunExampleRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Concepts_RunExampleClient = grpc.ServerStreamingClient[Event]
+
+func (c *conceptsClient) SubmitQuizAnswer(ctx context.Context, in *SubmitQuizAnswerRequest, opts ...grpc.CallOption) (*SubmitQuizAnswerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitQuizAnswerResponse)
+	err := c.cc.Invoke(ctx, Concepts_SubmitQuizAnswer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConceptsServer is the server API for Concepts service.
+// All implementations must embed UnimplementedConceptsServer
+// for forward compatibility.
+type ConceptsServer interface {
+	// ListExamples returns the examples matching the request's filters.
+	ListExamples(context.Context, *ListExamplesRequest) (*ListExamplesResponse, error)
+	// RunExample runs an example and streams its events as they happen.
+	RunExample(*RunExampleRequest, grpc.ServerStreamingServer[Event]) error
+	// SubmitQuizAnswer grades the answer to one quiz question.
+	SubmitQuizAnswer(context.Context, *SubmitQuizAnswerRequest) (*SubmitQuizAnswerResponse, error)
+	mustEmbedUnimplementedConceptsServer()
+}
+
+// UnimplementedConceptsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedConceptsServer struct{}
+
+func (UnimplementedConceptsServer) ListExamples(context.Context, *ListExamplesRequest) (*ListExamplesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListExamples not implemented")
+}
+func (UnimplementedConceptsServer) RunExample(*RunExampleRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Error(codes.Unimplemented, "method RunExample not implemented")
+}
+func (UnimplementedConceptsServer) SubmitQuizAnswer(context.Context, *SubmitQuizAnswerRequest) (*SubmitQuizAnswerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SubmitQuizAnswer not implemented")
+}
+func (UnimplementedConceptsServer) mustEmbedUnimplementedConceptsServer() {}
+func (UnimplementedConceptsServer) testEmbeddedByValue()                  {}
+
+// UnsafeConceptsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConceptsServer will
+// result in compilation errors.
+type UnsafeConceptsServer interface {
+	mustEmbedUnimplementedConceptsServer()
+}
+
+func RegisterConceptsServer(s grpc.ServiceRegistrar, srv ConceptsServer) {
+	// If the following call panics, it indicates UnimplementedConceptsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Concepts_ServiceDesc, srv)
+}
+
+func _Concepts_ListExamples_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExamplesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConceptsServer).ListExamples(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Concepts_ListExamples_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConceptsServer).ListExamples(ctx, req.(*ListExamplesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Concepts_RunExample_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunExampleRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConceptsServer).RunExample(m, &grpc.GenericServerStream[RunExampleRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Concepts_RunExampleServer = grpc.ServerStreamingServer[Event]
+
+func _Concepts_SubmitQuizAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitQuizAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConceptsServer).SubmitQuizAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Concepts_SubmitQuizAnswer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConceptsServer).SubmitQuizAnswer(ctx, req.(*SubmitQuizAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Concepts_ServiceDesc is the grpc.ServiceDesc for Concepts service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Concepts_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "concepts.v1.Concepts",
+	HandlerType: (*ConceptsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListExamples",
+			Handler:    _Concepts_ListExamples_Handler,
+		},
+		{
+			MethodName: "SubmitQuizAnswer",
+			Handler:    _Concepts_SubmitQuizAnswer_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunExample",
+			Handler:       _Concepts_RunExample_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "concepts.proto",
+}
@@ -0,0 +1,128 @@
+// Package rpc implements the Concepts gRPC service (see
+// conceptspb/concepts.proto) on top of the registry.
+package rpc
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/amandm/programming-concepts/internal/event"
+	"github.com/amandm/programming-concepts/internal/i18n"
+	"github.com/amandm/programming-concepts/internal/quiz"
+	"github.com/amandm/programming-concepts/internal/registry"
+	"github.com/amandm/programming-concepts/internal/rpc/conceptspb"
+)
+
+// NewServer returns a gRPC server offering the Concepts service. It also
+// offers server reflection, so tools like grpcurl can explore it without
+// the .proto file.
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	conceptspb.RegisterConceptsServer(s, service{})
+	reflection.Register(s)
+	return s
+}
+
+// service implements conceptspb.ConceptsServer.
+type service struct {
+	conceptspb.UnimplementedConceptsServer
+}
+
+// ListExamples returns the examples matching every filter that is set,
+// with their quizzes but not the answers.
+func (service) ListExamples(ctx context.Context, req *conceptspb.ListExamplesRequest) (*conceptspb.ListExamplesResponse, error) {
+	q := registry.Query{Topic: req.GetTopic(), Tag: req.GetTag()}
+	if req.GetLevel() != "" {
+		l, err := registry.ParseLevel(req.GetLevel())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		q.Level = l
+	}
+	res := &conceptspb.ListExamplesResponse{}
+	for _, c := range registry.Find(q) {
+		md := c.Describe()
+		ex := &conceptspb.Example{
+			Name:          md.Name,
+			Topic:         md.Topic,
+			Level:         md.Level.String(),
+			Description:   md.Description,
+			Tags:          md.Tags,
+			Prerequisites: md.Prerequisites,
+		}
+		if qz, ok := c.(quiz.Quizzer); ok {
+			for _, question := range qz.Questions() {
+				ex.Questions = append(ex.Questions, &conceptspb.Question{Prompt: question.Prompt, Choices: question.Choices})
+			}
+		}
+		res.Examples = append(res.Examples, ex)
+	}
+	return res, nil
+}
+
+// RunExample sends every event to the client as soon as the example
+// records it. A failed send (the client went away) stops the run.
+func (service) RunExample(req *conceptspb.RunExampleRequest, stream grpc.ServerStreamingServer[conceptspb.Event]) error {
+	c, ok := registry.Lookup(req.GetName())
+	if !ok {
+		return status.Errorf(codes.NotFound, "unknown example %q", req.GetName())
+	}
+	lang := req.GetLang()
+	if lang == "" {
+		lang = i18n.English
+	}
+	translate, err := i18n.Translator(lang)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := event.WithSink(stream.Context(), event.SinkFunc(func(ev event.Event) error {
+		return stream.Send(&conceptspb.Event{
+			Step:     ev.Step,
+			Variable: ev.Variable,
+			Address:  ev.Address,
+			Value:    ev.Value,
+			Diagram:  ev.Diagram,
+			Message:  ev.Message,
+			Warning:  ev.Warning,
+			Varies:   ev.Varies,
+		})
+	}))
+	ctx = event.WithTranslator(ctx, translate)
+	if err := c.Run(ctx, io.Discard); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Aborted, "%s: %v", req.GetName(), err)
+	}
+	return nil
+}
+
+// SubmitQuizAnswer grades an answer the way "concepts quiz" does: a choice
+// can be given by its text or its 1-based number.
+func (service) SubmitQuizAnswer(ctx context.Context, req *conceptspb.SubmitQuizAnswerRequest) (*conceptspb.SubmitQuizAnswerResponse, error) {
+	c, ok := registry.Lookup(req.GetExample())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown example %q", req.GetExample())
+	}
+	qz, ok := c.(quiz.Quizzer)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s has no quiz", req.GetExample())
+	}
+	questions := qz.Questions()
+	i := int(req.GetQuestion())
+	if i < 0 || i >= len(questions) {
+		return nil, status.Errorf(codes.OutOfRange, "%s has questions 0 to %d, not %d", req.GetExample(), len(questions)-1, i)
+	}
+	q := questions[i]
+	return &conceptspb.SubmitQuizAnswerResponse{
+		Correct: q.Correct(req.GetAnswer()),
+		Answer:  q.Answer,
+		Explain: q.Explain,
+	}, nil
+}
@@ -0,0 +1,119 @@
+// Package scaffold creates the skeleton of a new example, so contributors
+// don't have to copy an existing one and remember every piece: the
+// registration, the metadata, a quiz stub and, for a new topic, the import
+// that links the topic's package into the catalog.
+package scaffold
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+//go:embed example.go.tmpl
+var exampleTmpl string
+
+var example = template.Must(template.New("example").Parse(exampleTmpl))
+
+// nameRE is what topics and file names may look like: they become a Go
+// package name and part of a file name.
+var nameRE = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// modulePath is the import path prefix of every package in this repository.
+const modulePath = "github.com/amandm/programming-concepts"
+
+// Create writes the skeleton of the example name ("topic/file") into the
+// examples directory dir and returns the paths of the files it wrote or
+// changed. It refuses to overwrite an existing file.
+func Create(dir, name string) ([]string, error) {
+	topic, file, ok := strings.Cut(name, "/")
+	if !ok || !nameRE.MatchString(topic) || !nameRE.MatchString(file) {
+		return nil, fmt.Errorf("example name %q should look like topic/file_name, with lower-case letters, digits and underscores", name)
+	}
+
+	path := filepath.Join(dir, topic, file+".go")
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s already exists", path)
+	}
+	_, err := os.Stat(filepath.Join(dir, topic))
+	newTopic := errors.Is(err, os.ErrNotExist)
+
+	var buf bytes.Buffer
+	err = example.Execute(&buf, struct {
+		Name, Topic, Type string
+		NewTopic          bool
+	}{name, topic, typeName(file), newTopic})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		return nil, err
+	}
+	written := []string{path}
+
+	if newTopic {
+		all := filepath.Join(dir, "all", "all.go")
+		if err := addImport(all, modulePath+"/"+filepath.Base(dir)+"/"+topic); err != nil {
+			return written, fmt.Errorf("adding %s to %s: %w", topic, all, err)
+		}
+		written = append(written, all)
+	}
+	return written, nil
+}
+
+// typeName turns a file name like "function_example" into the name of the
+// example's type, "functionExample".
+func typeName(file string) string {
+	parts := strings.Split(file, "_")
+	for i, p := range parts[1:] {
+		if p != "" {
+			parts[i+1] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// addImport adds a blank import of pkg to the import block of the Go file
+// at path, keeping the block sorted.
+func addImport(path, pkg string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(data)
+	start := strings.Index(src, "import (\n")
+	if start < 0 {
+		return errors.New("no import block")
+	}
+	start += len("import (\n")
+	end := strings.Index(src[start:], ")")
+	if end < 0 {
+		return errors.New("unterminated import block")
+	}
+	end += start
+
+	lines := strings.Split(strings.TrimRight(src[start:end], "\n"), "\n")
+	lines = append(lines, "\t_ \""+pkg+"\"")
+	slices.Sort(lines)
+	lines = slices.Compact(lines)
+	out, err := format.Source([]byte(src[:start] + strings.Join(lines, "\n") + "\n" + src[end:]))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
@@ -0,0 +1,229 @@
+// Package search is a small full-text index over the examples.
+//
+// Each example contributes its name, description and tags, the comments in
+// its source file and the identifiers used in its code. Matches in the
+// name weigh most and matches in plain code least, so a query for "escape"
+// ranks the escape-analysis example above one that merely mentions it.
+package search
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Weights of the different places a term can appear in.
+const (
+	weightName        = 5
+	weightDescription = 4
+	weightComment     = 2
+	weightIdentifier  = 1
+)
+
+// posting records where a term occurs in one example.
+type posting struct {
+	score float64 // sum of the weights of all occurrences
+	lines []int   // source lines the term occurs on, for snippets
+}
+
+// doc is one indexed example.
+type doc struct {
+	md    registry.Metadata
+	lines []string // the source file, for snippets
+}
+
+// Index maps terms to the examples containing them.
+type Index struct {
+	docs  []doc
+	terms map[string]map[int]*posting // term -> doc index -> posting
+}
+
+// Match is one search result.
+type Match struct {
+	registry.Metadata
+	Score float64
+	// Snippets are a few source lines containing the query, each prefixed
+	// with its line number.
+	Snippets []string
+}
+
+// New indexes the given concepts. sources holds their source files, laid
+// out as described by registry.Metadata.Name; examples without a source
+// file are indexed by their metadata only.
+func New(concepts []registry.Concept, sources fs.FS) *Index {
+	ix := &Index{terms: map[string]map[int]*posting{}}
+	for _, c := range concepts {
+		md := c.Describe()
+		id := len(ix.docs)
+		d := doc{md: md}
+
+		ix.addText(id, md.Name, weightName, 0)
+		ix.addText(id, md.Description+" "+strings.Join(md.Tags, " ")+" "+md.Topic, weightDescription, 0)
+
+		if src, err := fs.ReadFile(sources, md.Name+".go"); err == nil {
+			d.lines = strings.Split(string(src), "\n")
+			ix.addSource(id, src)
+		}
+		ix.docs = append(ix.docs, d)
+	}
+	return ix
+}
+
+// addSource indexes the comments and identifiers of a Go file.
+func (ix *Index) addSource(id int, src []byte) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return
+		}
+		line := file.Line(pos)
+		switch tok {
+		case token.COMMENT:
+			// A comment can span lines; index each line separately so the
+			// snippet points at the right one.
+			for i, text := range strings.Split(lit, "\n") {
+				ix.addText(id, text, weightComment, line+i)
+			}
+		case token.IDENT:
+			ix.addText(id, lit, weightIdentifier, line)
+		}
+	}
+}
+
+// addText adds every term of text to the index. line is 0 when the text
+// isn't from the source file.
+func (ix *Index) addText(id int, text string, weight float64, line int) {
+	for _, t := range Terms(text) {
+		docs := ix.terms[t]
+		if docs == nil {
+			docs = map[int]*posting{}
+			ix.terms[t] = docs
+		}
+		p := docs[id]
+		if p == nil {
+			p = &posting{}
+			docs[id] = p
+		}
+		p.score += weight
+		if line > 0 && (len(p.lines) == 0 || p.lines[len(p.lines)-1] != line) {
+			p.lines = append(p.lines, line)
+		}
+	}
+}
+
+// Terms splits text into lower-case search terms. Identifiers are split
+// at case changes and underscores too, so "incrementValueNoPtr" is found
+// by "increment", "value", "ptr" and the whole word.
+func Terms(text string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		parts := splitIdent(word)
+		if len(parts) > 1 {
+			terms = append(terms, strings.ToLower(strings.ReplaceAll(word, "_", "")))
+		}
+		for _, p := range parts {
+			terms = append(terms, strings.ToLower(p))
+		}
+	}
+	return terms
+}
+
+// splitIdent splits camelCase and snake_case words into their parts.
+func splitIdent(word string) []string {
+	var parts []string
+	start := 0
+	runes := []rune(word)
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes) || runes[i] == '_' ||
+			(unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]))
+		if !boundary {
+			continue
+		}
+		if part := strings.Trim(string(runes[start:i]), "_"); part != "" {
+			parts = append(parts, part)
+		}
+		start = i
+	}
+	return parts
+}
+
+// maxSnippets is how many source lines a match shows.
+const maxSnippets = 3
+
+// Search returns the examples containing every term of query, best first.
+// Each term's weight is scaled by how rare it is across examples (idf), so
+// that a common word like "value" decides less than a rare one.
+func (ix *Index) Search(query string) []Match {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	scores := map[int]float64{}
+	lines := map[int]map[int]bool{}
+	for i, t := range terms {
+		docs := ix.terms[t]
+		idf := math.Log(1 + float64(len(ix.docs))/float64(1+len(docs)))
+		next := map[int]float64{}
+		for id, p := range docs {
+			// Keep only documents that matched all earlier terms.
+			if _, ok := scores[id]; i > 0 && !ok {
+				continue
+			}
+			next[id] = scores[id] + p.score*idf
+			if lines[id] == nil {
+				lines[id] = map[int]bool{}
+			}
+			for _, l := range p.lines {
+				lines[id][l] = true
+			}
+		}
+		scores = next
+	}
+
+	var matches []Match
+	for id, score := range scores {
+		d := ix.docs[id]
+		m := Match{Metadata: d.md, Score: score}
+		var ls []int
+		for l := range lines[id] {
+			ls = append(ls, l)
+		}
+		sort.Ints(ls)
+		for _, l := range ls[:min(len(ls), maxSnippets)] {
+			if l-1 < len(d.lines) {
+				m.Snippets = append(m.Snippets, snippet(l, d.lines[l-1]))
+			}
+		}
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
+
+// snippet formats a source line for display.
+func snippet(n int, line string) string {
+	line = strings.TrimSpace(line)
+	if r := []rune(line); len(r) > 100 {
+		line = string(r[:99]) + "…"
+	}
+	return fmt.Sprintf("%d: %s", n, line)
+}
@@ -0,0 +1,468 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// prunedFile is a file of the bundle, by its path from the repository root.
+type prunedFile struct {
+	path string
+	data []byte
+}
+
+// bundledPackage is one of the module's packages as the bundle needs it.
+type bundledPackage struct {
+	listed listedPackage
+	files  []*parsedFile
+	// whole names the files kept as they are: the example's own file.
+	whole []string
+	// seeds are the names the bundle's other packages use from this one,
+	// Step for event.Step. The example's own package has none.
+	seeds map[string]bool
+	// inits says whether the init functions are kept. The example's package
+	// leaves them out: they register its other examples.
+	inits bool
+}
+
+type parsedFile struct {
+	name  string
+	src   []byte
+	ast   *ast.File
+	decls []*topDecl
+}
+
+// topDecl is a top-level declaration other than an import, and whether
+// the bundle uses it.
+type topDecl struct {
+	decl ast.Decl
+	used bool
+}
+
+// pruner follows what an example uses through its package and the module's
+// other packages.
+type pruner struct {
+	modulePath string
+	fset       *token.FileSet
+	pkgs       map[string]*bundledPackage // by import path
+	order      []string                   // import paths, as they were found
+}
+
+// prune returns the files of the example in exampleFile of own, the
+// package it is in: that file whole, and from the rest of own and the
+// module's packages it imports, directly or not, only the declarations it
+// uses, with those they use in turn and the methods of every type kept.
+// The dependencies keep their init functions; own doesn't, since they
+// register its other examples. main is the bundle's main program, and
+// what it uses is kept too.
+func prune(ctx context.Context, root string, own listedPackage, exampleFile, main string) ([]prunedFile, error) {
+	p := &pruner{modulePath: own.Module.Path, fset: token.NewFileSet(), pkgs: map[string]*bundledPackage{}}
+	mainFile, err := parser.ParseFile(p.fset, "prog.go", main, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.add(own, []string{exampleFile}, false); err != nil {
+		return nil, err
+	}
+	queue := []string{own.ImportPath}
+	for len(queue) > 0 {
+		bp := p.pkgs[queue[0]]
+		queue = queue[1:]
+		refs := p.mark(bp)
+		if bp == p.pkgs[own.ImportPath] {
+			for imp, names := range selectors(mainFile, fileImports(mainFile)) {
+				if p.local(imp) {
+					if refs[imp] == nil {
+						refs[imp] = map[string]bool{}
+					}
+					maps.Copy(refs[imp], names)
+				}
+			}
+		}
+		for _, imp := range slices.Sorted(maps.Keys(refs)) {
+			dep, ok := p.pkgs[imp]
+			if !ok {
+				listed, err := list(ctx, root, imp)
+				if err != nil {
+					return nil, err
+				}
+				if err := p.add(listed[0], nil, true); err != nil {
+					return nil, err
+				}
+				dep = p.pkgs[imp]
+				queue = append(queue, imp)
+			}
+			grew := false
+			for name := range refs[imp] {
+				if !dep.seeds[name] {
+					dep.seeds[name] = true
+					grew = true
+				}
+			}
+			if grew && !slices.Contains(queue, imp) {
+				queue = append(queue, imp)
+			}
+		}
+	}
+
+	var files []prunedFile
+	for _, imp := range p.order {
+		bp := p.pkgs[imp]
+		dir, err := filepath.Rel(root, bp.listed.Dir)
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.ToSlash(dir)
+		pruned, err := p.source(bp)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range slices.Sorted(maps.Keys(pruned)) {
+			files = append(files, prunedFile{path.Join(dir, name), pruned[name]})
+		}
+	}
+	return files, nil
+}
+
+// add parses the Go files of a package of the bundle.
+func (p *pruner) add(listed listedPackage, whole []string, inits bool) error {
+	bp := &bundledPackage{listed: listed, whole: whole, seeds: map[string]bool{}, inits: inits}
+	for _, name := range listed.GoFiles {
+		src, err := os.ReadFile(filepath.Join(listed.Dir, name))
+		if err != nil {
+			return err
+		}
+		f, err := parser.ParseFile(p.fset, filepath.Join(listed.Dir, name), src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		pf := &parsedFile{name: name, src: src, ast: f}
+		for _, d := range f.Decls {
+			if g, ok := d.(*ast.GenDecl); ok && g.Tok == token.IMPORT {
+				continue
+			}
+			pf.decls = append(pf.decls, &topDecl{decl: d})
+		}
+		bp.files = append(bp.files, pf)
+	}
+	p.pkgs[listed.ImportPath] = bp
+	p.order = append(p.order, listed.ImportPath)
+	return nil
+}
+
+// mark marks the declarations of bp that its whole files, init functions
+// and seeds use, and returns what those use from the module's other
+// packages: the names selected from each, by import path.
+func (p *pruner) mark(bp *bundledPackage) map[string]map[string]bool {
+	byName := map[string][]*topDecl{} // declared name, or "T.m" for methods
+	for _, pf := range bp.files {
+		for _, td := range pf.decls {
+			for _, n := range declNames(td.decl) {
+				byName[n] = append(byName[n], td)
+			}
+		}
+	}
+
+	var work []ast.Decl
+	var use func(td *topDecl)
+	use = func(td *topDecl) {
+		if td.used {
+			return
+		}
+		td.used = true
+		work = append(work, td.decl)
+		// Keeping a type keeps its methods: the bundle may call them, or
+		// need them to satisfy an interface.
+		for _, name := range declNames(td.decl) {
+			if !strings.Contains(name, ".") {
+				for key, methods := range byName {
+					if strings.HasPrefix(key, name+".") {
+						for _, m := range methods {
+							use(m)
+						}
+					}
+				}
+			}
+		}
+	}
+	useName := func(name string) {
+		for _, td := range byName[name] {
+			use(td)
+		}
+	}
+	for _, pf := range bp.files {
+		whole := slices.Contains(bp.whole, pf.name)
+		for _, td := range pf.decls {
+			if f, ok := td.decl.(*ast.FuncDecl); whole || ok && f.Recv == nil && f.Name.Name == "init" && bp.inits {
+				use(td)
+			}
+		}
+	}
+	for name := range bp.seeds {
+		useName(name)
+	}
+	for len(work) > 0 {
+		d := work[len(work)-1]
+		work = work[:len(work)-1]
+		ast.Inspect(d, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				useName(id.Name)
+			}
+			return true
+		})
+	}
+
+	refs := map[string]map[string]bool{}
+	need := func(imp string) map[string]bool {
+		if refs[imp] == nil {
+			refs[imp] = map[string]bool{}
+		}
+		return refs[imp]
+	}
+	for _, pf := range bp.files {
+		if !pf.keeps() {
+			continue
+		}
+		imports := fileImports(pf.ast)
+		for _, spec := range pf.ast.Imports {
+			// A blank or dot import needs the package whatever else does.
+			if imp := importPath(spec); sideEffect(spec) && p.local(imp) {
+				need(imp)
+			}
+		}
+		for _, td := range pf.decls {
+			if !td.used {
+				continue
+			}
+			for imp, names := range selectors(td.decl, imports) {
+				if p.local(imp) {
+					maps.Copy(need(imp), names)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// local reports whether imp is a package of this module.
+func (p *pruner) local(imp string) bool {
+	return strings.HasPrefix(imp, p.modulePath+"/")
+}
+
+// keeps reports whether the bundle uses anything in pf.
+func (pf *parsedFile) keeps() bool {
+	return slices.ContainsFunc(pf.decls, func(td *topDecl) bool { return td.used })
+}
+
+// source returns bp's files for the bundle by name: the whole files as
+// they are, and each other file with only its used declarations and the
+// imports they need, with the package's embedded files if one of those
+// is a //go:embed variable. A file with nothing used is left out, though a
+// package keeps at least one for its package clause.
+func (p *pruner) source(bp *bundledPackage) (map[string][]byte, error) {
+	out := map[string][]byte{}
+	embeds := false
+	for _, pf := range bp.files {
+		if slices.Contains(bp.whole, pf.name) {
+			out[pf.name] = pf.src
+			continue
+		}
+		if !pf.keeps() {
+			continue
+		}
+		imports := fileImports(pf.ast)
+		needed := map[string]bool{}
+		var decls [][]byte
+		for _, td := range pf.decls {
+			if !td.used {
+				continue
+			}
+			start := td.decl.Pos()
+			var doc *ast.CommentGroup
+			switch d := td.decl.(type) {
+			case *ast.FuncDecl:
+				doc = d.Doc
+			case *ast.GenDecl:
+				doc = d.Doc
+			}
+			if doc != nil {
+				start = doc.Pos()
+				embeds = embeds || strings.Contains(pf.text(p.fset, doc.Pos(), doc.End()), "//go:embed")
+			}
+			decls = append(decls, []byte(pf.text(p.fset, start, td.decl.End())))
+			for imp := range selectors(td.decl, imports) {
+				needed[imp] = true
+			}
+		}
+
+		var b bytes.Buffer
+		if doc := pf.ast.Doc; doc != nil {
+			b.WriteString(pf.text(p.fset, doc.Pos(), doc.End()))
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "package %s\n\n", pf.ast.Name.Name)
+		var specs []string
+		for _, spec := range pf.ast.Imports {
+			imp := importPath(spec)
+			switch {
+			case spec.Name != nil && (sideEffect(spec) || needed[imp]):
+				specs = append(specs, spec.Name.Name+" "+strconv.Quote(imp))
+			case needed[imp]:
+				specs = append(specs, strconv.Quote(imp))
+			}
+		}
+		if len(specs) > 0 {
+			fmt.Fprintf(&b, "import (\n\t%s\n)\n\n", strings.Join(specs, "\n\t"))
+		}
+		b.Write(bytes.Join(decls, []byte("\n\n")))
+		b.WriteString("\n")
+		src, err := format.Source(b.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("pruning %s: %v", filepath.Join(bp.listed.Dir, pf.name), err)
+		}
+		out[pf.name] = src
+	}
+	if len(out) == 0 && len(bp.files) > 0 {
+		pf := bp.files[0]
+		out[pf.name] = fmt.Appendf(nil, "package %s\n", pf.ast.Name.Name)
+	}
+	if embeds {
+		for _, name := range bp.listed.EmbedFiles {
+			data, err := os.ReadFile(filepath.Join(bp.listed.Dir, name))
+			if err != nil {
+				return nil, err
+			}
+			out[name] = data
+		}
+	}
+	return out, nil
+}
+
+// text returns the source of pf from start to end.
+func (pf *parsedFile) text(fset *token.FileSet, start, end token.Pos) string {
+	f := fset.File(start)
+	return string(pf.src[f.Offset(start):f.Offset(end)])
+}
+
+// declNames returns the names d declares that other code could refer to:
+// "T.m" for a method m of T, and nothing for init and blank names.
+func declNames(d ast.Decl) []string {
+	var names []string
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		switch {
+		case d.Recv != nil && len(d.Recv.List) == 1:
+			names = append(names, receiverName(d.Recv.List[0].Type)+"."+d.Name.Name)
+		case d.Recv == nil && d.Name.Name != "init":
+			names = append(names, d.Name.Name)
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if n.Name != "_" {
+						names = append(names, n.Name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// receiverName returns the name of a method's receiver type, without the
+// pointer or type parameters.
+func receiverName(x ast.Expr) string {
+	for {
+		switch t := x.(type) {
+		case *ast.StarExpr:
+			x = t.X
+		case *ast.IndexExpr:
+			x = t.X
+		case *ast.IndexListExpr:
+			x = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// fileImports maps the names f refers to its imports by to their paths.
+func fileImports(f *ast.File) map[string]string {
+	byName := map[string]string{}
+	for _, spec := range f.Imports {
+		byName[importName(spec)] = importPath(spec)
+	}
+	return byName
+}
+
+// selectors returns the names n selects from the packages in imports, by
+// import path. The parser resolves every other name it can, so a package
+// name is one it left unresolved: a local variable called table is not
+// the table package.
+func selectors(n ast.Node, imports map[string]string) map[string]map[string]bool {
+	out := map[string]map[string]bool{}
+	ast.Inspect(n, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+			if imp, ok := imports[id.Name]; ok {
+				if out[imp] == nil {
+					out[imp] = map[string]bool{}
+				}
+				out[imp][sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// sideEffect reports whether spec is a blank or dot import, which a file
+// needs even though nothing selects from it.
+func sideEffect(spec *ast.ImportSpec) bool {
+	return spec.Name != nil && (spec.Name.Name == "_" || spec.Name.Name == ".")
+}
+
+func importPath(spec *ast.ImportSpec) string {
+	p, _ := strconv.Unquote(spec.Path.Value)
+	return p
+}
+
+// versionRE matches the major version suffix of an import path.
+var versionRE = regexp.MustCompile(`^v[0-9]+$|\.v[0-9]+$`)
+
+// importName returns the name an import is used by in its file: its own
+// name if it has one, or else the last element of the path, without a
+// major version suffix such as /v2 or .v3.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	p := importPath(spec)
+	base := path.Base(p)
+	if versionRE.MatchString(base) && strings.HasPrefix(base, "v") {
+		base = path.Base(path.Dir(p))
+	}
+	return versionRE.ReplaceAllString(base, "")
+}
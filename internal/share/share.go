@@ -0,0 +1,187 @@
+// Package share publishes an example to the Go Playground.
+//
+// An example isn't a single file: it lives in a package and uses helpers
+// from this module (event, memviz, ...). The Playground accepts several
+// files in one "txtar" text, so Bundle flattens the example into one such
+// text: a generated main program first, then go.mod, then the example's own
+// file, and only the declarations it uses from the rest of its package and
+// the module's other packages, and those they use, file by file.
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// DefaultEndpoint is the Playground's share API.
+const DefaultEndpoint = "https://play.golang.org/share"
+
+// viewURL is where a shared snippet can be opened.
+const viewURL = "https://go.dev/play/p/"
+
+// maxBundle is the Playground's size limit for a snippet.
+const maxBundle = 64 << 10
+
+// listedPackage is the part of "go list -json" output that Bundle needs.
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	EmbedFiles []string
+	Module     *struct{ Path string }
+}
+
+// Bundle returns the txtar text for the example described by md. root is
+// the repository root and pkg the import path of the example's package.
+func Bundle(ctx context.Context, root, pkg string, md registry.Metadata) ([]byte, error) {
+	pkgs, err := list(ctx, root, pkg)
+	if err != nil {
+		return nil, err
+	}
+	own := pkgs[0]
+	if own.Module == nil || !strings.HasPrefix(own.Dir, root) {
+		return nil, fmt.Errorf("%s is not a package of this module", pkg)
+	}
+	exampleFile := path.Base(md.Name) + ".go"
+	if !slices.Contains(own.GoFiles, exampleFile) {
+		return nil, fmt.Errorf("%s has no file %s for %s", pkg, exampleFile, md.Name)
+	}
+	main := mainProgram(pkg, md.Name)
+	files, err := prune(ctx, root, own, exampleFile, main)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString(main)
+	if err := addFile(&b, root, "go.mod"); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(root, "go.sum")); err == nil {
+		if err := addFile(&b, root, "go.sum"); err != nil {
+			return nil, err
+		}
+	}
+	// Standard library and third-party packages are fetched by the
+	// Playground itself; only this module's own files are bundled.
+	for _, f := range files {
+		addSection(&b, f.path, f.data)
+	}
+	if b.Len() > maxBundle {
+		return nil, fmt.Errorf("%s flattens to %d bytes, more than the Playground's %d byte limit", md.Name, b.Len(), maxBundle)
+	}
+	return b.Bytes(), nil
+}
+
+// list runs "go list -json" in root with args and returns the packages
+// it describes.
+func list(ctx context.Context, root string, args ...string) ([]listedPackage, error) {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"list", "-json"}, args...)...)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list %s: %v\n%s", strings.Join(args, " "), err, stderr.Bytes())
+	}
+	var pkgs []listedPackage
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var p listedPackage
+		if err := dec.Decode(&p); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, p)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("go list %s: no packages", strings.Join(args, " "))
+	}
+	return pkgs, nil
+}
+
+// mainProgram is the Playground's entry point: it runs the one example.
+func mainProgram(pkg, name string) string {
+	return fmt.Sprintf(`// This program runs the %[2]q example from
+// github.com/amandm/programming-concepts. The example's own code is in
+// the files further down.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	_ %[1]q
+	"github.com/amandm/programming-concepts/internal/isolate"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+func main() {
+	isolate.Main()
+	c, _ := registry.Lookup(%[2]q)
+	err := c.Run(context.Background(), os.Stdout)
+	isolate.Cleanup()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`, pkg, name)
+}
+
+// addFile appends a txtar section holding the file root/rel.
+func addFile(b *bytes.Buffer, root, rel string) error {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	addSection(b, rel, data)
+	return nil
+}
+
+// addSection appends a txtar section holding data as the file name.
+func addSection(b *bytes.Buffer, name string, data []byte) {
+	fmt.Fprintf(b, "-- %s --\n", name)
+	b.Write(data)
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+}
+
+// Upload posts a bundle to the share API at endpoint and returns the URL
+// at which it can be opened.
+func Upload(ctx context.Context, endpoint string, bundle []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bundle))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("share API: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return viewURL + strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,75 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/amandm/programming-concepts/GOlang/all"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+const modulePath = "github.com/amandm/programming-concepts"
+
+// TestEveryExampleFits bundles every example, and builds the bundle the
+// way the Playground would: as a module of its own, from nothing but the
+// bundle's files.
+func TestEveryExampleFits(t *testing.T) {
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range registry.All() {
+		md := c.Describe()
+		t.Run(md.Name, func(t *testing.T) {
+			pkg := path.Join(modulePath, "GOlang", path.Dir(md.Name))
+			bundle, err := Bundle(context.Background(), root, pkg, md)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Logf("%d bytes", len(bundle))
+			if testing.Short() {
+				return
+			}
+			dir := t.TempDir()
+			extract(t, dir, bundle)
+			cmd := exec.Command("go", "vet", "./...")
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("the bundle doesn't build: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+// extract writes the files of a txtar text into dir, its leading text as
+// prog.go, where the Playground puts it.
+func extract(t *testing.T, dir string, bundle []byte) {
+	t.Helper()
+	name := "prog.go"
+	var data bytes.Buffer
+	flush := func() {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, data.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		data.Reset()
+	}
+	for _, line := range strings.SplitAfter(string(bundle), "\n") {
+		if strings.HasPrefix(line, "-- ") && strings.HasSuffix(line, " --\n") {
+			flush()
+			name = strings.TrimSuffix(strings.TrimPrefix(line, "-- "), " --\n")
+			continue
+		}
+		data.WriteString(line)
+	}
+	flush()
+}
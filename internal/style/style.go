@@ -0,0 +1,126 @@
+// Package style adds color and emphasis to terminal output, and wraps text
+// to the width of the terminal.
+//
+// Styling is only switched on for terminals: output that is piped into a
+// file, a golden test or another program stays plain text. Setting the
+// NO_COLOR environment variable (see https://no-color.org) or TERM=dumb
+// switches it off everywhere.
+package style
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Styler decorates text with ANSI escapes. The zero Styler is switched off
+// and returns all text unchanged.
+type Styler struct {
+	on bool
+}
+
+// New returns a Styler that is switched on or off.
+func New(on bool) Styler {
+	return Styler{on: on}
+}
+
+// For returns the Styler suitable for output written to w: on if w is a
+// terminal and neither NO_COLOR nor TERM=dumb asks for plain text.
+func For(w io.Writer) Styler {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return Styler{}
+	}
+	f, ok := w.(*os.File)
+	return Styler{on: ok && term.IsTerminal(int(f.Fd()))}
+}
+
+// ForMode is like For, but mode can also switch styling on ("always") or
+// off ("never") regardless of w. "auto" and "" leave the decision to For.
+func ForMode(w io.Writer, mode string) (Styler, error) {
+	switch mode {
+	case "", "auto":
+		return For(w), nil
+	case "always":
+		return Styler{on: true}, nil
+	case "never":
+		return Styler{}, nil
+	}
+	return Styler{}, fmt.Errorf("unknown color mode %q (want auto, always or never)", mode)
+}
+
+// Enabled reports whether s adds any escapes.
+func (s Styler) Enabled() bool {
+	return s.on
+}
+
+func (s Styler) wrap(code, text string) string {
+	if !s.on || text == "" {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// Bold emphasizes text.
+func (s Styler) Bold(text string) string { return s.wrap("1", text) }
+
+// Dim de-emphasizes text, e.g. headers and hints.
+func (s Styler) Dim(text string) string { return s.wrap("2", text) }
+
+// Address colors a memory address.
+func (s Styler) Address(text string) string { return s.wrap("36", text) }
+
+// Value colors an observed value.
+func (s Styler) Value(text string) string { return s.wrap("32", text) }
+
+// Warning colors something the learner should watch out for.
+func (s Styler) Warning(text string) string { return s.wrap("1;33", text) }
+
+// Error colors a failure.
+func (s Styler) Error(text string) string { return s.wrap("1;31", text) }
+
+// Width returns the width of the terminal w writes to, or fallback if w
+// isn't a terminal.
+func Width(w io.Writer, fallback int) int {
+	if f, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	return fallback
+}
+
+// Wrap breaks text into lines of at most width runes, between words where
+// possible. Words longer than a line are split. Existing line breaks are
+// kept.
+func Wrap(text string, width int) []string {
+	if width <= 0 {
+		return strings.Split(text, "\n")
+	}
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines, line = append(lines, line), ""
+				}
+				r := []rune(word)
+				lines, word = append(lines, string(r[:width])), string(r[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines, line = append(lines, line), word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,89 @@
+// Package table lays out rows of text in aligned columns, e.g. a snapshot
+// of the variables an example is looking at:
+//
+//	variable  address             value               scope
+//	────────  ──────────────────  ──────────────────  ──────────────
+//	count     0x000000c000012345  11                  Run
+//	valPtr    0x000000c000012388  0x000000c000012345  incrementValue
+//
+// A Table is an fmt.Stringer, so examples emit it like a memviz diagram:
+// e.Diagram(t).
+package table
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table is a header and the rows below it.
+type Table struct {
+	header []string
+	rows   [][]string
+}
+
+// New returns an empty table with the given column headers.
+func New(header ...string) *Table {
+	return &Table{header: header}
+}
+
+// Row adds a row. Cells are formatted with fmt.Sprint, except pointers,
+// which are shown as addresses padded to 64 bits (like in memviz) so a
+// table has the same shape on every run. Missing cells are left empty and
+// cells beyond the header's columns are dropped. Row returns t so calls
+// can be chained.
+func (t *Table) Row(cells ...any) *Table {
+	row := make([]string, len(t.header))
+	for i := range min(len(cells), len(row)) {
+		row[i] = format(cells[i])
+	}
+	t.rows = append(t.rows, row)
+	return t
+}
+
+// format formats one cell.
+func format(cell any) string {
+	if p := reflect.ValueOf(cell); p.Kind() == reflect.Pointer {
+		if p.IsNil() {
+			return "nil"
+		}
+		return fmt.Sprintf("0x%016x", p.Pointer())
+	}
+	return fmt.Sprint(cell)
+}
+
+// String renders the table, with a rule below the header.
+func (t *Table) String() string {
+	widths := make([]int, len(t.header))
+	for i, h := range t.header {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	rule := make([]string, len(widths))
+	for i, w := range widths {
+		rule[i] = strings.Repeat("─", w)
+	}
+	lines := []string{t.line(t.header, widths), t.line(rule, widths)}
+	for _, row := range t.rows {
+		lines = append(lines, t.line(row, widths))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// line pads the cells to their column's width and joins them.
+func (t *Table) line(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, cell := range cells {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
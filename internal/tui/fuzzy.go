@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// fuzzyScore reports whether every character of query appears in text in
+// order (case-insensitively), and how good the match is. Consecutive
+// matches and matches at the start of a word score higher, so "pfe" ranks
+// "pointers/function_example" above a name that merely contains the letters.
+func fuzzyScore(query, text string) (score int, ok bool) {
+	if query == "" {
+		return 0, true
+	}
+	query, text = strings.ToLower(query), strings.ToLower(text)
+
+	prevMatched := false
+	prev := rune(-1)
+	for _, r := range text {
+		if query == "" {
+			break
+		}
+		q, size := utf8.DecodeRuneInString(query)
+		if r == q {
+			score++
+			if prevMatched {
+				score += 2
+			}
+			if prev == -1 || prev == '/' || prev == '_' || prev == '-' || prev == ' ' {
+				score += 3
+			}
+			query = query[size:]
+			prevMatched = true
+		} else {
+			prevMatched = false
+		}
+		prev = r
+	}
+	return score, query == ""
+}
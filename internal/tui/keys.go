@@ -0,0 +1,72 @@
+package tui
+
+// key is a decoded key press.
+type key struct {
+	kind keyKind
+	r    rune // set for keyRune
+}
+
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyBackspace
+	keyEscape
+	keyTab
+	keyCtrlC
+	keyUnknown
+)
+
+// decodeKeys turns the bytes of one terminal read into key presses. The
+// terminal is in raw mode, so arrow keys arrive as escape sequences like
+// "\x1b[A".
+func decodeKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		switch {
+		case b[0] == 0x1b && len(b) >= 3 && b[1] == '[':
+			n := 3
+			switch b[2] {
+			case 'A':
+				keys = append(keys, key{kind: keyUp})
+			case 'B':
+				keys = append(keys, key{kind: keyDown})
+			case '5', '6':
+				// Page up/down are "\x1b[5~" and "\x1b[6~".
+				kind := keyPageUp
+				if b[2] == '6' {
+					kind = keyPageDown
+				}
+				keys = append(keys, key{kind: kind})
+				if len(b) >= 4 && b[3] == '~' {
+					n = 4
+				}
+			default:
+				keys = append(keys, key{kind: keyUnknown})
+			}
+			b = b[n:]
+			continue
+		case b[0] == 0x1b:
+			keys = append(keys, key{kind: keyEscape})
+		case b[0] == '\r' || b[0] == '\n':
+			keys = append(keys, key{kind: keyEnter})
+		case b[0] == 0x7f || b[0] == 0x08:
+			keys = append(keys, key{kind: keyBackspace})
+		case b[0] == '\t':
+			keys = append(keys, key{kind: keyTab})
+		case b[0] == 0x03:
+			keys = append(keys, key{kind: keyCtrlC})
+		case b[0] >= 0x20 && b[0] < 0x7f:
+			keys = append(keys, key{kind: keyRune, r: rune(b[0])})
+		default:
+			keys = append(keys, key{kind: keyUnknown})
+		}
+		b = b[1:]
+	}
+	return keys
+}
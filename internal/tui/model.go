@@ -0,0 +1,258 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// paneMode says what the right-hand pane shows.
+type paneMode int
+
+const (
+	showSource paneMode = iota
+	showOutput
+)
+
+// model is the whole state of the browser. It doesn't do any terminal I/O
+// itself: keys go in through handle, and view turns the state into lines.
+type model struct {
+	ctx     context.Context
+	sources fs.FS
+	all     []registry.Concept
+
+	query     string
+	searching bool
+	matches   []registry.Concept // filtered by query, grouped by topic
+	cursor    int                // index into matches
+
+	mode   paneMode
+	output map[string]string // captured output per example name
+	scroll int
+	quit   bool
+}
+
+func newModel(ctx context.Context, sources fs.FS, all []registry.Concept) *model {
+	m := &model{ctx: ctx, sources: sources, all: all, output: map[string]string{}}
+	m.filter()
+	return m
+}
+
+// filter recomputes matches from the current query. Examples stay grouped
+// by topic; inside a topic the best fuzzy matches come first.
+func (m *model) filter() {
+	type scored struct {
+		c     registry.Concept
+		score int
+	}
+	var found []scored
+	for _, c := range m.all {
+		md := c.Describe()
+		score, ok := fuzzyScore(m.query, md.Name+" "+md.Description+" "+strings.Join(md.Tags, " "))
+		if ok {
+			found = append(found, scored{c, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i].c.Describe(), found[j].c.Describe()
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		if found[i].score != found[j].score {
+			return found[i].score > found[j].score
+		}
+		return a.Name < b.Name
+	})
+
+	m.matches = m.matches[:0]
+	for _, f := range found {
+		m.matches = append(m.matches, f.c)
+	}
+	m.cursor = min(m.cursor, max(len(m.matches)-1, 0))
+	m.scroll = 0
+}
+
+// selected returns the highlighted example, if any.
+func (m *model) selected() (registry.Concept, bool) {
+	if m.cursor < len(m.matches) {
+		return m.matches[m.cursor], true
+	}
+	return nil, false
+}
+
+// handle applies one key press to the state.
+func (m *model) handle(k key) {
+	if k.kind == keyCtrlC {
+		m.quit = true
+		return
+	}
+	if m.searching {
+		switch k.kind {
+		case keyRune:
+			m.query += string(k.r)
+			m.filter()
+			return
+		case keyBackspace:
+			if m.query != "" {
+				_, size := utf8.DecodeLastRuneInString(m.query)
+				m.query = m.query[:len(m.query)-size]
+				m.filter()
+			}
+			return
+		case keyEscape:
+			m.searching, m.query = false, ""
+			m.filter()
+			return
+		case keyEnter:
+			m.searching = false
+			return
+		}
+	}
+
+	switch k.kind {
+	case keyUp:
+		if m.cursor > 0 {
+			m.cursor--
+			m.mode, m.scroll = showSource, 0
+		}
+	case keyDown:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+			m.mode, m.scroll = showSource, 0
+		}
+	case keyPageUp:
+		m.scroll = max(m.scroll-10, 0)
+	case keyPageDown:
+		m.scroll += 10
+	case keyTab:
+		m.mode = 1 - m.mode
+		m.scroll = 0
+	case keyEnter:
+		m.runSelected()
+	case keyRune:
+		switch k.r {
+		case 'q':
+			m.quit = true
+		case '/':
+			m.searching = true
+		case 'k':
+			m.handle(key{kind: keyUp})
+		case 'j':
+			m.handle(key{kind: keyDown})
+		}
+	}
+}
+
+// runSelected runs the highlighted example and switches the pane to its output.
+func (m *model) runSelected() {
+	c, ok := m.selected()
+	if !ok {
+		return
+	}
+	var out bytes.Buffer
+	if err := c.Run(m.ctx, &out); err != nil {
+		fmt.Fprintf(&out, "\nerror: %v\n", err)
+	}
+	m.output[c.Describe().Name] = out.String()
+	m.mode, m.scroll = showOutput, 0
+}
+
+// paneLines returns the content of the right-hand pane.
+func (m *model) paneLines() []string {
+	c, ok := m.selected()
+	if !ok {
+		return []string{"No example matches the search."}
+	}
+	md := c.Describe()
+	lines := []string{
+		md.Name,
+		md.Description,
+		fmt.Sprintf("%s · %s · %s", md.Topic, md.Level, strings.Join(md.Tags, ", ")),
+		"",
+	}
+	switch m.mode {
+	case showOutput:
+		out, ran := m.output[md.Name]
+		if !ran {
+			return append(lines, "Press Enter to run this example.")
+		}
+		return append(lines, strings.Split(strings.TrimRight(out, "\n"), "\n")...)
+	default:
+		src, err := fs.ReadFile(m.sources, md.Name+".go")
+		if err != nil {
+			return append(lines, "source not available: "+err.Error())
+		}
+		return append(lines, strings.Split(strings.TrimRight(string(src), "\n"), "\n")...)
+	}
+}
+
+// view renders the state into exactly height lines of at most width columns.
+func (m *model) view(width, height int) []string {
+	listWidth := min(40, width/3)
+	bodyHeight := height - 1 // the last line is the status bar
+
+	// Left column: topic headers followed by their examples.
+	var list []string
+	cursorLine := 0
+	topic := ""
+	for i, c := range m.matches {
+		md := c.Describe()
+		if md.Topic != topic {
+			topic = md.Topic
+			list = append(list, strings.ToUpper(topic))
+		}
+		marker := "  "
+		if i == m.cursor {
+			marker = "> "
+			cursorLine = len(list)
+		}
+		list = append(list, marker+strings.TrimPrefix(md.Name, topic+"/"))
+	}
+	// Keep the cursor visible when the list is taller than the screen.
+	listStart := max(0, cursorLine-bodyHeight+1)
+
+	pane := m.paneLines()
+	m.scroll = min(m.scroll, max(len(pane)-bodyHeight, 0))
+
+	lines := make([]string, 0, height)
+	for row := 0; row < bodyHeight; row++ {
+		left, right := "", ""
+		if i := listStart + row; i < len(list) {
+			left = list[i]
+		}
+		if i := m.scroll + row; i < len(pane) {
+			right = pane[i]
+		}
+		lines = append(lines, fit(left, listWidth)+" │ "+fit(right, width-listWidth-3))
+	}
+
+	status := "↑/↓ move  Enter run  Tab source/output  PgUp/PgDn scroll  / search  q quit"
+	if m.searching || m.query != "" {
+		status = "search: " + m.query
+		if m.searching {
+			status += "_"
+		}
+	}
+	return append(lines, fit(status, width))
+}
+
+// fit pads or truncates s to exactly width runes. Tabs become spaces so
+// that source code lines up.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "\t", "    ")
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		r := []rune(s)
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
@@ -0,0 +1,60 @@
+// Package tui is an interactive terminal browser for the example catalog.
+//
+// The left column lists the examples grouped by topic, the right pane shows
+// the selected example's source or, after pressing Enter, its output.
+// Typing "/" starts a fuzzy search over names, descriptions and tags.
+package tui
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+// Run takes over the terminal until the user quits. in must be the
+// terminal itself, because it is switched to raw mode to read single keys.
+func Run(ctx context.Context, in, out *os.File, sources fs.FS) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("the browser needs an interactive terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	// Switch to the alternate screen so the user's scrollback survives, and
+	// hide the cursor while we draw.
+	out.WriteString("\x1b[?1049h\x1b[?25l")
+	defer out.WriteString("\x1b[?25h\x1b[?1049l")
+
+	m := newModel(ctx, sources, registry.All())
+	buf := make([]byte, 64)
+	for !m.quit {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			width, height = 100, 30
+		}
+		// Raw mode doesn't translate "\n", so every line ends in "\r\n".
+		out.WriteString("\x1b[H" + strings.Join(m.view(width, height), "\r\n"))
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, k := range decodeKeys(buf[:n]) {
+			m.handle(k)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
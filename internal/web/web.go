@@ -0,0 +1,122 @@
+// Package web serves a small browser UI for the example catalog: a list of
+// every registered example, and a page per example showing its highlighted
+// source next to the output of running it.
+package web
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amandm/programming-concepts/internal/highlight"
+	"github.com/amandm/programming-concepts/internal/registry"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// runTimeout bounds how long a single "Run" click may take.
+const runTimeout = 10 * time.Second
+
+// server holds what the handlers need.
+type server struct {
+	sources fs.FS
+}
+
+// NewHandler returns the UI's HTTP handler. sources must contain the
+// example source files laid out as described by registry.Metadata.Name.
+func NewHandler(sources fs.FS) http.Handler {
+	s := &server{sources: sources}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /examples/{name...}", s.example)
+	mux.HandleFunc("POST /run/{name...}", s.run)
+	return mux
+}
+
+// topic is one group of examples on the index page.
+type topic struct {
+	Name     string
+	Examples []registry.Metadata
+}
+
+// index lists every example grouped by topic.
+func (s *server) index(w http.ResponseWriter, r *http.Request) {
+	var all []registry.Metadata
+	for _, c := range registry.All() {
+		all = append(all, c.Describe())
+	}
+	// All is sorted by name; a stable sort by topic keeps that order inside each topic.
+	slices.SortStableFunc(all, func(a, b registry.Metadata) int { return strings.Compare(a.Topic, b.Topic) })
+
+	var topics []topic
+	for _, md := range all {
+		if len(topics) == 0 || topics[len(topics)-1].Name != md.Topic {
+			topics = append(topics, topic{Name: md.Topic})
+		}
+		t := &topics[len(topics)-1]
+		t.Examples = append(t.Examples, md)
+	}
+	s.render(w, "index.html", topics)
+}
+
+// example shows one example's metadata and highlighted source.
+func (s *server) example(w http.ResponseWriter, r *http.Request) {
+	c, ok := registry.Lookup(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	md := c.Describe()
+	src, err := fs.ReadFile(s.sources, md.Name+".go")
+	if err != nil {
+		http.Error(w, "source not found: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.render(w, "example.html", struct {
+		registry.Metadata
+		Source template.HTML
+	}{md, template.HTML(highlight.HTML(src))})
+}
+
+// run executes an example and replies with its output as plain text.
+func (s *server) run(w http.ResponseWriter, r *http.Request) {
+	c, ok := registry.Lookup(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), runTimeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	err := c.Run(ctx, &out)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		out.WriteString("\nerror: " + err.Error() + "\n")
+	}
+	w.Write(out.Bytes())
+}
+
+// render executes a template into a buffer first, so that a template error
+// turns into a clean 500 instead of a half-written page.
+func (s *server) render(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("web: rendering %s: %v", name, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}